@@ -5,6 +5,7 @@ package arbcompress
 
 import (
 	"bytes"
+	"errors"
 	"testing"
 
 	"github.com/offchainlabs/nitro/util/testhelpers"
@@ -48,3 +49,40 @@ func TestArbCompress(t *testing.T) {
 	// test empty data:
 	testCompressDecompress(t, []byte{})
 }
+
+func testZstdCompressDecompress(t *testing.T, data []byte) {
+	compressed, err := CompressZstd(data, ZstdDefaultLevel)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := DecompressZstd(compressed, len(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(res, data) {
+		t.Fatal("results differ ", res, " vs. ", data)
+	}
+	if len(data) > 0 {
+		if _, err := DecompressZstd(compressed, len(data)-1); !errors.Is(err, ErrOutputWontFit) {
+			t.Fatal("expected output not to fit, got", err)
+		}
+	}
+}
+
+func TestZstdCompress(t *testing.T) {
+	asciiData := []byte("This is a long and repetitive string. Yadda yadda yadda yadda yadda. The quick brown fox jumped over the lazy dog.")
+	for i := 0; i < 8; i++ {
+		asciiData = append(asciiData, asciiData...)
+	}
+	testZstdCompressDecompress(t, asciiData)
+
+	source := testhelpers.NewPseudoRandomDataSource(t, 0)
+	randData := source.GetData(2500)
+	testZstdCompressDecompress(t, randData)
+
+	testZstdCompressDecompress(t, []byte{})
+
+	if _, err := DecompressZstd(randData, len(randData)); err == nil {
+		t.Fatal("decompressed garbage without an error")
+	}
+}
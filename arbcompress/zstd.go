@@ -0,0 +1,105 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+package arbcompress
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// The zstd codec is implemented in pure go, so unlike brotli it's the same code in the native and wasm builds.
+// Decompression is fully determined by the input, which keeps it safe to use in the replay binary.
+
+const ZstdDefaultLevel = 3
+
+// zstdMaxWindowSize bounds the memory a malicious frame can make the decoder allocate.
+const zstdMaxWindowSize = 1 << 23
+
+func newZstdDecoder(maxSize int, dictionary []byte) (*zstd.Decoder, error) {
+	// the decoder rejects a zero memory limit
+	maxMemory := max(maxSize, 1)
+	options := []zstd.DOption{
+		zstd.WithDecoderConcurrency(1),
+		zstd.WithDecoderLowmem(true),
+		// #nosec G115
+		zstd.WithDecoderMaxMemory(uint64(maxMemory)),
+		zstd.WithDecoderMaxWindow(zstdMaxWindowSize),
+	}
+	if len(dictionary) > 0 {
+		options = append(options, zstd.WithDecoderDicts(dictionary))
+	}
+	return zstd.NewReader(nil, options...)
+}
+
+func newZstdEncoderOptions(level int, dictionary []byte) []zstd.EOption {
+	options := []zstd.EOption{
+		zstd.WithEncoderConcurrency(1),
+		zstd.WithEncoderLevel(zstd.EncoderLevelFromZstd(level)),
+		zstd.WithWindowSize(zstdMaxWindowSize),
+		zstd.WithEncoderCRC(false),
+	}
+	if len(dictionary) > 0 {
+		options = append(options, zstd.WithEncoderDict(dictionary))
+	}
+	return options
+}
+
+// NewZstdWriter returns a streaming zstd compressor writing to w, which can be flushed like a brotli writer.
+func NewZstdWriter(w io.Writer, level int) (*zstd.Encoder, error) {
+	return NewZstdWriterWithDictionary(w, level, nil)
+}
+
+func NewZstdWriterWithDictionary(w io.Writer, level int, dictionary []byte) (*zstd.Encoder, error) {
+	return zstd.NewWriter(w, newZstdEncoderOptions(level, dictionary)...)
+}
+
+func CompressZstd(input []byte, level int) ([]byte, error) {
+	return CompressZstdWithDictionary(input, level, nil)
+}
+
+func CompressZstdWithDictionary(input []byte, level int, dictionary []byte) ([]byte, error) {
+	var buffer bytes.Buffer
+	writer, err := NewZstdWriterWithDictionary(&buffer, level, dictionary)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := writer.Write(input); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+func DecompressZstd(input []byte, maxSize int) ([]byte, error) {
+	return DecompressZstdWithDictionary(input, maxSize, nil)
+}
+
+func DecompressZstdWithDictionary(input []byte, maxSize int, dictionary []byte) ([]byte, error) {
+	decoder, err := newZstdDecoder(maxSize, dictionary)
+	if err != nil {
+		return nil, err
+	}
+	defer decoder.Close()
+	if err := decoder.Reset(bytes.NewReader(input)); err != nil {
+		return nil, fmt.Errorf("failed zstd decompression: %w", err)
+	}
+	// Read one byte past the limit to tell a result that exactly fits from one that doesn't
+	output, err := io.ReadAll(io.LimitReader(decoder.IOReadCloser(), int64(maxSize)+1))
+	if err != nil {
+		if errors.Is(err, zstd.ErrDecoderSizeExceeded) || errors.Is(err, zstd.ErrWindowSizeExceeded) {
+			return nil, ErrOutputWontFit
+		}
+		return nil, fmt.Errorf("failed zstd decompression: %w", err)
+	}
+	if len(output) > maxSize {
+		return nil, ErrOutputWontFit
+	}
+	return output, nil
+}
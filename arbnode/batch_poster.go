@@ -10,6 +10,7 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"io"
 	"math"
 	"math/big"
 	"strings"
@@ -17,6 +18,7 @@ import (
 	"time"
 
 	"github.com/andybalholm/brotli"
+	"github.com/redis/go-redis/v9"
 	"github.com/spf13/pflag"
 
 	"github.com/ethereum/go-ethereum"
@@ -34,6 +36,7 @@ import (
 	"github.com/ethereum/go-ethereum/rpc"
 
 	"github.com/offchainlabs/bold/solgen/go/bridgegen"
+	"github.com/offchainlabs/nitro/arbcompress"
 	"github.com/offchainlabs/nitro/arbnode/dataposter"
 	"github.com/offchainlabs/nitro/arbnode/dataposter/storage"
 	"github.com/offchainlabs/nitro/arbnode/parent"
@@ -41,6 +44,7 @@ import (
 	"github.com/offchainlabs/nitro/arbos/arbostypes"
 	"github.com/offchainlabs/nitro/arbstate"
 	"github.com/offchainlabs/nitro/arbstate/daprovider"
+	"github.com/offchainlabs/nitro/arbstate/daprovider/externalda"
 	"github.com/offchainlabs/nitro/arbutil"
 	"github.com/offchainlabs/nitro/cmd/chaininfo"
 	"github.com/offchainlabs/nitro/cmd/genericconf"
@@ -108,9 +112,12 @@ type BatchPoster struct {
 	building           *buildingBatch
 	dapWriter          daprovider.Writer
 	dapReaders         []daprovider.Reader
-	dataPoster         *dataposter.DataPoster
+	dataPoster         *dataposter.DataPoster // nil in shadow mode
+	shadow             *shadowBatchPoster     // only set in shadow mode
+	catchUp            *catchUpPipeline       // nil in shadow mode
 	redisLock          *redislock.Simple
 	messagesPerBatch   *arbmath.MovingAverage[uint64]
+	feeForecaster      *l1FeeForecaster
 	non4844BatchCount  int // Count of consecutive non-4844 batches posted
 	// This is an atomic variable that should only be accessed atomically.
 	// An estimate of the number of batches we want to post but haven't yet.
@@ -120,7 +127,7 @@ type BatchPoster struct {
 
 	batchReverted        atomic.Bool // indicates whether data poster batch was reverted
 	nextRevertCheckBlock int64       // the last parent block scanned for reverting batches
-	postedFirstBatch     bool        // indicates if batch poster has posted the first batch
+	postedFirstBatch     atomic.Bool // indicates if batch poster has posted the first batch
 
 	accessList  func(SequencerInboxAccs, AfterDelayedMessagesRead uint64) types.AccessList
 	parentChain *parent.ParentChain
@@ -158,6 +165,7 @@ type BatchPosterConfig struct {
 	// Batch posting error delay.
 	ErrorDelay                     time.Duration               `koanf:"error-delay" reload:"hot"`
 	CompressionLevel               int                         `koanf:"compression-level" reload:"hot"`
+	CompressionCodec               string                      `koanf:"compression-codec" reload:"hot"`
 	DASRetentionPeriod             time.Duration               `koanf:"das-retention-period" reload:"hot"`
 	GasRefunderAddress             string                      `koanf:"gas-refunder-address" reload:"hot"`
 	DataPoster                     dataposter.DataPosterConfig `koanf:"data-poster" reload:"hot"`
@@ -166,6 +174,9 @@ type BatchPosterConfig struct {
 	ExtraBatchGas                  uint64                      `koanf:"extra-batch-gas" reload:"hot"`
 	Post4844Blobs                  bool                        `koanf:"post-4844-blobs" reload:"hot"`
 	IgnoreBlobPrice                bool                        `koanf:"ignore-blob-price" reload:"hot"`
+	FeePolicy                      FeePolicyConfig             `koanf:"fee-policy" reload:"hot"`
+	CatchUp                        CatchUpConfig               `koanf:"catch-up" reload:"hot"`
+	ExternalDA                     externalda.ClientConfig     `koanf:"external-da"`
 	ParentChainWallet              genericconf.WalletConfig    `koanf:"parent-chain-wallet"`
 	L1BlockBound                   string                      `koanf:"l1-block-bound" reload:"hot"`
 	L1BlockBoundBypass             time.Duration               `koanf:"l1-block-bound-bypass" reload:"hot"`
@@ -176,6 +187,7 @@ type BatchPosterConfig struct {
 	CheckBatchCorrectness          bool                        `koanf:"check-batch-correctness"`
 	MaxEmptyBatchDelay             time.Duration               `koanf:"max-empty-batch-delay"`
 	DelayBufferThresholdMargin     uint64                      `koanf:"delay-buffer-threshold-margin"`
+	Shadow                         BatchPosterShadowConfig     `koanf:"shadow"`
 
 	gasRefunder  common.Address
 	l1BlockBound l1BlockBound
@@ -189,6 +201,21 @@ func (c *BatchPosterConfig) Validate() error {
 	if c.MaxSize <= 40 {
 		return errors.New("MaxBatchSize too small")
 	}
+	if c.CompressionCodec != compressionCodecBrotli && c.CompressionCodec != compressionCodecZstd {
+		return fmt.Errorf("invalid compression codec \"%v\" (see --help for options)", c.CompressionCodec)
+	}
+	if err := c.FeePolicy.Validate(); err != nil {
+		return err
+	}
+	if err := c.CatchUp.Validate(); err != nil {
+		return err
+	}
+	if err := c.ExternalDA.Validate(); err != nil {
+		return err
+	}
+	if err := c.Shadow.Validate(); err != nil {
+		return err
+	}
 	if c.L1BlockBound == "" {
 		c.l1BlockBound = l1BlockBoundDefault
 	} else if c.L1BlockBound == "safe" {
@@ -221,11 +248,15 @@ func BatchPosterConfigAddOptions(prefix string, f *pflag.FlagSet) {
 	f.Duration(prefix+".poll-interval", DefaultBatchPosterConfig.PollInterval, "how long to wait after no batches are ready to be posted before checking again")
 	f.Duration(prefix+".error-delay", DefaultBatchPosterConfig.ErrorDelay, "how long to delay after error posting batch")
 	f.Int(prefix+".compression-level", DefaultBatchPosterConfig.CompressionLevel, "batch compression level")
+	f.String(prefix+".compression-codec", DefaultBatchPosterConfig.CompressionCodec, fmt.Sprintf("batch compression codec (\"brotli\", or the experimental \"zstd\" which is only used on chains launched with ArbOS version %d or later, and only for blob batches or DA batches without an on chain fallback)", arbstate.ArbosVersion_ZstdBatches))
 	f.Duration(prefix+".das-retention-period", DefaultBatchPosterConfig.DASRetentionPeriod, "In AnyTrust mode, the period which DASes are requested to retain the stored batches.")
 	f.String(prefix+".gas-refunder-address", DefaultBatchPosterConfig.GasRefunderAddress, "The gas refunder contract address (optional)")
 	f.Uint64(prefix+".extra-batch-gas", DefaultBatchPosterConfig.ExtraBatchGas, "use this much more gas than estimation says is necessary to post batches")
 	f.Bool(prefix+".post-4844-blobs", DefaultBatchPosterConfig.Post4844Blobs, "if the parent chain supports 4844 blobs and they're well priced, post EIP-4844 blobs")
 	f.Bool(prefix+".ignore-blob-price", DefaultBatchPosterConfig.IgnoreBlobPrice, "if the parent chain supports 4844 blobs and ignore-blob-price is true, post 4844 blobs even if it's not price efficient")
+	FeePolicyConfigAddOptions(prefix+".fee-policy", f)
+	CatchUpConfigAddOptions(prefix+".catch-up", f)
+	externalda.ClientConfigAddOptions(prefix+".external-da", f)
 	f.String(prefix+".redis-url", DefaultBatchPosterConfig.RedisUrl, "if non-empty, the Redis URL to store queued transactions in")
 	f.String(prefix+".l1-block-bound", DefaultBatchPosterConfig.L1BlockBound, "only post messages to batches when they're within the max future block/timestamp as of this L1 block tag (\"safe\", \"finalized\", \"latest\", or \"ignore\" to ignore this check)")
 	f.Duration(prefix+".l1-block-bound-bypass", DefaultBatchPosterConfig.L1BlockBoundBypass, "post batches even if not within the layer 1 future bounds if we're within this margin of the max delay")
@@ -239,6 +270,7 @@ func BatchPosterConfigAddOptions(prefix string, f *pflag.FlagSet) {
 	dataposter.DataPosterConfigAddOptions(prefix+".data-poster", f, dataposter.DefaultDataPosterConfig)
 	genericconf.WalletConfigAddOptions(prefix+".parent-chain-wallet", f, DefaultBatchPosterConfig.ParentChainWallet.Pathname)
 	DangerousBatchPosterConfigAddOptions(prefix+".dangerous", f)
+	BatchPosterShadowConfigAddOptions(prefix+".shadow", f)
 }
 
 var DefaultBatchPosterConfig = BatchPosterConfig{
@@ -255,11 +287,15 @@ var DefaultBatchPosterConfig = BatchPosterConfig{
 	MaxDelay:                       time.Hour,
 	WaitForMaxDelay:                false,
 	CompressionLevel:               brotli.BestCompression,
+	CompressionCodec:               compressionCodecBrotli,
 	DASRetentionPeriod:             daprovider.DefaultDASRetentionPeriod,
 	GasRefunderAddress:             "",
 	ExtraBatchGas:                  50_000,
 	Post4844Blobs:                  false,
 	IgnoreBlobPrice:                false,
+	FeePolicy:                      DefaultFeePolicyConfig,
+	CatchUp:                        DefaultCatchUpConfig,
+	ExternalDA:                     externalda.DefaultClientConfig,
 	DataPoster:                     dataposter.DefaultDataPosterConfig,
 	ParentChainWallet:              DefaultBatchPosterL1WalletConfig,
 	L1BlockBound:                   "",
@@ -271,6 +307,7 @@ var DefaultBatchPosterConfig = BatchPosterConfig{
 	CheckBatchCorrectness:          true,
 	MaxEmptyBatchDelay:             3 * 24 * time.Hour,
 	DelayBufferThresholdMargin:     25, // 5 minutes considering 12-second blocks
+	Shadow:                         DefaultBatchPosterShadowConfig,
 }
 
 var DefaultBatchPosterL1WalletConfig = genericconf.WalletConfig{
@@ -290,11 +327,15 @@ var TestBatchPosterConfig = BatchPosterConfig{
 	MaxDelay:                       0,
 	WaitForMaxDelay:                false,
 	CompressionLevel:               2,
+	CompressionCodec:               compressionCodecBrotli,
 	DASRetentionPeriod:             daprovider.DefaultDASRetentionPeriod,
 	GasRefunderAddress:             "",
 	ExtraBatchGas:                  10_000,
 	Post4844Blobs:                  false,
 	IgnoreBlobPrice:                false,
+	FeePolicy:                      DefaultFeePolicyConfig,
+	CatchUp:                        DefaultCatchUpConfig,
+	ExternalDA:                     externalda.DefaultClientConfig,
 	DataPoster:                     dataposter.TestDataPosterConfig,
 	ParentChainWallet:              DefaultBatchPosterL1WalletConfig,
 	L1BlockBound:                   "",
@@ -333,9 +374,14 @@ func NewBatchPoster(ctx context.Context, opts *BatchPosterOpts) (*BatchPoster, e
 	if err != nil {
 		return nil, err
 	}
-	redisClient, err := redisutil.RedisClientFromURL(opts.Config().RedisUrl)
-	if err != nil {
-		return nil, err
+	shadowMode := opts.Config().Shadow.Enable
+	var redisClient redis.UniversalClient
+	if !shadowMode {
+		// A shadow batch poster must never hold the lock of the real batch posters
+		redisClient, err = redisutil.RedisClientFromURL(opts.Config().RedisUrl)
+		if err != nil {
+			return nil, err
+		}
 	}
 	redisLockConfigFetcher := func() *redislock.SimpleCfg {
 		simpleRedisLockConfig := opts.Config().RedisLock
@@ -367,26 +413,42 @@ func NewBatchPoster(ctx context.Context, opts *BatchPosterOpts) (*BatchPoster, e
 	if err != nil {
 		return nil, err
 	}
-	dataPosterConfigFetcher := func() *dataposter.DataPosterConfig {
-		dpCfg := opts.Config().DataPoster
-		dpCfg.Post4844Blobs = opts.Config().Post4844Blobs
-		return &dpCfg
-	}
-	b.dataPoster, err = dataposter.NewDataPoster(ctx,
-		&dataposter.DataPosterOpts{
-			Database:          opts.DataPosterDB,
-			HeaderReader:      opts.L1Reader,
-			Auth:              opts.TransactOpts,
-			RedisClient:       redisClient,
-			Config:            dataPosterConfigFetcher,
-			MetadataRetriever: b.getBatchPosterPosition,
-			ExtraBacklog:      b.GetBacklogEstimate,
-			RedisKey:          "data-poster.queue",
-			ParentChainID:     opts.ParentChainID,
-		})
+	feeHistoryBlocks := opts.Config().FeePolicy.HistoryBlocks
+	if feeHistoryBlocks <= 0 {
+		feeHistoryBlocks = DefaultFeePolicyConfig.HistoryBlocks
+	}
+	b.feeForecaster, err = newL1FeeForecaster(feeHistoryBlocks)
 	if err != nil {
 		return nil, err
 	}
+	if shadowMode {
+		b.shadow, err = newShadowBatchPoster(&opts.Config().Shadow, b)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		dataPosterConfigFetcher := func() *dataposter.DataPosterConfig {
+			dpCfg := opts.Config().DataPoster
+			dpCfg.Post4844Blobs = opts.Config().Post4844Blobs
+			return &dpCfg
+		}
+		b.dataPoster, err = dataposter.NewDataPoster(ctx,
+			&dataposter.DataPosterOpts{
+				Database:          opts.DataPosterDB,
+				HeaderReader:      opts.L1Reader,
+				Auth:              opts.TransactOpts,
+				RedisClient:       redisClient,
+				Config:            dataPosterConfigFetcher,
+				MetadataRetriever: b.getBatchPosterPosition,
+				ExtraBacklog:      b.GetBacklogEstimate,
+				RedisKey:          "data-poster.queue",
+				ParentChainID:     opts.ParentChainID,
+			})
+		if err != nil {
+			return nil, err
+		}
+		b.catchUp = newCatchUpPipeline(opts.Config().CatchUp.MaxParallel, b.prepareCatchUpBatch, b.postCatchUpBatch, func() time.Duration { return b.config().PollInterval })
+	}
 	// Dataposter sender may be external signer address, so we should initialize
 	// access list after initializing dataposter.
 	b.accessList = func(SequencerInboxAccs, AfterDelayedMessagesRead uint64) types.AccessList {
@@ -397,7 +459,7 @@ func NewBatchPoster(ctx context.Context, opts *BatchPosterOpts) (*BatchPoster, e
 		}
 		return AccessList(&AccessListOpts{
 			SequencerInboxAddr:       opts.DeployInfo.SequencerInbox,
-			DataPosterAddr:           b.dataPoster.Sender(),
+			DataPosterAddr:           b.sender(),
 			BridgeAddr:               opts.DeployInfo.Bridge,
 			GasRefunderAddr:          opts.Config().gasRefunder,
 			SequencerInboxAccs:       SequencerInboxAccs,
@@ -576,7 +638,7 @@ func (b *BatchPoster) ParentChainIsUsingEIP7623(ctx context.Context, latestHeade
 	rpcClient := b.l1Reader.Client()
 	config := b.config()
 	maxFeePerGas := arbmath.BigMulByUBips(latestHeader.BaseFee, config.GasEstimateBaseFeeMultipleBips)
-	to := b.dataPoster.Sender()
+	to := b.sender()
 
 	data := []byte{}
 	for i := 0; i < 100_000; i++ {
@@ -584,7 +646,7 @@ func (b *BatchPoster) ParentChainIsUsingEIP7623(ctx context.Context, latestHeade
 	}
 
 	gas1, err := estimateGas(rpcClient.Client(), ctx, estimateGasParams{
-		From:         b.dataPoster.Sender(),
+		From:         b.sender(),
 		To:           &to,
 		Data:         data,
 		MaxFeePerGas: (*hexutil.Big)(maxFeePerGas),
@@ -595,7 +657,7 @@ func (b *BatchPoster) ParentChainIsUsingEIP7623(ctx context.Context, latestHeade
 
 	data = append(data, 1)
 	gas2, err := estimateGas(rpcClient.Client(), ctx, estimateGasParams{
-		From:         b.dataPoster.Sender(),
+		From:         b.sender(),
 		To:           &to,
 		Data:         data,
 		MaxFeePerGas: (*hexutil.Big)(maxFeePerGas),
@@ -710,6 +772,7 @@ func (b *BatchPoster) pollForL1PriceData(ctx context.Context) {
 			}
 			baseFeeGauge.Update(h.BaseFee.Int64())
 			l1GasPrice := h.BaseFee.Uint64()
+			fees := parentChainFees{baseFee: h.BaseFee.Uint64()}
 			if b.config().Post4844Blobs && h.BlobGasUsed != nil {
 				if h.ExcessBlobGas != nil {
 					blobFeePerByte, err := b.parentChain.BlobFeePerByte(ctx, h)
@@ -720,6 +783,7 @@ func (b *BatchPoster) pollForL1PriceData(ctx context.Context) {
 					blobFeePerByte.Mul(blobFeePerByte, blobTxBlobGasPerBlob)
 					blobFeePerByte.Div(blobFeePerByte, usableBytesInBlob)
 					blobFeeGauge.Update(blobFeePerByte.Int64())
+					fees.blobFeePerByte = blobFeePerByte.Uint64()
 					if l1GasPrice > blobFeePerByte.Uint64()/16 {
 						l1GasPrice = blobFeePerByte.Uint64() / 16
 					}
@@ -727,6 +791,7 @@ func (b *BatchPoster) pollForL1PriceData(ctx context.Context) {
 				// #nosec G115
 				blobGasUsedGauge.Update(int64(*h.BlobGasUsed))
 			}
+			b.feeForecaster.update(fees)
 			// #nosec G115
 			blockGasUsedGauge.Update(int64(h.GasUsed))
 			// #nosec G115
@@ -820,9 +885,22 @@ func (b *BatchPoster) getBatchPosterPosition(ctx context.Context, blockNum *big.
 
 var errBatchAlreadyClosed = errors.New("batch segments already closed")
 
+const (
+	compressionCodecBrotli = "brotli"
+	compressionCodecZstd   = "zstd"
+)
+
+// batchCompressor is the streaming compressor a batch is compressed with while it's being built.
+type batchCompressor interface {
+	io.Writer
+	Flush() error
+	Close() error
+}
+
 type batchSegments struct {
 	compressedBuffer      *bytes.Buffer
-	compressedWriter      *brotli.Writer
+	compressedWriter      batchCompressor
+	useZstd               bool
 	rawSegments           [][]byte
 	timestamp             uint64
 	blockNum              uint64
@@ -846,6 +924,28 @@ type buildingBatch struct {
 	firstDelayedMsg    *arbostypes.MessageWithMetadata
 	firstNonDelayedMsg *arbostypes.MessageWithMetadata
 	firstUsefulMsg     *arbostypes.MessageWithMetadata
+
+	// Only used by the fee policy
+	calldataFeePerByteMultiplier uint64
+	feeCosts                     feePolicyCostTracker
+}
+
+// useZstd returns true if a batch should be compressed with zstd rather than brotli.
+// The sequencer inbox only accepts calldata batches with header bytes it knows,
+// so zstd is only used for batches that can't end up in calldata.
+func (b *BatchPoster) useZstd(use4844 bool) bool {
+	config := b.config()
+	if config.CompressionCodec != compressionCodecZstd || !arbstate.ZstdBatchesEnabled(b.streamer.chainConfig) {
+		return false
+	}
+	return use4844 || (b.dapWriter != nil && config.DisableDapFallbackStoreDataOnChain)
+}
+
+func (s *batchSegments) newCompressedWriter(level int) (batchCompressor, error) {
+	if s.useZstd {
+		return arbcompress.NewZstdWriter(s.compressedBuffer, level)
+	}
+	return brotli.NewWriterLevel(s.compressedBuffer, level), nil
 }
 
 func (b *BatchPoster) newBatchSegments(ctx context.Context, firstDelayed uint64, use4844 bool) (*batchSegments, error) {
@@ -889,19 +989,29 @@ func (b *BatchPoster) newBatchSegments(ctx context.Context, firstDelayed uint64,
 		)
 		recompressionLevel = compressionLevel
 	}
-	return &batchSegments{
+	segments := &batchSegments{
 		compressedBuffer:   compressedBuffer,
-		compressedWriter:   brotli.NewWriterLevel(compressedBuffer, compressionLevel),
+		useZstd:            b.useZstd(use4844),
 		sizeLimit:          maxSize,
 		recompressionLevel: recompressionLevel,
 		rawSegments:        make([][]byte, 0, 128),
 		delayedMsg:         firstDelayed,
-	}, nil
+	}
+	var err error
+	segments.compressedWriter, err = segments.newCompressedWriter(compressionLevel)
+	if err != nil {
+		return nil, err
+	}
+	return segments, nil
 }
 
 func (s *batchSegments) recompressAll() error {
 	s.compressedBuffer = bytes.NewBuffer(make([]byte, 0, s.sizeLimit*2))
-	s.compressedWriter = brotli.NewWriterLevel(s.compressedBuffer, s.recompressionLevel)
+	var err error
+	s.compressedWriter, err = s.newCompressedWriter(s.recompressionLevel)
+	if err != nil {
+		return err
+	}
 	s.newUncompressedSize = 0
 	s.totalUncompressedSize = 0
 	for _, segment := range s.rawSegments {
@@ -1093,6 +1203,9 @@ func (s *batchSegments) CloseAndGetBytes() ([]byte, error) {
 	compressedBytes := s.compressedBuffer.Bytes()
 	fullMsg := make([]byte, 1, len(compressedBytes)+1)
 	fullMsg[0] = daprovider.BrotliMessageHeaderByte
+	if s.useZstd {
+		fullMsg[0] = daprovider.ZstdMessageHeaderByte
+	}
 	fullMsg = append(fullMsg, compressedBytes...)
 	return fullMsg, nil
 }
@@ -1182,8 +1295,9 @@ func (b *BatchPoster) estimateGas(
 	config := b.config()
 	rpcClient := b.l1Reader.Client()
 	rawRpcClient := rpcClient.Client()
-	useNormalEstimation := b.dataPoster.MaxMempoolTransactions() == 1
-	if !useNormalEstimation {
+	// The shadow batch poster's position is usually not the next one on chain, so it always uses the future tx estimate
+	useNormalEstimation := b.shadow == nil && b.dataPoster.MaxMempoolTransactions() == 1
+	if !useNormalEstimation && b.shadow == nil {
 		// Check if we can use normal estimation anyways because we're at the latest nonce
 		latestNonce, err := rpcClient.NonceAt(ctx, b.dataPoster.Sender(), nil)
 		if err != nil {
@@ -1203,7 +1317,7 @@ func (b *BatchPoster) estimateGas(
 		}
 		// If we're at the latest nonce, we can skip the special future tx estimate stuff
 		gas, err := estimateGas(rawRpcClient, ctx, estimateGasParams{
-			From:         b.dataPoster.Sender(),
+			From:         b.sender(),
 			To:           &b.seqInboxAddr,
 			Data:         realData,
 			MaxFeePerGas: (*hexutil.Big)(maxFeePerGas),
@@ -1229,7 +1343,7 @@ func (b *BatchPoster) estimateGas(
 		return 0, fmt.Errorf("failed to compute blob commitments: %w", err)
 	}
 	gas, err := estimateGas(rawRpcClient, ctx, estimateGasParams{
-		From:         b.dataPoster.Sender(),
+		From:         b.sender(),
 		To:           &b.seqInboxAddr,
 		Data:         data,
 		MaxFeePerGas: (*hexutil.Big)(maxFeePerGas),
@@ -1263,7 +1377,22 @@ func (b *BatchPoster) MaybePostSequencerBatch(ctx context.Context) (bool, error)
 	if b.batchReverted.Load() {
 		return false, fmt.Errorf("batch was reverted, not posting any more batches")
 	}
-	nonce, batchPositionBytes, err := b.dataPoster.GetNextNonceAndMeta(ctx)
+	var nonce uint64
+	var batchPositionBytes []byte
+	var err error
+	if b.shadow != nil {
+		nonce, batchPositionBytes, err = b.shadow.nextPosition(ctx)
+	} else {
+		if err := b.catchUp.takeFailure(); err != nil {
+			return false, err
+		}
+		// In catch-up mode, the next batch is built on top of the last queued batch rather than the last posted one
+		var queuedAhead bool
+		nonce, batchPositionBytes, queuedAhead, err = b.catchUp.nextPosition()
+		if err == nil && !queuedAhead {
+			nonce, batchPositionBytes, err = b.dataPoster.GetNextNonceAndMeta(ctx)
+		}
+	}
 	if err != nil {
 		return false, err
 	}
@@ -1285,6 +1414,8 @@ func (b *BatchPoster) MaybePostSequencerBatch(ctx context.Context) (bool, error)
 			return false, err
 		}
 		var use4844 bool
+		// See the comment on EIP-7623 below
+		calldataFeePerByteMultiplier := uint64(16)
 		config := b.config()
 		if config.Post4844Blobs && b.dapWriter == nil && latestHeader.ExcessBlobGas != nil && latestHeader.BlobGasUsed != nil {
 			arbOSVersion, err := b.arbOSVersionGetter.ArbOSVersionForMessageIndex(arbutil.MessageIndex(arbmath.SaturatingUSub(uint64(batchPosition.MessageCount), 1)))
@@ -1325,7 +1456,6 @@ func (b *BatchPoster) MaybePostSequencerBatch(ctx context.Context) (bool, error)
 						// in which calldata is also composed only of non-zero bytes,
 						// and that (TOTAL_COST_FLOOR_PER_TOKEN * tokens_in_calldata > STANDARD_TOKEN_COST * tokens_in_calldata + execution_gas_used),
 						// each calldata byte will consume TOTAL_COST_FLOOR_PER_TOKEN * 4, which is 40 gas.
-						calldataFeePerByteMultiplier := uint64(16)
 						parentChainIsUsingEIP7623, err := b.ParentChainIsUsingEIP7623(ctx, latestHeader)
 						if err != nil {
 							log.Error("ParentChainIsUsingEIP7623 failed", "err", err)
@@ -1334,6 +1464,14 @@ func (b *BatchPoster) MaybePostSequencerBatch(ctx context.Context) (bool, error)
 						}
 
 						calldataFeePerByte := arbmath.BigMulByUint(latestHeader.BaseFee, calldataFeePerByteMultiplier)
+						if config.FeePolicy.Enable && b.feeForecaster.ready() {
+							// Compare the fees forecast for when the batch will be posted instead of the latest block's
+							_, forecast := b.feeForecaster.current()
+							if forecast.blobFeePerByte != 0 {
+								blobFeePerByte = arbmath.UintToBig(forecast.feePerByte(calldataFeePerByteMultiplier, true))
+								calldataFeePerByte = arbmath.UintToBig(forecast.feePerByte(calldataFeePerByteMultiplier, false))
+							}
+						}
 						use4844 = arbmath.BigLessThan(blobFeePerByte, calldataFeePerByte)
 					}
 				}
@@ -1345,10 +1483,11 @@ func (b *BatchPoster) MaybePostSequencerBatch(ctx context.Context) (bool, error)
 			return false, err
 		}
 		b.building = &buildingBatch{
-			segments:      segments,
-			msgCount:      batchPosition.MessageCount,
-			startMsgCount: batchPosition.MessageCount,
-			use4844:       use4844,
+			segments:                     segments,
+			msgCount:                     batchPosition.MessageCount,
+			startMsgCount:                batchPosition.MessageCount,
+			use4844:                      use4844,
+			calldataFeePerByteMultiplier: calldataFeePerByteMultiplier,
 		}
 		if b.config().CheckBatchCorrectness {
 			b.building.muxBackend = &simulatedMuxBackend{
@@ -1373,6 +1512,7 @@ func (b *BatchPoster) MaybePostSequencerBatch(ctx context.Context) (bool, error)
 
 	config := b.config()
 	forcePostBatch := config.MaxDelay <= 0
+	batchFull := false
 
 	var l1BoundMaxBlockNumber uint64 = math.MaxUint64
 	var l1BoundMaxTimestamp uint64 = math.MaxUint64
@@ -1481,7 +1621,7 @@ func (b *BatchPoster) MaybePostSequencerBatch(ctx context.Context) (bool, error)
 		if !success {
 			// this batch is full
 			if !config.WaitForMaxDelay {
-				forcePostBatch = true
+				batchFull = true
 			}
 			b.building.haveUsefulMessage = true
 			if b.building.firstUsefulMsg == nil {
@@ -1563,6 +1703,12 @@ func (b *BatchPoster) MaybePostSequencerBatch(ctx context.Context) (bool, error)
 		}
 	}
 
+	if config.FeePolicy.Enable && b.building.haveUsefulMessage {
+		forcePostBatch = b.applyFeePolicy(&config.FeePolicy, time.Since(firstUsefulMsgTime), forcePostBatch, batchFull)
+	} else if batchFull {
+		forcePostBatch = true
+	}
+
 	if !forcePostBatch || !b.building.haveUsefulMessage {
 		// the batch isn't full yet and we've posted a batch recently
 		// don't post anything for now
@@ -1579,7 +1725,32 @@ func (b *BatchPoster) MaybePostSequencerBatch(ctx context.Context) (bool, error)
 		return false, nil
 	}
 
-	if b.dapWriter != nil {
+	batch := &builtBatch{
+		nonce:                   nonce,
+		position:                batchPosition,
+		positionBytes:           batchPositionBytes,
+		newPosition:             batchPosterPosition{MessageCount: b.building.msgCount, DelayedMessageCount: b.building.segments.delayedMsg, NextSeqNum: batchPosition.NextSeqNum + 1},
+		sequencerMsg:            sequencerMsg,
+		use4844:                 b.building.use4844,
+		firstDelayedMsg:         b.building.firstDelayedMsg,
+		delayBuffer:             delayBuffer,
+		estimateDelayedMessages: lastPotentialMsg.DelayedMessagesRead,
+		firstUsefulMsgTime:      firstUsefulMsgTime,
+		numSegments:             len(b.building.segments.rawSegments),
+	}
+	l1Bounds := batchL1Bounds{
+		minTimestamp:   l1BoundMinTimestamp,
+		maxTimestamp:   l1BoundMaxTimestamp,
+		minBlockNumber: l1BoundMinBlockNumber,
+		maxBlockNumber: l1BoundMaxBlockNumber,
+	}
+
+	if b.catchUpActive() {
+		return b.queueCatchUpBatch(ctx, batch, l1Bounds, msgCount)
+	}
+
+	if b.dapWriter != nil && b.shadow == nil {
+		// In shadow mode, the batch isn't stored with the DA provider, and is reported as if it was posted on chain
 		if !b.redisLock.AttemptLock(ctx) {
 			return false, errAttemptLockFailed
 		}
@@ -1593,19 +1764,101 @@ func (b *BatchPoster) MaybePostSequencerBatch(ctx context.Context) (bool, error)
 			batchPosterDAFailureCounter.Inc(1)
 			return false, fmt.Errorf("%w: nonce changed from %d to %d while creating batch", storage.ErrStorageRace, nonce, gotNonce)
 		}
-		// #nosec G115
-		sequencerMsg, err = b.dapWriter.Store(ctx, sequencerMsg, uint64(time.Now().Add(config.DASRetentionPeriod).Unix()), config.DisableDapFallbackStoreDataOnChain)
-		if err != nil {
-			batchPosterDAFailureCounter.Inc(1)
+		if err := b.storeBatchWithDAProvider(ctx, batch); err != nil {
 			return false, err
 		}
+	}
+
+	batchTx, err := b.encodeBatchTx(ctx, batch)
+	if err != nil {
+		return false, err
+	}
 
-		batchPosterDASuccessCounter.Inc(1)
-		batchPosterDALastSuccessfulActionGauge.Update(time.Now().Unix())
+	if config.CheckBatchCorrectness {
+		if err := b.checkBatchCorrectness(ctx, batch, b.building.muxBackend, batchTx.kzgBlobs, l1Bounds); err != nil {
+			b.building = nil
+			return false, err
+		}
 	}
 
-	prevMessageCount := batchPosition.MessageCount
-	if b.config().Dangerous.AllowPostingFirstBatchWhenSequencerMessageCountMismatch && !b.postedFirstBatch {
+	var tx *types.Transaction
+	if b.shadow != nil {
+		err = b.shadow.post(ctx, batch, batchTx, b.building.segments.totalUncompressedSize, config.CompressionLevel)
+	} else {
+		tx, err = b.postBatchTx(ctx, batch, batchTx)
+	}
+	if err != nil {
+		return false, err
+	}
+	if config.FeePolicy.Enable {
+		b.recordFeePolicyCost(len(batch.sequencerMsg))
+	}
+	b.updateBacklogEstimate(batchPosition, msgCount)
+	b.building = nil
+
+	// If we aren't queueing up transactions, wait for the receipt before moving on to the next batch.
+	if config.DataPoster.UseNoOpStorage && tx != nil {
+		receipt, err := b.l1Reader.WaitForTxApproval(ctx, tx)
+		if err != nil {
+			return false, fmt.Errorf("error waiting for tx receipt: %w", err)
+		}
+		log.Info("Got successful receipt from batch poster transaction", "txHash", tx.Hash(), "blockNumber", receipt.BlockNumber, "blockHash", receipt.BlockHash)
+	}
+
+	return true, nil
+}
+
+// builtBatch is a closed batch, with everything needed to post it.
+type builtBatch struct {
+	nonce         uint64
+	position      batchPosterPosition
+	positionBytes []byte
+	newPosition   batchPosterPosition
+	// The compressed batch, replaced by the DA certificate once stored with a DA provider
+	sequencerMsg            []byte
+	use4844                 bool
+	firstDelayedMsg         *arbostypes.MessageWithMetadata
+	delayBuffer             *DelayBufferConfig
+	estimateDelayedMessages uint64
+	firstUsefulMsgTime      time.Time
+	numSegments             int
+}
+
+// batchTx is the parent chain transaction posting a built batch.
+type batchTx struct {
+	data       []byte
+	kzgBlobs   []kzg4844.Blob
+	accessList types.AccessList
+	gasLimit   uint64
+}
+
+// batchL1Bounds are the parent chain bounds of a batch's sequencer message header.
+type batchL1Bounds struct {
+	minTimestamp   uint64
+	maxTimestamp   uint64
+	minBlockNumber uint64
+	maxBlockNumber uint64
+}
+
+func (b *BatchPoster) storeBatchWithDAProvider(ctx context.Context, batch *builtBatch) error {
+	config := b.config()
+	// #nosec G115
+	sequencerMsg, err := b.dapWriter.Store(ctx, batch.sequencerMsg, uint64(time.Now().Add(config.DASRetentionPeriod).Unix()), config.DisableDapFallbackStoreDataOnChain)
+	if err != nil {
+		batchPosterDAFailureCounter.Inc(1)
+		return err
+	}
+	batch.sequencerMsg = sequencerMsg
+
+	batchPosterDASuccessCounter.Inc(1)
+	batchPosterDALastSuccessfulActionGauge.Update(time.Now().Unix())
+	return nil
+}
+
+// encodeBatchTx encodes the transaction posting a batch, and estimates its gas.
+func (b *BatchPoster) encodeBatchTx(ctx context.Context, batch *builtBatch) (*batchTx, error) {
+	prevMessageCount := batch.position.MessageCount
+	if b.config().Dangerous.AllowPostingFirstBatchWhenSequencerMessageCountMismatch && !b.postedFirstBatch.Load() {
 		// AllowPostingFirstBatchWhenSequencerMessageCountMismatch can be used when the
 		// message count stored in batch poster's database gets out
 		// of sync with the sequencerReportedSubMessageCount stored in the parent chain.
@@ -1637,31 +1890,31 @@ func (b *BatchPoster) MaybePostSequencerBatch(ctx context.Context) (bool, error)
 	var delayProof *bridgegen.DelayProof
 	latestHeader, err := b.l1Reader.LastHeader(ctx)
 	if err != nil {
-		return false, err
+		return nil, err
 	}
-	if delayBuffer.Enabled && b.building.firstDelayedMsg != nil && delayBuffer.isUpdatable(latestHeader.Number.Uint64()) {
-		delayProof, err = GenDelayProof(ctx, b.building.firstDelayedMsg, b.inbox)
+	if batch.delayBuffer.Enabled && batch.firstDelayedMsg != nil && batch.delayBuffer.isUpdatable(latestHeader.Number.Uint64()) {
+		delayProof, err = GenDelayProof(ctx, batch.firstDelayedMsg, b.inbox)
 		if err != nil {
-			return false, fmt.Errorf("failed to generate delay proof: %w", err)
+			return nil, fmt.Errorf("failed to generate delay proof: %w", err)
 		}
 	}
 
-	data, kzgBlobs, err := b.encodeAddBatch(new(big.Int).SetUint64(batchPosition.NextSeqNum), prevMessageCount, b.building.msgCount, sequencerMsg, b.building.segments.delayedMsg, b.building.use4844, delayProof)
+	data, kzgBlobs, err := b.encodeAddBatch(new(big.Int).SetUint64(batch.position.NextSeqNum), prevMessageCount, batch.newPosition.MessageCount, batch.sequencerMsg, batch.newPosition.DelayedMessageCount, batch.use4844, delayProof)
 	if err != nil {
-		return false, err
+		return nil, err
 	}
 	if len(kzgBlobs) > 0 {
 		maxBlobGasPerBlock, err := b.parentChain.MaxBlobGasPerBlock(ctx, latestHeader)
 		if err != nil {
-			return false, err
+			return nil, err
 		}
 		// #nosec G115
 		if len(kzgBlobs)*params.BlobTxBlobGasPerBlob > int(maxBlobGasPerBlock) {
 			// #nosec G115
-			return false, fmt.Errorf("produced %v blobs for batch but a block can only hold %v (compressed batch was %v bytes long)", len(kzgBlobs), int(maxBlobGasPerBlock)/params.BlobTxBlobGasPerBlob, len(sequencerMsg))
+			return nil, fmt.Errorf("produced %v blobs for batch but a block can only hold %v (compressed batch was %v bytes long)", len(kzgBlobs), int(maxBlobGasPerBlock)/params.BlobTxBlobGasPerBlob, len(batch.sequencerMsg))
 		}
 	}
-	accessList := b.accessList(batchPosition.NextSeqNum, b.building.segments.delayedMsg)
+	accessList := b.accessList(batch.position.NextSeqNum, batch.newPosition.DelayedMessageCount)
 	// On restart, we may be trying to estimate gas for a batch whose successor has
 	// already made it into pending state, if not latest state.
 	// In that case, we might get a revert with `DelayedBackwards()`.
@@ -1669,78 +1922,93 @@ func (b *BatchPoster) MaybePostSequencerBatch(ctx context.Context) (bool, error)
 	// In theory, this might reduce gas usage, but only by a factor that's already
 	// accounted for in `config.ExtraBatchGas`, as that same factor can appear if a user
 	// posts a new delayed message that we didn't see while gas estimating.
-	gasLimit, err := b.estimateGas(ctx, sequencerMsg, lastPotentialMsg.DelayedMessagesRead, data, kzgBlobs, nonce, accessList, delayProof)
-	if err != nil {
-		return false, err
-	}
-	newMeta, err := rlp.EncodeToBytes(batchPosterPosition{
-		MessageCount:        b.building.msgCount,
-		DelayedMessageCount: b.building.segments.delayedMsg,
-		NextSeqNum:          batchPosition.NextSeqNum + 1,
-	})
-	if err != nil {
-		return false, err
+	var gasLimit uint64
+	if b.shadow == nil || b.shadow.sender != (common.Address{}) {
+		gasLimit, err = b.estimateGas(ctx, batch.sequencerMsg, batch.estimateDelayedMessages, data, kzgBlobs, batch.nonce, accessList, delayProof)
+		if err != nil {
+			return nil, err
+		}
 	}
+	return &batchTx{
+		data:       data,
+		kzgBlobs:   kzgBlobs,
+		accessList: accessList,
+		gasLimit:   gasLimit,
+	}, nil
+}
 
-	if config.CheckBatchCorrectness {
-		dapReaders := b.dapReaders
-		if b.building.use4844 {
-			dapReaders = append(dapReaders, daprovider.NewReaderForBlobReader(&simulatedBlobReader{kzgBlobs}))
-		}
-		seqMsg := binary.BigEndian.AppendUint64([]byte{}, l1BoundMinTimestamp)
-		seqMsg = binary.BigEndian.AppendUint64(seqMsg, l1BoundMaxTimestamp)
-		seqMsg = binary.BigEndian.AppendUint64(seqMsg, l1BoundMinBlockNumber)
-		seqMsg = binary.BigEndian.AppendUint64(seqMsg, l1BoundMaxBlockNumber)
-		seqMsg = binary.BigEndian.AppendUint64(seqMsg, b.building.segments.delayedMsg)
-		seqMsg = append(seqMsg, sequencerMsg...)
-		b.building.muxBackend.seqMsg = seqMsg
-		b.building.muxBackend.delayedInboxStart = batchPosition.DelayedMessageCount
-		b.building.muxBackend.SetPositionWithinMessage(0)
-		simMux := arbstate.NewInboxMultiplexer(b.building.muxBackend, batchPosition.DelayedMessageCount, dapReaders, daprovider.KeysetValidate)
-		log.Debug("Begin checking the correctness of batch against inbox multiplexer", "startMsgSeqNum", batchPosition.MessageCount, "endMsgSeqNum", b.building.msgCount-1)
-		for i := batchPosition.MessageCount; i < b.building.msgCount; i++ {
-			msg, err := simMux.Pop(ctx)
-			if err != nil {
-				return false, fmt.Errorf("error getting message from simulated inbox multiplexer (Pop) when testing correctness of batch: %w", err)
-			}
-			if msg.DelayedMessagesRead != b.building.muxBackend.allMsgs[i].DelayedMessagesRead {
-				b.building = nil
-				return false, fmt.Errorf("simulated inbox multiplexer failed to produce correct delayedMessagesRead field for msg with seqNum: %d. Got: %d, Want: %d", i, msg.DelayedMessagesRead, b.building.muxBackend.allMsgs[i].DelayedMessagesRead)
-			}
-			if !msg.Message.Equals(b.building.muxBackend.allMsgs[i].Message) {
-				b.building = nil
-				return false, fmt.Errorf("simulated inbox multiplexer failed to produce correct message field for msg with seqNum: %d", i)
-			}
+// checkBatchCorrectness runs the batch through an inbox multiplexer,
+// and checks that it produces the messages of the simulated inbox it was built with.
+func (b *BatchPoster) checkBatchCorrectness(ctx context.Context, batch *builtBatch, muxBackend *simulatedMuxBackend, kzgBlobs []kzg4844.Blob, l1Bounds batchL1Bounds) error {
+	batchPosition := batch.position
+	// Batches are checked in parallel in catch-up mode, so the shared readers mustn't be appended to in place
+	dapReaders := b.dapReaders[:len(b.dapReaders):len(b.dapReaders)]
+	if batch.use4844 {
+		dapReaders = append(dapReaders, daprovider.NewReaderForBlobReader(&simulatedBlobReader{kzgBlobs}))
+	}
+	seqMsg := binary.BigEndian.AppendUint64([]byte{}, l1Bounds.minTimestamp)
+	seqMsg = binary.BigEndian.AppendUint64(seqMsg, l1Bounds.maxTimestamp)
+	seqMsg = binary.BigEndian.AppendUint64(seqMsg, l1Bounds.minBlockNumber)
+	seqMsg = binary.BigEndian.AppendUint64(seqMsg, l1Bounds.maxBlockNumber)
+	seqMsg = binary.BigEndian.AppendUint64(seqMsg, batch.newPosition.DelayedMessageCount)
+	seqMsg = append(seqMsg, batch.sequencerMsg...)
+	muxBackend.seqMsg = seqMsg
+	muxBackend.delayedInboxStart = batchPosition.DelayedMessageCount
+	muxBackend.SetPositionWithinMessage(0)
+	simMux := arbstate.NewInboxMultiplexer(muxBackend, batchPosition.DelayedMessageCount, dapReaders, daprovider.KeysetValidate, arbstate.ZstdBatchesEnabled(b.streamer.chainConfig))
+	log.Debug("Begin checking the correctness of batch against inbox multiplexer", "startMsgSeqNum", batchPosition.MessageCount, "endMsgSeqNum", batch.newPosition.MessageCount-1)
+	for i := batchPosition.MessageCount; i < batch.newPosition.MessageCount; i++ {
+		msg, err := simMux.Pop(ctx)
+		if err != nil {
+			return fmt.Errorf("error getting message from simulated inbox multiplexer (Pop) when testing correctness of batch: %w", err)
+		}
+		if msg.DelayedMessagesRead != muxBackend.allMsgs[i].DelayedMessagesRead {
+			return fmt.Errorf("simulated inbox multiplexer failed to produce correct delayedMessagesRead field for msg with seqNum: %d. Got: %d, Want: %d", i, msg.DelayedMessagesRead, muxBackend.allMsgs[i].DelayedMessagesRead)
+		}
+		if !msg.Message.Equals(muxBackend.allMsgs[i].Message) {
+			return fmt.Errorf("simulated inbox multiplexer failed to produce correct message field for msg with seqNum: %d", i)
 		}
-		log.Debug("Successfully checked that the batch produces correct messages when ran through inbox multiplexer", "sequenceNumber", batchPosition.NextSeqNum)
 	}
+	log.Debug("Successfully checked that the batch produces correct messages when ran through inbox multiplexer", "sequenceNumber", batchPosition.NextSeqNum)
+	return nil
+}
 
+func (b *BatchPoster) postBatchTx(ctx context.Context, batch *builtBatch, batchTx *batchTx) (*types.Transaction, error) {
+	newMeta, err := rlp.EncodeToBytes(batch.newPosition)
+	if err != nil {
+		return nil, err
+	}
 	tx, err := b.dataPoster.PostTransaction(ctx,
-		firstUsefulMsgTime,
-		nonce,
+		batch.firstUsefulMsgTime,
+		batch.nonce,
 		newMeta,
 		b.seqInboxAddr,
-		data,
-		gasLimit,
+		batchTx.data,
+		batchTx.gasLimit,
 		new(big.Int),
-		kzgBlobs,
-		accessList,
+		batchTx.kzgBlobs,
+		batchTx.accessList,
 	)
 	if err != nil {
-		return false, err
+		return nil, err
 	}
-	b.postedFirstBatch = true
+	b.postedFirstBatch.Store(true)
 	log.Info(
 		"BatchPoster: batch sent",
-		"sequenceNumber", batchPosition.NextSeqNum,
-		"from", batchPosition.MessageCount,
-		"to", b.building.msgCount,
-		"prevDelayed", batchPosition.DelayedMessageCount,
-		"currentDelayed", b.building.segments.delayedMsg,
-		"totalSegments", len(b.building.segments.rawSegments),
-		"numBlobs", len(kzgBlobs),
+		"sequenceNumber", batch.position.NextSeqNum,
+		"from", batch.position.MessageCount,
+		"to", batch.newPosition.MessageCount,
+		"prevDelayed", batch.position.DelayedMessageCount,
+		"currentDelayed", batch.newPosition.DelayedMessageCount,
+		"totalSegments", batch.numSegments,
+		"numBlobs", len(batchTx.kzgBlobs),
 	)
+	return tx, nil
+}
 
+// updateBacklogEstimate updates the estimated number of batches left to post after the batch being built.
+func (b *BatchPoster) updateBacklogEstimate(batchPosition batchPosterPosition, msgCount arbutil.MessageIndex) {
+	config := b.config()
 	recentlyHitL1Bounds := time.Since(b.lastHitL1Bounds) < config.PollInterval*3
 	postedMessages := b.building.msgCount - batchPosition.MessageCount
 	b.messagesPerBatch.Update(uint64(postedMessages))
@@ -1789,18 +2057,6 @@ func (b *BatchPoster) MaybePostSequencerBatch(ctx context.Context) (bool, error)
 		backlog = 0
 	}
 	b.backlog.Store(backlog)
-	b.building = nil
-
-	// If we aren't queueing up transactions, wait for the receipt before moving on to the next batch.
-	if config.DataPoster.UseNoOpStorage {
-		receipt, err := b.l1Reader.WaitForTxApproval(ctx, tx)
-		if err != nil {
-			return false, fmt.Errorf("error waiting for tx receipt: %w", err)
-		}
-		log.Info("Got successful receipt from batch poster transaction", "txHash", tx.Hash(), "blockNumber", receipt.BlockNumber, "blockHash", receipt.BlockHash)
-	}
-
-	return true, nil
 }
 
 func (b *BatchPoster) GetBacklogEstimate() uint64 {
@@ -1808,10 +2064,15 @@ func (b *BatchPoster) GetBacklogEstimate() uint64 {
 }
 
 func (b *BatchPoster) Start(ctxIn context.Context) {
-	b.dataPoster.Start(ctxIn)
+	if b.dataPoster != nil {
+		b.dataPoster.Start(ctxIn)
+	}
 	b.redisLock.Start(ctxIn)
 	b.StopWaiter.Start(ctxIn, b)
-	b.LaunchThread(b.pollForReverts)
+	if b.shadow == nil {
+		b.LaunchThread(b.pollForReverts)
+		b.LaunchThread(b.catchUp.run)
+	}
 	b.LaunchThread(b.pollForL1PriceData)
 	commonEphemeralErrorHandler := util.NewEphemeralErrorHandler(time.Minute, "", 0)
 	exceedMaxMempoolSizeEphemeralErrorHandler := util.NewEphemeralErrorHandler(5*time.Minute, dataposter.ErrExceedsMaxMempoolSize.Error(), time.Minute)
@@ -1835,8 +2096,8 @@ func (b *BatchPoster) Start(ctxIn context.Context) {
 				batchPosterGasRefunderBalance.Update(arbmath.BalancePerEther(gasRefunderBalance))
 			}
 		}
-		if b.dataPoster.Sender() != (common.Address{}) {
-			walletBalance, err := b.l1Reader.Client().BalanceAt(ctx, b.dataPoster.Sender(), nil)
+		if b.sender() != (common.Address{}) {
+			walletBalance, err := b.l1Reader.Client().BalanceAt(ctx, b.sender(), nil)
 			if err != nil {
 				log.Warn("error fetching batch poster wallet balance", "err", err)
 			} else {
@@ -1889,8 +2150,13 @@ func (b *BatchPoster) Start(ctxIn context.Context) {
 
 func (b *BatchPoster) StopAndWait() {
 	b.StopWaiter.StopAndWait()
-	b.dataPoster.StopAndWait()
+	if b.dataPoster != nil {
+		b.dataPoster.StopAndWait()
+	}
 	b.redisLock.StopAndWait()
+	if b.shadow != nil {
+		b.shadow.close()
+	}
 }
 
 type BoolRing struct {
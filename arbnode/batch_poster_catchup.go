@@ -0,0 +1,314 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+package arbnode
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/offchainlabs/nitro/arbnode/dataposter"
+	"github.com/offchainlabs/nitro/arbutil"
+)
+
+var (
+	catchUpQueuedCounter    = metrics.NewRegisteredCounter("arb/batchposter/catchup/queued", nil)
+	catchUpPostedCounter    = metrics.NewRegisteredCounter("arb/batchposter/catchup/posted", nil)
+	catchUpFailureCounter   = metrics.NewRegisteredCounter("arb/batchposter/catchup/failed", nil)
+	catchUpDiscardedCounter = metrics.NewRegisteredCounter("arb/batchposter/catchup/discarded", nil)
+	catchUpInFlightGauge    = metrics.NewRegisteredGauge("arb/batchposter/catchup/inflight", nil)
+)
+
+type CatchUpConfig struct {
+	// Build batches ahead of the posted ones and post them in parallel while the backlog is large.
+	Enable bool `koanf:"enable" reload:"hot"`
+	// Estimated batch backlog at which catch-up mode kicks in.
+	MinBacklog uint64 `koanf:"min-backlog" reload:"hot"`
+	// Maximum number of batches being stored with the DA provider, encoded or posted at once.
+	MaxParallel int `koanf:"max-parallel"`
+}
+
+var DefaultCatchUpConfig = CatchUpConfig{
+	Enable:      false,
+	MinBacklog:  10,
+	MaxParallel: 4,
+}
+
+func CatchUpConfigAddOptions(prefix string, f *pflag.FlagSet) {
+	f.Bool(prefix+".enable", DefaultCatchUpConfig.Enable, "when far behind, build batches ahead of time and store, encode and post them in parallel (requires data-poster.max-mempool-transactions > 1)")
+	f.Uint64(prefix+".min-backlog", DefaultCatchUpConfig.MinBacklog, "estimated batch backlog at which to start posting batches in parallel")
+	f.Int(prefix+".max-parallel", DefaultCatchUpConfig.MaxParallel, "maximum number of batches to store with the DA provider, encode and post at once")
+}
+
+func (c *CatchUpConfig) Validate() error {
+	if c.MaxParallel < 1 {
+		return errors.New("catch-up.max-parallel must be at least 1")
+	}
+	return nil
+}
+
+// catchUpBatch is a batch queued for posting in catch-up mode.
+type catchUpBatch struct {
+	batch *builtBatch
+	// The simulated inbox and bounds the batch is checked against once stored and encoded, nil if it isn't checked
+	muxBackend *simulatedMuxBackend
+	l1Bounds   batchL1Bounds
+	generation uint64
+	done       chan struct{} // closed once tx or err is set
+	tx         *batchTx
+	err        error
+}
+
+// catchUpPipeline stores and encodes queued batches in parallel, and posts them in nonce order.
+// If a batch fails, the batches queued after it are discarded, and building restarts from the data poster's position.
+type catchUpPipeline struct {
+	slots chan struct{} // bounds the number of batches in flight
+	queue chan *catchUpBatch
+
+	// prepareFn stores a batch with the DA provider if there is one, encodes its transaction and checks it
+	prepareFn func(ctx context.Context, item *catchUpBatch) (*batchTx, error)
+	// postFn posts a prepared batch
+	postFn         func(ctx context.Context, item *catchUpBatch) error
+	pollIntervalFn func() time.Duration
+
+	mutex      sync.Mutex
+	last       *builtBatch // the last queued batch, which the next batch is built on top of
+	inFlight   int
+	generation uint64 // incremented whenever a batch fails, to discard the batches queued after it
+	failure    error  // returned by the next MaybePostSequencerBatch call
+}
+
+func newCatchUpPipeline(
+	maxParallel int,
+	prepareFn func(ctx context.Context, item *catchUpBatch) (*batchTx, error),
+	postFn func(ctx context.Context, item *catchUpBatch) error,
+	pollIntervalFn func() time.Duration,
+) *catchUpPipeline {
+	return &catchUpPipeline{
+		slots:          make(chan struct{}, maxParallel),
+		queue:          make(chan *catchUpBatch, maxParallel),
+		prepareFn:      prepareFn,
+		postFn:         postFn,
+		pollIntervalFn: pollIntervalFn,
+	}
+}
+
+// nextPosition returns the nonce and position of the batch after the last queued batch, if any batch is queued.
+func (p *catchUpPipeline) nextPosition() (uint64, []byte, bool, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if p.last == nil {
+		return 0, nil, false, nil
+	}
+	positionBytes, err := rlp.EncodeToBytes(p.last.newPosition)
+	if err != nil {
+		return 0, nil, false, err
+	}
+	return p.last.nonce + 1, positionBytes, true, nil
+}
+
+// busy returns true while batches are queued, in which case new batches must be queued behind them.
+func (p *catchUpPipeline) busy() bool {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return p.last != nil || p.inFlight > 0
+}
+
+func (p *catchUpPipeline) takeFailure() error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	err := p.failure
+	p.failure = nil
+	return err
+}
+
+func (p *catchUpPipeline) add(batch *builtBatch, muxBackend *simulatedMuxBackend, l1Bounds batchL1Bounds) *catchUpBatch {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.last = batch
+	p.inFlight++
+	catchUpInFlightGauge.Update(int64(p.inFlight))
+	return &catchUpBatch{
+		batch:      batch,
+		muxBackend: muxBackend,
+		l1Bounds:   l1Bounds,
+		generation: p.generation,
+		done:       make(chan struct{}),
+	}
+}
+
+func (p *catchUpPipeline) isCurrent(item *catchUpBatch) bool {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return item.generation == p.generation
+}
+
+func (p *catchUpPipeline) fail(item *catchUpBatch, err error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if item.generation != p.generation {
+		return
+	}
+	p.generation++
+	p.last = nil
+	p.failure = fmt.Errorf("posting batch %v in catch-up mode failed, discarding the batches queued after it: %w", item.batch.position.NextSeqNum, err)
+}
+
+func (p *catchUpPipeline) finish() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.inFlight--
+	if p.inFlight == 0 {
+		// Everything queued was posted, so the data poster's position is up to date again
+		p.last = nil
+	}
+	catchUpInFlightGauge.Update(int64(p.inFlight))
+	<-p.slots
+}
+
+// catchUpActive returns true if the batch being built should be posted through the catch-up pipeline.
+func (b *BatchPoster) catchUpActive() bool {
+	if b.catchUp == nil {
+		return false
+	}
+	if b.catchUp.busy() {
+		return true
+	}
+	config := b.config()
+	return config.CatchUp.Enable &&
+		!config.DataPoster.UseNoOpStorage &&
+		b.dataPoster.MaxMempoolTransactions() > 1 &&
+		b.postedFirstBatch.Load() &&
+		b.GetBacklogEstimate() >= config.CatchUp.MinBacklog
+}
+
+// queueCatchUpBatch hands a built batch to the catch-up pipeline, so the next batch can be built right away.
+func (b *BatchPoster) queueCatchUpBatch(ctx context.Context, batch *builtBatch, l1Bounds batchL1Bounds, msgCount arbutil.MessageIndex) (bool, error) {
+	config := b.config()
+	var muxBackend *simulatedMuxBackend
+	if config.CheckBatchCorrectness {
+		// The batch is checked once it's stored with the DA provider and encoded, which happens in parallel
+		muxBackend = b.building.muxBackend
+	}
+	select {
+	case b.catchUp.slots <- struct{}{}:
+	case <-ctx.Done():
+		return false, ctx.Err()
+	}
+	item := b.catchUp.add(batch, muxBackend, l1Bounds)
+	b.LaunchThread(func(ctx context.Context) {
+		b.catchUp.prepare(ctx, item)
+	})
+	b.catchUp.queue <- item
+	catchUpQueuedCounter.Inc(1)
+	log.Info(
+		"BatchPoster: batch queued in catch-up mode",
+		"sequenceNumber", batch.position.NextSeqNum,
+		"from", batch.position.MessageCount,
+		"to", batch.newPosition.MessageCount,
+		"nonce", batch.nonce,
+	)
+
+	if config.FeePolicy.Enable {
+		b.recordFeePolicyCost(len(batch.sequencerMsg))
+	}
+	b.updateBacklogEstimate(batch.position, msgCount)
+	b.building = nil
+	return true, nil
+}
+
+// prepareCatchUpBatch stores a batch with the DA provider if there is one, encodes its transaction, and checks the
+// stored and encoded batch produces the messages it was built from.
+func (b *BatchPoster) prepareCatchUpBatch(ctx context.Context, item *catchUpBatch) (*batchTx, error) {
+	batch := item.batch
+	if b.dapWriter != nil {
+		if !b.redisLock.AttemptLock(ctx) {
+			return nil, errAttemptLockFailed
+		}
+		if err := b.storeBatchWithDAProvider(ctx, batch); err != nil {
+			return nil, err
+		}
+	}
+	tx, err := b.encodeBatchTx(ctx, batch)
+	if err != nil {
+		return nil, err
+	}
+	if item.muxBackend != nil {
+		if err := b.checkBatchCorrectness(ctx, batch, item.muxBackend, tx.kzgBlobs, item.l1Bounds); err != nil {
+			return nil, err
+		}
+	}
+	return tx, nil
+}
+
+// postCatchUpBatch posts a prepared batch in catch-up mode.
+func (b *BatchPoster) postCatchUpBatch(ctx context.Context, item *catchUpBatch) error {
+	if !b.redisLock.AttemptLock(ctx) {
+		return errAttemptLockFailed
+	}
+	_, err := b.postBatchTx(ctx, item.batch, item.tx)
+	return err
+}
+
+// prepare prepares a queued batch, which may be discarded after all if a batch queued before it fails.
+func (p *catchUpPipeline) prepare(ctx context.Context, item *catchUpBatch) {
+	item.tx, item.err = p.prepareFn(ctx, item)
+	close(item.done)
+}
+
+// run posts the queued batches in order, once they're prepared.
+func (p *catchUpPipeline) run(ctx context.Context) {
+	for {
+		select {
+		case item := <-p.queue:
+			p.post(ctx, item)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (p *catchUpPipeline) post(ctx context.Context, item *catchUpBatch) {
+	defer p.finish()
+	select {
+	case <-item.done:
+	case <-ctx.Done():
+		return
+	}
+	if !p.isCurrent(item) {
+		catchUpDiscardedCounter.Inc(1)
+		log.Info("BatchPoster: discarding batch queued after a failed batch", "sequenceNumber", item.batch.position.NextSeqNum)
+		return
+	}
+	err := item.err
+	for err == nil {
+		err = p.postFn(ctx, item)
+		if !errors.Is(err, dataposter.ErrExceedsMaxMempoolSize) {
+			break
+		}
+		// The batches ahead of this one need to land first, which isn't a reason to discard the queued batches
+		log.Debug("BatchPoster: waiting for the mempool to make room for a catch-up batch", "sequenceNumber", item.batch.position.NextSeqNum)
+		select {
+		case <-time.After(p.pollIntervalFn()):
+			err = nil
+		case <-ctx.Done():
+		}
+	}
+	if err != nil {
+		if ctx.Err() == nil {
+			catchUpFailureCounter.Inc(1)
+		}
+		p.fail(item, err)
+		return
+	}
+	catchUpPostedCounter.Inc(1)
+}
@@ -0,0 +1,164 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+package arbnode
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/offchainlabs/nitro/arbnode/dataposter"
+)
+
+func TestCatchUpPipeline(t *testing.T) {
+	p := newCatchUpPipeline(2, nil, nil, nil)
+	if _, _, queued, err := p.nextPosition(); err != nil || queued {
+		t.Fatal("empty pipeline returned a queued position", err)
+	}
+
+	queue := func(nonce uint64, seqNum uint64) *catchUpBatch {
+		p.slots <- struct{}{}
+		return p.add(&builtBatch{
+			nonce:       nonce,
+			position:    batchPosterPosition{NextSeqNum: seqNum},
+			newPosition: batchPosterPosition{NextSeqNum: seqNum + 1},
+		}, nil, batchL1Bounds{})
+	}
+	first := queue(5, 10)
+	second := queue(6, 11)
+
+	nonce, positionBytes, queued, err := p.nextPosition()
+	if err != nil || !queued {
+		t.Fatal("expected a queued position", err)
+	}
+	var position batchPosterPosition
+	if err := rlp.DecodeBytes(positionBytes, &position); err != nil {
+		t.Fatal(err)
+	}
+	if nonce != 7 || position.NextSeqNum != 12 {
+		t.Fatal("unexpected next position", nonce, position.NextSeqNum)
+	}
+
+	// A failure discards the batches queued after it, and is reported once
+	p.fail(first, errors.New("test"))
+	p.finish()
+	if p.isCurrent(second) {
+		t.Fatal("batch queued after a failed batch is still current")
+	}
+	p.fail(second, errors.New("test"))
+	if p.takeFailure() == nil {
+		t.Fatal("failure not reported")
+	}
+	if p.takeFailure() != nil {
+		t.Fatal("failure reported twice")
+	}
+	if _, _, queued, _ := p.nextPosition(); queued {
+		t.Fatal("failed pipeline still builds on the queued batches")
+	}
+	if !p.busy() {
+		t.Fatal("pipeline with a batch in flight isn't busy")
+	}
+	p.finish()
+	if p.busy() {
+		t.Fatal("drained pipeline is busy")
+	}
+
+	third := queue(5, 10)
+	if !p.isCurrent(third) {
+		t.Fatal("batch queued after the failure isn't current")
+	}
+	p.finish()
+	if _, _, queued, _ := p.nextPosition(); queued {
+		t.Fatal("drained pipeline still has a queued position")
+	}
+}
+
+func TestCatchUpPipelineFailsPartway(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	errIncorrect := errors.New("batch produces the wrong messages")
+	var mutex sync.Mutex
+	var posted []uint64
+	mempoolFull, incorrect := true, true
+	p := newCatchUpPipeline(
+		3,
+		func(_ context.Context, item *catchUpBatch) (*batchTx, error) {
+			mutex.Lock()
+			defer mutex.Unlock()
+			// The second batch fails its check once stored and encoded, the first time it's built
+			if item.batch.position.NextSeqNum == 11 && incorrect {
+				incorrect = false
+				return nil, errIncorrect
+			}
+			return &batchTx{}, nil
+		},
+		func(_ context.Context, item *catchUpBatch) error {
+			mutex.Lock()
+			defer mutex.Unlock()
+			if mempoolFull {
+				// Waiting for room in the mempool doesn't discard the queued batches
+				mempoolFull = false
+				return dataposter.ErrExceedsMaxMempoolSize
+			}
+			posted = append(posted, item.batch.position.NextSeqNum)
+			return nil
+		},
+		func() time.Duration { return time.Millisecond },
+	)
+	queue := func(nonce uint64, seqNum uint64) {
+		p.slots <- struct{}{}
+		item := p.add(&builtBatch{
+			nonce:       nonce,
+			position:    batchPosterPosition{NextSeqNum: seqNum},
+			newPosition: batchPosterPosition{NextSeqNum: seqNum + 1},
+		}, nil, batchL1Bounds{})
+		go p.prepare(ctx, item)
+		p.queue <- item
+	}
+	drain := func() {
+		t.Helper()
+		deadline := time.Now().Add(10 * time.Second)
+		for p.busy() {
+			if time.Now().After(deadline) {
+				t.Fatal("catch-up pipeline didn't drain")
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+	getPosted := func() []uint64 {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return append([]uint64{}, posted...)
+	}
+
+	queue(5, 10)
+	queue(6, 11)
+	queue(7, 12)
+	go p.run(ctx)
+	drain()
+	if got := getPosted(); len(got) != 1 || got[0] != 10 {
+		t.Fatal("expected only the batch before the failed one to be posted, got", got)
+	}
+	if err := p.takeFailure(); !errors.Is(err, errIncorrect) {
+		t.Fatal("failure not reported", err)
+	}
+	if _, _, queued, _ := p.nextPosition(); queued {
+		t.Fatal("failed pipeline still builds on the queued batches")
+	}
+
+	// Building restarts from the batch that failed
+	queue(6, 11)
+	drain()
+	if got := getPosted(); len(got) != 2 || got[1] != 11 {
+		t.Fatal("batch queued after the failure wasn't posted, got", got)
+	}
+	if err := p.takeFailure(); err != nil {
+		t.Fatal("unexpected failure", err)
+	}
+}
@@ -0,0 +1,262 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+package arbnode
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/params"
+
+	"github.com/offchainlabs/nitro/util/arbmath"
+)
+
+var (
+	feePolicyForecastBaseFeeGauge      = metrics.NewRegisteredGauge("arb/batchposter/feepolicy/forecast/basefee", nil)
+	feePolicyForecastBlobFeeGauge      = metrics.NewRegisteredGauge("arb/batchposter/feepolicy/forecast/blobfee", nil)
+	feePolicyCostPerByteGauge          = metrics.NewRegisteredGauge("arb/batchposter/feepolicy/costperbyte", nil)
+	feePolicyNaiveCostPerByteGauge     = metrics.NewRegisteredGauge("arb/batchposter/feepolicy/naivecostperbyte", nil)
+	feePolicyCostGweiCounter           = metrics.NewRegisteredCounter("arb/batchposter/feepolicy/cost_gwei", nil)
+	feePolicyNaiveCostGweiCounter      = metrics.NewRegisteredCounter("arb/batchposter/feepolicy/naivecost_gwei", nil)
+	feePolicyHurriedCounter            = metrics.NewRegisteredCounter("arb/batchposter/feepolicy/hurried", nil)
+	feePolicyDelayedCounter            = metrics.NewRegisteredCounter("arb/batchposter/feepolicy/delayed", nil)
+	feePolicyTargetDelayReachedCounter = metrics.NewRegisteredCounter("arb/batchposter/feepolicy/targetdelayreached", nil)
+)
+
+type FeePolicyConfig struct {
+	// Enables the cost-aware posting schedule; otherwise batches are posted on the naive schedule.
+	Enable bool `koanf:"enable" reload:"hot"`
+	// Batches younger than this aren't posted unless they must be.
+	MinDelay time.Duration `koanf:"min-delay" reload:"hot"`
+	// Batches older than this are posted regardless of the parent chain fees.
+	TargetDelay time.Duration `koanf:"target-delay" reload:"hot"`
+	// Number of parent chain blocks the fee forecast is averaged over.
+	HistoryBlocks int `koanf:"history-blocks"`
+	// Post a non-full batch early if the current fee is at most this fraction of the forecast.
+	CheapBips arbmath.UBips `koanf:"cheap-bips" reload:"hot"`
+	// Hold back a full batch if the current fee is more than this fraction of the forecast.
+	ExpensiveBips arbmath.UBips `koanf:"expensive-bips" reload:"hot"`
+}
+
+var DefaultFeePolicyConfig = FeePolicyConfig{
+	Enable:        false,
+	MinDelay:      time.Minute,
+	TargetDelay:   time.Minute * 30,
+	HistoryBlocks: 50,
+	CheapBips:     arbmath.OneInUBips * 85 / 100,
+	ExpensiveBips: arbmath.OneInUBips * 125 / 100,
+}
+
+func FeePolicyConfigAddOptions(prefix string, f *pflag.FlagSet) {
+	f.Bool(prefix+".enable", DefaultFeePolicyConfig.Enable, "decide when to post batches and whether to use 4844 blobs based on a forecast of the parent chain fees")
+	f.Duration(prefix+".min-delay", DefaultFeePolicyConfig.MinDelay, "don't post batches younger than this unless they must be posted (e.g. because of the delay buffer)")
+	f.Duration(prefix+".target-delay", DefaultFeePolicyConfig.TargetDelay, "post batches older than this regardless of the parent chain fees (capped by max-delay)")
+	f.Int(prefix+".history-blocks", DefaultFeePolicyConfig.HistoryBlocks, "number of parent chain blocks to forecast fees from")
+	f.Uint64(prefix+".cheap-bips", uint64(DefaultFeePolicyConfig.CheapBips), "post a batch that isn't full yet if the current fee is at most this fraction (in basis points) of the forecast")
+	f.Uint64(prefix+".expensive-bips", uint64(DefaultFeePolicyConfig.ExpensiveBips), "hold back a full batch while the current fee is above this fraction (in basis points) of the forecast")
+}
+
+func (c *FeePolicyConfig) Validate() error {
+	if !c.Enable {
+		return nil
+	}
+	if c.HistoryBlocks <= 0 {
+		return errors.New("fee-policy.history-blocks must be positive")
+	}
+	if c.MinDelay > c.TargetDelay {
+		return errors.New("fee-policy.min-delay cannot be greater than fee-policy.target-delay")
+	}
+	if c.CheapBips > c.ExpensiveBips {
+		return errors.New("fee-policy.cheap-bips cannot be greater than fee-policy.expensive-bips")
+	}
+	return nil
+}
+
+// parentChainFees are the fees of a single parent chain block, in wei.
+type parentChainFees struct {
+	baseFee        uint64
+	blobFeePerByte uint64 // zero if the block has no blob fee
+}
+
+// feePerByte returns the fee per byte of posting data with the given calldata multiplier, or in blobs.
+func (f parentChainFees) feePerByte(calldataFeePerByteMultiplier uint64, use4844 bool) uint64 {
+	if use4844 {
+		return f.blobFeePerByte
+	}
+	return arbmath.SaturatingUMul(f.baseFee, calldataFeePerByteMultiplier)
+}
+
+// l1FeeForecaster tracks recent parent chain fees. Its forecast is their moving average.
+type l1FeeForecaster struct {
+	mutex    sync.Mutex
+	baseFees *arbmath.MovingAverage[uint64]
+	blobFees *arbmath.MovingAverage[uint64]
+	latest   parentChainFees
+	samples  int
+	period   int
+}
+
+func newL1FeeForecaster(historyBlocks int) (*l1FeeForecaster, error) {
+	baseFees, err := arbmath.NewMovingAverage[uint64](historyBlocks)
+	if err != nil {
+		return nil, err
+	}
+	blobFees, err := arbmath.NewMovingAverage[uint64](historyBlocks)
+	if err != nil {
+		return nil, err
+	}
+	return &l1FeeForecaster{
+		baseFees: baseFees,
+		blobFees: blobFees,
+		period:   historyBlocks,
+	}, nil
+}
+
+func (f *l1FeeForecaster) update(fees parentChainFees) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.baseFees.Update(fees.baseFee)
+	f.blobFees.Update(fees.blobFeePerByte)
+	f.latest = fees
+	f.samples++
+	// #nosec G115
+	feePolicyForecastBaseFeeGauge.Update(int64(f.baseFees.Average()))
+	// #nosec G115
+	feePolicyForecastBlobFeeGauge.Update(int64(f.blobFees.Average()))
+}
+
+// ready returns true once a full history of fees has been observed.
+func (f *l1FeeForecaster) ready() bool {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.samples >= f.period
+}
+
+// current returns the latest fees and the forecast.
+func (f *l1FeeForecaster) current() (parentChainFees, parentChainFees) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	forecast := parentChainFees{
+		baseFee:        f.baseFees.Average(),
+		blobFeePerByte: f.blobFees.Average(),
+	}
+	return f.latest, forecast
+}
+
+func feePolicyThreshold(forecastFeePerByte uint64, bips arbmath.UBips) uint64 {
+	return arbmath.SaturatingUMul(forecastFeePerByte, uint64(bips)) / uint64(arbmath.OneInUBips)
+}
+
+type feePolicyDecision struct {
+	post   bool
+	reason string
+}
+
+// decideFeePolicyPost decides whether a batch with useful messages should be posted now.
+// mustPost is set if the naive schedule must post regardless of fees (e.g. max delay or the delay buffer),
+// full is set if the naive schedule would post because the batch is full.
+func decideFeePolicyPost(config *FeePolicyConfig, batchAge time.Duration, mustPost bool, full bool, currentFeePerByte, forecastFeePerByte uint64) feePolicyDecision {
+	if mustPost {
+		return feePolicyDecision{true, "required"}
+	}
+	if batchAge >= config.TargetDelay {
+		return feePolicyDecision{true, "target-delay"}
+	}
+	if batchAge < config.MinDelay {
+		return feePolicyDecision{false, "min-delay"}
+	}
+	if forecastFeePerByte == 0 {
+		return feePolicyDecision{full, "no-forecast"}
+	}
+	if currentFeePerByte <= feePolicyThreshold(forecastFeePerByte, config.CheapBips) {
+		return feePolicyDecision{true, "cheap"}
+	}
+	if full && currentFeePerByte <= feePolicyThreshold(forecastFeePerByte, config.ExpensiveBips) {
+		return feePolicyDecision{true, "full"}
+	}
+	return feePolicyDecision{false, "expensive"}
+}
+
+// feePolicyCostTracker compares the fees actually paid with the fees the naive schedule would have paid.
+type feePolicyCostTracker struct {
+	naiveFeePerByte uint64 // the fee per byte when the naive schedule would first have posted the batch
+	naiveSet        bool
+}
+
+func (t *feePolicyCostTracker) recordNaivePost(feePerByte uint64) {
+	if t.naiveSet {
+		return
+	}
+	t.naiveFeePerByte = feePerByte
+	t.naiveSet = true
+}
+
+func (t *feePolicyCostTracker) recordPosted(feePerByte uint64, forecastFeePerByte uint64, bytes int) {
+	// If the batch was hurried, the naive schedule would have posted it later, at a fee we estimate with the forecast
+	naiveFeePerByte := forecastFeePerByte
+	if t.naiveSet {
+		naiveFeePerByte = t.naiveFeePerByte
+	}
+	if naiveFeePerByte == 0 {
+		naiveFeePerByte = feePerByte
+	}
+	// #nosec G115
+	feePolicyCostPerByteGauge.Update(int64(feePerByte))
+	// #nosec G115
+	feePolicyNaiveCostPerByteGauge.Update(int64(naiveFeePerByte))
+	// #nosec G115
+	feePolicyCostGweiCounter.Inc(int64(arbmath.SaturatingUMul(feePerByte, uint64(bytes)) / params.GWei))
+	// #nosec G115
+	feePolicyNaiveCostGweiCounter.Inc(int64(arbmath.SaturatingUMul(naiveFeePerByte, uint64(bytes)) / params.GWei))
+}
+
+// applyFeePolicy returns whether the batch being built should be posted now according to the fee policy.
+func (b *BatchPoster) applyFeePolicy(config *FeePolicyConfig, batchAge time.Duration, mustPost bool, full bool) bool {
+	latest, forecast := b.feeForecaster.current()
+	multiplier := b.building.calldataFeePerByteMultiplier
+	currentFeePerByte := latest.feePerByte(multiplier, b.building.use4844)
+	var forecastFeePerByte uint64
+	if b.feeForecaster.ready() {
+		forecastFeePerByte = forecast.feePerByte(multiplier, b.building.use4844)
+	}
+	if mustPost || full {
+		b.building.feeCosts.recordNaivePost(currentFeePerByte)
+	}
+	decision := decideFeePolicyPost(config, batchAge, mustPost, full, currentFeePerByte, forecastFeePerByte)
+	if decision.post && !mustPost && !full {
+		if decision.reason == "target-delay" {
+			feePolicyTargetDelayReachedCounter.Inc(1)
+		} else {
+			feePolicyHurriedCounter.Inc(1)
+		}
+	} else if !decision.post && full {
+		feePolicyDelayedCounter.Inc(1)
+	}
+	log.Debug(
+		"BatchPoster: fee policy decision",
+		"post", decision.post,
+		"reason", decision.reason,
+		"batchAge", batchAge,
+		"currentFeePerByte", currentFeePerByte,
+		"forecastFeePerByte", forecastFeePerByte,
+		"use4844", b.building.use4844,
+	)
+	return decision.post
+}
+
+// recordFeePolicyCost updates the metrics comparing the fee policy's cost with the naive schedule's after posting a batch.
+func (b *BatchPoster) recordFeePolicyCost(batchBytes int) {
+	latest, forecast := b.feeForecaster.current()
+	multiplier := b.building.calldataFeePerByteMultiplier
+	var forecastFeePerByte uint64
+	if b.feeForecaster.ready() {
+		forecastFeePerByte = forecast.feePerByte(multiplier, b.building.use4844)
+	}
+	b.building.feeCosts.recordPosted(latest.feePerByte(multiplier, b.building.use4844), forecastFeePerByte, batchBytes)
+}
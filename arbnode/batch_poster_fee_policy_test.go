@@ -0,0 +1,71 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+package arbnode
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDecideFeePolicyPost(t *testing.T) {
+	config := DefaultFeePolicyConfig
+	config.Enable = true
+	config.MinDelay = time.Minute
+	config.TargetDelay = time.Hour
+
+	testCases := []struct {
+		name     string
+		age      time.Duration
+		mustPost bool
+		full     bool
+		current  uint64
+		forecast uint64
+		post     bool
+		reason   string
+	}{
+		{"required", time.Second, true, false, 1000, 100, true, "required"},
+		{"target delay", time.Hour, false, false, 1000, 100, true, "target-delay"},
+		{"too young", time.Second, false, true, 10, 100, false, "min-delay"},
+		{"no forecast full", time.Minute * 2, false, true, 100, 0, true, "no-forecast"},
+		{"no forecast", time.Minute * 2, false, false, 100, 0, false, "no-forecast"},
+		{"cheap", time.Minute * 2, false, false, 85, 100, true, "cheap"},
+		{"not cheap enough", time.Minute * 2, false, false, 86, 100, false, "expensive"},
+		{"full", time.Minute * 2, false, true, 125, 100, true, "full"},
+		{"full but expensive", time.Minute * 2, false, true, 126, 100, false, "expensive"},
+	}
+	for _, tc := range testCases {
+		decision := decideFeePolicyPost(&config, tc.age, tc.mustPost, tc.full, tc.current, tc.forecast)
+		if decision.post != tc.post || decision.reason != tc.reason {
+			t.Errorf("%v: got post=%v reason=%v, expected post=%v reason=%v", tc.name, decision.post, decision.reason, tc.post, tc.reason)
+		}
+	}
+}
+
+func TestL1FeeForecaster(t *testing.T) {
+	forecaster, err := newL1FeeForecaster(4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := uint64(1); i <= 3; i++ {
+		forecaster.update(parentChainFees{baseFee: i * 10, blobFeePerByte: i})
+	}
+	if forecaster.ready() {
+		t.Fatal("forecaster ready before a full history was observed")
+	}
+	forecaster.update(parentChainFees{baseFee: 40, blobFeePerByte: 4})
+	forecaster.update(parentChainFees{baseFee: 50, blobFeePerByte: 5})
+	if !forecaster.ready() {
+		t.Fatal("forecaster not ready after a full history was observed")
+	}
+	latest, forecast := forecaster.current()
+	if latest.baseFee != 50 || latest.blobFeePerByte != 5 {
+		t.Errorf("unexpected latest fees %+v", latest)
+	}
+	if forecast.baseFee != 35 || forecast.blobFeePerByte != 3 {
+		t.Errorf("unexpected forecast %+v", forecast)
+	}
+	if forecast.feePerByte(16, false) != 35*16 || forecast.feePerByte(16, true) != 3 {
+		t.Error("unexpected forecast fee per byte")
+	}
+}
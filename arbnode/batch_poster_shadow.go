@@ -0,0 +1,259 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+package arbnode
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/offchainlabs/nitro/util/arbmath"
+)
+
+var (
+	shadowBatchCounter            = metrics.NewRegisteredCounter("arb/batchposter/shadow/batches", nil)
+	shadow4844BatchCounter        = metrics.NewRegisteredCounter("arb/batchposter/shadow/4844batches", nil)
+	shadowUncompressedSizeGauge   = metrics.NewRegisteredGauge("arb/batchposter/shadow/uncompressedsize", nil)
+	shadowCompressedSizeGauge     = metrics.NewRegisteredGauge("arb/batchposter/shadow/compressedsize", nil)
+	shadowCompressionRatioGauge   = metrics.NewRegisteredGaugeFloat64("arb/batchposter/shadow/compressionratio", nil)
+	shadowGasEstimateGauge        = metrics.NewRegisteredGauge("arb/batchposter/shadow/gasestimate", nil)
+	shadowEstimatedCostGweiGauge  = metrics.NewRegisteredGauge("arb/batchposter/shadow/estimatedcost_gwei", nil)
+	shadowTotalEstimatedCostGwei  = metrics.NewRegisteredCounter("arb/batchposter/shadow/totalestimatedcost_gwei", nil)
+	shadowMessagesPerBatchGauge   = metrics.NewRegisteredGauge("arb/batchposter/shadow/messages", nil)
+	shadowReportWriteErrorCounter = metrics.NewRegisteredCounter("arb/batchposter/shadow/reporterrors", nil)
+)
+
+type BatchPosterShadowConfig struct {
+	// Build, compress and estimate batches exactly like a real batch poster, but never sign or send anything.
+	Enable bool `koanf:"enable"`
+	// File to append one JSON report per batch to.
+	ReportFile string `koanf:"report-file"`
+	// Address to estimate gas from; must be an allowed batch poster of the sequencer inbox for estimates to succeed.
+	EstimateGasFrom string `koanf:"estimate-gas-from"`
+}
+
+var DefaultBatchPosterShadowConfig = BatchPosterShadowConfig{
+	Enable:          false,
+	ReportFile:      "",
+	EstimateGasFrom: "",
+}
+
+func BatchPosterShadowConfigAddOptions(prefix string, f *pflag.FlagSet) {
+	f.Bool(prefix+".enable", DefaultBatchPosterShadowConfig.Enable, "run the batch poster in shadow mode: build and estimate batches without signing or sending them, and report on them instead")
+	f.String(prefix+".report-file", DefaultBatchPosterShadowConfig.ReportFile, "file to append a JSON report of each shadow batch to (reports are only exported as metrics if empty)")
+	f.String(prefix+".estimate-gas-from", DefaultBatchPosterShadowConfig.EstimateGasFrom, "batch poster address to estimate shadow batches' gas from (gas isn't estimated if empty)")
+}
+
+func (c *BatchPosterShadowConfig) Validate() error {
+	if len(c.EstimateGasFrom) > 0 && !common.IsHexAddress(c.EstimateGasFrom) {
+		return fmt.Errorf("invalid shadow estimate-gas-from address \"%v\"", c.EstimateGasFrom)
+	}
+	return nil
+}
+
+// ShadowBatchReport describes a batch the shadow batch poster would have posted.
+type ShadowBatchReport struct {
+	Time                time.Time `json:"time"`
+	SequenceNumber      uint64    `json:"sequenceNumber"`
+	FromMessage         uint64    `json:"fromMessage"`
+	ToMessage           uint64    `json:"toMessage"`
+	DelayedMessageCount uint64    `json:"delayedMessageCount"`
+	UncompressedSize    int       `json:"uncompressedSize"`
+	CompressedSize      int       `json:"compressedSize"`
+	CompressionRatio    float64   `json:"compressionRatio"`
+	CompressionLevel    int       `json:"compressionLevel"`
+	Use4844             bool      `json:"use4844"`
+	NumBlobs            int       `json:"numBlobs"`
+	CalldataSize        int       `json:"calldataSize"`
+	GasEstimate         uint64    `json:"gasEstimate"`
+	BaseFee             *big.Int  `json:"baseFee"`
+	BlobBaseFee         *big.Int  `json:"blobBaseFee,omitempty"`
+	EstimatedCost       *big.Int  `json:"estimatedCost"`
+	BatchAge            string    `json:"batchAge"`
+	// CalldataOnly is set when the batch poster has a DA provider. Shadow batches are never stored with it, so the
+	// sizes and costs describe posting the whole batch on chain, not the DA certificate the real batch poster posts.
+	CalldataOnly bool `json:"calldataOnly"`
+}
+
+// shadowBatchPoster replaces the data poster of a batch poster in shadow mode.
+// It tracks the position of its own batches, and follows the parent chain whenever the real batch poster gets ahead of it.
+type shadowBatchPoster struct {
+	mutex      sync.Mutex
+	position   *batchPosterPosition
+	sender     common.Address
+	reportFile *os.File
+
+	calldataOnly   bool
+	chainPosition  func(ctx context.Context, blockNum *big.Int) ([]byte, error)
+	lastHeader     func(ctx context.Context) (*types.Header, error)
+	blobFeePerByte func(ctx context.Context, header *types.Header) (*big.Int, error)
+}
+
+func newShadowBatchPoster(config *BatchPosterShadowConfig, b *BatchPoster) (*shadowBatchPoster, error) {
+	s := &shadowBatchPoster{
+		calldataOnly:   b.dapWriter != nil,
+		chainPosition:  b.getBatchPosterPosition,
+		lastHeader:     b.l1Reader.LastHeader,
+		blobFeePerByte: b.parentChain.BlobFeePerByte,
+	}
+	if s.calldataOnly {
+		log.Warn("shadow batches aren't stored with the DA provider, so they're reported as if posted on chain")
+	}
+	if config.EstimateGasFrom != "" {
+		s.sender = common.HexToAddress(config.EstimateGasFrom)
+	}
+	if config.ReportFile != "" {
+		file, err := os.OpenFile(config.ReportFile, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open shadow batch report file: %w", err)
+		}
+		s.reportFile = file
+	}
+	return s, nil
+}
+
+// nextPosition returns the position the next shadow batch starts at, in the data poster's metadata format.
+func (s *shadowBatchPoster) nextPosition(ctx context.Context) (uint64, []byte, error) {
+	chainPositionBytes, err := s.chainPosition(ctx, nil)
+	if err != nil {
+		return 0, nil, err
+	}
+	var chainPosition batchPosterPosition
+	if err := rlp.DecodeBytes(chainPositionBytes, &chainPosition); err != nil {
+		return 0, nil, fmt.Errorf("decoding batch position: %w", err)
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.position == nil || chainPosition.MessageCount >= s.position.MessageCount {
+		s.position = &chainPosition
+	}
+	positionBytes, err := rlp.EncodeToBytes(s.position)
+	if err != nil {
+		return 0, nil, err
+	}
+	return s.position.NextSeqNum, positionBytes, nil
+}
+
+func (s *shadowBatchPoster) posted(newPosition batchPosterPosition, report *ShadowBatchReport) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.position = &newPosition
+
+	shadowBatchCounter.Inc(1)
+	if report.Use4844 {
+		shadow4844BatchCounter.Inc(1)
+	}
+	shadowUncompressedSizeGauge.Update(int64(report.UncompressedSize))
+	shadowCompressedSizeGauge.Update(int64(report.CompressedSize))
+	shadowCompressionRatioGauge.Update(report.CompressionRatio)
+	// #nosec G115
+	shadowGasEstimateGauge.Update(int64(report.GasEstimate))
+	// #nosec G115
+	shadowMessagesPerBatchGauge.Update(int64(report.ToMessage - report.FromMessage))
+	costGwei := new(big.Int).Div(report.EstimatedCost, big.NewInt(params.GWei)).Int64()
+	shadowEstimatedCostGweiGauge.Update(costGwei)
+	shadowTotalEstimatedCostGwei.Inc(costGwei)
+
+	log.Info(
+		"BatchPoster: shadow batch built",
+		"sequenceNumber", report.SequenceNumber,
+		"from", report.FromMessage,
+		"to", report.ToMessage,
+		"compressedSize", report.CompressedSize,
+		"compressionRatio", report.CompressionRatio,
+		"use4844", report.Use4844,
+		"gasEstimate", report.GasEstimate,
+		"estimatedCost", report.EstimatedCost,
+	)
+	if s.reportFile == nil {
+		return
+	}
+	data, err := json.Marshal(report)
+	if err == nil {
+		_, err = s.reportFile.Write(append(data, '\n'))
+	}
+	if err != nil {
+		shadowReportWriteErrorCounter.Inc(1)
+		log.Warn("error writing shadow batch report", "err", err)
+	}
+}
+
+func (s *shadowBatchPoster) close() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.reportFile == nil {
+		return
+	}
+	if err := s.reportFile.Close(); err != nil {
+		log.Warn("error closing shadow batch report file", "err", err)
+	}
+	s.reportFile = nil
+}
+
+// post records the batch the batch poster would have posted instead of posting it.
+func (s *shadowBatchPoster) post(
+	ctx context.Context,
+	batch *builtBatch,
+	tx *batchTx,
+	uncompressedSize int,
+	compressionLevel int,
+) error {
+	latestHeader, err := s.lastHeader(ctx)
+	if err != nil {
+		return err
+	}
+	report := &ShadowBatchReport{
+		Time:                time.Now(),
+		SequenceNumber:      batch.position.NextSeqNum,
+		FromMessage:         uint64(batch.position.MessageCount),
+		ToMessage:           uint64(batch.newPosition.MessageCount),
+		DelayedMessageCount: batch.newPosition.DelayedMessageCount,
+		UncompressedSize:    uncompressedSize,
+		CompressedSize:      len(batch.sequencerMsg),
+		CompressionLevel:    compressionLevel,
+		Use4844:             batch.use4844,
+		NumBlobs:            len(tx.kzgBlobs),
+		CalldataSize:        len(tx.data),
+		GasEstimate:         tx.gasLimit,
+		BaseFee:             latestHeader.BaseFee,
+		BatchAge:            time.Since(batch.firstUsefulMsgTime).Round(time.Second).String(),
+		CalldataOnly:        s.calldataOnly,
+	}
+	if len(batch.sequencerMsg) > 0 {
+		report.CompressionRatio = float64(uncompressedSize) / float64(len(batch.sequencerMsg))
+	}
+	report.EstimatedCost = arbmath.BigMulByUint(latestHeader.BaseFee, tx.gasLimit)
+	if report.NumBlobs > 0 {
+		report.BlobBaseFee, err = s.blobFeePerByte(ctx, latestHeader)
+		if err != nil {
+			return err
+		}
+		// #nosec G115
+		blobGas := uint64(report.NumBlobs) * params.BlobTxBlobGasPerBlob
+		report.EstimatedCost.Add(report.EstimatedCost, arbmath.BigMulByUint(report.BlobBaseFee, blobGas))
+	}
+	s.posted(batch.newPosition, report)
+	return nil
+}
+
+// sender returns the address batches are posted (or, in shadow mode, estimated) from.
+func (b *BatchPoster) sender() common.Address {
+	if b.shadow != nil {
+		return b.shadow.sender
+	}
+	return b.dataPoster.Sender()
+}
@@ -0,0 +1,117 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+package arbnode
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+func TestShadowBatchPoster(t *testing.T) {
+	ctx := context.Background()
+	reportFile := filepath.Join(t.TempDir(), "shadow.jsonl")
+	s, err := newShadowBatchPoster(&BatchPosterShadowConfig{ReportFile: reportFile}, &BatchPoster{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	chainPosition := batchPosterPosition{MessageCount: 10, DelayedMessageCount: 2, NextSeqNum: 5}
+	s.chainPosition = func(context.Context, *big.Int) ([]byte, error) {
+		return rlp.EncodeToBytes(chainPosition)
+	}
+	s.lastHeader = func(context.Context) (*types.Header, error) {
+		return &types.Header{BaseFee: big.NewInt(params.GWei)}, nil
+	}
+	s.blobFeePerByte = func(context.Context, *types.Header) (*big.Int, error) {
+		return big.NewInt(3), nil
+	}
+	s.calldataOnly = true
+
+	nextPosition := func(expected batchPosterPosition) {
+		t.Helper()
+		nonce, positionBytes, err := s.nextPosition(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var position batchPosterPosition
+		if err := rlp.DecodeBytes(positionBytes, &position); err != nil {
+			t.Fatal(err)
+		}
+		if nonce != expected.NextSeqNum || position != expected {
+			t.Fatal("unexpected next position", nonce, position, "expected", expected)
+		}
+	}
+
+	// The first shadow batch starts where the chain is.
+	nextPosition(chainPosition)
+
+	shadowPosition := batchPosterPosition{MessageCount: 20, DelayedMessageCount: 3, NextSeqNum: 6}
+	batch := &builtBatch{
+		position:           chainPosition,
+		newPosition:        shadowPosition,
+		sequencerMsg:       make([]byte, 100),
+		use4844:            true,
+		firstUsefulMsgTime: time.Now(),
+	}
+	tx := &batchTx{
+		data:     make([]byte, 10),
+		kzgBlobs: make([]kzg4844.Blob, 1),
+		gasLimit: 100_000,
+	}
+	if err := s.post(ctx, batch, tx, 400, 11); err != nil {
+		t.Fatal(err)
+	}
+
+	// The next shadow batch follows the shadow one, as long as the chain is behind it.
+	nextPosition(shadowPosition)
+	chainPosition = batchPosterPosition{MessageCount: 30, DelayedMessageCount: 4, NextSeqNum: 7}
+	nextPosition(chainPosition)
+
+	s.close()
+	file, err := os.Open(reportFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	var reports []ShadowBatchReport
+	for scanner.Scan() {
+		var report ShadowBatchReport
+		if err := json.Unmarshal(scanner.Bytes(), &report); err != nil {
+			t.Fatal(err)
+		}
+		reports = append(reports, report)
+	}
+	if err := scanner.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if len(reports) != 1 {
+		t.Fatal("expected a single report, got", len(reports))
+	}
+	report := reports[0]
+	if report.SequenceNumber != 5 || report.FromMessage != 10 || report.ToMessage != 20 || report.DelayedMessageCount != 3 {
+		t.Fatal("unexpected batch position in report", report)
+	}
+	if report.CompressedSize != 100 || report.UncompressedSize != 400 || report.CompressionRatio != 4 || report.CompressionLevel != 11 {
+		t.Fatal("unexpected sizes in report", report)
+	}
+	if !report.Use4844 || report.NumBlobs != 1 || report.CalldataSize != 10 || !report.CalldataOnly {
+		t.Fatal("unexpected batch data in report", report)
+	}
+	expectedCost := new(big.Int).SetUint64(params.GWei * 100_000)
+	expectedCost.Add(expectedCost, big.NewInt(3*params.BlobTxBlobGasPerBlob))
+	if report.EstimatedCost.Cmp(expectedCost) != 0 {
+		t.Fatal("unexpected estimated cost", report.EstimatedCost, "expected", expectedCost)
+	}
+}
@@ -19,6 +19,7 @@ import (
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethereum/go-ethereum/log"
 
+	"github.com/offchainlabs/nitro/arbstate/daprovider/externalda"
 	"github.com/offchainlabs/nitro/arbutil"
 	"github.com/offchainlabs/nitro/util/arbmath"
 	"github.com/offchainlabs/nitro/util/headerreader"
@@ -26,13 +27,14 @@ import (
 )
 
 type InboxReaderConfig struct {
-	DelayBlocks         uint64        `koanf:"delay-blocks" reload:"hot"`
-	CheckDelay          time.Duration `koanf:"check-delay" reload:"hot"`
-	MinBlocksToRead     uint64        `koanf:"min-blocks-to-read" reload:"hot"`
-	DefaultBlocksToRead uint64        `koanf:"default-blocks-to-read" reload:"hot"`
-	TargetMessagesRead  uint64        `koanf:"target-messages-read" reload:"hot"`
-	MaxBlocksToRead     uint64        `koanf:"max-blocks-to-read" reload:"hot"`
-	ReadMode            string        `koanf:"read-mode" reload:"hot"`
+	DelayBlocks         uint64                  `koanf:"delay-blocks" reload:"hot"`
+	CheckDelay          time.Duration           `koanf:"check-delay" reload:"hot"`
+	MinBlocksToRead     uint64                  `koanf:"min-blocks-to-read" reload:"hot"`
+	DefaultBlocksToRead uint64                  `koanf:"default-blocks-to-read" reload:"hot"`
+	TargetMessagesRead  uint64                  `koanf:"target-messages-read" reload:"hot"`
+	MaxBlocksToRead     uint64                  `koanf:"max-blocks-to-read" reload:"hot"`
+	ReadMode            string                  `koanf:"read-mode" reload:"hot"`
+	ExternalDA          externalda.ClientConfig `koanf:"external-da"`
 }
 
 type InboxReaderConfigFetcher func() *InboxReaderConfig
@@ -45,7 +47,7 @@ func (c *InboxReaderConfig) Validate() error {
 	if c.ReadMode != "latest" && c.ReadMode != "safe" && c.ReadMode != "finalized" {
 		return fmt.Errorf("inbox reader read-mode is invalid, want: latest or safe or finalized, got: %s", c.ReadMode)
 	}
-	return nil
+	return c.ExternalDA.Validate()
 }
 
 func InboxReaderConfigAddOptions(prefix string, f *flag.FlagSet) {
@@ -56,6 +58,7 @@ func InboxReaderConfigAddOptions(prefix string, f *flag.FlagSet) {
 	f.Uint64(prefix+".target-messages-read", DefaultInboxReaderConfig.TargetMessagesRead, "if adjust-blocks-to-read is enabled, the target number of messages to read at once")
 	f.Uint64(prefix+".max-blocks-to-read", DefaultInboxReaderConfig.MaxBlocksToRead, "if adjust-blocks-to-read is enabled, the maximum number of blocks to read at once")
 	f.String(prefix+".read-mode", DefaultInboxReaderConfig.ReadMode, "mode to only read latest or safe or finalized L1 blocks. Enabling safe or finalized disables feed input and output. Defaults to latest. Takes string input, valid strings- latest, safe, finalized")
+	externalda.ClientConfigAddOptions(prefix+".external-da", f)
 }
 
 var DefaultInboxReaderConfig = InboxReaderConfig{
@@ -66,6 +69,7 @@ var DefaultInboxReaderConfig = InboxReaderConfig{
 	TargetMessagesRead:  500,
 	MaxBlocksToRead:     2000,
 	ReadMode:            "latest",
+	ExternalDA:          externalda.DefaultClientConfig,
 }
 
 var TestInboxReaderConfig = InboxReaderConfig{
@@ -76,6 +80,7 @@ var TestInboxReaderConfig = InboxReaderConfig{
 	TargetMessagesRead:  500,
 	MaxBlocksToRead:     2000,
 	ReadMode:            "latest",
+	ExternalDA:          externalda.DefaultClientConfig,
 }
 
 type InboxReader struct {
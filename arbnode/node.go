@@ -30,6 +30,7 @@ import (
 	"github.com/offchainlabs/nitro/arbnode/resourcemanager"
 	"github.com/offchainlabs/nitro/arbos/arbostypes"
 	"github.com/offchainlabs/nitro/arbstate/daprovider"
+	"github.com/offchainlabs/nitro/arbstate/daprovider/externalda"
 	"github.com/offchainlabs/nitro/arbutil"
 	"github.com/offchainlabs/nitro/broadcastclient"
 	"github.com/offchainlabs/nitro/broadcastclients"
@@ -131,6 +132,12 @@ func (c *Config) Validate() error {
 	if err := c.BatchPoster.Validate(); err != nil {
 		return err
 	}
+	if c.BatchPoster.Enable && c.BatchPoster.ExternalDA.Enable && c.DataAvailability.Enable {
+		return errors.New("the batch poster can't store batches with both the data availability service and an external data availability provider")
+	}
+	if c.BatchPoster.Enable && c.BatchPoster.ExternalDA.Enable && !c.InboxReader.ExternalDA.Enable {
+		return errors.New("the batch poster stores batches with an external data availability provider, but the inbox reader can't read them back without inbox-reader.external-da.enable")
+	}
 	if err := c.Feed.Validate(); err != nil {
 		return err
 	}
@@ -292,6 +299,8 @@ type Node struct {
 	SeqCoordinator           *SeqCoordinator
 	MaintenanceRunner        *MaintenanceRunner
 	DASLifecycleManager      *das.LifecycleManager
+	DASAggregator            *das.Aggregator
+	ExternalDAClients        []*externalda.Client
 	SyncMonitor              *SyncMonitor
 	blockMetadataFetcher     *BlockMetadataFetcher
 	configFetcher            ConfigFetcher
@@ -581,8 +590,9 @@ func getDAS(
 	deployInfo *chaininfo.RollupAddresses,
 	dataSigner signature.DataSignerFunc,
 	l1client *ethclient.Client,
-) (das.DataAvailabilityServiceWriter, *das.LifecycleManager, []daprovider.Reader, error) {
+) (das.DataAvailabilityServiceWriter, *das.Aggregator, *das.LifecycleManager, *externalda.Client, []daprovider.Reader, error) {
 	var daWriter das.DataAvailabilityServiceWriter
+	var dasAggregator *das.Aggregator
 	var daReader das.DataAvailabilityServiceReader
 	var dasLifecycleManager *das.LifecycleManager
 	var dasKeysetFetcher *das.KeysetFetcher
@@ -591,12 +601,13 @@ func getDAS(
 		if config.BatchPoster.Enable {
 			daWriter, daReader, dasKeysetFetcher, dasLifecycleManager, err = das.CreateBatchPosterDAS(ctx, &config.DataAvailability, dataSigner, l1client, deployInfo.SequencerInbox)
 			if err != nil {
-				return nil, nil, nil, err
+				return nil, nil, nil, nil, nil, err
 			}
+			dasAggregator, _ = daWriter.(*das.Aggregator)
 		} else {
 			daReader, dasKeysetFetcher, dasLifecycleManager, err = das.CreateDAReaderForNode(ctx, &config.DataAvailability, l1Reader, &deployInfo.SequencerInbox)
 			if err != nil {
-				return nil, nil, nil, err
+				return nil, nil, nil, nil, nil, err
 			}
 		}
 
@@ -609,12 +620,12 @@ func getDAS(
 			daReader = das.NewReaderPanicWrapper(daReader)
 		}
 	} else if l2Config.ArbitrumChainParams.DataAvailabilityCommittee {
-		return nil, nil, nil, errors.New("a data availability service is required for this chain, but it was not configured")
+		return nil, nil, nil, nil, nil, errors.New("a data availability service is required for this chain, but it was not configured")
 	}
 
 	// We support a nil txStreamer for the pruning code
 	if txStreamer != nil && txStreamer.chainConfig.ArbitrumChainParams.DataAvailabilityCommittee && daReader == nil {
-		return nil, nil, nil, errors.New("data availability service required but unconfigured")
+		return nil, nil, nil, nil, nil, errors.New("data availability service required but unconfigured")
 	}
 	var dapReaders []daprovider.Reader
 	if daReader != nil {
@@ -623,8 +634,17 @@ func getDAS(
 	if blobReader != nil {
 		dapReaders = append(dapReaders, daprovider.NewReaderForBlobReader(blobReader))
 	}
+	var externalDAReader *externalda.Client
+	if config.InboxReader.ExternalDA.Enable {
+		var err error
+		externalDAReader, err = externalda.NewClient(ctx, func() *externalda.ClientConfig { return &config.InboxReader.ExternalDA })
+		if err != nil {
+			return nil, nil, nil, nil, nil, err
+		}
+		dapReaders = append(dapReaders, externalDAReader)
+	}
 
-	return daWriter, dasLifecycleManager, dapReaders, nil
+	return daWriter, dasAggregator, dasLifecycleManager, externalDAReader, dapReaders, nil
 }
 
 func getInboxTrackerAndReader(
@@ -892,21 +912,30 @@ func getBatchPoster(
 	parentChainID *big.Int,
 	dapReaders []daprovider.Reader,
 	stakerAddr common.Address,
-) (*BatchPoster, error) {
+) (*BatchPoster, *externalda.Client, error) {
 	var batchPoster *BatchPoster
+	var externalDAWriter *externalda.Client
 	if config.BatchPoster.Enable {
 		if exec == nil {
-			return nil, errors.New("batch poster requires an execution batch poster")
+			return nil, nil, errors.New("batch poster requires an execution batch poster")
 		}
 
-		if txOptsBatchPoster == nil && config.BatchPoster.DataPoster.ExternalSigner.URL == "" {
-			return nil, errors.New("batchposter, but no TxOpts")
+		if txOptsBatchPoster == nil && config.BatchPoster.DataPoster.ExternalSigner.URL == "" && !config.BatchPoster.Shadow.Enable {
+			return nil, nil, errors.New("batchposter, but no TxOpts")
 		}
 		var dapWriter daprovider.Writer
 		if daWriter != nil {
 			dapWriter = daprovider.NewWriterForDAS(daWriter)
 		}
 		var err error
+		if config.BatchPoster.ExternalDA.Enable {
+			// Config validation makes sure the inbox reader, and so dapReaders, can read the batches back
+			externalDAWriter, err = externalda.NewClient(ctx, func() *externalda.ClientConfig { return &configFetcher.Get().BatchPoster.ExternalDA })
+			if err != nil {
+				return nil, nil, err
+			}
+			dapWriter = externalDAWriter
+		}
 		batchPoster, err = NewBatchPoster(ctx, &BatchPosterOpts{
 			DataPosterDB:  rawdb.NewTable(arbDb, storage.BatchPosterPrefix),
 			L1Reader:      l1Reader,
@@ -922,16 +951,22 @@ func getBatchPoster(
 			DAPReaders:    dapReaders,
 		})
 		if err != nil {
-			return nil, err
+			if externalDAWriter != nil {
+				externalDAWriter.Close()
+			}
+			return nil, nil, err
 		}
 
 		// Check if staker and batch poster are using the same address
-		if stakerAddr != (common.Address{}) && !strings.EqualFold(config.Staker.Strategy, "watchtower") && stakerAddr == batchPoster.dataPoster.Sender() {
-			return nil, fmt.Errorf("staker and batch poster are using the same address which is not allowed: %v", stakerAddr)
+		if stakerAddr != (common.Address{}) && !strings.EqualFold(config.Staker.Strategy, "watchtower") && !config.BatchPoster.Shadow.Enable && stakerAddr == batchPoster.sender() {
+			if externalDAWriter != nil {
+				externalDAWriter.Close()
+			}
+			return nil, nil, fmt.Errorf("staker and batch poster are using the same address which is not allowed: %v", stakerAddr)
 		}
 	}
 
-	return batchPoster, nil
+	return batchPoster, externalDAWriter, nil
 }
 
 func getDelayedSequencer(
@@ -993,6 +1028,7 @@ func getNodeParentChainReaderDisabled(
 		SeqCoordinator:          coordinator,
 		MaintenanceRunner:       maintenanceRunner,
 		DASLifecycleManager:     nil,
+		DASAggregator:           nil,
 		SyncMonitor:             syncMonitor,
 		configFetcher:           configFetcher,
 		ctx:                     ctx,
@@ -1077,7 +1113,7 @@ func createNodeImpl(
 		return nil, err
 	}
 
-	daWriter, dasLifecycleManager, dapReaders, err := getDAS(ctx, config, l2Config, txStreamer, blobReader, l1Reader, deployInfo, dataSigner, l1client)
+	daWriter, dasAggregator, dasLifecycleManager, externalDAReader, dapReaders, err := getDAS(ctx, config, l2Config, txStreamer, blobReader, l1Reader, deployInfo, dataSigner, l1client)
 	if err != nil {
 		return nil, err
 	}
@@ -1102,7 +1138,7 @@ func createNodeImpl(
 		return nil, err
 	}
 
-	batchPoster, err := getBatchPoster(ctx, config, configFetcher, txOptsBatchPoster, daWriter, l1Reader, inboxTracker, txStreamer, executionBatchPoster, arbDb, syncMonitor, deployInfo, parentChainID, dapReaders, stakerAddr)
+	batchPoster, externalDAWriter, err := getBatchPoster(ctx, config, configFetcher, txOptsBatchPoster, daWriter, l1Reader, inboxTracker, txStreamer, executionBatchPoster, arbDb, syncMonitor, deployInfo, parentChainID, dapReaders, stakerAddr)
 	if err != nil {
 		return nil, err
 	}
@@ -1117,6 +1153,13 @@ func createNodeImpl(
 	}
 	consensusExecutionSyncer := NewConsensusExecutionSyncer(consensusExecutionSyncerConfigFetcher, inboxReader, executionClient, blockValidator)
 
+	var externalDAClients []*externalda.Client
+	for _, client := range []*externalda.Client{externalDAReader, externalDAWriter} {
+		if client != nil {
+			externalDAClients = append(externalDAClients, client)
+		}
+	}
+
 	return &Node{
 		ArbDB:                    arbDb,
 		Stack:                    stack,
@@ -1140,6 +1183,8 @@ func createNodeImpl(
 		SeqCoordinator:           coordinator,
 		MaintenanceRunner:        maintenanceRunner,
 		DASLifecycleManager:      dasLifecycleManager,
+		DASAggregator:            dasAggregator,
+		ExternalDAClients:        externalDAClients,
 		SyncMonitor:              syncMonitor,
 		blockMetadataFetcher:     blockMetadataFetcher,
 		configFetcher:            configFetcher,
@@ -1230,6 +1275,14 @@ func registerAPIs(currentNode *Node, stack *node.Node) {
 			Public: false,
 		})
 	}
+	if currentNode.DASAggregator != nil {
+		apis = append(apis, rpc.API{
+			Namespace: "das",
+			Version:   "1.0",
+			Service:   das.NewAggregatorAPI(currentNode.DASAggregator),
+			Public:    false,
+		})
+	}
 	stack.RegisterAPIs(apis)
 }
 
@@ -1487,6 +1540,9 @@ func (n *Node) StopAndWait() {
 	if n.DASLifecycleManager != nil {
 		n.DASLifecycleManager.StopAndWaitUntil(2 * time.Second)
 	}
+	for _, client := range n.ExternalDAClients {
+		client.Close()
+	}
 	if n.ExecutionClient != nil {
 		_, err := n.ExecutionClient.StopAndWait().Await(n.ctx)
 		if err != nil {
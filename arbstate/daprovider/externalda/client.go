@@ -0,0 +1,183 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+package externalda
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+
+	flag "github.com/spf13/pflag"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/offchainlabs/nitro/arbstate/daprovider"
+	"github.com/offchainlabs/nitro/arbutil"
+	"github.com/offchainlabs/nitro/util/rpcclient"
+)
+
+type ClientConfig struct {
+	Enable    bool                   `koanf:"enable"`
+	RPC       rpcclient.ClientConfig `koanf:"rpc"`
+	Dangerous DangerousConfig        `koanf:"dangerous"`
+}
+
+type DangerousConfig struct {
+	AllowUnprovableBatches bool `koanf:"allow-unprovable-batches"`
+}
+
+type ClientConfigFetcher func() *ClientConfig
+
+var DefaultClientConfig = ClientConfig{
+	Enable: false,
+	RPC: rpcclient.ClientConfig{
+		URL:                       "",
+		Retries:                   3,
+		RetryErrors:               rpcclient.DefaultClientConfig.RetryErrors,
+		ArgLogLimit:               2048,
+		WebsocketMessageSizeLimit: 256 * 1024 * 1024,
+	},
+}
+
+func ClientConfigAddOptions(prefix string, f *flag.FlagSet) {
+	f.Bool(prefix+".enable", DefaultClientConfig.Enable, "enable the external data availability provider")
+	rpcclient.RPCClientAddOptions(prefix+".rpc", f, &DefaultClientConfig.RPC)
+	f.Bool(prefix+".dangerous.allow-unprovable-batches", DefaultClientConfig.Dangerous.AllowUnprovableBatches, "DANGEROUS! enable the external data availability provider although the replay binary can't read its batches, so validators can't validate or prove them")
+}
+
+func (c *ClientConfig) Validate() error {
+	if !c.Enable {
+		return nil
+	}
+	if !c.Dangerous.AllowUnprovableBatches {
+		// The replay binary has no reader for external DA certificates, so it would treat these batches as empty
+		// and diverge from the nodes reading them.
+		return errors.New("batches stored with an external data availability provider can't be validated or proven, set dangerous.allow-unprovable-batches to enable it anyway")
+	}
+	if c.RPC.URL == "" {
+		return errors.New("external data availability provider enabled without an rpc url")
+	}
+	return c.RPC.Validate()
+}
+
+// Client talks to an external DA provider server, and implements both daprovider.Reader and daprovider.Writer.
+type Client struct {
+	rpc         *rpcclient.RpcClient
+	headerBytes []byte
+}
+
+func NewClient(ctx context.Context, config ClientConfigFetcher) (*Client, error) {
+	rpc := rpcclient.NewRpcClient(func() *rpcclient.ClientConfig { return &config().RPC }, nil)
+	if err := rpc.Start(ctx); err != nil {
+		return nil, fmt.Errorf("connecting to external data availability provider: %w", err)
+	}
+	var headerBytes hexutil.Bytes
+	if err := rpc.CallContext(ctx, &headerBytes, namespace+"_headerBytes"); err != nil {
+		rpc.Close()
+		return nil, fmt.Errorf("fetching external data availability provider header bytes: %w", err)
+	}
+	if len(headerBytes) == 0 {
+		rpc.Close()
+		return nil, errors.New("external data availability provider returned no header bytes")
+	}
+	for _, headerByte := range headerBytes {
+		if err := checkHeaderByte(headerByte); err != nil {
+			rpc.Close()
+			return nil, err
+		}
+	}
+	return &Client{
+		rpc:         rpc,
+		headerBytes: headerBytes,
+	}, nil
+}
+
+// checkHeaderByte makes sure a provider's header byte can't be mistaken for one of the built in batch formats.
+func checkHeaderByte(headerByte byte) error {
+	if daprovider.IsL1AuthenticatedMessageHeaderByte(headerByte) ||
+		daprovider.IsDASMessageHeaderByte(headerByte) ||
+		daprovider.IsZeroheavyEncodedHeaderByte(headerByte) ||
+		daprovider.IsBrotliMessageHeaderByte(headerByte) ||
+		daprovider.IsZstdMessageHeaderByte(headerByte) {
+		return fmt.Errorf("external data availability provider header byte 0x%02x is reserved", headerByte)
+	}
+	return nil
+}
+
+func (c *Client) Close() {
+	c.rpc.Close()
+}
+
+func (c *Client) IsValidHeaderByte(headerByte byte) bool {
+	return bytes.IndexByte(c.headerBytes, headerByte) >= 0
+}
+
+func (c *Client) RecoverPayloadFromBatch(
+	ctx context.Context,
+	batchNum uint64,
+	batchBlockHash common.Hash,
+	sequencerMsg []byte,
+	preimageRecorder daprovider.PreimageRecorder,
+	validateSeqMsg bool,
+) ([]byte, error) {
+	var result RecoverPayloadResult
+	err := c.rpc.CallContext(ctx, &result, namespace+"_recoverPayload", hexutil.Uint64(batchNum), batchBlockHash, hexutil.Bytes(sequencerMsg), validateSeqMsg)
+	if err != nil {
+		return nil, fmt.Errorf("recovering batch %v from external data availability provider: %w", batchNum, err)
+	}
+	if len(result.Payload) == 0 {
+		return nil, nil
+	}
+	if preimageRecorder != nil {
+		for _, preimage := range result.Preimages {
+			if err := checkPreimage(preimage); err != nil {
+				return nil, fmt.Errorf("batch %v: %w", batchNum, err)
+			}
+			preimageRecorder(preimage.Hash, preimage.Data, preimage.Type)
+		}
+	}
+	return result.Payload, nil
+}
+
+// checkPreimage verifies the preimages whose hash is cheap to compute, so a faulty provider can't poison the recorded preimages.
+func checkPreimage(preimage Preimage) error {
+	var hash common.Hash
+	switch preimage.Type {
+	case arbutil.Keccak256PreimageType:
+		hash = crypto.Keccak256Hash(preimage.Data)
+	case arbutil.Sha2_256PreimageType:
+		hash = sha256.Sum256(preimage.Data)
+	default:
+		return nil
+	}
+	if hash != preimage.Hash {
+		return fmt.Errorf("external data availability provider returned a preimage of type %v that doesn't match its hash %v", preimage.Type, preimage.Hash)
+	}
+	return nil
+}
+
+func (c *Client) Store(ctx context.Context, message []byte, timeout uint64, disableFallbackStoreDataOnChain bool) ([]byte, error) {
+	var result StoreResult
+	err := c.rpc.CallContext(ctx, &result, namespace+"_store", hexutil.Bytes(message), hexutil.Uint64(timeout), disableFallbackStoreDataOnChain)
+	if err != nil {
+		return nil, fmt.Errorf("storing batch with external data availability provider: %w", err)
+	}
+	cert := []byte(result.SerializedDACert)
+	if bytes.Equal(cert, message) {
+		if disableFallbackStoreDataOnChain {
+			return nil, errors.New("external data availability provider fell back to storing data on chain, which is disabled")
+		}
+		log.Warn("External data availability provider fell back to storing data on chain")
+		return message, nil
+	}
+	if len(cert) == 0 || !c.IsValidHeaderByte(cert[0]) {
+		return nil, errors.New("external data availability provider returned a certificate without one of its header bytes")
+	}
+	return cert, nil
+}
@@ -0,0 +1,107 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+package externalda
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"net"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/offchainlabs/nitro/arbstate/daprovider"
+	"github.com/offchainlabs/nitro/arbutil"
+	"github.com/offchainlabs/nitro/util/testhelpers"
+)
+
+const testHeaderByte byte = 0x02
+
+func startTestClient(t *testing.T, ctx context.Context) *Client {
+	server, err := NewMemoryServer(testHeaderByte)
+	testhelpers.RequireImpl(t, err)
+	listener, err := net.Listen("tcp", "localhost:0")
+	testhelpers.RequireImpl(t, err)
+	running, err := StartMemoryServerOnListener(ctx, listener, server)
+	testhelpers.RequireImpl(t, err)
+	t.Cleanup(func() { testhelpers.RequireImpl(t, running.Shutdown()) })
+
+	config := DefaultClientConfig
+	config.Enable = true
+	config.RPC.URL = "http://" + listener.Addr().String()
+	if config.Validate() == nil {
+		testhelpers.FailImpl(t, "external data availability provider enabled without allowing unprovable batches")
+	}
+	config.Dangerous.AllowUnprovableBatches = true
+	testhelpers.RequireImpl(t, config.Validate())
+	client, err := NewClient(ctx, func() *ClientConfig { return &config })
+	testhelpers.RequireImpl(t, err)
+	t.Cleanup(client.Close)
+	return client
+}
+
+func sequencerMessage(cert []byte) []byte {
+	msg := binary.BigEndian.AppendUint64(make([]byte, 32), 1)
+	return append(msg, cert...)
+}
+
+func TestExternalDAStoreAndRecover(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	client := startTestClient(t, ctx)
+
+	if !client.IsValidHeaderByte(testHeaderByte) || client.IsValidHeaderByte(daprovider.BrotliMessageHeaderByte) {
+		t.Fatal("unexpected header bytes", client.headerBytes)
+	}
+
+	batch := append([]byte{daprovider.BrotliMessageHeaderByte}, []byte("test batch")...)
+	cert, err := client.Store(ctx, batch, 0, true)
+	testhelpers.RequireImpl(t, err)
+	if cert[0] != testHeaderByte {
+		t.Fatal("certificate doesn't start with the header byte", cert)
+	}
+
+	preimages := make(map[arbutil.PreimageType]map[common.Hash][]byte)
+	payload, err := client.RecoverPayloadFromBatch(ctx, 1, common.Hash{}, sequencerMessage(cert), daprovider.RecordPreimagesTo(preimages), true)
+	testhelpers.RequireImpl(t, err)
+	if !bytes.Equal(payload, batch) {
+		t.Fatal("recovered payload doesn't match the batch", payload)
+	}
+	if !bytes.Equal(preimages[arbutil.Keccak256PreimageType][crypto.Keccak256Hash(batch)], batch) {
+		t.Fatal("batch preimage wasn't recorded")
+	}
+
+	// An invalid certificate makes the batch empty rather than failing
+	payload, err = client.RecoverPayloadFromBatch(ctx, 1, common.Hash{}, sequencerMessage(cert[:10]), nil, true)
+	testhelpers.RequireImpl(t, err)
+	if payload != nil {
+		t.Fatal("invalid certificate returned a payload", payload)
+	}
+
+	// Missing data is an error, so it's retried
+	missing := append([]byte{testHeaderByte}, crypto.Keccak256([]byte("missing"))...)
+	if _, err := client.RecoverPayloadFromBatch(ctx, 1, common.Hash{}, sequencerMessage(missing), nil, true); err == nil {
+		t.Fatal("recovering a missing batch didn't fail")
+	}
+}
+
+func TestExternalDAReservedHeaderBytes(t *testing.T) {
+	for _, headerByte := range []byte{
+		daprovider.BrotliMessageHeaderByte,
+		daprovider.ZstdMessageHeaderByte,
+		daprovider.DASMessageHeaderFlag,
+		daprovider.BlobHashesHeaderFlag,
+		daprovider.ZeroheavyMessageHeaderFlag,
+		daprovider.L1AuthenticatedMessageHeaderFlag,
+	} {
+		if _, err := NewMemoryServer(headerByte); err == nil {
+			t.Fatalf("header byte 0x%02x wasn't rejected", headerByte)
+		}
+	}
+	if err := checkPreimage(Preimage{Type: arbutil.Keccak256PreimageType, Hash: common.Hash{1}, Data: []byte("data")}); err == nil {
+		t.Fatal("mismatched preimage wasn't rejected")
+	}
+}
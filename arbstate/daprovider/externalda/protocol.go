@@ -0,0 +1,49 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+// Package externalda lets nitro use a data availability layer that runs as a separate JSON-RPC server.
+//
+// The server implements three methods in the daprovider namespace:
+//
+//	daprovider_headerBytes() -> hex bytes
+//	    The sequencer message header bytes the provider handles. Each must be a byte nitro doesn't
+//	    already give a meaning to, and must not have the L1 authenticated bit (0x40) set.
+//	daprovider_store(message: hex bytes, timeout: hex uint64, disableFallbackStoreDataOnChain: bool) -> StoreResult
+//	    Stores a compressed batch until at least the unix timestamp timeout, and returns the serialized
+//	    certificate posted to the sequencer inbox in its place. The certificate must start with one of the
+//	    provider's header bytes. Unless fallback is disabled, the provider may instead return the message
+//	    unchanged, and the batch is posted on chain.
+//	daprovider_recoverPayload(batchNum: hex uint64, batchBlockHash: hash, sequencerMsg: hex bytes, validateSeqMsg: bool) -> RecoverPayloadResult
+//	    Returns the batch a certificate commits to. sequencerMsg is the full sequencer message, including its
+//	    40 byte header. A missing or empty payload marks the certificate as invalid, and the batch is treated as empty.
+//	    Errors are retried, so they must only be returned for failures that may go away.
+//
+// The batch data is only available to the nodes that can reach the server; the replay binary can't
+// recover it, so batches stored with an external provider can't be validated or proven in a challenge.
+// Until the replay binary can, the client refuses to start without dangerous.allow-unprovable-batches.
+package externalda
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"github.com/offchainlabs/nitro/arbutil"
+)
+
+const namespace = "daprovider"
+
+type StoreResult struct {
+	SerializedDACert hexutil.Bytes `json:"serializedDACert"`
+}
+
+// Preimage is a preimage that validators need to record to replay a batch.
+type Preimage struct {
+	Type arbutil.PreimageType `json:"type"`
+	Hash common.Hash          `json:"hash"`
+	Data hexutil.Bytes        `json:"data"`
+}
+
+type RecoverPayloadResult struct {
+	Payload   hexutil.Bytes `json:"payload,omitempty"`
+	Preimages []Preimage    `json:"preimages,omitempty"`
+}
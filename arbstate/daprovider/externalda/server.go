@@ -0,0 +1,123 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+package externalda
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/offchainlabs/nitro/arbutil"
+)
+
+// MemoryServer is a reference implementation of the external DA provider protocol, which keeps batches in memory.
+// Its certificates are the header byte followed by the keccak256 hash of the batch.
+type MemoryServer struct {
+	headerByte byte
+
+	mutex   sync.Mutex
+	batches map[common.Hash][]byte
+}
+
+func NewMemoryServer(headerByte byte) (*MemoryServer, error) {
+	if err := checkHeaderByte(headerByte); err != nil {
+		return nil, err
+	}
+	return &MemoryServer{
+		headerByte: headerByte,
+		batches:    make(map[common.Hash][]byte),
+	}, nil
+}
+
+func (s *MemoryServer) HeaderBytes(ctx context.Context) (hexutil.Bytes, error) {
+	return hexutil.Bytes{s.headerByte}, nil
+}
+
+func (s *MemoryServer) Store(ctx context.Context, message hexutil.Bytes, timeout hexutil.Uint64, disableFallbackStoreDataOnChain bool) (*StoreResult, error) {
+	hash := crypto.Keccak256Hash(message)
+	s.mutex.Lock()
+	s.batches[hash] = common.CopyBytes(message)
+	s.mutex.Unlock()
+	return &StoreResult{
+		SerializedDACert: append([]byte{s.headerByte}, hash.Bytes()...),
+	}, nil
+}
+
+func (s *MemoryServer) RecoverPayload(ctx context.Context, batchNum hexutil.Uint64, batchBlockHash common.Hash, sequencerMsg hexutil.Bytes, validateSeqMsg bool) (*RecoverPayloadResult, error) {
+	if len(sequencerMsg) != 40+1+len(common.Hash{}) || sequencerMsg[40] != s.headerByte {
+		// An invalid certificate makes the batch empty
+		return &RecoverPayloadResult{}, nil
+	}
+	hash := common.BytesToHash(sequencerMsg[41:])
+	s.mutex.Lock()
+	batch, ok := s.batches[hash]
+	s.mutex.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("batch %v not found", hash)
+	}
+	return &RecoverPayloadResult{
+		Payload: batch,
+		Preimages: []Preimage{{
+			Type: arbutil.Keccak256PreimageType,
+			Hash: hash,
+			Data: batch,
+		}},
+	}, nil
+}
+
+// RunningMemoryServer is a MemoryServer being served over JSON-RPC.
+type RunningMemoryServer struct {
+	server     *http.Server
+	exitedChan chan struct{}
+	serveErr   error
+}
+
+// StartMemoryServerOnListener serves a MemoryServer over JSON-RPC until ctx is done or it's shut down.
+func StartMemoryServerOnListener(ctx context.Context, listener net.Listener, server *MemoryServer) (*RunningMemoryServer, error) {
+	rpcServer := rpc.NewServer()
+	if err := rpcServer.RegisterName(namespace, server); err != nil {
+		return nil, err
+	}
+	ret := &RunningMemoryServer{
+		server: &http.Server{
+			Handler:           rpcServer,
+			ReadHeaderTimeout: rpc.DefaultHTTPTimeouts.ReadHeaderTimeout,
+		},
+		exitedChan: make(chan struct{}),
+	}
+	go func() {
+		if err := ret.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			ret.serveErr = err
+		}
+		close(ret.exitedChan)
+	}()
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = ret.server.Shutdown(context.Background())
+		case <-ret.exitedChan:
+		}
+	}()
+	return ret, nil
+}
+
+// WaitForShutdown waits for the server to stop, and returns the error it stopped serving with, if any.
+func (s *RunningMemoryServer) WaitForShutdown() error {
+	<-s.exitedChan
+	return s.serveErr
+}
+
+// Shutdown stops the server, which may already have been shut down by its context being done.
+func (s *RunningMemoryServer) Shutdown() error {
+	_ = s.server.Close()
+	return s.WaitForShutdown()
+}
@@ -88,6 +88,10 @@ const BlobHashesHeaderFlag byte = L1AuthenticatedMessageHeaderFlag | 0x10 // 0x5
 // BrotliMessageHeaderByte indicates that the message is brotli-compressed.
 const BrotliMessageHeaderByte byte = 0
 
+// ZstdMessageHeaderByte indicates that the message is zstd-compressed.
+// It's experimental, and only decoded on chains that enable it, so it's deliberately not part of KnownHeaderBits.
+const ZstdMessageHeaderByte byte = 0x01
+
 // KnownHeaderBits is all header bits with known meaning to this nitro version
 const KnownHeaderBits byte = DASMessageHeaderFlag | TreeDASMessageHeaderFlag | L1AuthenticatedMessageHeaderFlag | ZeroheavyMessageHeaderFlag | BlobHashesHeaderFlag | BrotliMessageHeaderByte
 
@@ -120,6 +124,10 @@ func IsBrotliMessageHeaderByte(b uint8) bool {
 	return b == BrotliMessageHeaderByte
 }
 
+func IsZstdMessageHeaderByte(b uint8) bool {
+	return b == ZstdMessageHeaderByte
+}
+
 // IsKnownHeaderByte returns true if the supplied header byte has only known bits
 func IsKnownHeaderByte(b uint8) bool {
 	return b&^KnownHeaderBits == 0
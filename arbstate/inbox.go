@@ -14,6 +14,7 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/rlp"
 
 	"github.com/offchainlabs/nitro/arbcompress"
@@ -49,7 +50,20 @@ const MaxDecompressedLen int = 1024 * 1024 * 16 // 16 MiB
 const maxZeroheavyDecompressedLen = 101*MaxDecompressedLen/100 + 64
 const MaxSegmentsPerSequencerMessage = 100 * 1024
 
-func parseSequencerMessage(ctx context.Context, batchNum uint64, batchBlockHash common.Hash, data []byte, dapReaders []daprovider.Reader, keysetValidationMode daprovider.KeysetValidationMode) (*sequencerMessage, error) {
+// ArbosVersion_ZstdBatches is the first ArbOS version chains can be launched with to accept zstd-compressed batches.
+// It's a fixed version rather than derived from the versions this node supports, so that every release agrees on it,
+// and it can't change once a chain has launched at or above it. Zstd batches are experimental, so it's above every
+// ArbOS version released so far.
+const ArbosVersion_ZstdBatches uint64 = 40
+
+// ZstdBatchesEnabled returns true if batches of the chain may be zstd-compressed.
+// This is consensus critical: it must be the same for every node and validator of a chain, so it only depends
+// on the ArbOS version the chain was launched with.
+func ZstdBatchesEnabled(chainConfig *params.ChainConfig) bool {
+	return chainConfig != nil && chainConfig.ArbitrumChainParams.InitialArbOSVersion >= ArbosVersion_ZstdBatches
+}
+
+func parseSequencerMessage(ctx context.Context, batchNum uint64, batchBlockHash common.Hash, data []byte, dapReaders []daprovider.Reader, keysetValidationMode daprovider.KeysetValidationMode, zstdEnabled bool) (*sequencerMessage, error) {
 	if len(data) < 40 {
 		return nil, errors.New("sequencer message missing L1 header")
 	}
@@ -124,9 +138,15 @@ func parseSequencerMessage(ctx context.Context, batchNum uint64, batchBlockHash
 		payload = pl
 	}
 
-	// Stage 3: Decompress the brotli payload and fill the parsedMsg.segments list.
-	if len(payload) > 0 && daprovider.IsBrotliMessageHeaderByte(payload[0]) {
-		decompressed, err := arbcompress.Decompress(payload[1:], MaxDecompressedLen)
+	// Stage 3: Decompress the brotli (or, if enabled, zstd) payload and fill the parsedMsg.segments list.
+	if len(payload) > 0 && (daprovider.IsBrotliMessageHeaderByte(payload[0]) || (zstdEnabled && daprovider.IsZstdMessageHeaderByte(payload[0]))) {
+		var decompressed []byte
+		var err error
+		if daprovider.IsZstdMessageHeaderByte(payload[0]) {
+			decompressed, err = arbcompress.DecompressZstd(payload[1:], MaxDecompressedLen)
+		} else {
+			decompressed, err = arbcompress.Decompress(payload[1:], MaxDecompressedLen)
+		}
 		if err == nil {
 			reader := bytes.NewReader(decompressed)
 			stream := rlp.NewStream(reader, uint64(MaxDecompressedLen))
@@ -172,14 +192,16 @@ type inboxMultiplexer struct {
 	cachedSegmentBlockNumber  uint64
 	cachedSubMessageNumber    uint64
 	keysetValidationMode      daprovider.KeysetValidationMode
+	zstdEnabled               bool
 }
 
-func NewInboxMultiplexer(backend InboxBackend, delayedMessagesRead uint64, dapReaders []daprovider.Reader, keysetValidationMode daprovider.KeysetValidationMode) arbostypes.InboxMultiplexer {
+func NewInboxMultiplexer(backend InboxBackend, delayedMessagesRead uint64, dapReaders []daprovider.Reader, keysetValidationMode daprovider.KeysetValidationMode, zstdEnabled bool) arbostypes.InboxMultiplexer {
 	return &inboxMultiplexer{
 		backend:              backend,
 		delayedMessagesRead:  delayedMessagesRead,
 		dapReaders:           dapReaders,
 		keysetValidationMode: keysetValidationMode,
+		zstdEnabled:          zstdEnabled,
 	}
 }
 
@@ -200,7 +222,7 @@ func (r *inboxMultiplexer) Pop(ctx context.Context) (*arbostypes.MessageWithMeta
 		}
 		r.cachedSequencerMessageNum = r.backend.GetSequencerInboxPosition()
 		var err error
-		r.cachedSequencerMessage, err = parseSequencerMessage(ctx, r.cachedSequencerMessageNum, batchBlockHash, bytes, r.dapReaders, r.keysetValidationMode)
+		r.cachedSequencerMessage, err = parseSequencerMessage(ctx, r.cachedSequencerMessageNum, batchBlockHash, bytes, r.dapReaders, r.keysetValidationMode, r.zstdEnabled)
 		if err != nil {
 			return nil, err
 		}
@@ -0,0 +1,71 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+package arbstate
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/offchainlabs/nitro/arbcompress"
+	"github.com/offchainlabs/nitro/arbstate/daprovider"
+)
+
+func TestParseZstdSequencerMessage(t *testing.T) {
+	segment := append([]byte{BatchSegmentKindL2Message}, []byte("zstd compressed l2 message")...)
+	encoded, err := rlp.EncodeToBytes(segment)
+	if err != nil {
+		t.Fatal(err)
+	}
+	compressed, err := arbcompress.CompressZstd(encoded, arbcompress.ZstdDefaultLevel)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := make([]byte, 40)
+	data = append(data, daprovider.ZstdMessageHeaderByte)
+	data = append(data, compressed...)
+
+	parsed, err := parseSequencerMessage(context.Background(), 0, common.Hash{}, data, nil, daprovider.KeysetValidate, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(parsed.segments) != 1 || !bytes.Equal(parsed.segments[0], segment) {
+		t.Fatal("unexpected segments", parsed.segments)
+	}
+
+	// Chains that don't enable zstd treat the batch as having an unknown format
+	parsed, err = parseSequencerMessage(context.Background(), 0, common.Hash{}, data, nil, daprovider.KeysetValidate, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(parsed.segments) != 0 {
+		t.Fatal("zstd batch parsed with zstd disabled")
+	}
+}
+
+func TestZstdBatchesEnabled(t *testing.T) {
+	chainConfig := &params.ChainConfig{}
+	chainConfig.ArbitrumChainParams.InitialArbOSVersion = params.ArbosVersion_32
+	if ZstdBatchesEnabled(chainConfig) {
+		t.Fatal("zstd batches enabled on a chain launched with a released ArbOS version")
+	}
+	chainConfig.ArbitrumChainParams.InitialArbOSVersion = ArbosVersion_ZstdBatches - 1
+	if ZstdBatchesEnabled(chainConfig) {
+		t.Fatal("zstd batches enabled on a chain launched before ArbosVersion_ZstdBatches")
+	}
+	for _, version := range []uint64{ArbosVersion_ZstdBatches, ArbosVersion_ZstdBatches + 1} {
+		chainConfig.ArbitrumChainParams.InitialArbOSVersion = version
+		if !ZstdBatchesEnabled(chainConfig) {
+			t.Fatal("zstd batches disabled on a chain launched with ArbOS version", version)
+		}
+	}
+	// The zstd header byte doesn't change which authenticated header bytes are known, whether or not it's enabled
+	if daprovider.IsKnownHeaderByte(daprovider.L1AuthenticatedMessageHeaderFlag | daprovider.ZstdMessageHeaderByte) {
+		t.Fatal("zstd header bit is a known header bit")
+	}
+}
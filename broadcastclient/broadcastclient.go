@@ -37,6 +37,7 @@ import (
 var (
 	sourcesConnectedGauge    = metrics.NewRegisteredGauge("arb/feed/sources/connected", nil)
 	sourcesDisconnectedGauge = metrics.NewRegisteredGauge("arb/feed/sources/disconnected", nil)
+	invalidSignatureCounter  = metrics.NewRegisteredCounter("arb/feed/sources/invalidsignature", nil)
 )
 
 type FeedConfig struct {
@@ -45,6 +46,9 @@ type FeedConfig struct {
 }
 
 func (fc *FeedConfig) Validate() error {
+	if err := fc.Input.Validate(); err != nil {
+		return err
+	}
 	return fc.Output.Validate()
 }
 
@@ -72,12 +76,48 @@ type Config struct {
 	SecondaryURL            []string                 `koanf:"secondary-url"`
 	Verify                  signature.VerifierConfig `koanf:"verify"`
 	EnableCompression       bool                     `koanf:"enable-compression" reload:"hot"`
+	EnableBinaryEncoding    bool                     `koanf:"enable-binary-encoding" reload:"hot"`
+	Quorum                  QuorumConfig             `koanf:"quorum"`
 }
 
 func (c *Config) Enable() bool {
 	return len(c.URL) > 0 && c.URL[0] != ""
 }
 
+func (c *Config) Validate() error {
+	if !c.Quorum.Enable {
+		return nil
+	}
+	if c.Quorum.Required < 1 || c.Quorum.Required > len(c.URL) {
+		return fmt.Errorf("quorum.required must be between 1 and the number of feed urls (%d), got %d", len(c.URL), c.Quorum.Required)
+	}
+	if len(c.SecondaryURL) > 0 {
+		return errors.New("secondary-url can't be used with a quorum of feeds, list every feed in url instead")
+	}
+	if c.Verify.Dangerous.AcceptMissing {
+		// The quorum only protects against feeds that can't forge the sequencer's signature.
+		return errors.New("a quorum of feeds requires signed messages, disable verify.dangerous.accept-missing")
+	}
+	return nil
+}
+
+// QuorumConfig makes the node connect to all the feed URLs at once, and only accept a message once enough of
+// them agree on it, instead of trusting whichever feed is connected.
+type QuorumConfig struct {
+	Enable   bool `koanf:"enable"`
+	Required int  `koanf:"required"`
+}
+
+func QuorumConfigAddOptions(prefix string, f *flag.FlagSet) {
+	f.Bool(prefix+".enable", DefaultQuorumConfig.Enable, "connect to every feed url and only accept messages that enough of them agree on, dropping messages with invalid signatures instead of failing")
+	f.Int(prefix+".required", DefaultQuorumConfig.Required, "number of feeds that must send the same message for it to be accepted; should be more than half of the feed urls")
+}
+
+var DefaultQuorumConfig = QuorumConfig{
+	Enable:   false,
+	Required: 2,
+}
+
 type ConfigFetcher func() *Config
 
 func ConfigAddOptions(prefix string, f *flag.FlagSet) {
@@ -90,6 +130,8 @@ func ConfigAddOptions(prefix string, f *flag.FlagSet) {
 	f.StringSlice(prefix+".secondary-url", DefaultConfig.SecondaryURL, "list of secondary URLs of sequencer feed source. Would be started in the order they appear in the list when primary feeds fails")
 	signature.FeedVerifierConfigAddOptions(prefix+".verify", f)
 	f.Bool(prefix+".enable-compression", DefaultConfig.EnableCompression, "enable per message deflate compression support")
+	f.Bool(prefix+".enable-binary-encoding", DefaultConfig.EnableBinaryEncoding, "ask the feed server to send messages in the compact binary encoding, falling back to JSON if it doesn't support it")
+	QuorumConfigAddOptions(prefix+".quorum", f)
 }
 
 var DefaultConfig = Config{
@@ -102,6 +144,8 @@ var DefaultConfig = Config{
 	SecondaryURL:            []string{},
 	Timeout:                 20 * time.Second,
 	EnableCompression:       true,
+	EnableBinaryEncoding:    true,
+	Quorum:                  DefaultQuorumConfig,
 }
 
 var DefaultTestConfig = Config{
@@ -114,6 +158,8 @@ var DefaultTestConfig = Config{
 	SecondaryURL:            []string{},
 	Timeout:                 200 * time.Millisecond,
 	EnableCompression:       true,
+	EnableBinaryEncoding:    true,
+	Quorum:                  DefaultQuorumConfig,
 }
 
 type TransactionStreamerInterface interface {
@@ -130,10 +176,11 @@ type BroadcastClient struct {
 
 	chainId uint64
 
-	// Protects conn, shuttingDown and compression
+	// Protects conn, shuttingDown, compression and encoding
 	connMutex   sync.Mutex
 	conn        net.Conn
 	compression bool
+	encoding    m.Encoding
 
 	retryCount atomic.Int64
 
@@ -220,18 +267,23 @@ func (bc *BroadcastClient) connect(ctx context.Context, nextSeqNum arbutil.Messa
 		return nil, nil
 	}
 
-	header := ws.HandshakeHeaderHTTP(http.Header{
+	config := bc.config()
+	httpHeader := http.Header{
 		wsbroadcastserver.HTTPHeaderFeedClientVersion:       []string{strconv.Itoa(wsbroadcastserver.FeedClientVersion)},
 		wsbroadcastserver.HTTPHeaderRequestedSequenceNumber: []string{strconv.FormatUint(uint64(nextSeqNum), 10)},
-	})
+	}
+	if config.EnableBinaryEncoding {
+		httpHeader[wsbroadcastserver.HTTPHeaderFeedEncoding] = []string{m.EncodingBinary.String() + ", " + m.EncodingJSON.String()}
+	}
+	header := ws.HandshakeHeaderHTTP(httpHeader)
 
 	log.Info("connecting to arbitrum inbox message broadcaster", "url", bc.websocketUrl)
 	var foundChainId bool
 	var foundFeedServerVersion bool
 	var chainId uint64
 	var feedServerVersion uint64
+	encoding := m.EncodingJSON
 
-	config := bc.config()
 	var extensions []httphead.Option
 	deflateExt := wsflate.DefaultParameters.Option()
 	if config.EnableCompression {
@@ -274,6 +326,14 @@ func (bc *BroadcastClient) connect(ctx context.Context, nextSeqNum arbutil.Messa
 					)
 					return ErrIncorrectChainId
 				}
+			} else if headerName == wsbroadcastserver.HTTPHeaderFeedEncoding {
+				encoding, err = m.ParseEncoding(headerValue)
+				if err != nil {
+					return err
+				}
+				if encoding == m.EncodingBinary && !config.EnableBinaryEncoding {
+					return errors.New("feed server chose the binary encoding, but it is disabled")
+				}
 			}
 			return nil
 		},
@@ -359,8 +419,9 @@ func (bc *BroadcastClient) connect(ctx context.Context, nextSeqNum arbutil.Messa
 	bc.connMutex.Lock()
 	bc.conn = conn
 	bc.compression = compressionNegotiated
+	bc.encoding = encoding
 	bc.connMutex.Unlock()
-	log.Info("Feed connected", "feedServerVersion", feedServerVersion, "chainId", chainId, "requestedSeqNum", nextSeqNum)
+	log.Info("Feed connected", "feedServerVersion", feedServerVersion, "chainId", chainId, "requestedSeqNum", nextSeqNum, "encoding", encoding)
 
 	return earlyFrameData, nil
 }
@@ -418,7 +479,11 @@ func (bc *BroadcastClient) startBackgroundReader(earlyFrameData io.Reader) {
 
 			if msg != nil {
 				res := m.BroadcastMessage{}
-				err = json.Unmarshal(msg, &res)
+				if op == ws.OpBinary {
+					err = res.UnmarshalBinary(msg)
+				} else {
+					err = json.Unmarshal(msg, &res)
+				}
 				if err != nil {
 					log.Error("error unmarshalling message", "msg", msg, "err", err)
 					continue
@@ -439,6 +504,8 @@ func (bc *BroadcastClient) startBackgroundReader(earlyFrameData io.Reader) {
 				}
 				if res.Version == 1 {
 					if len(res.Messages) > 0 {
+						quorum := bc.config().Quorum.Enable
+						validMessages := make([]*m.BroadcastFeedMessage, 0, len(res.Messages))
 						for _, message := range res.Messages {
 							if message == nil {
 								log.Warn("ignoring nil feed message")
@@ -446,15 +513,25 @@ func (bc *BroadcastClient) startBackgroundReader(earlyFrameData io.Reader) {
 							}
 
 							err := bc.isValidSignature(ctx, message)
-							if err != nil {
+							if err != nil && quorum {
+								// With a quorum of feeds, a feed sending invalid signatures is outvoted rather than fatal.
+								invalidSignatureCounter.Inc(1)
+								log.Error("dropping feed message with invalid signature", "url", bc.websocketUrl, "error", err, "sequence number", message.SequenceNumber)
+								continue
+							} else if err != nil {
 								log.Error("error validating feed signature", "error", err, "sequence number", message.SequenceNumber)
 								bc.fatalErrChan <- fmt.Errorf("error validating feed signature %v: %w", message.SequenceNumber, err)
 								continue
 							}
 
 							bc.nextSeqNum = message.SequenceNumber + 1
+							validMessages = append(validMessages, message)
+						}
+						forwardMessages := res.Messages
+						if quorum {
+							forwardMessages = validMessages
 						}
-						if err := bc.txStreamer.AddBroadcastMessages(res.Messages); err != nil {
+						if err := bc.txStreamer.AddBroadcastMessages(forwardMessages); err != nil {
 							log.Error("Error adding message from Sequencer Feed", "err", err)
 						}
 					}
@@ -52,6 +52,57 @@ func TestReceiveMessages(t *testing.T) {
 	})
 }
 
+func TestReceiveMessagesBinaryEncoding(t *testing.T) {
+	t.Parallel()
+	t.Run("withBinaryEncoding", func(t *testing.T) {
+		testReceiveMessagesWithEncoding(t, true, true)
+	})
+	t.Run("withServerOnlyBinaryEncoding", func(t *testing.T) {
+		testReceiveMessagesWithEncoding(t, false, true)
+	})
+	t.Run("withClientOnlyBinaryEncoding", func(t *testing.T) {
+		testReceiveMessagesWithEncoding(t, true, false)
+	})
+}
+
+func testReceiveMessagesWithEncoding(t *testing.T, clientBinary bool, serverBinary bool) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	broadcasterConfig := wsbroadcastserver.DefaultTestBroadcasterConfig
+	broadcasterConfig.EnableBinaryEncoding = serverBinary
+
+	messageCount := 100
+	chainId := uint64(9742)
+
+	privateKey, err := crypto.GenerateKey()
+	Require(t, err)
+	sequencerAddr := crypto.PubkeyToAddress(privateKey.PublicKey)
+	dataSigner := signature.DataSignerFromPrivateKey(privateKey)
+
+	feedErrChan := make(chan error, 10)
+	b := broadcaster.NewBroadcaster(func() *wsbroadcastserver.BroadcasterConfig { return &broadcasterConfig }, chainId, feedErrChan, dataSigner)
+
+	Require(t, b.Initialize())
+	Require(t, b.Start(ctx))
+	defer b.StopAndWait()
+
+	config := DefaultTestConfig
+	config.EnableBinaryEncoding = clientBinary
+	var wg sync.WaitGroup
+	startMakeBroadcastClient(ctx, t, config, b.ListenerAddr(), 0, messageCount, chainId, &wg, &sequencerAddr)
+
+	go func() {
+		for i := 0; i < messageCount; i++ {
+			// #nosec G115
+			Require(t, b.BroadcastSingle(arbostypes.TestMessageWithMetadataAndRequestId, arbutil.MessageIndex(i), nil, nil))
+		}
+	}()
+
+	wg.Wait()
+}
+
 func testReceiveMessages(t *testing.T, clientCompression bool, serverCompression bool, serverRequire bool, expectNoMessagesReceived bool) {
 	t.Parallel()
 	ctx, cancel := context.WithCancel(context.Background())
@@ -784,6 +835,86 @@ func TestBroadcasterSendsCachedMessagesOnClientConnect(t *testing.T) {
 	}
 }
 
+func TestBroadcasterSendsPersistedMessagesOnClientConnect(t *testing.T) {
+	t.Parallel()
+	testBroadcasterSendsPersistedMessages(t, false, 0, 3, 9)
+}
+
+func TestBroadcasterLimitsPersistedCatchup(t *testing.T) {
+	t.Parallel()
+	testBroadcasterSendsPersistedMessages(t, true, 4, 3, 6)
+}
+
+func testBroadcasterSendsPersistedMessages(t *testing.T, limitCatchup bool, maxCatchup int, requestedSeqNum arbutil.MessageIndex, lastExpected arbutil.MessageIndex) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	settings := wsbroadcastserver.DefaultTestBroadcasterConfig
+	settings.Backlog.Persistent.Enable = true
+	settings.Backlog.Persistent.Directory = t.TempDir()
+	settings.Backlog.Persistent.Retention = time.Hour
+	settings.LimitCatchup = limitCatchup
+	settings.MaxCatchup = maxCatchup
+
+	privateKey, err := crypto.GenerateKey()
+	Require(t, err)
+	sequencerAddr := crypto.PubkeyToAddress(privateKey.PublicKey)
+	dataSigner := signature.DataSignerFromPrivateKey(privateKey)
+
+	feedErrChan := make(chan error, 10)
+	chainId := uint64(8744)
+	b := broadcaster.NewBroadcaster(func() *wsbroadcastserver.BroadcasterConfig { return &settings }, chainId, feedErrChan, dataSigner)
+
+	Require(t, b.Initialize())
+	Require(t, b.Start(ctx))
+	defer b.StopAndWait()
+
+	messageCount := 10
+	for i := 0; i < messageCount; i++ {
+		// #nosec G115
+		Require(t, b.BroadcastSingle(arbostypes.TestMessageWithMetadataAndRequestId, arbutil.MessageIndex(i), nil, nil))
+	}
+	// Confirming all the messages drops them from memory, so they can only be sent from disk.
+	// #nosec G115
+	b.Confirm(arbutil.MessageIndex(messageCount - 1))
+	updateTimer := time.NewTimer(2 * time.Second)
+	defer updateTimer.Stop()
+	for b.GetCachedMessageCount() != 0 {
+		select {
+		case <-updateTimer.C:
+			t.Fatal("cache did not get cleared")
+		default:
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	ts := NewDummyTransactionStreamer(chainId, &sequencerAddr)
+	broadcastClient, err := newTestBroadcastClient(DefaultTestConfig, b.ListenerAddr(), chainId, requestedSeqNum, ts, nil, feedErrChan, &sequencerAddr, t)
+	Require(t, err)
+	broadcastClient.Start(ctx)
+	defer broadcastClient.StopAndWait()
+
+	for expected := requestedSeqNum; expected <= lastExpected; expected++ {
+		timer := time.NewTimer(10 * time.Second)
+		select {
+		case receivedMsg := <-ts.messageReceiver:
+			if receivedMsg.SequenceNumber != expected {
+				t.Fatalf("expected message %d, got %d", expected, receivedMsg.SequenceNumber)
+			}
+		case err := <-feedErrChan:
+			t.Fatalf("feed error: %v", err)
+		case <-timer.C:
+			t.Fatalf("did not receive message %d", expected)
+		}
+		timer.Stop()
+	}
+	// No more than the catch-up limit is sent.
+	select {
+	case receivedMsg := <-ts.messageReceiver:
+		t.Fatalf("unexpected message %d past the catch-up limit", receivedMsg.SequenceNumber)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
 func connectAndGetCachedMessages(ctx context.Context, addr net.Addr, chainId uint64, t *testing.T, clientIndex int, feedErrChan chan error, sequencerAddr *common.Address, wg *sync.WaitGroup) {
 	ts := NewDummyTransactionStreamer(chainId, nil)
 	broadcastClient, err := newTestBroadcastClient(
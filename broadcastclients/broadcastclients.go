@@ -5,6 +5,7 @@ package broadcastclients
 
 import (
 	"context"
+	"fmt"
 	"sync/atomic"
 	"time"
 
@@ -48,6 +49,10 @@ type BroadcastClients struct {
 	primaryRouter   *Router
 	secondaryRouter *Router
 
+	// Only set in quorum mode, where every client is primary and the
+	// messages of all of them go through the quorum.
+	quorum *feedQuorum
+
 	// Use atomic access
 	connected atomic.Int32
 }
@@ -94,6 +99,31 @@ func NewBroadcastClients(
 		)
 	}
 
+	if config.Quorum.Enable {
+		if err := config.Validate(); err != nil {
+			return nil, err
+		}
+		clients.quorum = newFeedQuorum(config.Quorum.Required, config.URL, l2ChainId)
+		for i, address := range config.URL {
+			client, err := broadcastclient.NewBroadcastClient(
+				configFetcher,
+				address,
+				l2ChainId,
+				currentMessageCount,
+				&quorumSource{index: i, messageChan: clients.quorum.messageChan},
+				clients.quorum.confirmedChans[i],
+				fatalErrChan,
+				addrVerifier,
+				func(delta int32) { clients.adjustCount(delta) },
+			)
+			if err != nil {
+				return nil, fmt.Errorf("init broadcast client of quorum failed for %v: %w", address, err)
+			}
+			clients.primaryClients = append(clients.primaryClients, client)
+		}
+		return &clients, nil
+	}
+
 	var lastClientErr error
 	for _, address := range config.URL {
 		client, err := clients.makeClient(address, clients.primaryRouter)
@@ -139,6 +169,13 @@ func (bcs *BroadcastClients) Start(ctx context.Context) {
 		client.Start(ctx)
 	}
 
+	if bcs.quorum != nil {
+		bcs.primaryRouter.LaunchThread(func(ctx context.Context) {
+			bcs.quorum.run(ctx, bcs.primaryRouter)
+		})
+		return
+	}
+
 	var lastConfirmed arbutil.MessageIndex
 	recentFeedItemsNew := make(map[arbutil.MessageIndex]time.Time, RECENT_FEED_INITIAL_MAP_SIZE)
 	recentFeedItemsOld := make(map[arbutil.MessageIndex]time.Time, RECENT_FEED_INITIAL_MAP_SIZE)
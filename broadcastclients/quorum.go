@@ -0,0 +1,260 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+package broadcastclients
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+
+	"github.com/offchainlabs/nitro/arbutil"
+	m "github.com/offchainlabs/nitro/broadcaster/message"
+)
+
+var (
+	quorumForwardedCounter    = metrics.NewRegisteredCounter("arb/feed/quorum/forwarded", nil)
+	quorumEquivocationCounter = metrics.NewRegisteredCounter("arb/feed/quorum/equivocation", nil)
+	quorumExpiredCounter      = metrics.NewRegisteredCounter("arb/feed/quorum/expired", nil)
+)
+
+type quorumFeedMessage struct {
+	source  int
+	message m.BroadcastFeedMessage
+}
+
+type quorumConfirmation struct {
+	source         int
+	sequenceNumber arbutil.MessageIndex
+}
+
+// quorumSource is the TransactionStreamerInterface of one feed's client, tagging its messages with the feed they came from.
+type quorumSource struct {
+	index       int
+	messageChan chan<- quorumFeedMessage
+}
+
+func (s *quorumSource) AddBroadcastMessages(feedMessages []*m.BroadcastFeedMessage) error {
+	for _, feedMessage := range feedMessages {
+		s.messageChan <- quorumFeedMessage{source: s.index, message: *feedMessage}
+	}
+	return nil
+}
+
+// quorumVoteKey identifies what a feed sent for a sequence number. Besides the signed message, it covers the
+// block hash and metadata, which aren't signed but are forwarded along with the message.
+func quorumVoteKey(msg *m.BroadcastFeedMessage, chainId uint64) (common.Hash, error) {
+	hash, err := msg.Hash(chainId)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	blockHash := []byte{0}
+	if msg.BlockHash != nil {
+		blockHash = append([]byte{1}, msg.BlockHash.Bytes()...)
+	}
+	return crypto.Keccak256Hash(hash.Bytes(), blockHash, msg.BlockMetadata), nil
+}
+
+// quorumVotes are the hashes each feed sent for a sequence number that hasn't reached the quorum yet.
+type quorumVotes struct {
+	firstSeen time.Time
+	bySource  map[int]common.Hash
+	counts    map[common.Hash]int
+	messages  map[common.Hash]*m.BroadcastFeedMessage
+}
+
+// feedQuorum only forwards a message once the required number of feeds sent the same one, and reports the feeds
+// that send anything else. It isn't thread safe, and is only used by the router's thread.
+type feedQuorum struct {
+	required    int
+	urls        []string
+	chainId     uint64
+	messageChan chan quorumFeedMessage
+	// Each feed has its own confirmation channel, so the quorum knows which feed confirmed what.
+	confirmedChans   []chan arbutil.MessageIndex
+	confirmationChan chan quorumConfirmation
+	confirmed        map[int]arbutil.MessageIndex
+	lastConfirmed    arbutil.MessageIndex
+
+	pending map[arbutil.MessageIndex]*quorumVotes
+	// The hashes of the recently forwarded messages, to check the feeds that send them later. Like the recent feed
+	// items of the failover mode, these are kept in two buckets that are cycled.
+	forwardedNew map[arbutil.MessageIndex]common.Hash
+	forwardedOld map[arbutil.MessageIndex]common.Hash
+}
+
+func newFeedQuorum(required int, urls []string, chainId uint64) *feedQuorum {
+	confirmedChans := make([]chan arbutil.MessageIndex, len(urls))
+	for i := range confirmedChans {
+		confirmedChans[i] = make(chan arbutil.MessageIndex, ROUTER_QUEUE_SIZE)
+	}
+	return &feedQuorum{
+		required:         required,
+		urls:             urls,
+		chainId:          chainId,
+		messageChan:      make(chan quorumFeedMessage, ROUTER_QUEUE_SIZE),
+		confirmedChans:   confirmedChans,
+		confirmationChan: make(chan quorumConfirmation, ROUTER_QUEUE_SIZE),
+		confirmed:        make(map[int]arbutil.MessageIndex, len(urls)),
+		pending:          make(map[arbutil.MessageIndex]*quorumVotes),
+		forwardedNew:     make(map[arbutil.MessageIndex]common.Hash, RECENT_FEED_INITIAL_MAP_SIZE),
+		forwardedOld:     make(map[arbutil.MessageIndex]common.Hash, RECENT_FEED_INITIAL_MAP_SIZE),
+	}
+}
+
+func (q *feedQuorum) forwarded(seqNum arbutil.MessageIndex) (common.Hash, bool) {
+	if hash, ok := q.forwardedNew[seqNum]; ok {
+		return hash, true
+	}
+	hash, ok := q.forwardedOld[seqNum]
+	return hash, ok
+}
+
+func (q *feedQuorum) equivocation(source int, seqNum arbutil.MessageIndex, hash common.Hash, reason string) {
+	quorumEquivocationCounter.Inc(1)
+	log.Error("feed equivocated", "url", q.urls[source], "sequenceNumber", seqNum, "hash", hash, "reason", reason)
+}
+
+// add records the message a feed sent, and returns the message to forward if it just reached the quorum.
+func (q *feedQuorum) add(source int, msg *m.BroadcastFeedMessage, now time.Time) (*m.BroadcastFeedMessage, error) {
+	hash, err := quorumVoteKey(msg, q.chainId)
+	if err != nil {
+		return nil, err
+	}
+	seqNum := msg.SequenceNumber
+	if forwardedHash, ok := q.forwarded(seqNum); ok {
+		if forwardedHash != hash {
+			q.equivocation(source, seqNum, hash, "disagrees with the message forwarded by the quorum")
+		}
+		return nil, nil
+	}
+
+	votes, ok := q.pending[seqNum]
+	if !ok {
+		votes = &quorumVotes{
+			firstSeen: now,
+			bySource:  make(map[int]common.Hash),
+			counts:    make(map[common.Hash]int),
+			messages:  make(map[common.Hash]*m.BroadcastFeedMessage),
+		}
+		q.pending[seqNum] = votes
+	}
+	if previousHash, voted := votes.bySource[source]; voted {
+		if previousHash != hash {
+			q.equivocation(source, seqNum, hash, "sent conflicting messages")
+		}
+		return nil, nil
+	}
+	votes.bySource[source] = hash
+	votes.counts[hash]++
+	if _, ok := votes.messages[hash]; !ok {
+		votes.messages[hash] = msg
+	}
+	if votes.counts[hash] < q.required {
+		return nil, nil
+	}
+
+	delete(q.pending, seqNum)
+	q.forwardedNew[seqNum] = hash
+	for otherSource, otherHash := range votes.bySource {
+		if otherHash != hash {
+			q.equivocation(otherSource, seqNum, otherHash, "disagrees with the message forwarded by the quorum")
+		}
+	}
+	quorumForwardedCounter.Inc(1)
+	return votes.messages[hash], nil
+}
+
+// confirm records the sequence number a feed confirmed, and returns the highest sequence number that the required
+// number of feeds confirmed, if it just increased.
+func (q *feedQuorum) confirm(source int, seqNum arbutil.MessageIndex) (arbutil.MessageIndex, bool) {
+	q.confirmed[source] = seqNum
+	if len(q.confirmed) < q.required {
+		return 0, false
+	}
+	confirmations := make([]arbutil.MessageIndex, 0, len(q.confirmed))
+	for _, confirmed := range q.confirmed {
+		confirmations = append(confirmations, confirmed)
+	}
+	slices.Sort(confirmations)
+	quorumConfirmed := confirmations[len(confirmations)-q.required]
+	if quorumConfirmed <= q.lastConfirmed {
+		return 0, false
+	}
+	q.lastConfirmed = quorumConfirmed
+	return quorumConfirmed, true
+}
+
+// expire cycles the forwarded buckets, and drops the messages that didn't reach the quorum in time.
+func (q *feedQuorum) expire(now time.Time) {
+	q.forwardedOld = q.forwardedNew
+	q.forwardedNew = make(map[arbutil.MessageIndex]common.Hash, RECENT_FEED_INITIAL_MAP_SIZE)
+	for seqNum, votes := range q.pending {
+		if now.Sub(votes.firstSeen) < RECENT_FEED_ITEM_TTL {
+			continue
+		}
+		delete(q.pending, seqNum)
+		quorumExpiredCounter.Inc(1)
+		if len(votes.counts) > 1 {
+			// The feeds disagree, and not enough of them agree to know which are honest.
+			quorumEquivocationCounter.Inc(1)
+			log.Error("feeds disagree on message without a quorum", "sequenceNumber", seqNum, "hashes", len(votes.counts), "votes", len(votes.bySource), "required", q.required)
+		} else {
+			log.Debug("feed message expired without reaching the quorum", "sequenceNumber", seqNum, "votes", len(votes.bySource), "required", q.required)
+		}
+	}
+}
+
+func (q *feedQuorum) run(ctx context.Context, router *Router) {
+	for source, confirmedChan := range q.confirmedChans {
+		router.LaunchThread(func(ctx context.Context) {
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case cs := <-confirmedChan:
+					select {
+					case <-ctx.Done():
+						return
+					case q.confirmationChan <- quorumConfirmation{source: source, sequenceNumber: cs}:
+					}
+				}
+			}
+		})
+	}
+	expireTicker := time.NewTicker(RECENT_FEED_ITEM_TTL)
+	defer expireTicker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-expireTicker.C:
+			q.expire(time.Now())
+		case feedMsg := <-q.messageChan:
+			msg, err := q.add(feedMsg.source, &feedMsg.message, time.Now())
+			if err != nil {
+				log.Error("Error checking message from Sequencer Feed against the quorum", "url", q.urls[feedMsg.source], "err", err)
+				continue
+			}
+			if msg == nil {
+				continue
+			}
+			if err := router.forwardTxStreamer.AddBroadcastMessages([]*m.BroadcastFeedMessage{msg}); err != nil {
+				log.Error("Error routing message from Sequencer Feeds", "err", err)
+			}
+		case confirmation := <-q.confirmationChan:
+			cs, ok := q.confirm(confirmation.source, confirmation.sequenceNumber)
+			if !ok {
+				continue
+			}
+			if router.forwardConfirmationChan != nil {
+				router.forwardConfirmationChan <- cs
+			}
+		}
+	}
+}
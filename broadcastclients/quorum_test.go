@@ -0,0 +1,153 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+package broadcastclients
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/offchainlabs/nitro/arbos/arbostypes"
+	"github.com/offchainlabs/nitro/arbutil"
+	m "github.com/offchainlabs/nitro/broadcaster/message"
+)
+
+func testQuorumMessage(seqNum arbutil.MessageIndex, l2msg byte) *m.BroadcastFeedMessage {
+	return &m.BroadcastFeedMessage{
+		SequenceNumber: seqNum,
+		Message: arbostypes.MessageWithMetadata{
+			Message: &arbostypes.L1IncomingMessage{
+				Header: &arbostypes.L1IncomingMessageHeader{},
+				L2msg:  []byte{l2msg},
+			},
+		},
+	}
+}
+
+func TestFeedQuorum(t *testing.T) {
+	q := newFeedQuorum(2, []string{"ws://a", "ws://b", "ws://c"}, 412346)
+	now := time.Now()
+	add := func(source int, msg *m.BroadcastFeedMessage) *m.BroadcastFeedMessage {
+		t.Helper()
+		forwarded, err := q.add(source, msg, now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return forwarded
+	}
+	equivocations := func() int64 {
+		return quorumEquivocationCounter.Snapshot().Count()
+	}
+
+	// A message is only forwarded once two feeds agree on it.
+	if add(0, testQuorumMessage(1, 1)) != nil {
+		t.Fatal("forwarded a message sent by a single feed")
+	}
+	if add(0, testQuorumMessage(1, 1)) != nil {
+		t.Fatal("forwarded a message sent twice by the same feed")
+	}
+	if forwarded := add(1, testQuorumMessage(1, 1)); forwarded == nil || forwarded.SequenceNumber != 1 {
+		t.Fatal("didn't forward a message two feeds agree on", forwarded)
+	}
+	if add(2, testQuorumMessage(1, 1)) != nil {
+		t.Fatal("forwarded a message twice")
+	}
+
+	// A feed disagreeing with the forwarded message equivocates.
+	before := equivocations()
+	if add(2, testQuorumMessage(1, 2)) != nil {
+		t.Fatal("forwarded a conflicting message")
+	}
+	if equivocations() != before+1 {
+		t.Fatal("didn't report a feed disagreeing with the quorum")
+	}
+
+	// The minority is reported once the quorum is reached.
+	before = equivocations()
+	add(0, testQuorumMessage(2, 9))
+	add(1, testQuorumMessage(2, 1))
+	if forwarded := add(2, testQuorumMessage(2, 1)); forwarded == nil || forwarded.Message.Message.L2msg[0] != 1 {
+		t.Fatal("didn't forward the message the majority agrees on", forwarded)
+	}
+	if equivocations() != before+1 {
+		t.Fatal("didn't report the feed outvoted by the quorum")
+	}
+
+	// A feed sending two different messages for the same sequence number equivocates.
+	before = equivocations()
+	add(0, testQuorumMessage(3, 1))
+	add(0, testQuorumMessage(3, 2))
+	if equivocations() != before+1 {
+		t.Fatal("didn't report a feed sending conflicting messages")
+	}
+
+	// Messages that don't reach the quorum expire.
+	expiredBefore := quorumExpiredCounter.Snapshot().Count()
+	q.expire(now.Add(RECENT_FEED_ITEM_TTL))
+	if len(q.pending) != 0 {
+		t.Fatal("pending messages didn't expire", len(q.pending))
+	}
+	if quorumExpiredCounter.Snapshot().Count() != expiredBefore+1 {
+		t.Fatal("unexpected expired count")
+	}
+}
+
+func TestFeedQuorumUnsignedFields(t *testing.T) {
+	q := newFeedQuorum(2, []string{"ws://a", "ws://b", "ws://c"}, 412346)
+	now := time.Now()
+	withBlock := func(blockHash common.Hash, metadata []byte) *m.BroadcastFeedMessage {
+		msg := testQuorumMessage(1, 1)
+		msg.BlockHash = &blockHash
+		msg.BlockMetadata = metadata
+		return msg
+	}
+
+	// Feeds agreeing on the signed message but not on the block hash or metadata don't reach the quorum.
+	for source, msg := range []*m.BroadcastFeedMessage{
+		withBlock(common.Hash{1}, []byte{0}),
+		withBlock(common.Hash{2}, []byte{0}),
+		withBlock(common.Hash{1}, []byte{0, 1}),
+	} {
+		forwarded, err := q.add(source, msg, now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if forwarded != nil {
+			t.Fatal("forwarded a message the feeds disagree on", source)
+		}
+	}
+
+	q = newFeedQuorum(2, []string{"ws://a", "ws://b", "ws://c"}, 412346)
+	if forwarded, err := q.add(0, withBlock(common.Hash{1}, []byte{0}), now); err != nil || forwarded != nil {
+		t.Fatal("unexpected forward", forwarded, err)
+	}
+	forwarded, err := q.add(1, withBlock(common.Hash{1}, []byte{0}), now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if forwarded == nil || *forwarded.BlockHash != (common.Hash{1}) {
+		t.Fatal("didn't forward the block hash the feeds agree on", forwarded)
+	}
+}
+
+func TestFeedQuorumConfirmations(t *testing.T) {
+	q := newFeedQuorum(2, []string{"ws://a", "ws://b", "ws://c"}, 412346)
+	confirm := func(source int, seqNum arbutil.MessageIndex, expected arbutil.MessageIndex, expectedOk bool) {
+		t.Helper()
+		confirmed, ok := q.confirm(source, seqNum)
+		if ok != expectedOk || confirmed != expected {
+			t.Fatalf("feed %d confirming %d: got %d %v, expected %d %v", source, seqNum, confirmed, ok, expected, expectedOk)
+		}
+	}
+
+	// A single feed can't confirm messages.
+	confirm(0, 100, 0, false)
+	// Two feeds confirm up to the lower of their confirmations.
+	confirm(1, 50, 50, true)
+	confirm(2, 70, 70, true)
+	// The confirmation doesn't go back when a feed confirms less.
+	confirm(2, 60, 0, false)
+	confirm(1, 120, 100, true)
+}
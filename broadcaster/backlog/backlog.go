@@ -31,6 +31,9 @@ type Backlog interface {
 	Get(uint64, uint64) (*m.BroadcastMessage, error)
 	Count() uint64
 	Lookup(uint64) (BacklogSegment, error)
+	GetPersisted(uint64, uint64) ([]*m.BroadcastFeedMessage, error)
+	Open() error
+	Close() error
 }
 
 // backlog stores backlogSegments and provides the ability to read/write
@@ -41,6 +44,7 @@ type backlog struct {
 	lookupByIndex atomic.Pointer[containers.SyncMap[uint64, *backlogSegment]]
 	config        ConfigFetcher
 	messageCount  atomic.Uint64
+	persistent    atomic.Pointer[persistentStore]
 }
 
 // NewBacklog creates a backlog.
@@ -52,6 +56,40 @@ func NewBacklog(c ConfigFetcher) Backlog {
 	return b
 }
 
+// Open opens the persistent backlog, if enabled. It must be called before
+// messages are appended.
+func (b *backlog) Open() error {
+	if !b.config().Persistent.Enable || b.persistent.Load() != nil {
+		return nil
+	}
+	store, err := openPersistentStore(func() *PersistentConfig { return &b.config().Persistent })
+	if err != nil {
+		return err
+	}
+	b.persistent.Store(store)
+	return nil
+}
+
+// Close closes the persistent backlog, if open.
+func (b *backlog) Close() error {
+	store := b.persistent.Swap(nil)
+	if store == nil {
+		return nil
+	}
+	return store.close()
+}
+
+// GetPersisted reads messages from the given start to end MessageIndex from
+// the persistent backlog. Fewer messages are returned if end is past the last
+// persisted message before a gap in sequence numbers.
+func (b *backlog) GetPersisted(start, end uint64) ([]*m.BroadcastFeedMessage, error) {
+	store := b.persistent.Load()
+	if store == nil {
+		return nil, errOutOfBounds
+	}
+	return store.get(start, end)
+}
+
 // Head return the head backlogSegment within the backlog.
 func (b *backlog) Head() BacklogSegment {
 	return b.head.Load()
@@ -101,6 +139,19 @@ func (b *backlog) Append(bm *m.BroadcastMessage) error {
 			// #nosec G115
 			backlogSizeInBytesGauge.Update(int64(size))
 		}
+		if store := b.persistent.Load(); store != nil {
+			if err := store.prune(uint64(bm.ConfirmedSequenceNumberMessage.SequenceNumber)); err != nil {
+				log.Error("error pruning persistent backlog", "err", err)
+			}
+		}
+	}
+
+	// A failure to persist only loses catch-up from disk, so it doesn't stop
+	// the messages being broadcast.
+	if store := b.persistent.Load(); store != nil && len(bm.Messages) > 0 {
+		if err := store.append(bm.Messages); err != nil {
+			log.Error("error appending messages to persistent backlog", "err", err)
+		}
 	}
 
 	lookupByIndex := b.lookupByIndex.Load()
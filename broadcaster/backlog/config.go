@@ -1,24 +1,65 @@
 package backlog
 
 import (
+	"errors"
+	"time"
+
 	flag "github.com/spf13/pflag"
 )
 
 type ConfigFetcher func() *Config
 
 type Config struct {
-	SegmentLimit int `koanf:"segment-limit" reload:"hot"`
+	SegmentLimit int              `koanf:"segment-limit" reload:"hot"`
+	Persistent   PersistentConfig `koanf:"persistent"`
+}
+
+func (c *Config) Validate() error {
+	return c.Persistent.Validate()
 }
 
 func AddOptions(prefix string, f *flag.FlagSet) {
 	f.Int(prefix+".segment-limit", DefaultConfig.SegmentLimit, "the maximum number of messages each segment within the backlog can contain")
+	PersistentConfigAddOptions(prefix+".persistent", f)
 }
 
 var (
 	DefaultConfig = Config{
 		SegmentLimit: 240,
+		Persistent:   DefaultPersistentConfig,
 	}
 	DefaultTestConfig = Config{
 		SegmentLimit: 3,
+		Persistent:   DefaultPersistentConfig,
 	}
 )
+
+// PersistentConfig configures keeping the backlog on disk as well, so clients can catch up from messages that
+// have already been confirmed and dropped from memory.
+type PersistentConfig struct {
+	Enable    bool          `koanf:"enable"`
+	Directory string        `koanf:"directory"`
+	Retention time.Duration `koanf:"retention" reload:"hot"`
+}
+
+func (c *PersistentConfig) Validate() error {
+	if c.Enable && c.Directory == "" {
+		return errors.New("backlog.persistent.directory must be set when the persistent backlog is enabled")
+	}
+	if c.Retention < 0 {
+		return errors.New("backlog.persistent.retention must not be negative")
+	}
+	return nil
+}
+
+func PersistentConfigAddOptions(prefix string, f *flag.FlagSet) {
+	f.Bool(prefix+".enable", DefaultPersistentConfig.Enable, "also keep the backlog on disk, to serve catch-up from messages no longer in memory")
+	f.String(prefix+".directory", DefaultPersistentConfig.Directory, "directory of the database the backlog is kept in")
+	f.Duration(prefix+".retention", DefaultPersistentConfig.Retention, "how long messages are kept on disk; messages are only pruned once confirmed")
+}
+
+var DefaultPersistentConfig = PersistentConfig{
+	Enable:    false,
+	Directory: "",
+	Retention: 24 * time.Hour,
+}
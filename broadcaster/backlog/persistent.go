@@ -0,0 +1,325 @@
+package backlog
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/node"
+
+	m "github.com/offchainlabs/nitro/broadcaster/message"
+	"github.com/offchainlabs/nitro/cmd/conf"
+)
+
+var (
+	// persistedMessagePrefix is followed by the big endian sequence number of the message.
+	persistedMessagePrefix = []byte("m")
+	// persistedRangePrefix is followed by the big endian sequence number of the first message of a contiguous
+	// range of stored messages, and its value is the big endian sequence number of the last one.
+	persistedRangePrefix = []byte("r")
+
+	persistedMessagesGauge = metrics.NewRegisteredGauge("arb/feed/backlog/persistent/messages", nil)
+	persistedPrunedCounter = metrics.NewRegisteredCounter("arb/feed/backlog/persistent/pruned", nil)
+)
+
+type persistedRange struct {
+	first uint64
+	last  uint64
+}
+
+// persistentStore keeps feed messages on disk, in contiguous ranges that are split where the feed skipped
+// sequence numbers. Each message is stored with the time it was stored at, and is only pruned once confirmed
+// and older than the retention.
+type persistentStore struct {
+	db     ethdb.Database
+	config func() *PersistentConfig
+
+	// mutex guards the ranges of stored messages, and is held while writing so the ranges match the database.
+	mutex  sync.RWMutex
+	ranges []persistedRange
+	count  uint64
+}
+
+func openPersistentStore(config func() *PersistentConfig) (*persistentStore, error) {
+	db, err := node.NewPebbleDBDatabase(config().Directory, 0, 0, "arb/feed/backlog/persistent/", false, conf.PersistentConfigDefault.Pebble.ExtraOptions("feed-backlog"))
+	if err != nil {
+		return nil, fmt.Errorf("error opening persistent backlog database: %w", err)
+	}
+	s := &persistentStore{
+		db:     db,
+		config: config,
+	}
+	iter := db.NewIterator(persistedRangePrefix, nil)
+	defer iter.Release()
+	for iter.Next() {
+		if len(iter.Value()) != 8 {
+			db.Close()
+			return nil, fmt.Errorf("invalid range of the persistent backlog %x", iter.Key())
+		}
+		r := persistedRange{
+			first: binary.BigEndian.Uint64(iter.Key()[len(persistedRangePrefix):]),
+			last:  binary.BigEndian.Uint64(iter.Value()),
+		}
+		s.ranges = append(s.ranges, r)
+		s.count += r.last - r.first + 1
+	}
+	if err := iter.Error(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	// #nosec G115
+	persistedMessagesGauge.Update(int64(s.count))
+	log.Info("opened persistent feed backlog", "directory", config().Directory, "ranges", len(s.ranges), "count", s.count)
+	return s, nil
+}
+
+func persistedRangeKey(first uint64) []byte {
+	return binary.BigEndian.AppendUint64(common.CopyBytes(persistedRangePrefix), first)
+}
+
+func putPersistedRange(batch ethdb.Batch, r persistedRange) error {
+	return batch.Put(persistedRangeKey(r.first), binary.BigEndian.AppendUint64(nil, r.last))
+}
+
+func persistedMessageKey(seqNum uint64) []byte {
+	return binary.BigEndian.AppendUint64(common.CopyBytes(persistedMessagePrefix), seqNum)
+}
+
+func persistedSequenceNumber(key []byte) uint64 {
+	return binary.BigEndian.Uint64(key[len(persistedMessagePrefix):])
+}
+
+// encodePersistedMessage prefixes the binary encoding of the message with the unix time it was stored at.
+func encodePersistedMessage(storedAt time.Time, msg *m.BroadcastFeedMessage) ([]byte, error) {
+	bm := m.BroadcastMessage{
+		Version:  m.V1,
+		Messages: []*m.BroadcastFeedMessage{msg},
+	}
+	encoded, err := bm.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	// #nosec G115
+	return append(binary.BigEndian.AppendUint64(nil, uint64(storedAt.Unix())), encoded...), nil
+}
+
+func persistedStoredAt(value []byte) (time.Time, error) {
+	if len(value) < 8 {
+		return time.Time{}, errors.New("persisted feed message too short")
+	}
+	// #nosec G115
+	return time.Unix(int64(binary.BigEndian.Uint64(value[:8])), 0), nil
+}
+
+func decodePersistedMessage(value []byte) (*m.BroadcastFeedMessage, error) {
+	if len(value) < 8 {
+		return nil, errors.New("persisted feed message too short")
+	}
+	var bm m.BroadcastMessage
+	if err := bm.UnmarshalBinary(value[8:]); err != nil {
+		return nil, err
+	}
+	if len(bm.Messages) != 1 {
+		return nil, fmt.Errorf("persisted feed message has %d messages", len(bm.Messages))
+	}
+	return bm.Messages[0], nil
+}
+
+// append stores the messages after the last stored message. Messages already stored are ignored. Unlike in the
+// in-memory backlog, a gap in sequence numbers keeps the stored messages and starts a new range after the gap,
+// so clients can still catch up from before it.
+func (s *persistentStore) append(msgs []*m.BroadcastFeedMessage) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var tail persistedRange
+	hasTail := len(s.ranges) > 0
+	if hasTail {
+		tail = s.ranges[len(s.ranges)-1]
+	}
+	var newRanges []persistedRange
+	batch := s.db.NewBatch()
+	now := time.Now()
+	stored := uint64(0)
+	for _, msg := range msgs {
+		seqNum := uint64(msg.SequenceNumber)
+		if hasTail && seqNum <= tail.last {
+			continue
+		}
+		if hasTail && seqNum > tail.last+1 {
+			log.Warn("gap in persisted feed backlog, starting a new range", "last", tail.last, "sequenceNumber", seqNum)
+			if err := putPersistedRange(batch, tail); err != nil {
+				return err
+			}
+			newRanges = append(newRanges, tail)
+			hasTail = false
+		}
+		value, err := encodePersistedMessage(now, msg)
+		if err != nil {
+			return err
+		}
+		if err := batch.Put(persistedMessageKey(seqNum), value); err != nil {
+			return err
+		}
+		if !hasTail {
+			tail = persistedRange{first: seqNum}
+			hasTail = true
+		}
+		tail.last = seqNum
+		stored++
+	}
+	if stored == 0 {
+		return nil
+	}
+	if err := putPersistedRange(batch, tail); err != nil {
+		return err
+	}
+	if err := batch.Write(); err != nil {
+		return err
+	}
+	newRanges = append(newRanges, tail)
+	// The first new range may extend the last stored one.
+	if len(s.ranges) > 0 && s.ranges[len(s.ranges)-1].first == newRanges[0].first {
+		s.ranges = s.ranges[:len(s.ranges)-1]
+	}
+	s.ranges = append(s.ranges, newRanges...)
+	s.count += stored
+	// #nosec G115
+	persistedMessagesGauge.Update(int64(s.count))
+	return nil
+}
+
+// get reads the stored messages from start up to end. Fewer messages are returned if end is past the last
+// message of the range start is in, and errOutOfBounds if start isn't stored.
+func (s *persistentStore) get(start, end uint64) ([]*m.BroadcastFeedMessage, error) {
+	s.mutex.RLock()
+	i := sort.Search(len(s.ranges), func(i int) bool { return s.ranges[i].last >= start })
+	var r persistedRange
+	found := i < len(s.ranges) && s.ranges[i].first <= start
+	if found {
+		r = s.ranges[i]
+	}
+	s.mutex.RUnlock()
+	if !found {
+		return nil, errOutOfBounds
+	}
+	end = min(end, r.last)
+
+	iter := s.db.NewIterator(persistedMessagePrefix, binary.BigEndian.AppendUint64(nil, start))
+	defer iter.Release()
+	msgs := make([]*m.BroadcastFeedMessage, 0, end-start+1)
+	for expected := start; expected <= end && iter.Next(); expected++ {
+		// Messages may have been pruned since the range was read.
+		if persistedSequenceNumber(iter.Key()) != expected {
+			break
+		}
+		msg, err := decodePersistedMessage(iter.Value())
+		if err != nil {
+			return nil, fmt.Errorf("error decoding persisted feed message %d: %w", expected, err)
+		}
+		msgs = append(msgs, msg)
+	}
+	if err := iter.Error(); err != nil {
+		return nil, err
+	}
+	if len(msgs) == 0 {
+		return nil, errOutOfBounds
+	}
+	return msgs, nil
+}
+
+// prunedRanges returns the ranges left once the messages up to and including lastPruned are deleted, and
+// updates the range keys in the batch accordingly.
+func (s *persistentStore) prunedRanges(batch ethdb.Batch, lastPruned uint64) ([]persistedRange, error) {
+	ranges := s.ranges
+	for len(ranges) > 0 && ranges[0].first <= lastPruned {
+		r := ranges[0]
+		if err := batch.Delete(persistedRangeKey(r.first)); err != nil {
+			return nil, err
+		}
+		if r.last > lastPruned {
+			r.first = lastPruned + 1
+			if err := putPersistedRange(batch, r); err != nil {
+				return nil, err
+			}
+			return append([]persistedRange{r}, ranges[1:]...), nil
+		}
+		ranges = ranges[1:]
+	}
+	return ranges, nil
+}
+
+// prune deletes the messages up to and including confirmed that were stored longer than the retention ago.
+func (s *persistentStore) prune(confirmed uint64) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if len(s.ranges) == 0 || confirmed < s.ranges[0].first {
+		return nil
+	}
+
+	cutoff := time.Now().Add(-s.config().Retention)
+	iter := s.db.NewIterator(persistedMessagePrefix, nil)
+	defer iter.Release()
+	batch := s.db.NewBatch()
+	pruned := uint64(0)
+	var lastPruned uint64
+	write := func() error {
+		ranges, err := s.prunedRanges(batch, lastPruned)
+		if err != nil {
+			return err
+		}
+		if err := batch.Write(); err != nil {
+			return err
+		}
+		batch.Reset()
+		s.ranges = ranges
+		s.count -= pruned
+		// #nosec G115
+		persistedPrunedCounter.Inc(int64(pruned))
+		// #nosec G115
+		persistedMessagesGauge.Update(int64(s.count))
+		pruned = 0
+		return nil
+	}
+	for iter.Next() {
+		seqNum := persistedSequenceNumber(iter.Key())
+		if seqNum > confirmed {
+			break
+		}
+		storedAt, err := persistedStoredAt(iter.Value())
+		if err != nil {
+			return err
+		}
+		if storedAt.After(cutoff) {
+			break
+		}
+		if err := batch.Delete(common.CopyBytes(iter.Key())); err != nil {
+			return err
+		}
+		lastPruned = seqNum
+		pruned++
+		if batch.ValueSize() >= ethdb.IdealBatchSize {
+			if err := write(); err != nil {
+				return err
+			}
+		}
+	}
+	if err := iter.Error(); err != nil {
+		return err
+	}
+	if pruned == 0 {
+		return nil
+	}
+	return write()
+}
+
+func (s *persistentStore) close() error {
+	return s.db.Close()
+}
@@ -0,0 +1,150 @@
+package backlog
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/offchainlabs/nitro/arbutil"
+	m "github.com/offchainlabs/nitro/broadcaster/message"
+)
+
+func newTestPersistentBacklog(t *testing.T, dir string, retention time.Duration) *backlog {
+	t.Helper()
+	config := DefaultTestConfig
+	config.Persistent = PersistentConfig{
+		Enable:    true,
+		Directory: dir,
+		Retention: retention,
+	}
+	b, ok := NewBacklog(func() *Config { return &config }).(*backlog)
+	if !ok {
+		t.Fatal("NewBacklog did not return a *backlog")
+	}
+	if err := b.Open(); err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func validatePersisted(t *testing.T, b *backlog, start, end uint64) {
+	t.Helper()
+	msgs, err := b.GetPersisted(start, end)
+	if err != nil {
+		t.Fatalf("error getting persisted messages %d to %d: %v", start, end, err)
+	}
+	if uint64(len(msgs)) != end-start+1 {
+		t.Fatalf("expected %d persisted messages, got %d", end-start+1, len(msgs))
+	}
+	for i, msg := range msgs {
+		if uint64(msg.SequenceNumber) != start+uint64(i) {
+			t.Fatalf("unexpected sequence number (%d) in %d returned message", msg.SequenceNumber, i)
+		}
+	}
+}
+
+func TestPersistentBacklog(t *testing.T) {
+	dir := t.TempDir()
+	b := newTestPersistentBacklog(t, dir, 0)
+	indexes := []arbutil.MessageIndex{40, 41, 42, 43, 44, 45, 46}
+	if err := b.Append(m.CreateDummyBroadcastMessage(indexes)); err != nil {
+		t.Fatal(err)
+	}
+	validatePersisted(t, b, 40, 46)
+
+	// End past the last message returns the messages up to the last one.
+	validatePersisted(t, b, 44, 100)
+	if _, err := b.GetPersisted(47, 50); !errors.Is(err, errOutOfBounds) {
+		t.Fatalf("expected errOutOfBounds reading past the persisted messages, got %v", err)
+	}
+
+	// Messages already persisted are ignored.
+	if err := b.Append(m.CreateDummyBroadcastMessage([]arbutil.MessageIndex{45, 46, 47})); err != nil {
+		t.Fatal(err)
+	}
+	validatePersisted(t, b, 40, 47)
+
+	// Confirmation prunes the persisted messages as well as the in-memory ones.
+	if err := b.Append(&m.BroadcastMessage{ConfirmedSequenceNumberMessage: &m.ConfirmedSequenceNumberMessage{SequenceNumber: 42}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.GetPersisted(42, 42); !errors.Is(err, errOutOfBounds) {
+		t.Fatalf("expected errOutOfBounds reading a pruned message, got %v", err)
+	}
+	validatePersisted(t, b, 43, 47)
+
+	// The messages are still there after reopening.
+	if err := b.Close(); err != nil {
+		t.Fatal(err)
+	}
+	b = newTestPersistentBacklog(t, dir, 0)
+	if b.Count() != 0 {
+		t.Fatalf("expected an empty in-memory backlog after reopening, got %d messages", b.Count())
+	}
+	validatePersisted(t, b, 43, 47)
+
+	// A gap keeps the persisted messages before it, and starts a new range.
+	if err := b.Append(m.CreateDummyBroadcastMessage([]arbutil.MessageIndex{50, 51})); err != nil {
+		t.Fatal(err)
+	}
+	validatePersisted(t, b, 43, 47)
+	validatePersisted(t, b, 50, 51)
+	// Reading stops at the end of the range, and the gap isn't stored.
+	validatePersisted(t, b, 45, 51)
+	if _, err := b.GetPersisted(48, 51); !errors.Is(err, errOutOfBounds) {
+		t.Fatalf("expected errOutOfBounds reading messages in a gap, got %v", err)
+	}
+	if err := b.Append(m.CreateDummyBroadcastMessage([]arbutil.MessageIndex{52, 55})); err != nil {
+		t.Fatal(err)
+	}
+
+	// The ranges are still there after reopening.
+	if err := b.Close(); err != nil {
+		t.Fatal(err)
+	}
+	b = newTestPersistentBacklog(t, dir, 0)
+	validatePersisted(t, b, 43, 47)
+	validatePersisted(t, b, 50, 52)
+	validatePersisted(t, b, 55, 55)
+
+	// Pruning past a gap drops the whole range before it, and trims the range it stops in.
+	if err := b.Append(&m.BroadcastMessage{ConfirmedSequenceNumberMessage: &m.ConfirmedSequenceNumberMessage{SequenceNumber: 50}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.GetPersisted(47, 47); !errors.Is(err, errOutOfBounds) {
+		t.Fatalf("expected errOutOfBounds reading a pruned message, got %v", err)
+	}
+	if _, err := b.GetPersisted(50, 50); !errors.Is(err, errOutOfBounds) {
+		t.Fatalf("expected errOutOfBounds reading a pruned message, got %v", err)
+	}
+	validatePersisted(t, b, 51, 52)
+	validatePersisted(t, b, 55, 55)
+	if err := b.Close(); err != nil {
+		t.Fatal(err)
+	}
+	b = newTestPersistentBacklog(t, dir, 0)
+	validatePersisted(t, b, 51, 52)
+	validatePersisted(t, b, 55, 55)
+	if err := b.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPersistentBacklogRetention(t *testing.T) {
+	b := newTestPersistentBacklog(t, t.TempDir(), time.Hour)
+	defer b.Close()
+	indexes := []arbutil.MessageIndex{40, 41, 42, 43, 44}
+	if err := b.Append(m.CreateDummyBroadcastMessage(indexes)); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Append(&m.BroadcastMessage{ConfirmedSequenceNumberMessage: &m.ConfirmedSequenceNumberMessage{SequenceNumber: 43}}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The confirmed messages are dropped from memory but kept on disk until
+	// they're older than the retention.
+	if b.Count() != 1 {
+		t.Fatalf("expected 1 message left in memory, got %d", b.Count())
+	}
+	validatePersisted(t, b, 40, 44)
+}
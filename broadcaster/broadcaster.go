@@ -152,6 +152,9 @@ func (b *Broadcaster) GetCachedMessageCount() int {
 }
 
 func (b *Broadcaster) Initialize() error {
+	if err := b.backlog.Open(); err != nil {
+		return err
+	}
 	return b.server.Initialize()
 }
 
@@ -165,6 +168,9 @@ func (b *Broadcaster) StartWithHeader(ctx context.Context, header ws.HandshakeHe
 
 func (b *Broadcaster) StopAndWait() {
 	b.server.StopAndWait()
+	if err := b.backlog.Close(); err != nil {
+		log.Warn("error closing feed backlog", "err", err)
+	}
 }
 
 func (b *Broadcaster) Started() bool {
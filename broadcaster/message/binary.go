@@ -0,0 +1,294 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+package message
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/offchainlabs/nitro/arbos/arbostypes"
+	"github.com/offchainlabs/nitro/arbutil"
+)
+
+// Encoding is the wire encoding of BroadcastMessages, negotiated when a feed client connects.
+type Encoding uint8
+
+const (
+	EncodingJSON Encoding = iota
+	EncodingBinary
+)
+
+func (e Encoding) String() string {
+	switch e {
+	case EncodingJSON:
+		return "json"
+	case EncodingBinary:
+		return "binary"
+	default:
+		return fmt.Sprintf("unknown(%d)", e)
+	}
+}
+
+func ParseEncoding(s string) (Encoding, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "json":
+		return EncodingJSON, nil
+	case "binary":
+		return EncodingBinary, nil
+	default:
+		return 0, fmt.Errorf("unknown feed encoding %q", s)
+	}
+}
+
+// binaryFormatVersion is the first byte of every binary encoded BroadcastMessage.
+const binaryFormatVersion = byte(1)
+
+var ErrInvalidBinaryMessage = errors.New("invalid binary broadcast message")
+
+// MarshalBinary encodes the message in the compact binary feed encoding. Unlike JSON, it keeps
+// the difference between nil and empty fields, so a decoded message is identical to the original.
+//
+// Every message is a format version byte followed by the fields in order. Integers are uvarints,
+// optional fields are preceded by a presence byte, and byte slices by their length plus one, 0 meaning nil.
+func (bm *BroadcastMessage) MarshalBinary() ([]byte, error) {
+	if bm.Version < 0 {
+		return nil, fmt.Errorf("can't encode negative version %d", bm.Version)
+	}
+	size := uint64(16)
+	for _, msg := range bm.Messages {
+		// Not msg.Size(), which doesn't allow for a feed message without an L1 incoming message
+		if msg != nil {
+			// #nosec G115
+			size += uint64(len(msg.Signature)) + 160
+			if msg.Message.Message != nil {
+				// #nosec G115
+				size += uint64(len(msg.Message.Message.L2msg))
+			}
+		}
+	}
+	buf := make([]byte, 0, size)
+	buf = append(buf, binaryFormatVersion)
+	buf = binary.AppendUvarint(buf, uint64(bm.Version))
+	buf = binary.AppendUvarint(buf, uint64(len(bm.Messages)))
+	for i, msg := range bm.Messages {
+		if msg == nil {
+			return nil, fmt.Errorf("can't encode nil feed message %d", i)
+		}
+		var err error
+		buf, err = msg.appendBinary(buf)
+		if err != nil {
+			return nil, err
+		}
+	}
+	buf = appendPresence(buf, bm.ConfirmedSequenceNumberMessage != nil)
+	if bm.ConfirmedSequenceNumberMessage != nil {
+		buf = binary.AppendUvarint(buf, uint64(bm.ConfirmedSequenceNumberMessage.SequenceNumber))
+	}
+	return buf, nil
+}
+
+func (m *BroadcastFeedMessage) appendBinary(buf []byte) ([]byte, error) {
+	buf = binary.AppendUvarint(buf, uint64(m.SequenceNumber))
+	msg := m.Message.Message
+	buf = appendPresence(buf, msg != nil)
+	if msg != nil {
+		header := msg.Header
+		buf = appendPresence(buf, header != nil)
+		if header != nil {
+			buf = append(buf, header.Kind)
+			buf = append(buf, header.Poster[:]...)
+			buf = binary.AppendUvarint(buf, header.BlockNumber)
+			buf = binary.AppendUvarint(buf, header.Timestamp)
+			buf = appendPresence(buf, header.RequestId != nil)
+			if header.RequestId != nil {
+				buf = append(buf, header.RequestId[:]...)
+			}
+			buf = appendPresence(buf, header.L1BaseFee != nil)
+			if header.L1BaseFee != nil {
+				if header.L1BaseFee.Sign() < 0 {
+					return nil, fmt.Errorf("can't encode negative L1 base fee of feed message %d", m.SequenceNumber)
+				}
+				buf = appendBytes(buf, header.L1BaseFee.Bytes())
+			}
+		}
+		buf = appendBytes(buf, msg.L2msg)
+		buf = appendPresence(buf, msg.BatchGasCost != nil)
+		if msg.BatchGasCost != nil {
+			buf = binary.AppendUvarint(buf, *msg.BatchGasCost)
+		}
+	}
+	buf = binary.AppendUvarint(buf, m.Message.DelayedMessagesRead)
+	buf = appendPresence(buf, m.BlockHash != nil)
+	if m.BlockHash != nil {
+		buf = append(buf, m.BlockHash[:]...)
+	}
+	buf = appendBytes(buf, m.Signature)
+	buf = appendBytes(buf, m.BlockMetadata)
+	return buf, nil
+}
+
+func appendPresence(buf []byte, present bool) []byte {
+	if present {
+		return append(buf, 1)
+	}
+	return append(buf, 0)
+}
+
+func appendBytes(buf []byte, data []byte) []byte {
+	if data == nil {
+		return binary.AppendUvarint(buf, 0)
+	}
+	buf = binary.AppendUvarint(buf, uint64(len(data))+1)
+	return append(buf, data...)
+}
+
+// UnmarshalBinary decodes a message encoded with MarshalBinary.
+func (bm *BroadcastMessage) UnmarshalBinary(data []byte) error {
+	r := binaryReader{data: data}
+	if format := r.byte(); r.err == nil && format != binaryFormatVersion {
+		return fmt.Errorf("%w: unsupported format version %d", ErrInvalidBinaryMessage, format)
+	}
+	version := r.uvarint()
+	count := r.uvarint()
+	// Each feed message takes at least a few bytes, which bounds the allocation.
+	if r.err == nil && count > uint64(len(r.data)) {
+		return fmt.Errorf("%w: %d feed messages in %d bytes", ErrInvalidBinaryMessage, count, len(r.data))
+	}
+	var decoded BroadcastMessage
+	// #nosec G115
+	decoded.Version = int(version)
+	if count > 0 {
+		decoded.Messages = make([]*BroadcastFeedMessage, 0, count)
+	}
+	for i := uint64(0); i < count && r.err == nil; i++ {
+		decoded.Messages = append(decoded.Messages, r.feedMessage())
+	}
+	if r.presence() {
+		decoded.ConfirmedSequenceNumberMessage = &ConfirmedSequenceNumberMessage{
+			SequenceNumber: arbutil.MessageIndex(r.uvarint()),
+		}
+	}
+	if r.err != nil {
+		return r.err
+	}
+	if len(r.data) != 0 {
+		return fmt.Errorf("%w: %d trailing bytes", ErrInvalidBinaryMessage, len(r.data))
+	}
+	*bm = decoded
+	return nil
+}
+
+// binaryReader consumes a binary encoded message, remembering the first error so fields can be read unchecked.
+type binaryReader struct {
+	data []byte
+	err  error
+}
+
+func (r *binaryReader) fail(format string, args ...any) {
+	if r.err == nil {
+		r.err = fmt.Errorf("%w: %s", ErrInvalidBinaryMessage, fmt.Sprintf(format, args...))
+	}
+}
+
+func (r *binaryReader) take(n uint64) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if n > uint64(len(r.data)) {
+		r.fail("truncated")
+		return nil
+	}
+	taken := r.data[:n:n]
+	r.data = r.data[n:]
+	return taken
+}
+
+func (r *binaryReader) byte() byte {
+	b := r.take(1)
+	if b == nil {
+		return 0
+	}
+	return b[0]
+}
+
+func (r *binaryReader) presence() bool {
+	switch r.byte() {
+	case 0:
+		return false
+	case 1:
+		return true
+	default:
+		r.fail("invalid presence byte")
+		return false
+	}
+}
+
+func (r *binaryReader) uvarint() uint64 {
+	if r.err != nil {
+		return 0
+	}
+	val, n := binary.Uvarint(r.data)
+	if n <= 0 {
+		r.fail("invalid uvarint")
+		return 0
+	}
+	r.data = r.data[n:]
+	return val
+}
+
+func (r *binaryReader) bytes() []byte {
+	length := r.uvarint()
+	if length == 0 {
+		return nil
+	}
+	taken := r.take(length - 1)
+	if taken == nil {
+		return nil
+	}
+	// Copy so the decoded message doesn't hold on to the whole frame.
+	return append(make([]byte, 0, len(taken)), taken...)
+}
+
+func (r *binaryReader) feedMessage() *BroadcastFeedMessage {
+	m := &BroadcastFeedMessage{
+		SequenceNumber: arbutil.MessageIndex(r.uvarint()),
+	}
+	if r.presence() {
+		msg := &arbostypes.L1IncomingMessage{}
+		if r.presence() {
+			header := &arbostypes.L1IncomingMessageHeader{}
+			header.Kind = r.byte()
+			copy(header.Poster[:], r.take(common.AddressLength))
+			header.BlockNumber = r.uvarint()
+			header.Timestamp = r.uvarint()
+			if r.presence() {
+				requestId := common.BytesToHash(r.take(common.HashLength))
+				header.RequestId = &requestId
+			}
+			if r.presence() {
+				header.L1BaseFee = new(big.Int).SetBytes(r.bytes())
+			}
+			msg.Header = header
+		}
+		msg.L2msg = r.bytes()
+		if r.presence() {
+			batchGasCost := r.uvarint()
+			msg.BatchGasCost = &batchGasCost
+		}
+		m.Message.Message = msg
+	}
+	m.Message.DelayedMessagesRead = r.uvarint()
+	if r.presence() {
+		blockHash := common.BytesToHash(r.take(common.HashLength))
+		m.BlockHash = &blockHash
+	}
+	m.Signature = r.bytes()
+	m.BlockMetadata = r.bytes()
+	return m
+}
@@ -0,0 +1,176 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+package message
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/offchainlabs/nitro/arbos/arbostypes"
+	"github.com/offchainlabs/nitro/arbutil"
+)
+
+func testBinaryBroadcastMessage(count int) *BroadcastMessage {
+	requestId := common.Hash{0: 0x12, 31: 0x34}
+	blockHash := common.Hash{0: 0xff}
+	batchGasCost := uint64(21000)
+	bm := &BroadcastMessage{
+		Version:                        1,
+		ConfirmedSequenceNumberMessage: &ConfirmedSequenceNumberMessage{SequenceNumber: 1234},
+	}
+	for i := 0; i < count; i++ {
+		bm.Messages = append(bm.Messages, &BroadcastFeedMessage{
+			// #nosec G115
+			SequenceNumber: arbutil.MessageIndex(12345 + i),
+			Message: arbostypes.MessageWithMetadata{
+				Message: &arbostypes.L1IncomingMessage{
+					Header: &arbostypes.L1IncomingMessageHeader{
+						Kind:        arbostypes.L1MessageType_L2Message,
+						Poster:      common.Address{19: 0x01},
+						BlockNumber: 20000000,
+						Timestamp:   1700000000,
+						RequestId:   &requestId,
+						L1BaseFee:   big.NewInt(30_000_000_000),
+					},
+					L2msg:        make([]byte, 200),
+					BatchGasCost: &batchGasCost,
+				},
+				DelayedMessagesRead: 3333,
+			},
+			BlockHash:     &blockHash,
+			Signature:     make([]byte, 65),
+			BlockMetadata: []byte{0, 2},
+		})
+	}
+	return bm
+}
+
+func testBinaryRoundTrip(t *testing.T, bm *BroadcastMessage) {
+	t.Helper()
+	data, err := bm.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded BroadcastMessage
+	if err := decoded.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(*bm, decoded) {
+		t.Fatalf("decoded message differs, have %+v, want %+v", decoded, *bm)
+	}
+}
+
+func TestBinaryEncodingRoundTrip(t *testing.T) {
+	t.Parallel()
+	testBinaryRoundTrip(t, testBinaryBroadcastMessage(3))
+	testBinaryRoundTrip(t, &BroadcastMessage{Version: 1})
+	testBinaryRoundTrip(t, &BroadcastMessage{
+		Version:                        1,
+		ConfirmedSequenceNumberMessage: &ConfirmedSequenceNumberMessage{SequenceNumber: 7},
+	})
+
+	// Nil and empty fields must stay distinct, which JSON doesn't preserve.
+	bm := testBinaryBroadcastMessage(2)
+	bm.Messages[0].Message.Message.Header.RequestId = nil
+	bm.Messages[0].Message.Message.Header.L1BaseFee = nil
+	bm.Messages[0].Message.Message.L2msg = []byte{}
+	bm.Messages[0].Message.Message.BatchGasCost = nil
+	bm.Messages[0].BlockHash = nil
+	bm.Messages[0].Signature = nil
+	bm.Messages[0].BlockMetadata = nil
+	bm.Messages[1].Message.Message.Header.L1BaseFee = new(big.Int)
+	bm.Messages[1].Message.Message.L2msg = nil
+	bm.Messages[1].Signature = []byte{}
+	testBinaryRoundTrip(t, bm)
+
+	// A feed message without an L1 incoming message
+	bm = testBinaryBroadcastMessage(2)
+	bm.Messages[1].Message.Message = nil
+	testBinaryRoundTrip(t, bm)
+
+	testBinaryRoundTrip(t, CreateDummyBroadcastMessage([]arbutil.MessageIndex{1, 2, 3}))
+}
+
+func TestBinaryEncodingInvalid(t *testing.T) {
+	t.Parallel()
+	data, err := testBinaryBroadcastMessage(2).MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, invalid := range [][]byte{
+		nil,
+		{binaryFormatVersion + 1},
+		data[:len(data)-1],
+		data[:len(data)/2],
+		append(append([]byte{}, data...), 0),
+		// Claims far more feed messages than there are bytes.
+		{binaryFormatVersion, 1, 0xff, 0xff, 0x03},
+	} {
+		var decoded BroadcastMessage
+		if err := decoded.UnmarshalBinary(invalid); !errors.Is(err, ErrInvalidBinaryMessage) {
+			t.Fatalf("decoding %x: expected ErrInvalidBinaryMessage, got %v", invalid, err)
+		}
+	}
+}
+
+func BenchmarkBroadcastMessageMarshalJSON(b *testing.B) {
+	bm := testBinaryBroadcastMessage(10)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		data, err := json.Marshal(bm)
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.SetBytes(int64(len(data)))
+	}
+}
+
+func BenchmarkBroadcastMessageMarshalBinary(b *testing.B) {
+	bm := testBinaryBroadcastMessage(10)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		data, err := bm.MarshalBinary()
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.SetBytes(int64(len(data)))
+	}
+}
+
+func BenchmarkBroadcastMessageUnmarshalJSON(b *testing.B) {
+	data, err := json.Marshal(testBinaryBroadcastMessage(10))
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var decoded BroadcastMessage
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkBroadcastMessageUnmarshalBinary(b *testing.B) {
+	data, err := testBinaryBroadcastMessage(10).MarshalBinary()
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var decoded BroadcastMessage
+		if err := decoded.UnmarshalBinary(data); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
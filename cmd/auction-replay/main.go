@@ -0,0 +1,282 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"time"
+
+	flag "github.com/spf13/pflag"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/offchainlabs/nitro/cmd/genericconf"
+	"github.com/offchainlabs/nitro/cmd/util/confighelpers"
+	"github.com/offchainlabs/nitro/solgen/go/express_lane_auctiongen"
+	"github.com/offchainlabs/nitro/timeboost"
+)
+
+type AuctionReplayConfig struct {
+	ArbitrumNodeEndpoint   string                           `koanf:"arbitrum-node-endpoint"`
+	AuctionContractAddress string                           `koanf:"auction-contract-address"`
+	DbDirectory            string                           `koanf:"db-directory"`
+	S3Storage              timeboost.S3StorageServiceConfig `koanf:"s3-storage"`
+	FromRound              uint64                           `koanf:"from-round"`
+	ToRound                uint64                           `koanf:"to-round"`
+	CheckBalances          bool                             `koanf:"check-balances"`
+	ReservePrice           string                           `koanf:"reserve-price"`
+	ExpressLaneAdvantage   time.Duration                    `koanf:"express-lane-advantage"`
+	Simulate               SimulateConfig                   `koanf:"simulate"`
+	Output                 string                           `koanf:"output"`
+	LogLevel               string                           `koanf:"log-level"`
+	LogType                string                           `koanf:"log-type"`
+}
+
+// SimulateConfig are the alternative auction rules, where zero values keep the production ones.
+type SimulateConfig struct {
+	ReservePrice         string        `koanf:"reserve-price"`
+	RoundDuration        time.Duration `koanf:"round-duration"`
+	AuctionClosing       time.Duration `koanf:"auction-closing"`
+	ExpressLaneAdvantage time.Duration `koanf:"express-lane-advantage"`
+	TieBreaking          string        `koanf:"tie-breaking"`
+	ScaleBids            bool          `koanf:"scale-bids"`
+}
+
+var AuctionReplayConfigDefault = AuctionReplayConfig{
+	ArbitrumNodeEndpoint: "http://localhost:8547",
+	S3Storage:            timeboost.DefaultS3StorageServiceConfig,
+	ToRound:              ^uint64(0),
+	ExpressLaneAdvantage: 200 * time.Millisecond,
+	Simulate:             SimulateConfigDefault,
+	LogLevel:             "INFO",
+	LogType:              "plaintext",
+}
+
+var SimulateConfigDefault = SimulateConfig{
+	TieBreaking: timeboost.TieBreakingHash,
+}
+
+func AuctionReplayConfigAddOptions(f *flag.FlagSet) {
+	f.String("arbitrum-node-endpoint", AuctionReplayConfigDefault.ArbitrumNodeEndpoint, "arbitrum node RPC http endpoint to read the auction contract from")
+	f.String("auction-contract-address", AuctionReplayConfigDefault.AuctionContractAddress, "express lane auction contract address")
+	f.String("db-directory", AuctionReplayConfigDefault.DbDirectory, "path to the auctioneer's database directory, leave empty to only read the S3 archive")
+	timeboost.S3StorageServiceConfigAddOptions("s3-storage", f)
+	f.Uint64("from-round", AuctionReplayConfigDefault.FromRound, "first round to replay")
+	f.Uint64("to-round", AuctionReplayConfigDefault.ToRound, "last round to replay")
+	f.Bool("check-balances", AuctionReplayConfigDefault.CheckBalances, "check bids against the current deposits of the bidders, which archived bids were already checked against when submitted")
+	f.String("reserve-price", AuctionReplayConfigDefault.ReservePrice, "reserve price in wei the replayed rounds were auctioned with, the contract's current one may have changed since")
+	f.Duration("express-lane-advantage", AuctionReplayConfigDefault.ExpressLaneAdvantage, "express lane advantage the sequencer ran with")
+	SimulateConfigAddOptions("simulate", f)
+	f.String("output", AuctionReplayConfigDefault.Output, "file to write the JSON report to, leave empty to write it to stdout")
+	f.String("log-level", AuctionReplayConfigDefault.LogLevel, "log level, valid values are CRIT, ERROR, WARN, INFO, DEBUG, TRACE")
+	f.String("log-type", AuctionReplayConfigDefault.LogType, "log type (plaintext or json)")
+}
+
+func SimulateConfigAddOptions(prefix string, f *flag.FlagSet) {
+	f.String(prefix+".reserve-price", SimulateConfigDefault.ReservePrice, "reserve price in wei to replay the auctions with, leave empty for the contract's")
+	f.Duration(prefix+".round-duration", SimulateConfigDefault.RoundDuration, "round duration to replay the auctions with, 0 for the contract's")
+	f.Duration(prefix+".auction-closing", SimulateConfigDefault.AuctionClosing, "auction closing duration to replay the auctions with, 0 for the contract's")
+	f.Duration(prefix+".express-lane-advantage", SimulateConfigDefault.ExpressLaneAdvantage, "express lane advantage to replay the auctions with, 0 for the production one")
+	f.String(prefix+".tie-breaking", SimulateConfigDefault.TieBreaking, "how to break ties between bids of the same amount, one of hash, first-seen or last-seen")
+	f.Bool(prefix+".scale-bids", SimulateConfigDefault.ScaleBids, "scale the bid amounts by how much the round duration times the express lane advantage changes, assuming bids are proportional to it")
+}
+
+func (c *AuctionReplayConfig) Validate() error {
+	if c.AuctionContractAddress == "" {
+		return errors.New("auction-contract-address must be set")
+	}
+	if c.DbDirectory == "" && !c.S3Storage.Enable {
+		return errors.New("set db-directory or enable s3-storage to read bids from")
+	}
+	if c.ReservePrice == "" {
+		return errors.New("reserve-price must be set to the reserve price the replayed rounds were auctioned with")
+	}
+	if c.FromRound > c.ToRound {
+		return fmt.Errorf("from-round %d is after to-round %d", c.FromRound, c.ToRound)
+	}
+	if c.ExpressLaneAdvantage <= 0 {
+		return errors.New("express-lane-advantage must be positive")
+	}
+	if c.Simulate.RoundDuration < 0 || c.Simulate.AuctionClosing < 0 || c.Simulate.ExpressLaneAdvantage < 0 {
+		return errors.New("simulated durations must not be negative")
+	}
+	return c.S3Storage.Validate()
+}
+
+// AuctionReplayOutput compares the auctions replayed under the production and the simulated rules.
+type AuctionReplayOutput struct {
+	FromRound  uint64                         `json:"fromRound"`
+	ToRound    uint64                         `json:"toRound"`
+	Production *timeboost.AuctionReplayReport `json:"production"`
+	Simulated  *timeboost.AuctionReplayReport `json:"simulated"`
+}
+
+func printSampleUsage(name string) {
+	fmt.Printf("Sample usage: %s --auction-contract-address=<address> --db-directory=<path> --reserve-price=<wei> --simulate.reserve-price=<wei> \n", name)
+}
+
+func main() {
+	args := os.Args[1:]
+	config, err := parseAuctionReplayArgs(args)
+	if err != nil {
+		confighelpers.PrintErrorAndExit(err, printSampleUsage)
+	}
+	if err := genericconf.InitLog(config.LogType, config.LogLevel, &genericconf.FileLoggingConfig{Enable: false}, nil); err != nil {
+		fmt.Fprintf(os.Stderr, "Error initializing logging: %v\n", err)
+		os.Exit(1)
+	}
+	if err := replayAuctions(context.Background(), config); err != nil {
+		log.Error("Error replaying auctions", "err", err)
+		os.Exit(1)
+	}
+}
+
+func replayAuctions(ctx context.Context, config *AuctionReplayConfig) error {
+	client, err := ethclient.DialContext(ctx, config.ArbitrumNodeEndpoint)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	chainId, err := client.ChainID(ctx)
+	if err != nil {
+		return err
+	}
+	auctionContractAddr := common.HexToAddress(config.AuctionContractAddress)
+	auctionContract, err := express_lane_auctiongen.NewExpressLaneAuction(auctionContractAddr, client)
+	if err != nil {
+		return err
+	}
+	callOpts := &bind.CallOpts{Context: ctx}
+	rawRoundTimingInfo, err := auctionContract.RoundTimingInfo(callOpts)
+	if err != nil {
+		return err
+	}
+	roundTimingInfo, err := timeboost.NewRoundTimingInfo(rawRoundTimingInfo)
+	if err != nil {
+		return err
+	}
+	// The reserve price may have been changed since the replayed rounds were auctioned, so it isn't read from the
+	// contract.
+	reservePrice, ok := new(big.Int).SetString(config.ReservePrice, 10)
+	if !ok {
+		return fmt.Errorf("invalid reserve-price %q", config.ReservePrice)
+	}
+	domainSeparator, err := auctionContract.DomainSeparator(callOpts)
+	if err != nil {
+		return err
+	}
+
+	production := timeboost.AuctionReplayRules{
+		RoundTimingInfo:      *roundTimingInfo,
+		ReservePrice:         reservePrice,
+		ExpressLaneAdvantage: config.ExpressLaneAdvantage,
+		TieBreaking:          timeboost.TieBreakingHash,
+	}
+	simulated, err := simulatedRules(&config.Simulate, &production, rawRoundTimingInfo)
+	if err != nil {
+		return err
+	}
+	var balanceCheckerFn func(opts *bind.CallOpts, account common.Address) (*big.Int, error)
+	if config.CheckBalances {
+		balanceCheckerFn = auctionContract.BalanceOf
+	}
+	replayer, err := timeboost.NewAuctionReplayer(chainId, auctionContractAddr, domainSeparator, production, balanceCheckerFn)
+	if err != nil {
+		return err
+	}
+
+	var database *timeboost.SqliteDatabase
+	if config.DbDirectory != "" {
+		database, err = timeboost.NewDatabase(config.DbDirectory)
+		if err != nil {
+			return err
+		}
+	}
+	var s3StorageService *timeboost.S3StorageService
+	if config.S3Storage.Enable {
+		s3StorageService, err = timeboost.NewS3StorageService(&config.S3Storage, database)
+		if err != nil {
+			return err
+		}
+	}
+	bids, err := timeboost.LoadReplayBids(ctx, database, s3StorageService, config.FromRound, config.ToRound)
+	if err != nil {
+		return err
+	}
+	log.Info("Replaying auctions", "bids", len(bids), "fromRound", config.FromRound, "toRound", config.ToRound)
+
+	output := &AuctionReplayOutput{
+		FromRound: config.FromRound,
+		ToRound:   config.ToRound,
+	}
+	output.Production, err = replayer.Replay(bids, &production)
+	if err != nil {
+		return err
+	}
+	output.Simulated, err = replayer.Replay(bids, simulated)
+	if err != nil {
+		return err
+	}
+	report, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return err
+	}
+	if config.Output == "" {
+		fmt.Println(string(report))
+		return nil
+	}
+	return os.WriteFile(config.Output, report, 0600)
+}
+
+// simulatedRules overrides the production rules with the simulated ones that are set.
+func simulatedRules(config *SimulateConfig, production *timeboost.AuctionReplayRules, rawRoundTimingInfo express_lane_auctiongen.RoundTimingInfo) (*timeboost.AuctionReplayRules, error) {
+	rules := *production
+	if config.ReservePrice != "" {
+		reservePrice, ok := new(big.Int).SetString(config.ReservePrice, 10)
+		if !ok {
+			return nil, fmt.Errorf("invalid simulate.reserve-price %q", config.ReservePrice)
+		}
+		rules.ReservePrice = reservePrice
+	}
+	if config.RoundDuration != 0 || config.AuctionClosing != 0 {
+		if config.RoundDuration != 0 {
+			// #nosec G115
+			rawRoundTimingInfo.RoundDurationSeconds = uint64(config.RoundDuration / time.Second)
+		}
+		if config.AuctionClosing != 0 {
+			// #nosec G115
+			rawRoundTimingInfo.AuctionClosingSeconds = uint64(config.AuctionClosing / time.Second)
+		}
+		roundTimingInfo, err := timeboost.NewRoundTimingInfo(rawRoundTimingInfo)
+		if err != nil {
+			return nil, fmt.Errorf("invalid simulated round timing: %w", err)
+		}
+		rules.RoundTimingInfo = *roundTimingInfo
+	}
+	if config.ExpressLaneAdvantage != 0 {
+		rules.ExpressLaneAdvantage = config.ExpressLaneAdvantage
+	}
+	rules.TieBreaking = config.TieBreaking
+	rules.ScaleBids = config.ScaleBids
+	return &rules, rules.Validate()
+}
+
+func parseAuctionReplayArgs(args []string) (*AuctionReplayConfig, error) {
+	f := flag.NewFlagSet("auction-replay", flag.ContinueOnError)
+	AuctionReplayConfigAddOptions(f)
+	k, err := confighelpers.BeginCommonParse(f, args)
+	if err != nil {
+		return nil, err
+	}
+	var config AuctionReplayConfig
+	if err := confighelpers.EndCommonParse(k, &config); err != nil {
+		return nil, err
+	}
+	return &config, config.Validate()
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
 	"time"
@@ -134,6 +135,12 @@ func (c *AutonomousAuctioneerConfig) Validate() error {
 	if err := c.AuctioneerServer.S3Storage.Validate(); err != nil {
 		return err
 	}
+	if err := c.AuctioneerServer.History.Validate(); err != nil {
+		return err
+	}
+	if c.AuctioneerServer.History.Enable && c.AuctioneerServer.History.S3Fallback && !c.AuctioneerServer.S3Storage.Enable {
+		return errors.New("auctioneer-server.history.s3-fallback needs auctioneer-server.s3-storage.enable")
+	}
 	return nil
 }
 
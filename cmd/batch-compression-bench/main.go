@@ -0,0 +1,370 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+// batch-compression-bench compares the compression ratio and CPU time of the
+// batch compression codecs on real batches, either fetched from the parent
+// chain's sequencer inbox or read from a file of hex encoded batches.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	flag "github.com/spf13/pflag"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/offchainlabs/nitro/arbcompress"
+	"github.com/offchainlabs/nitro/arbnode"
+	"github.com/offchainlabs/nitro/arbstate"
+	"github.com/offchainlabs/nitro/arbstate/daprovider"
+	"github.com/offchainlabs/nitro/cmd/genericconf"
+	"github.com/offchainlabs/nitro/cmd/util/confighelpers"
+	"github.com/offchainlabs/nitro/util/headerreader"
+)
+
+type BenchConfig struct {
+	ParentChainUrl string                        `koanf:"parent-chain-url"`
+	SequencerInbox string                        `koanf:"sequencer-inbox"`
+	FromBlock      uint64                        `koanf:"from-block"`
+	ToBlock        uint64                        `koanf:"to-block"`
+	BlockRange     uint64                        `koanf:"block-range"`
+	MaxBatches     int                           `koanf:"max-batches"`
+	BatchFile      string                        `koanf:"batch-file"`
+	BlobClient     headerreader.BlobClientConfig `koanf:"blob-client"`
+	BrotliLevels   []int                         `koanf:"brotli-levels"`
+	ZstdLevels     []int                         `koanf:"zstd-levels"`
+	ZstdDictionary string                        `koanf:"zstd-dictionary"`
+	Iterations     int                           `koanf:"iterations"`
+	LogLevel       string                        `koanf:"log-level"`
+	LogType        string                        `koanf:"log-type"`
+}
+
+var DefaultBenchConfig = BenchConfig{
+	BlockRange:   1000,
+	MaxBatches:   100,
+	BlobClient:   headerreader.DefaultBlobClientConfig,
+	BrotliLevels: []int{1, 6, 11},
+	ZstdLevels:   []int{3, 9, 19},
+	Iterations:   1,
+	LogLevel:     "INFO",
+	LogType:      "plaintext",
+}
+
+func BenchConfigAddOptions(f *flag.FlagSet) {
+	f.String("parent-chain-url", DefaultBenchConfig.ParentChainUrl, "parent chain RPC URL to fetch batches from")
+	f.String("sequencer-inbox", DefaultBenchConfig.SequencerInbox, "address of the sequencer inbox to fetch batches from")
+	f.Uint64("from-block", DefaultBenchConfig.FromBlock, "parent chain block to start fetching batches at")
+	f.Uint64("to-block", DefaultBenchConfig.ToBlock, "parent chain block to stop fetching batches at (latest if 0)")
+	f.Uint64("block-range", DefaultBenchConfig.BlockRange, "number of parent chain blocks to query for batches at once")
+	f.Int("max-batches", DefaultBenchConfig.MaxBatches, "maximum number of batches to benchmark")
+	f.String("batch-file", DefaultBenchConfig.BatchFile, "file of hex encoded serialized batches, one per line, to benchmark instead of fetching batches")
+	headerreader.BlobClientAddOptions("blob-client", f)
+	f.IntSlice("brotli-levels", DefaultBenchConfig.BrotliLevels, "brotli compression levels to benchmark")
+	f.IntSlice("zstd-levels", DefaultBenchConfig.ZstdLevels, "zstd compression levels to benchmark")
+	f.String("zstd-dictionary", DefaultBenchConfig.ZstdDictionary, "file containing a trained zstd dictionary to also benchmark zstd with")
+	f.Int("iterations", DefaultBenchConfig.Iterations, "number of times to compress and decompress each batch when measuring CPU time")
+	f.String("log-level", DefaultBenchConfig.LogLevel, "log level, valid values are CRIT, ERROR, WARN, INFO, DEBUG, TRACE")
+	f.String("log-type", DefaultBenchConfig.LogType, "log type (plaintext or json)")
+}
+
+func (c *BenchConfig) Validate() error {
+	if c.BatchFile == "" && (c.ParentChainUrl == "" || !common.IsHexAddress(c.SequencerInbox)) {
+		return errors.New("either --batch-file or both --parent-chain-url and --sequencer-inbox must be specified")
+	}
+	if c.Iterations < 1 {
+		return errors.New("--iterations must be at least 1")
+	}
+	if c.BlockRange == 0 {
+		return errors.New("--block-range must be positive")
+	}
+	return nil
+}
+
+func parseBenchConfig(args []string) (*BenchConfig, error) {
+	f := flag.NewFlagSet("batch-compression-bench", flag.ContinueOnError)
+	BenchConfigAddOptions(f)
+	k, err := confighelpers.BeginCommonParse(f, args)
+	if err != nil {
+		return nil, err
+	}
+	var config BenchConfig
+	if err := confighelpers.EndCommonParse(k, &config); err != nil {
+		return nil, err
+	}
+	return &config, config.Validate()
+}
+
+func printSampleUsage(name string) {
+	fmt.Printf("Sample usage: %s --parent-chain-url <url> --sequencer-inbox <address> --from-block <block>\n", name)
+	fmt.Printf("              %s --batch-file <file>\n\n", name)
+}
+
+// decompressBatch returns the uncompressed segments of a serialized batch, or nil if its payload isn't available.
+func decompressBatch(ctx context.Context, batchNum uint64, blockHash common.Hash, serialized []byte, blobReader daprovider.Reader) ([]byte, error) {
+	if len(serialized) <= 40 {
+		return nil, nil
+	}
+	payload := serialized[40:]
+	if blobReader != nil && blobReader.IsValidHeaderByte(payload[0]) {
+		var err error
+		payload, err = blobReader.RecoverPayloadFromBatch(ctx, batchNum, blockHash, serialized, nil, false)
+		if err != nil {
+			return nil, err
+		}
+		if len(payload) == 0 {
+			return nil, nil
+		}
+	}
+	switch {
+	case daprovider.IsBrotliMessageHeaderByte(payload[0]):
+		return arbcompress.Decompress(payload[1:], arbstate.MaxDecompressedLen)
+	case daprovider.IsZstdMessageHeaderByte(payload[0]):
+		return arbcompress.DecompressZstd(payload[1:], arbstate.MaxDecompressedLen)
+	default:
+		return nil, nil
+	}
+}
+
+func readBatchFile(ctx context.Context, path string, maxBatches int) ([][]byte, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	var batches [][]byte
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, 4*arbstate.MaxDecompressedLen)
+	for scanner.Scan() && len(batches) < maxBatches {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		serialized, err := hexutil.Decode(line)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding batch %v: %w", len(batches), err)
+		}
+		batch, err := decompressBatch(ctx, 0, common.Hash{}, serialized, nil)
+		if err != nil {
+			return nil, fmt.Errorf("error decompressing batch %v: %w", len(batches), err)
+		}
+		if batch == nil {
+			log.Warn("skipping batch that isn't brotli or zstd compressed calldata", "index", len(batches))
+			continue
+		}
+		batches = append(batches, batch)
+	}
+	return batches, scanner.Err()
+}
+
+func fetchBatches(ctx context.Context, config *BenchConfig) ([][]byte, error) {
+	client, err := ethclient.DialContext(ctx, config.ParentChainUrl)
+	if err != nil {
+		return nil, err
+	}
+	// #nosec G115
+	inbox, err := arbnode.NewSequencerInbox(client, common.HexToAddress(config.SequencerInbox), int64(config.FromBlock))
+	if err != nil {
+		return nil, err
+	}
+	var blobReader daprovider.Reader
+	if config.BlobClient.BeaconUrl != "" {
+		blobClient, err := headerreader.NewBlobClient(config.BlobClient, client)
+		if err != nil {
+			return nil, err
+		}
+		if err := blobClient.Initialize(ctx); err != nil {
+			return nil, err
+		}
+		blobReader = daprovider.NewReaderForBlobReader(blobClient)
+	}
+	toBlock := config.ToBlock
+	if toBlock == 0 {
+		toBlock, err = client.BlockNumber(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+	var batches [][]byte
+	for from := config.FromBlock; from <= toBlock && len(batches) < config.MaxBatches; from += config.BlockRange {
+		to := min(from+config.BlockRange-1, toBlock)
+		found, err := inbox.LookupBatchesInRange(ctx, new(big.Int).SetUint64(from), new(big.Int).SetUint64(to))
+		if err != nil {
+			return nil, err
+		}
+		for _, inboxBatch := range found {
+			if len(batches) >= config.MaxBatches {
+				break
+			}
+			serialized, err := inboxBatch.Serialize(ctx, client)
+			if err != nil {
+				return nil, err
+			}
+			batch, err := decompressBatch(ctx, inboxBatch.SequenceNumber, inboxBatch.BlockHash, serialized, blobReader)
+			if err != nil {
+				return nil, fmt.Errorf("error decompressing batch %v: %w", inboxBatch.SequenceNumber, err)
+			}
+			if batch == nil {
+				log.Warn("skipping batch whose payload isn't available", "sequenceNumber", inboxBatch.SequenceNumber)
+				continue
+			}
+			batches = append(batches, batch)
+		}
+		log.Info("fetched batches", "toBlock", to, "batches", len(batches))
+	}
+	return batches, nil
+}
+
+type codec struct {
+	name       string
+	compress   func([]byte) ([]byte, error)
+	decompress func([]byte) ([]byte, error)
+}
+
+type codecResult struct {
+	uncompressedSize int
+	compressedSize   int
+	compressTime     time.Duration
+	decompressTime   time.Duration
+}
+
+func benchmarkCodec(c codec, batches [][]byte, iterations int) (*codecResult, error) {
+	result := &codecResult{}
+	for i, batch := range batches {
+		var compressed []byte
+		start := time.Now()
+		for j := 0; j < iterations; j++ {
+			var err error
+			compressed, err = c.compress(batch)
+			if err != nil {
+				return nil, fmt.Errorf("%v: error compressing batch %v: %w", c.name, i, err)
+			}
+		}
+		result.compressTime += time.Since(start)
+		var decompressed []byte
+		start = time.Now()
+		for j := 0; j < iterations; j++ {
+			var err error
+			decompressed, err = c.decompress(compressed)
+			if err != nil {
+				return nil, fmt.Errorf("%v: error decompressing batch %v: %w", c.name, i, err)
+			}
+		}
+		result.decompressTime += time.Since(start)
+		if !bytes.Equal(decompressed, batch) {
+			return nil, fmt.Errorf("%v: batch %v doesn't round trip", c.name, i)
+		}
+		result.uncompressedSize += len(batch)
+		result.compressedSize += len(compressed)
+	}
+	// #nosec G115
+	result.compressTime /= time.Duration(iterations)
+	// #nosec G115
+	result.decompressTime /= time.Duration(iterations)
+	return result, nil
+}
+
+func codecs(config *BenchConfig) ([]codec, error) {
+	var dictionary []byte
+	if config.ZstdDictionary != "" {
+		var err error
+		dictionary, err = os.ReadFile(config.ZstdDictionary)
+		if err != nil {
+			return nil, err
+		}
+	}
+	var result []codec
+	for _, level := range config.BrotliLevels {
+		result = append(result, codec{
+			name: fmt.Sprintf("brotli-%d", level),
+			compress: func(input []byte) ([]byte, error) {
+				// #nosec G115
+				return arbcompress.CompressLevel(input, uint64(level))
+			},
+			decompress: func(input []byte) ([]byte, error) {
+				return arbcompress.Decompress(input, arbstate.MaxDecompressedLen)
+			},
+		})
+	}
+	for _, level := range config.ZstdLevels {
+		result = append(result, codec{
+			name: fmt.Sprintf("zstd-%d", level),
+			compress: func(input []byte) ([]byte, error) {
+				return arbcompress.CompressZstd(input, level)
+			},
+			decompress: func(input []byte) ([]byte, error) {
+				return arbcompress.DecompressZstd(input, arbstate.MaxDecompressedLen)
+			},
+		})
+		if dictionary != nil {
+			result = append(result, codec{
+				name: fmt.Sprintf("zstd-%d-dict", level),
+				compress: func(input []byte) ([]byte, error) {
+					return arbcompress.CompressZstdWithDictionary(input, level, dictionary)
+				},
+				decompress: func(input []byte) ([]byte, error) {
+					return arbcompress.DecompressZstdWithDictionary(input, arbstate.MaxDecompressedLen, dictionary)
+				},
+			})
+		}
+	}
+	return result, nil
+}
+
+func run(ctx context.Context, config *BenchConfig) error {
+	var batches [][]byte
+	var err error
+	if config.BatchFile != "" {
+		batches, err = readBatchFile(ctx, config.BatchFile, config.MaxBatches)
+	} else {
+		batches, err = fetchBatches(ctx, config)
+	}
+	if err != nil {
+		return err
+	}
+	if len(batches) == 0 {
+		return errors.New("no batches to benchmark")
+	}
+	candidates, err := codecs(config)
+	if err != nil {
+		return err
+	}
+	writer := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintf(writer, "codec\tbatches\tuncompressed\tcompressed\tratio\tcompress time\tdecompress time\t\n")
+	for _, c := range candidates {
+		result, err := benchmarkCodec(c, batches, config.Iterations)
+		if err != nil {
+			return err
+		}
+		ratio := float64(result.uncompressedSize) / float64(max(result.compressedSize, 1))
+		fmt.Fprintf(writer, "%v\t%v\t%v\t%v\t%.3f\t%v\t%v\t\n", c.name, len(batches), result.uncompressedSize, result.compressedSize, ratio, result.compressTime, result.decompressTime)
+	}
+	return writer.Flush()
+}
+
+func main() {
+	config, err := parseBenchConfig(os.Args[1:])
+	if err != nil {
+		confighelpers.PrintErrorAndExit(err, printSampleUsage)
+	}
+	err = genericconf.InitLog(config.LogType, config.LogLevel, &genericconf.FileLoggingConfig{Enable: false}, nil)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error initializing logging: %v\n", err)
+		os.Exit(1)
+	}
+	if err := run(context.Background(), config); err != nil {
+		log.Error("benchmark failed", "err", err)
+		os.Exit(1)
+	}
+}
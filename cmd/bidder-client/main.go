@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"math/big"
 	"os"
+	"os/signal"
+	"syscall"
 
 	flag "github.com/spf13/pflag"
 
@@ -52,6 +54,13 @@ func mainImpl() error {
 		return errors.New("--deposit-gwei and --bid-gwei can't both be set, either make a deposit or a bid")
 	}
 
+	if bidderClientConfig.Bidding.Enable {
+		if bidderClientConfig.DepositGwei > 0 || bidderClientConfig.BidGwei > 0 {
+			return errors.New("--deposit-gwei and --bid-gwei can't be set with --bidding.enable, the bidding engine makes the deposits and bids")
+		}
+		return runBiddingEngine(ctx, bidderClient, &bidderClientConfig.Bidding)
+	}
+
 	if bidderClientConfig.DepositGwei > 0 {
 		err = bidderClient.Deposit(ctx, big.NewInt(int64(bidderClientConfig.DepositGwei)*1_000_000_000))
 		if err == nil {
@@ -69,7 +78,28 @@ func mainImpl() error {
 		return err
 	}
 
-	return errors.New("select one of --deposit-gwei or --bid-gwei")
+	return errors.New("select one of --deposit-gwei, --bid-gwei or --bidding.enable")
+}
+
+func runBiddingEngine(ctx context.Context, bidderClient *timeboost.BidderClient, config *timeboost.BiddingEngineConfig) error {
+	engine, err := timeboost.NewBiddingEngine(config, bidderClient)
+	if err != nil {
+		return err
+	}
+	bidderClient.Start(ctx)
+	defer bidderClient.StopAndWait()
+	if err := engine.Start(ctx); err != nil {
+		return err
+	}
+	defer engine.StopAndWait()
+
+	sigint := make(chan os.Signal, 1)
+	signal.Notify(sigint, os.Interrupt, syscall.SIGTERM)
+	<-sigint
+	log.Info("shutting down because of sigint")
+	// cause future ctrl+c's to panic
+	close(sigint)
+	return nil
 }
 
 func parseBidderClientArgs(ctx context.Context, args []string) (*timeboost.BidderClientConfig, error) {
@@ -91,6 +121,9 @@ func parseBidderClientArgs(ctx context.Context, args []string) (*timeboost.Bidde
 	if err := confighelpers.EndCommonParse(k, &cfg); err != nil {
 		return nil, err
 	}
+	if err := cfg.Bidding.Validate(); err != nil {
+		return nil, err
+	}
 
 	return &cfg, nil
 }
@@ -115,6 +115,10 @@ func parseDAServer(args []string) (*DAServerConfig, error) {
 		return nil, err
 	}
 
+	if err = das.FixWriterKeysCLIParsing("data-availability.writer-keys", k); err != nil {
+		return nil, err
+	}
+
 	var serverConfig DAServerConfig
 	if err := confighelpers.EndCommonParse(k, &serverConfig); err != nil {
 		return nil, err
@@ -13,6 +13,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
@@ -35,7 +36,7 @@ import (
 func main() {
 	args := os.Args
 	if len(args) < 2 {
-		panic("Usage: datool [client|keygen|generatehash|dumpkeyset] ...")
+		panic("Usage: datool [client|keygen|generatehash|dumpkeyset|scrub|export|import] ...")
 	}
 
 	var err error
@@ -48,8 +49,14 @@ func main() {
 		err = generateHash(args[2])
 	case "dumpkeyset":
 		err = dumpKeyset(args[2:])
+	case "scrub":
+		err = scrub(args[2:])
+	case "export":
+		err = exportArchive(args[2:])
+	case "import":
+		err = importArchive(args[2:])
 	default:
-		panic(fmt.Sprintf("Unknown tool '%s' specified, valid tools are 'client', 'keygen', 'generatehash'", args[1]))
+		panic(fmt.Sprintf("Unknown tool '%s' specified, valid tools are 'client', 'keygen', 'generatehash', 'dumpkeyset', 'scrub', 'export', 'import'", args[1]))
 	}
 	if err != nil {
 		panic(err)
@@ -378,3 +385,295 @@ func dumpKeyset(args []string) error {
 
 	return err
 }
+
+// Storage backends used by datool scrub, export and import
+
+func storageConfigAddOptions(f *flag.FlagSet) {
+	das.LocalDBStorageConfigAddOptions("local-db-storage", f)
+	das.LocalFileStorageConfigAddOptions("local-file-storage", f)
+	das.S3ConfigAddOptions("s3-storage", f)
+}
+
+func createStorage(ctx context.Context, localDB das.LocalDBStorageConfig, localFile das.LocalFileStorageConfig, s3 das.S3StorageServiceConfig) (das.StorageService, *das.LifecycleManager, error) {
+	dasConfig := das.DefaultDataAvailabilityConfig
+	dasConfig.LocalDBStorage = localDB
+	dasConfig.LocalFileStorage = localFile
+	dasConfig.S3Storage = s3
+	return das.CreatePersistentStorageService(ctx, &dasConfig)
+}
+
+// datool scrub
+
+type ScrubConfig struct {
+	LocalDBStorage   das.LocalDBStorageConfig          `koanf:"local-db-storage"`
+	LocalFileStorage das.LocalFileStorageConfig        `koanf:"local-file-storage"`
+	S3Storage        das.S3StorageServiceConfig        `koanf:"s3-storage"`
+	RestAggregator   das.RestfulClientAggregatorConfig `koanf:"rest-aggregator"`
+	Scrubber         das.ScrubberConfig                `koanf:"scrubber"`
+	Conf             genericconf.ConfConfig            `koanf:"conf"`
+}
+
+func parseScrubConfig(args []string) (*ScrubConfig, error) {
+	f := flag.NewFlagSet("datool scrub", flag.ContinueOnError)
+
+	storageConfigAddOptions(f)
+	das.RestfulClientAggregatorConfigAddOptions("rest-aggregator", f)
+	f.Uint64("scrubber.max-bytes-per-second", das.DefaultScrubberConfig.MaxBytesPerSecond, "maximum rate at which stored batches are read (0 for unlimited)")
+	f.Bool("scrubber.repair", das.DefaultScrubberConfig.Repair, "re-fetch missing and corrupt batches from the REST aggregator, if it's enabled")
+	f.Duration("scrubber.repair-retention-time", das.DefaultScrubberConfig.RepairRetentionTime, "retention time of repaired batches whose original expiry time isn't known")
+	genericconf.ConfConfigAddOptions("conf", f)
+
+	k, err := confighelpers.BeginCommonParse(f, args)
+	if err != nil {
+		return nil, err
+	}
+
+	var config ScrubConfig
+	if err := confighelpers.EndCommonParse(k, &config); err != nil {
+		return nil, err
+	}
+
+	if config.Conf.Dump {
+		c, err := k.Marshal(koanfjson.Parser())
+		if err != nil {
+			return nil, fmt.Errorf("unable to marshal config file to JSON: %w", err)
+		}
+
+		fmt.Println(string(c))
+		os.Exit(0)
+	}
+
+	return &config, nil
+}
+
+func scrub(args []string) error {
+	config, err := parseScrubConfig(args)
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+
+	storageService, lifecycleManager, err := createStorage(ctx, config.LocalDBStorage, config.LocalFileStorage, config.S3Storage)
+	if err != nil {
+		return err
+	}
+	defer lifecycleManager.StopAndWaitUntil(time.Second)
+
+	var source das.DataAvailabilityServiceReader
+	if config.RestAggregator.Enable {
+		restAgg, err := das.NewRestfulClientAggregator(ctx, &config.RestAggregator)
+		if err != nil {
+			return err
+		}
+		restAgg.Start(ctx)
+		lifecycleManager.Register(restAgg)
+		source = restAgg
+	}
+
+	scrubber, err := das.NewScrubber(config.Scrubber, storageService, source)
+	if err != nil {
+		return err
+	}
+	stats, err := scrubber.ScrubOnce(ctx)
+	fmt.Printf("Checked: %d\nMismatched: %d\nMissing: %d\nRepaired: %d\n", stats.Checked, stats.Mismatched, stats.Missing, stats.Repaired)
+	return err
+}
+
+// datool export
+
+type ExportConfig struct {
+	LocalDBStorage   das.LocalDBStorageConfig   `koanf:"local-db-storage"`
+	LocalFileStorage das.LocalFileStorageConfig `koanf:"local-file-storage"`
+	S3Storage        das.S3StorageServiceConfig `koanf:"s3-storage"`
+	Archive          string                     `koanf:"archive"`
+	MinExpiry        uint64                     `koanf:"min-expiry"`
+	MaxExpiry        uint64                     `koanf:"max-expiry"`
+	Conf             genericconf.ConfConfig     `koanf:"conf"`
+}
+
+func parseExportConfig(args []string) (*ExportConfig, error) {
+	f := flag.NewFlagSet("datool export", flag.ContinueOnError)
+
+	storageConfigAddOptions(f)
+	f.String("archive", "", "file to write the archive to")
+	f.Uint64("min-expiry", 0, "only export batches expiring at or after this unix timestamp (0 for no lower bound)")
+	f.Uint64("max-expiry", 0, "only export batches expiring at or before this unix timestamp (0 for no upper bound)")
+	genericconf.ConfConfigAddOptions("conf", f)
+
+	k, err := confighelpers.BeginCommonParse(f, args)
+	if err != nil {
+		return nil, err
+	}
+
+	var config ExportConfig
+	if err := confighelpers.EndCommonParse(k, &config); err != nil {
+		return nil, err
+	}
+
+	if config.Conf.Dump {
+		c, err := k.Marshal(koanfjson.Parser())
+		if err != nil {
+			return nil, fmt.Errorf("unable to marshal config file to JSON: %w", err)
+		}
+
+		fmt.Println(string(c))
+		os.Exit(0)
+	}
+
+	if config.Archive == "" {
+		return nil, errors.New("--archive must be set")
+	}
+	if config.MaxExpiry != 0 && config.MaxExpiry < config.MinExpiry {
+		return nil, errors.New("--max-expiry must not be before --min-expiry")
+	}
+
+	return &config, nil
+}
+
+func exportArchive(args []string) error {
+	config, err := parseExportConfig(args)
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+
+	storageService, lifecycleManager, err := createStorage(ctx, config.LocalDBStorage, config.LocalFileStorage, config.S3Storage)
+	if err != nil {
+		return err
+	}
+	defer lifecycleManager.StopAndWaitUntil(time.Second)
+
+	// Write to a temporary file so an interrupted export doesn't leave a valid looking archive behind
+	tmpPath := config.Archive + ".tmp"
+	file, err := os.Create(tmpPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	exported, err := das.ExportArchive(ctx, storageService, file, config.MinExpiry, config.MaxExpiry)
+	if err != nil {
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, config.Archive); err != nil {
+		return err
+	}
+	fmt.Printf("Exported %d batches to %s\n", exported, config.Archive)
+	return nil
+}
+
+// datool import
+
+type ImportConfig struct {
+	LocalDBStorage   das.LocalDBStorageConfig   `koanf:"local-db-storage"`
+	LocalFileStorage das.LocalFileStorageConfig `koanf:"local-file-storage"`
+	S3Storage        das.S3StorageServiceConfig `koanf:"s3-storage"`
+	Archive          string                     `koanf:"archive"`
+	ProgressFile     string                     `koanf:"progress-file"`
+	ProgressInterval uint64                     `koanf:"progress-interval"`
+	RetentionTime    time.Duration              `koanf:"retention-time"`
+	Verify           bool                       `koanf:"verify"`
+	Conf             genericconf.ConfConfig     `koanf:"conf"`
+}
+
+func parseImportConfig(args []string) (*ImportConfig, error) {
+	f := flag.NewFlagSet("datool import", flag.ContinueOnError)
+
+	storageConfigAddOptions(f)
+	f.String("archive", "", "archive file to import")
+	f.String("progress-file", "", "file recording how much of the archive has been imported, so an interrupted import can resume (defaults to the archive path with a .progress suffix)")
+	f.Uint64("progress-interval", 1000, "number of batches between progress updates")
+	f.Duration("retention-time", 24*21*time.Hour, "retention time of batches whose expiry time the archive doesn't know")
+	f.Bool("verify", true, "once imported, check that the storage holds every unexpired batch of the archive")
+	genericconf.ConfConfigAddOptions("conf", f)
+
+	k, err := confighelpers.BeginCommonParse(f, args)
+	if err != nil {
+		return nil, err
+	}
+
+	var config ImportConfig
+	if err := confighelpers.EndCommonParse(k, &config); err != nil {
+		return nil, err
+	}
+
+	if config.Conf.Dump {
+		c, err := k.Marshal(koanfjson.Parser())
+		if err != nil {
+			return nil, fmt.Errorf("unable to marshal config file to JSON: %w", err)
+		}
+
+		fmt.Println(string(c))
+		os.Exit(0)
+	}
+
+	if config.Archive == "" {
+		return nil, errors.New("--archive must be set")
+	}
+	if config.ProgressFile == "" {
+		config.ProgressFile = config.Archive + ".progress"
+	}
+
+	return &config, nil
+}
+
+func importArchive(args []string) error {
+	config, err := parseImportConfig(args)
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+
+	var skip uint64
+	progress, err := os.ReadFile(config.ProgressFile)
+	if err == nil {
+		skip, err = strconv.ParseUint(strings.TrimSpace(string(progress)), 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid progress file %s: %w", config.ProgressFile, err)
+		}
+		fmt.Printf("Resuming import after %d batches\n", skip)
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	storageService, lifecycleManager, err := createStorage(ctx, config.LocalDBStorage, config.LocalFileStorage, config.S3Storage)
+	if err != nil {
+		return err
+	}
+	defer lifecycleManager.StopAndWaitUntil(time.Second)
+
+	file, err := os.Open(config.Archive)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	stats, err := das.ImportArchive(ctx, storageService, file, das.ImportOptions{
+		Skip:          skip,
+		RetentionTime: config.RetentionTime,
+		Progress: func(handled uint64) error {
+			return os.WriteFile(config.ProgressFile, []byte(strconv.FormatUint(handled, 10)), 0o600)
+		},
+		ProgressInterval: config.ProgressInterval,
+	})
+	fmt.Printf("Imported: %d\nExpired: %d\nAlready imported: %d\n", stats.Imported, stats.Expired, stats.Skipped)
+	if err != nil {
+		return err
+	}
+
+	if config.Verify {
+		if _, err := file.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+		verifyStats, err := das.VerifyArchive(ctx, storageService, file)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Verified: %d\nMissing: %d\nMismatched: %d\n", verifyStats.Checked, verifyStats.Missing, verifyStats.Mismatched)
+		if verifyStats.Missing != 0 || verifyStats.Mismatched != 0 {
+			return errors.New("verification failed, the storage doesn't hold every batch of the archive")
+		}
+	}
+	return os.Remove(config.ProgressFile)
+}
@@ -42,6 +42,7 @@ type AggregatorConfig struct {
 	Backends              BackendConfigList `koanf:"backends"`
 	MaxStoreChunkBodySize int               `koanf:"max-store-chunk-body-size"`
 	EnableChunkedStore    bool              `koanf:"enable-chunked-store"`
+	WriteQuorum           WriteQuorumConfig `koanf:"write-quorum"`
 }
 
 var DefaultAggregatorConfig = AggregatorConfig{
@@ -49,6 +50,7 @@ var DefaultAggregatorConfig = AggregatorConfig{
 	Backends:              nil,
 	MaxStoreChunkBodySize: 512 * 1024,
 	EnableChunkedStore:    true,
+	WriteQuorum:           DefaultWriteQuorumConfig,
 }
 
 var parsedBackendsConf BackendConfigList
@@ -59,6 +61,7 @@ func AggregatorConfigAddOptions(prefix string, f *flag.FlagSet) {
 	f.Var(&parsedBackendsConf, prefix+".backends", "JSON RPC backend configuration. This can be specified on the command line as a JSON array, eg: [{\"url\": \"...\", \"pubkey\": \"...\"},...], or as a JSON array in the config file.")
 	f.Int(prefix+".max-store-chunk-body-size", DefaultAggregatorConfig.MaxStoreChunkBodySize, "maximum HTTP POST body size to use for individual batch chunks, including JSON RPC overhead and an estimated overhead of 512B of headers")
 	f.Bool(prefix+".enable-chunked-store", DefaultAggregatorConfig.EnableChunkedStore, "enable data to be sent to DAS in chunks instead of all at once")
+	WriteQuorumConfigAddOptions(prefix+".write-quorum", f)
 }
 
 type Aggregator struct {
@@ -71,6 +74,7 @@ type Aggregator struct {
 	maxAllowedServiceStoreFailures int
 	keysetHash                     [32]byte
 	keysetBytes                    []byte
+	health                         *memberHealthTracker
 }
 
 type ServiceDetails struct {
@@ -139,7 +143,13 @@ func NewAggregatorWithSeqInboxCaller(
 		return nil, err
 	}
 
-	return &Aggregator{
+	if config.RPCAggregator.WriteQuorum.Enable {
+		if err := config.RPCAggregator.WriteQuorum.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	a := &Aggregator{
 		config:                         config.RPCAggregator,
 		services:                       services,
 		requestTimeout:                 config.RequestTimeout,
@@ -147,13 +157,17 @@ func NewAggregatorWithSeqInboxCaller(
 		maxAllowedServiceStoreFailures: config.RPCAggregator.AssumedHonest - 1,
 		keysetHash:                     keysetHash,
 		keysetBytes:                    keysetBytes,
-	}, nil
+	}
+	a.health = newMemberHealthTracker(&a.config.WriteQuorum, services)
+	return a, nil
 }
 
 type storeResponse struct {
+	index   int
 	details ServiceDetails
 	sig     blsSignatures.Signature
 	err     error
+	latency time.Duration
 }
 
 // Store calls Store on each backend DAS in parallel and collects responses.
@@ -164,6 +178,12 @@ type storeResponse struct {
 // continue running until the context is canceled (eg via TimeoutWrapper),
 // with their results discarded.
 //
+// If the write quorum is enabled, Store instead starts with the K backends
+// with the best latency and success rate, plus write-quorum.extra-backends,
+// and only calls further backends as others fail or take longer than
+// write-quorum.hedge-delay. Once there are K responses the remaining calls are
+// canceled.
+//
 // If Store gets enough errors that K successes is impossible, then it stops early
 // and returns an error.
 //
@@ -184,60 +204,92 @@ func (a *Aggregator) Store(ctx context.Context, message []byte, timeout uint64)
 
 	responses := make(chan storeResponse, len(a.services))
 
+	storesCtx, cancelStores := context.WithCancel(ctx)
+	var quorumReached atomic.Bool
 	expectedHash := dastree.Hash(message)
-	for _, d := range a.services {
-		go func(ctx context.Context, d ServiceDetails) {
-			storeCtx, cancel := context.WithTimeout(ctx, a.requestTimeout)
-			var metricWithServiceName = metricBase + "/" + d.metricName
-			defer cancel()
-			incFailureMetric := func() {
-				metrics.GetOrRegisterCounter(metricWithServiceName+"/error/total", nil).Inc(1)
-				metrics.GetOrRegisterCounter(metricBase+"/error/all/total", nil).Inc(1)
-			}
+	store := func(ctx context.Context, index int, d ServiceDetails) {
+		storeCtx, cancel := context.WithTimeout(ctx, a.requestTimeout)
+		var metricWithServiceName = metricBase + "/" + d.metricName
+		defer cancel()
+		start := time.Now()
+		incFailureMetric := func() {
+			metrics.GetOrRegisterCounter(metricWithServiceName+"/error/total", nil).Inc(1)
+			metrics.GetOrRegisterCounter(metricBase+"/error/all/total", nil).Inc(1)
+		}
 
-			cert, err := d.service.Store(storeCtx, message, timeout)
-			if err != nil {
-				incFailureMetric()
-				log.Warn("DAS Aggregator failed to store batch to backend", "backend", d.metricName, "err", err)
-				responses <- storeResponse{d, nil, err}
+		cert, err := d.service.Store(storeCtx, message, timeout)
+		if err != nil {
+			if quorumReached.Load() && errors.Is(err, context.Canceled) {
+				// Canceled by the write quorum, not a failure of the backend.
+				responses <- storeResponse{index, d, nil, err, 0}
 				return
 			}
+			incFailureMetric()
+			log.Warn("DAS Aggregator failed to store batch to backend", "backend", d.metricName, "err", err)
+			responses <- storeResponse{index, d, nil, err, time.Since(start)}
+			return
+		}
 
-			verified, err := blsSignatures.VerifySignature(
-				cert.Sig, cert.SerializeSignableFields(), d.pubKey,
-			)
-			if err != nil {
-				incFailureMetric()
-				log.Warn("DAS Aggregator couldn't parse backend's store response signature", "backend", d.metricName, "err", err)
-				responses <- storeResponse{d, nil, err}
-				return
-			}
-			if !verified {
-				incFailureMetric()
-				log.Warn("DAS Aggregator failed to verify backend's store response signature", "backend", d.metricName, "err", err)
-				responses <- storeResponse{d, nil, errors.New("signature verification failed")}
-				return
-			}
+		verified, err := blsSignatures.VerifySignature(
+			cert.Sig, cert.SerializeSignableFields(), d.pubKey,
+		)
+		if err != nil {
+			incFailureMetric()
+			log.Warn("DAS Aggregator couldn't parse backend's store response signature", "backend", d.metricName, "err", err)
+			responses <- storeResponse{index, d, nil, err, time.Since(start)}
+			return
+		}
+		if !verified {
+			incFailureMetric()
+			log.Warn("DAS Aggregator failed to verify backend's store response signature", "backend", d.metricName, "err", err)
+			responses <- storeResponse{index, d, nil, errors.New("signature verification failed"), time.Since(start)}
+			return
+		}
 
-			// SignersMask from backend DAS is ignored.
+		// SignersMask from backend DAS is ignored.
 
-			if cert.DataHash != expectedHash {
-				incFailureMetric()
-				log.Warn("DAS Aggregator got a store response with a data hash not matching the expected hash", "backend", d.metricName, "dataHash", cert.DataHash, "expectedHash", expectedHash, "err", err)
-				responses <- storeResponse{d, nil, errors.New("hash verification failed")}
-				return
-			}
-			if cert.Timeout != timeout {
-				incFailureMetric()
-				log.Warn("DAS Aggregator got a store response with any expiry time not matching the expected expiry time", "backend", d.metricName, "dataHash", cert.DataHash, "expectedHash", expectedHash, "err", err)
-				responses <- storeResponse{d, nil, fmt.Errorf("timeout was %d, expected %d", cert.Timeout, timeout)}
-				return
-			}
+		if cert.DataHash != expectedHash {
+			incFailureMetric()
+			log.Warn("DAS Aggregator got a store response with a data hash not matching the expected hash", "backend", d.metricName, "dataHash", cert.DataHash, "expectedHash", expectedHash, "err", err)
+			responses <- storeResponse{index, d, nil, errors.New("hash verification failed"), time.Since(start)}
+			return
+		}
+		if cert.Timeout != timeout {
+			incFailureMetric()
+			log.Warn("DAS Aggregator got a store response with any expiry time not matching the expected expiry time", "backend", d.metricName, "dataHash", cert.DataHash, "expectedHash", expectedHash, "err", err)
+			responses <- storeResponse{index, d, nil, fmt.Errorf("timeout was %d, expected %d", cert.Timeout, timeout), time.Since(start)}
+			return
+		}
 
-			metrics.GetOrRegisterCounter(metricWithServiceName+"/success/total", nil).Inc(1)
-			metrics.GetOrRegisterCounter(metricBase+"/success/all/total", nil).Inc(1)
-			responses <- storeResponse{d, cert.Sig, nil}
-		}(ctx, d)
+		metrics.GetOrRegisterCounter(metricWithServiceName+"/success/total", nil).Inc(1)
+		metrics.GetOrRegisterCounter(metricBase+"/success/all/total", nil).Inc(1)
+		responses <- storeResponse{index, d, cert.Sig, nil, time.Since(start)}
+	}
+
+	// Backends are called in this order; all at once unless the write quorum is enabled.
+	var order []int
+	initialStores := len(a.services)
+	if a.config.WriteQuorum.Enable {
+		order = a.health.order()
+		initialStores = min(len(order), a.requiredServicesForStore+a.config.WriteQuorum.ExtraBackends)
+	} else {
+		order = make([]int, len(a.services))
+		for i := range order {
+			order[i] = i
+		}
+	}
+	launched := 0
+	launchNext := func() bool {
+		if launched >= len(order) {
+			return false
+		}
+		index := order[launched]
+		launched++
+		go store(storesCtx, index, a.services[index])
+		return true
+	}
+	for launched < initialStores {
+		launchNext()
 	}
 
 	var aggCert daprovider.DataAvailabilityCertificate
@@ -253,19 +305,45 @@ func (a *Aggregator) Store(ctx context.Context, message []byte, timeout uint64)
 	// Collect responses from backends.
 	certDetailsChan := make(chan certDetails)
 	go func() {
+		defer cancelStores()
+		var hedge <-chan time.Time
+		if a.config.WriteQuorum.Enable && a.config.WriteQuorum.HedgeDelay > 0 {
+			hedgeTicker := time.NewTicker(a.config.WriteQuorum.HedgeDelay)
+			defer hedgeTicker.Stop()
+			hedge = hedgeTicker.C
+		}
 		var pubKeys []blsSignatures.PublicKey
 		var sigs []blsSignatures.Signature
 		var aggSignersMask uint64
 		var successfullyStoredCount int
 		var returned int // 0-no status, 1-succeeded, 2-failed
-		for i := 0; i < len(a.services); i++ {
+		for received := 0; received < launched; {
 			select {
 			case <-ctx.Done():
-				break
+				if returned == 0 {
+					certDetailsChan <- certDetails{err: fmt.Errorf("aggregator failed to store message to at least %d out of %d DASes before the context ended: %w. %w", a.requiredServicesForStore, len(a.services), ctx.Err(), daprovider.ErrBatchToDasFailed)}
+				}
+				return
+			case <-hedge:
+				if returned == 0 && launchNext() {
+					log.Debug("das.Aggregator: quorum not reached in time, also storing to next backend", "backend", a.services[order[launched-1]].metricName)
+				}
+				continue
 			case r := <-responses:
+				received++
+				if r.err != nil && quorumReached.Load() && errors.Is(r.err, context.Canceled) {
+					continue
+				}
+				if ctx.Err() == nil {
+					// Stores cut short because the caller's context ended don't reflect on the backend
+					a.health.record(r.index, r.details.metricName, r.latency, r.err == nil)
+				}
 				if r.err != nil {
 					_ = storeFailures.Add(1)
 					log.Warn("das.Aggregator: Error from backend", "backend", r.details.service, "signerMask", r.details.signersMask, "err", r.err)
+					if returned == 0 && a.config.WriteQuorum.Enable {
+						launchNext()
+					}
 				} else {
 					pubKeys = append(pubKeys, r.details.pubKey)
 					sigs = append(sigs, r.sig)
@@ -285,6 +363,10 @@ func (a *Aggregator) Store(ctx context.Context, message []byte, timeout uint64)
 					cd.pubKeys = append(cd.pubKeys, pubKeys...)
 					cd.sigs = append(cd.sigs, sigs...)
 					cd.aggSignersMask = aggSignersMask
+					if a.config.WriteQuorum.Enable {
+						quorumReached.Store(true)
+						cancelStores()
+					}
 					certDetailsChan <- cd
 					returned = 1
 				} else if int(storeFailures.Load()) > a.maxAllowedServiceStoreFailures {
@@ -333,6 +415,11 @@ func (a *Aggregator) Store(ctx context.Context, message []byte, timeout uint64)
 	return &aggCert, nil
 }
 
+// MemberHealth returns the recent latency and success rate of each backend, and whether it's on probation.
+func (a *Aggregator) MemberHealth() []AggregatorMemberHealth {
+	return a.health.health(a.services)
+}
+
 func (a *Aggregator) String() string {
 	var b bytes.Buffer
 	b.WriteString("das.Aggregator{")
@@ -0,0 +1,207 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+package das
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	flag "github.com/spf13/pflag"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+type WriteQuorumConfig struct {
+	Enable              bool          `koanf:"enable"`
+	ExtraBackends       int           `koanf:"extra-backends"`
+	HedgeDelay          time.Duration `koanf:"hedge-delay"`
+	MaxPerBackendStats  int           `koanf:"max-per-backend-stats"`
+	ExploreIterations   uint32        `koanf:"explore-iterations"`
+	ExploitIterations   uint32        `koanf:"exploit-iterations"`
+	ProbationAfterFails int           `koanf:"probation-after-fails"`
+	ProbationDuration   time.Duration `koanf:"probation-duration"`
+}
+
+var DefaultWriteQuorumConfig = WriteQuorumConfig{
+	Enable:              false,
+	ExtraBackends:       1,
+	HedgeDelay:          2 * time.Second,
+	MaxPerBackendStats:  20,
+	ExploreIterations:   20,
+	ExploitIterations:   1000,
+	ProbationAfterFails: 5,
+	ProbationDuration:   10 * time.Minute,
+}
+
+func WriteQuorumConfigAddOptions(prefix string, f *flag.FlagSet) {
+	f.Bool(prefix+".enable", DefaultWriteQuorumConfig.Enable, "only send batches to the fastest and most reliable backends needed for the signer quorum, instead of to every backend, and stop once the quorum is reached")
+	f.Int(prefix+".extra-backends", DefaultWriteQuorumConfig.ExtraBackends, "number of backends beyond the quorum to send each batch to straight away")
+	f.Duration(prefix+".hedge-delay", DefaultWriteQuorumConfig.HedgeDelay, "time to wait for the quorum before also sending the batch to the next backend; 0 to only do so when a backend fails")
+	f.Int(prefix+".max-per-backend-stats", DefaultWriteQuorumConfig.MaxPerBackendStats, "number of stats entries (latency and success) to keep for each backend")
+	f.Uint32(prefix+".explore-iterations", DefaultWriteQuorumConfig.ExploreIterations, "number of consecutive stores that choose backends in a random order, before switching to exploit mode")
+	f.Uint32(prefix+".exploit-iterations", DefaultWriteQuorumConfig.ExploitIterations, "number of consecutive stores that choose backends in order of best latency and success rate, before switching to explore mode")
+	f.Int(prefix+".probation-after-fails", DefaultWriteQuorumConfig.ProbationAfterFails, "number of consecutive failed stores after which a backend is put on probation, and only used when the other backends can't reach the quorum; 0 to disable")
+	f.Duration(prefix+".probation-duration", DefaultWriteQuorumConfig.ProbationDuration, "how long a backend stays on probation, unless it succeeds a store first")
+}
+
+func (c *WriteQuorumConfig) Validate() error {
+	if c.ExtraBackends < 0 {
+		return errors.New("write-quorum.extra-backends must not be negative")
+	}
+	if c.MaxPerBackendStats <= 0 {
+		return errors.New("write-quorum.max-per-backend-stats must be positive")
+	}
+	if c.ExploreIterations+c.ExploitIterations == 0 {
+		return errors.New("write-quorum.explore-iterations and exploit-iterations can't both be 0")
+	}
+	return nil
+}
+
+type memberHealth struct {
+	// readerStats has nothing specific to readers, so it's reused for backend stores.
+	stats               readerStats
+	consecutiveFailures int
+	probationUntil      time.Time
+	probationGauge      *metrics.Gauge
+}
+
+// memberHealthTracker keeps the latency and success of the recent stores to each aggregator backend,
+// and decides the order in which backends are sent batches.
+type memberHealthTracker struct {
+	config     *WriteQuorumConfig
+	mutex      sync.Mutex
+	iterations uint32
+	members    []memberHealth
+}
+
+func newMemberHealthTracker(config *WriteQuorumConfig, services []ServiceDetails) *memberHealthTracker {
+	trackerConfig := *config
+	if trackerConfig.MaxPerBackendStats <= 0 {
+		// Health is tracked even with the write quorum disabled, for MemberHealth.
+		trackerConfig.MaxPerBackendStats = DefaultWriteQuorumConfig.MaxPerBackendStats
+	}
+	members := make([]memberHealth, len(services))
+	for i, d := range services {
+		members[i].stats = make(readerStats, 0, trackerConfig.MaxPerBackendStats)
+		members[i].probationGauge = metrics.GetOrRegisterGauge(metricBase+"/"+d.metricName+"/probation", nil)
+	}
+	return &memberHealthTracker{
+		config:  &trackerConfig,
+		members: members,
+	}
+}
+
+func (t *memberHealthTracker) record(i int, name string, latency time.Duration, success bool) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	m := &t.members[i]
+	m.stats = append(m.stats, readerStat{latency: latency, success: success})
+	if len(m.stats) > t.config.MaxPerBackendStats {
+		m.stats = m.stats[len(m.stats)-t.config.MaxPerBackendStats:]
+	}
+	if success {
+		if !m.probationUntil.IsZero() {
+			log.Info("DAS Aggregator backend is off probation", "backend", name)
+		}
+		m.consecutiveFailures = 0
+		m.probationUntil = time.Time{}
+		m.probationGauge.Update(0)
+		return
+	}
+	m.consecutiveFailures++
+	now := time.Now()
+	if t.config.ProbationAfterFails > 0 && m.consecutiveFailures >= t.config.ProbationAfterFails && !now.Before(m.probationUntil) {
+		m.probationUntil = now.Add(t.config.ProbationDuration)
+		m.probationGauge.Update(1)
+		log.Warn("DAS Aggregator backend put on probation", "backend", name, "consecutiveFailures", m.consecutiveFailures, "until", m.probationUntil)
+	}
+}
+
+// order returns the indexes of the backends in the order they should be sent a batch, alternating between
+// exploring in a random order and exploiting the best success-weighted latency. Backends on probation come last.
+func (t *memberHealthTracker) order() []int {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.iterations++
+
+	order := make([]int, len(t.members))
+	for i := range order {
+		order[i] = i
+	}
+	if t.iterations%(t.config.ExploreIterations+t.config.ExploitIterations) < t.config.ExploreIterations {
+		rand.Shuffle(len(order), func(i, j int) { order[i], order[j] = order[j], order[i] })
+	} else {
+		sort.SliceStable(order, func(i, j int) bool {
+			return t.members[order[i]].stats.successRatioWeightedMeanLatency() < t.members[order[j]].stats.successRatioWeightedMeanLatency()
+		})
+	}
+	now := time.Now()
+	sort.SliceStable(order, func(i, j int) bool {
+		return !now.Before(t.members[order[i]].probationUntil) && now.Before(t.members[order[j]].probationUntil)
+	})
+	return order
+}
+
+type AggregatorMemberHealth struct {
+	Name                string         `json:"name"`
+	SignersMask         hexutil.Uint64 `json:"signersMask"`
+	Attempts            int            `json:"attempts"`
+	Successes           int            `json:"successes"`
+	MeanLatencyMillis   int64          `json:"meanLatencyMillis"`
+	ConsecutiveFailures int            `json:"consecutiveFailures"`
+	OnProbation         bool           `json:"onProbation"`
+	ProbationUntil      *time.Time     `json:"probationUntil,omitempty"`
+}
+
+func (t *memberHealthTracker) health(services []ServiceDetails) []AggregatorMemberHealth {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	now := time.Now()
+	health := make([]AggregatorMemberHealth, len(services))
+	for i, d := range services {
+		m := &t.members[i]
+		h := AggregatorMemberHealth{
+			Name:                d.metricName,
+			SignersMask:         hexutil.Uint64(d.signersMask),
+			Attempts:            len(m.stats),
+			ConsecutiveFailures: m.consecutiveFailures,
+		}
+		var totalLatency time.Duration
+		for _, stat := range m.stats {
+			if stat.success {
+				h.Successes++
+				totalLatency += stat.latency
+			}
+		}
+		if h.Successes > 0 {
+			h.MeanLatencyMillis = (totalLatency / time.Duration(h.Successes)).Milliseconds()
+		}
+		if now.Before(m.probationUntil) {
+			probationUntil := m.probationUntil
+			h.OnProbation = true
+			h.ProbationUntil = &probationUntil
+		}
+		health[i] = h
+	}
+	return health
+}
+
+// AggregatorAPI is served by the batch poster to show the health of the committee members it stores batches to.
+type AggregatorAPI struct {
+	aggregator *Aggregator
+}
+
+func NewAggregatorAPI(aggregator *Aggregator) *AggregatorAPI {
+	return &AggregatorAPI{aggregator: aggregator}
+}
+
+func (api *AggregatorAPI) MemberHealth(ctx context.Context) ([]AggregatorMemberHealth, error) {
+	return api.aggregator.MemberHealth(), nil
+}
@@ -9,6 +9,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"math/bits"
 	"math/rand"
 	"os"
 	"strconv"
@@ -20,6 +21,7 @@ import (
 
 	"github.com/offchainlabs/nitro/arbstate/daprovider"
 	"github.com/offchainlabs/nitro/blsSignatures"
+	"github.com/offchainlabs/nitro/das/dastree"
 )
 
 func TestDAS_BasicAggregationLocal(t *testing.T) {
@@ -276,3 +278,119 @@ func TestDAS_AtLeastHStorageFailures(t *testing.T) {
 		})
 	}
 }
+
+type alwaysFail struct{}
+
+func (alwaysFail) shouldFail() failureType {
+	return immediateError
+}
+
+type neverFail struct{}
+
+func (neverFail) shouldFail() failureType {
+	return success
+}
+
+func TestDAS_WriteQuorum(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	numBackendDAS := 5
+	var backends []ServiceDetails
+	var storageServices []StorageService
+	for i := 0; i < numBackendDAS; i++ {
+		privKey, err := blsSignatures.GeneratePrivKeyString()
+		Require(t, err)
+
+		config := DataAvailabilityConfig{
+			Enable: true,
+			Key: KeyConfig{
+				PrivKey: privKey,
+			},
+			ParentChainNodeURL: "none",
+		}
+
+		storageServices = append(storageServices, NewMemoryBackedStorageService(ctx))
+		das, err := NewSignAfterStoreDASWriter(ctx, config, storageServices[i])
+		Require(t, err)
+		var injector failureInjector = neverFail{}
+		if i == 0 {
+			injector = alwaysFail{}
+		}
+		details, err := NewServiceDetails(&WrapStore{t, injector, das}, *das.pubKey, uint64(1<<i), "service"+strconv.Itoa(i))
+		Require(t, err)
+		backends = append(backends, *details)
+	}
+
+	writeQuorum := DefaultWriteQuorumConfig
+	writeQuorum.Enable = true
+	writeQuorum.ExtraBackends = 0
+	writeQuorum.HedgeDelay = 0
+	writeQuorum.ExploreIterations = 0
+	writeQuorum.ExploitIterations = 1
+	writeQuorum.ProbationAfterFails = 1
+	aggregator, err := NewAggregator(ctx, DataAvailabilityConfig{
+		RPCAggregator: AggregatorConfig{
+			AssumedHonest: 3,
+			WriteQuorum:   writeQuorum,
+		},
+		ParentChainNodeURL: "none",
+		RequestTimeout:     time.Second * 5,
+	}, backends)
+	Require(t, err)
+
+	storedTo := func(message []byte) int {
+		count := 0
+		for _, storageService := range storageServices {
+			if _, err := storageService.GetByHash(ctx, dastree.Hash(message)); err == nil {
+				count++
+			}
+		}
+		return count
+	}
+
+	// The failing backend is tried first, and replaced by the next one
+	first := []byte("first")
+	cert, err := aggregator.Store(ctx, first, 0)
+	Require(t, err)
+	if bits.OnesCount64(cert.SignersMask) != 3 || cert.SignersMask&1 != 0 {
+		Fail(t, "unexpected signers", cert.SignersMask)
+	}
+	if stored := storedTo(first); stored != 3 {
+		Fail(t, "stored to", stored, "backends, expected 3")
+	}
+
+	health := aggregator.MemberHealth()
+	if !health[0].OnProbation || health[0].ConsecutiveFailures != 1 {
+		Fail(t, "failing backend isn't on probation", health[0])
+	}
+	if health[4].Attempts != 0 {
+		Fail(t, "backend outside the quorum was tried", health[4])
+	}
+
+	// The backend on probation is no longer tried first
+	second := []byte("second")
+	_, err = aggregator.Store(ctx, second, 0)
+	Require(t, err)
+	if stored := storedTo(second); stored != 3 {
+		Fail(t, "stored to", stored, "backends, expected 3")
+	}
+	if health := aggregator.MemberHealth(); health[0].Attempts != 1 {
+		Fail(t, "backend on probation was tried again", health[0])
+	}
+}
+
+func TestMemberHealthTrackerDefaultStats(t *testing.T) {
+	config := DefaultWriteQuorumConfig
+	config.MaxPerBackendStats = 0
+	tracker := newMemberHealthTracker(&config, []ServiceDetails{{metricName: "test-default-stats"}})
+	if config.MaxPerBackendStats != 0 {
+		Fail(t, "tracker changed the aggregator's config", config.MaxPerBackendStats)
+	}
+	for i := 0; i < 2*DefaultWriteQuorumConfig.MaxPerBackendStats; i++ {
+		tracker.record(0, "test-default-stats", time.Millisecond, true)
+	}
+	if attempts := tracker.health([]ServiceDetails{{}})[0].Attempts; attempts != DefaultWriteQuorumConfig.MaxPerBackendStats {
+		Fail(t, "tracker kept", attempts, "stats, expected the default", DefaultWriteQuorumConfig.MaxPerBackendStats)
+	}
+}
@@ -0,0 +1,362 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+package das
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/offchainlabs/nitro/das/dastree"
+	"github.com/offchainlabs/nitro/util/pretty"
+)
+
+// A DAS archive is a portable copy of the batches of a storage service:
+//
+//	magic | version (1 byte) | header length (4 bytes) | JSON ArchiveHeader
+//	batch records: 0x00 | key (32 bytes) | expiry (8 bytes) | data length (4 bytes) | data
+//	end record:    0x01 | number of batch records (8 bytes) | keccak256 of all the batch records (32 bytes)
+//
+// An expiry of 0 means the source storage didn't know the batch's expiry time.
+const (
+	archiveMagic   = "NITRO-DAS-ARCHIVE"
+	archiveVersion = 1
+
+	archiveRecordBatch = 0
+	archiveRecordEnd   = 1
+
+	archiveMaxHeaderSize = 1 << 16
+	archiveMaxBatchSize  = 1 << 28
+)
+
+var ErrInvalidArchive = errors.New("invalid DAS archive")
+
+type ArchiveHeader struct {
+	Source    string `json:"source"`
+	CreatedAt int64  `json:"createdAt"`
+	MinExpiry uint64 `json:"minExpiry,omitempty"`
+	MaxExpiry uint64 `json:"maxExpiry,omitempty"`
+}
+
+type ArchiveEntry struct {
+	Key    common.Hash
+	Expiry uint64
+	Data   []byte
+}
+
+type ArchiveWriter struct {
+	w      *bufio.Writer
+	count  uint64
+	digest crypto.KeccakState
+}
+
+func NewArchiveWriter(w io.Writer, header ArchiveHeader) (*ArchiveWriter, error) {
+	encodedHeader, err := json.Marshal(header)
+	if err != nil {
+		return nil, err
+	}
+	bw := bufio.NewWriter(w)
+	prefix := append([]byte(archiveMagic), archiveVersion)
+	// #nosec G115
+	prefix = binary.BigEndian.AppendUint32(prefix, uint32(len(encodedHeader)))
+	if _, err := bw.Write(append(prefix, encodedHeader...)); err != nil {
+		return nil, err
+	}
+	return &ArchiveWriter{
+		w:      bw,
+		digest: crypto.NewKeccakState(),
+	}, nil
+}
+
+func (a *ArchiveWriter) Write(entry ArchiveEntry) error {
+	if len(entry.Data) > archiveMaxBatchSize {
+		return fmt.Errorf("batch %v is too large to archive (%v bytes)", entry.Key, len(entry.Data))
+	}
+	record := make([]byte, 0, 1+32+8+4)
+	record = append(record, archiveRecordBatch)
+	record = append(record, entry.Key.Bytes()...)
+	record = binary.BigEndian.AppendUint64(record, entry.Expiry)
+	// #nosec G115
+	record = binary.BigEndian.AppendUint32(record, uint32(len(entry.Data)))
+	for _, part := range [][]byte{record, entry.Data} {
+		a.digest.Write(part)
+		if _, err := a.w.Write(part); err != nil {
+			return err
+		}
+	}
+	a.count++
+	return nil
+}
+
+// Close writes the end record and flushes the archive, without closing the underlying writer.
+func (a *ArchiveWriter) Close() error {
+	record := []byte{archiveRecordEnd}
+	record = binary.BigEndian.AppendUint64(record, a.count)
+	record = a.digest.Sum(record)
+	if _, err := a.w.Write(record); err != nil {
+		return err
+	}
+	return a.w.Flush()
+}
+
+type ArchiveReader struct {
+	Header ArchiveHeader
+
+	r      *bufio.Reader
+	count  uint64
+	digest crypto.KeccakState
+	done   bool
+}
+
+func NewArchiveReader(r io.Reader) (*ArchiveReader, error) {
+	br := bufio.NewReader(r)
+	prefix := make([]byte, len(archiveMagic)+1+4)
+	if _, err := io.ReadFull(br, prefix); err != nil {
+		return nil, fmt.Errorf("%w: reading header: %w", ErrInvalidArchive, err)
+	}
+	if !bytes.Equal(prefix[:len(archiveMagic)], []byte(archiveMagic)) {
+		return nil, fmt.Errorf("%w: bad magic", ErrInvalidArchive)
+	}
+	if version := prefix[len(archiveMagic)]; version != archiveVersion {
+		return nil, fmt.Errorf("%w: unsupported version %v", ErrInvalidArchive, version)
+	}
+	headerLen := binary.BigEndian.Uint32(prefix[len(archiveMagic)+1:])
+	if headerLen > archiveMaxHeaderSize {
+		return nil, fmt.Errorf("%w: header too large (%v bytes)", ErrInvalidArchive, headerLen)
+	}
+	encodedHeader := make([]byte, headerLen)
+	if _, err := io.ReadFull(br, encodedHeader); err != nil {
+		return nil, fmt.Errorf("%w: reading header: %w", ErrInvalidArchive, err)
+	}
+	reader := &ArchiveReader{
+		r:      br,
+		digest: crypto.NewKeccakState(),
+	}
+	if err := json.Unmarshal(encodedHeader, &reader.Header); err != nil {
+		return nil, fmt.Errorf("%w: decoding header: %w", ErrInvalidArchive, err)
+	}
+	return reader, nil
+}
+
+// Next returns the next batch of the archive, or io.EOF once the end record has been read and checked.
+func (a *ArchiveReader) Next() (ArchiveEntry, error) {
+	if a.done {
+		return ArchiveEntry{}, io.EOF
+	}
+	tag, err := a.r.ReadByte()
+	if err != nil {
+		return ArchiveEntry{}, fmt.Errorf("%w: archive ends without an end record: %w", ErrInvalidArchive, err)
+	}
+	switch tag {
+	case archiveRecordBatch:
+		record := make([]byte, 32+8+4)
+		if _, err := io.ReadFull(a.r, record); err != nil {
+			return ArchiveEntry{}, fmt.Errorf("%w: truncated batch record: %w", ErrInvalidArchive, err)
+		}
+		dataLen := binary.BigEndian.Uint32(record[40:])
+		if dataLen > archiveMaxBatchSize {
+			return ArchiveEntry{}, fmt.Errorf("%w: batch record too large (%v bytes)", ErrInvalidArchive, dataLen)
+		}
+		data := make([]byte, dataLen)
+		if _, err := io.ReadFull(a.r, data); err != nil {
+			return ArchiveEntry{}, fmt.Errorf("%w: truncated batch record: %w", ErrInvalidArchive, err)
+		}
+		a.digest.Write([]byte{tag})
+		a.digest.Write(record)
+		a.digest.Write(data)
+		a.count++
+		return ArchiveEntry{
+			Key:    common.BytesToHash(record[:32]),
+			Expiry: binary.BigEndian.Uint64(record[32:40]),
+			Data:   data,
+		}, nil
+	case archiveRecordEnd:
+		record := make([]byte, 8+32)
+		if _, err := io.ReadFull(a.r, record); err != nil {
+			return ArchiveEntry{}, fmt.Errorf("%w: truncated end record: %w", ErrInvalidArchive, err)
+		}
+		if count := binary.BigEndian.Uint64(record[:8]); count != a.count {
+			return ArchiveEntry{}, fmt.Errorf("%w: end record counts %v batches, read %v", ErrInvalidArchive, count, a.count)
+		}
+		if !bytes.Equal(record[8:], a.digest.Sum(nil)) {
+			return ArchiveEntry{}, fmt.Errorf("%w: checksum mismatch", ErrInvalidArchive)
+		}
+		a.done = true
+		return ArchiveEntry{}, io.EOF
+	default:
+		return ArchiveEntry{}, fmt.Errorf("%w: unknown record type %v", ErrInvalidArchive, tag)
+	}
+}
+
+// ExportArchive writes the batches of storageService whose expiry times are within [minExpiry, maxExpiry]
+// to w, 0 leaving that end of the range open. Batches whose expiry isn't known are always exported.
+func ExportArchive(ctx context.Context, storageService StorageService, w io.Writer, minExpiry, maxExpiry uint64) (uint64, error) {
+	storage, ok := storageService.(iterableStorage)
+	if !ok {
+		return 0, fmt.Errorf("%v can't list its batches, export needs a single local-file, local-db or s3 storage backend", storageService)
+	}
+	writer, err := NewArchiveWriter(w, ArchiveHeader{
+		Source:    storage.String(),
+		CreatedAt: time.Now().Unix(),
+		MinExpiry: minExpiry,
+		MaxExpiry: maxExpiry,
+	})
+	if err != nil {
+		return 0, err
+	}
+	var exported uint64
+	err = storage.iterateEntries(ctx, func(key common.Hash, expiry uint64) error {
+		if expiry != 0 && (expiry < minExpiry || (maxExpiry != 0 && expiry > maxExpiry)) {
+			return nil
+		}
+		data, err := storage.GetByHash(ctx, key)
+		if errors.Is(err, ErrNotFound) {
+			log.Warn("DAS batch disappeared during export", "key", pretty.PrettyHash(key))
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if !dastree.ValidHash(key, data) {
+			log.Error("Not exporting DAS batch that doesn't match its hash", "key", pretty.PrettyHash(key))
+			return nil
+		}
+		exported++
+		return writer.Write(ArchiveEntry{Key: key, Expiry: expiry, Data: data})
+	})
+	if err != nil {
+		return exported, err
+	}
+	return exported, writer.Close()
+}
+
+type ImportOptions struct {
+	// Skip is the number of batch records imported by a previous, interrupted import.
+	Skip uint64
+	// RetentionTime is used for batches whose expiry time the archive doesn't know.
+	RetentionTime time.Duration
+	// Progress, if set, is called with the number of batch records handled so far
+	// every ProgressInterval records, once they've been synced to storage.
+	Progress         func(handled uint64) error
+	ProgressInterval uint64
+}
+
+type ImportStats struct {
+	Imported uint64
+	Expired  uint64
+	Skipped  uint64
+}
+
+// ImportArchive stores the batches of an archive in storageService, skipping expired ones.
+func ImportArchive(ctx context.Context, storageService StorageService, r io.Reader, opts ImportOptions) (ImportStats, error) {
+	var stats ImportStats
+	reader, err := NewArchiveReader(r)
+	if err != nil {
+		return stats, err
+	}
+	var handled uint64
+	reportProgress := func() error {
+		if opts.Progress == nil {
+			return nil
+		}
+		if err := storageService.Sync(ctx); err != nil {
+			return err
+		}
+		return opts.Progress(handled)
+	}
+	for {
+		if ctx.Err() != nil {
+			return stats, ctx.Err()
+		}
+		entry, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return stats, err
+		}
+		handled++
+		if handled <= opts.Skip {
+			stats.Skipped++
+			continue
+		}
+		if !dastree.ValidHash(entry.Key, entry.Data) {
+			return stats, fmt.Errorf("%w: batch %v doesn't match its hash", ErrInvalidArchive, entry.Key)
+		}
+		expiry := entry.Expiry
+		if expiry == 0 {
+			// #nosec G115
+			expiry = uint64(time.Now().Add(opts.RetentionTime).Unix())
+		}
+		// #nosec G115
+		if expiry < uint64(time.Now().Unix()) {
+			stats.Expired++
+		} else {
+			if err := storageService.Put(ctx, entry.Data, expiry); err != nil {
+				return stats, fmt.Errorf("storing batch %v: %w", entry.Key, err)
+			}
+			stats.Imported++
+		}
+		if opts.ProgressInterval != 0 && handled%opts.ProgressInterval == 0 {
+			if err := reportProgress(); err != nil {
+				return stats, err
+			}
+		}
+	}
+	return stats, reportProgress()
+}
+
+type VerifyStats struct {
+	Checked    uint64
+	Expired    uint64
+	Missing    uint64
+	Mismatched uint64
+}
+
+// VerifyArchive checks that storageService holds every unexpired batch of an archive.
+func VerifyArchive(ctx context.Context, storageService StorageService, r io.Reader) (VerifyStats, error) {
+	var stats VerifyStats
+	reader, err := NewArchiveReader(r)
+	if err != nil {
+		return stats, err
+	}
+	for {
+		entry, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return stats, nil
+		}
+		if err != nil {
+			return stats, err
+		}
+		// #nosec G115
+		if entry.Expiry != 0 && entry.Expiry < uint64(time.Now().Unix()) {
+			stats.Expired++
+			continue
+		}
+		stats.Checked++
+		data, err := storageService.GetByHash(ctx, entry.Key)
+		if errors.Is(err, ErrNotFound) {
+			stats.Missing++
+			log.Warn("Archived DAS batch is missing from storage", "key", pretty.PrettyHash(entry.Key))
+			continue
+		}
+		if err != nil {
+			return stats, err
+		}
+		if !bytes.Equal(data, entry.Data) {
+			stats.Mismatched++
+			log.Error("Stored DAS batch doesn't match the archive", "key", pretty.PrettyHash(entry.Key))
+		}
+	}
+}
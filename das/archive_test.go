@@ -0,0 +1,122 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+package das
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/offchainlabs/nitro/das/dastree"
+)
+
+func TestArchiveRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	source := NewMemoryBackedStorageService(ctx)
+	values := []string{"first", "second", "third"}
+	for _, val := range values {
+		Require(t, source.Put(ctx, []byte(val), 0))
+	}
+
+	var archive bytes.Buffer
+	exported, err := ExportArchive(ctx, source, &archive, 0, 0)
+	Require(t, err)
+	if exported != uint64(len(values)) {
+		Fail(t, "exported", exported, "batches, expected", len(values))
+	}
+
+	target := NewMemoryBackedStorageService(ctx)
+	stats, err := ImportArchive(ctx, target, bytes.NewReader(archive.Bytes()), ImportOptions{RetentionTime: time.Hour})
+	Require(t, err)
+	if stats != (ImportStats{Imported: 3}) {
+		Fail(t, "unexpected import stats", stats)
+	}
+	verifyStats, err := VerifyArchive(ctx, target, bytes.NewReader(archive.Bytes()))
+	Require(t, err)
+	if verifyStats != (VerifyStats{Checked: 3}) {
+		Fail(t, "unexpected verify stats", verifyStats)
+	}
+
+	// Resume an import that stopped after the first batch
+	resumed := NewMemoryBackedStorageService(ctx)
+	var progress []uint64
+	stats, err = ImportArchive(ctx, resumed, bytes.NewReader(archive.Bytes()), ImportOptions{
+		Skip:          1,
+		RetentionTime: time.Hour,
+		Progress: func(handled uint64) error {
+			progress = append(progress, handled)
+			return nil
+		},
+		ProgressInterval: 2,
+	})
+	Require(t, err)
+	if stats != (ImportStats{Imported: 2, Skipped: 1}) {
+		Fail(t, "unexpected resumed import stats", stats)
+	}
+	if len(progress) != 2 || progress[0] != 2 || progress[1] != 3 {
+		Fail(t, "unexpected progress updates", progress)
+	}
+	verifyStats, err = VerifyArchive(ctx, resumed, bytes.NewReader(archive.Bytes()))
+	Require(t, err)
+	if verifyStats != (VerifyStats{Checked: 3, Missing: 1}) {
+		Fail(t, "unexpected verify stats of resumed import", verifyStats)
+	}
+}
+
+func TestArchiveExpiryRange(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewLocalFileStorageService(LocalFileStorageConfig{
+		Enable:       true,
+		DataDir:      t.TempDir(),
+		EnableExpiry: true,
+		MaxRetention: time.Hour * 24 * 30,
+	})
+	Require(t, err)
+	Require(t, s.start(ctx))
+
+	// #nosec G115
+	now := uint64(time.Now().Unix())
+	Require(t, s.Put(ctx, []byte("soon"), now+100))
+	Require(t, s.Put(ctx, []byte("later"), now+100_000))
+
+	var archive bytes.Buffer
+	exported, err := ExportArchive(ctx, s, &archive, now+1000, 0)
+	Require(t, err)
+	if exported != 1 {
+		Fail(t, "exported", exported, "batches, expected 1")
+	}
+	reader, err := NewArchiveReader(bytes.NewReader(archive.Bytes()))
+	Require(t, err)
+	entry, err := reader.Next()
+	Require(t, err)
+	if entry.Key != dastree.Hash([]byte("later")) || entry.Expiry != now+100_000 {
+		Fail(t, "unexpected archive entry", entry.Key, entry.Expiry)
+	}
+}
+
+func TestArchiveCorruption(t *testing.T) {
+	ctx := context.Background()
+	var archive bytes.Buffer
+	writer, err := NewArchiveWriter(&archive, ArchiveHeader{Source: "test"})
+	Require(t, err)
+	data := []byte("batch")
+	Require(t, writer.Write(ArchiveEntry{Key: dastree.Hash(data), Data: data}))
+	Require(t, writer.Close())
+	encoded := archive.Bytes()
+
+	truncated := encoded[:len(encoded)-1]
+	_, err = ImportArchive(ctx, NewMemoryBackedStorageService(ctx), bytes.NewReader(truncated), ImportOptions{})
+	if !errors.Is(err, ErrInvalidArchive) {
+		Fail(t, "imported a truncated archive", err)
+	}
+
+	corrupt := bytes.Clone(encoded)
+	corrupt[len(encoded)-40-2] ^= 1
+	_, err = ImportArchive(ctx, NewMemoryBackedStorageService(ctx), bytes.NewReader(corrupt), ImportOptions{})
+	if !errors.Is(err, ErrInvalidArchive) {
+		Fail(t, "imported a corrupt archive", err)
+	}
+}
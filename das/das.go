@@ -46,6 +46,8 @@ type DataAvailabilityConfig struct {
 	LocalFileStorage   LocalFileStorageConfig          `koanf:"local-file-storage"`
 	S3Storage          S3StorageServiceConfig          `koanf:"s3-storage"`
 	GoogleCloudStorage GoogleCloudStorageServiceConfig `koanf:"google-cloud-storage"`
+	ErasureCoding      ErasureCodingConfig             `koanf:"erasure-coding"`
+	Scrubber           ScrubberConfig                  `koanf:"scrubber"`
 
 	MigrateLocalDBToFileStorage bool `koanf:"migrate-local-db-to-file-storage"`
 
@@ -59,6 +61,8 @@ type DataAvailabilityConfig struct {
 	SequencerInboxAddress           string `koanf:"sequencer-inbox-address"`
 	ExtraSignatureCheckingPublicKey string `koanf:"extra-signature-checking-public-key"`
 
+	WriterKeys WriterKeyConfigList `koanf:"writer-keys"`
+
 	PanicOnError             bool `koanf:"panic-on-error"`
 	DisableSignatureChecking bool `koanf:"disable-signature-checking"`
 }
@@ -69,6 +73,8 @@ var DefaultDataAvailabilityConfig = DataAvailabilityConfig{
 	RestAggregator:                DefaultRestfulClientAggregatorConfig,
 	RPCAggregator:                 DefaultAggregatorConfig,
 	ParentChainConnectionAttempts: 15,
+	ErasureCoding:                 DefaultErasureCodingConfig,
+	Scrubber:                      DefaultScrubberConfig,
 	PanicOnError:                  false,
 }
 
@@ -117,12 +123,15 @@ func dataAvailabilityConfigAddOptions(prefix string, f *flag.FlagSet, r role) {
 		LocalFileStorageConfigAddOptions(prefix+".local-file-storage", f)
 		S3ConfigAddOptions(prefix+".s3-storage", f)
 		GoogleCloudConfigAddOptions(prefix+".google-cloud-storage", f)
+		ErasureCodingConfigAddOptions(prefix+".erasure-coding", f)
+		ScrubberConfigAddOptions(prefix+".scrubber", f)
 		f.Bool(prefix+".migrate-local-db-to-file-storage", DefaultDataAvailabilityConfig.MigrateLocalDBToFileStorage, "daserver will migrate all data on startup from local-db-storage to local-file-storage, then mark local-db-storage as unusable")
 
 		// Key config for storage
 		KeyConfigAddOptions(prefix+".key", f)
 
 		f.String(prefix+".extra-signature-checking-public-key", DefaultDataAvailabilityConfig.ExtraSignatureCheckingPublicKey, "public key to use to validate Data Availability Store requests in addition to the Sequencer's public key determined using sequencer-inbox-address, can be a file or the hex-encoded public key beginning with 0x; useful for testing")
+		f.Var(&parsedWriterKeysConf, prefix+".writer-keys", "additional keys allowed to store data, each with its own quotas. This can be specified on the command line as a JSON array, eg: [{\"name\": \"...\", \"address\": \"...\", \"max-bytes-per-hour\": ..., \"max-requests-per-hour\": ..., \"max-retention\": \"720h\"},...], or as a JSON array in the config file.")
 	}
 	if r == roleNode {
 		// These are only for batch poster
@@ -121,15 +121,25 @@ func (s *DASRPCServer) Store(ctx context.Context, message hexutil.Bytes, timeout
 		rpcStoreDurationHistogram.Update(time.Since(start).Nanoseconds())
 	}()
 
-	if err := s.signatureVerifier.verify(ctx, message, sig, uint64(timeout)); err != nil {
+	writer, err := s.signatureVerifier.authenticate(ctx, message, sig, uint64(timeout))
+	if err != nil {
 		return nil, err
 	}
+	if writer != nil {
+		if err := writer.admit(uint64(len(message)), uint64(timeout)); err != nil {
+			return nil, err
+		}
+	}
 
 	cert, err := s.daWriter.Store(ctx, message, uint64(timeout))
 	if err != nil {
+		writer.refund(uint64(len(message)))
 		return nil, err
 	}
 	rpcStoreStoredBytesGauge.Inc(int64(len(message)))
+	if writer != nil {
+		writer.audit(cert.DataHash[:], len(message), cert.Timeout)
+	}
 	success = true
 	return &StoreResult{
 		KeysetHash:  cert.KeysetHash[:],
@@ -156,6 +166,7 @@ type batch struct {
 	expectedChunkSize, expectedSize uint64
 	timeout                         uint64
 	startTime                       time.Time
+	writer                          *writerKey
 }
 
 const (
@@ -179,7 +190,7 @@ func newBatchBuilder() *batchBuilder {
 	}
 }
 
-func (b *batchBuilder) assign(nChunks, timeout, chunkSize, totalSize uint64) (uint64, error) {
+func (b *batchBuilder) assign(nChunks, timeout, chunkSize, totalSize uint64, writer *writerKey) (uint64, error) {
 	b.mutex.Lock()
 	defer b.mutex.Unlock()
 	if len(b.batches) >= maxPendingBatches {
@@ -199,21 +210,23 @@ func (b *batchBuilder) assign(nChunks, timeout, chunkSize, totalSize uint64) (ui
 		expectedSize:      totalSize,
 		timeout:           timeout,
 		startTime:         time.Now(),
+		writer:            writer,
 	}
 	go func(id uint64) {
 		<-time.After(batchBuildingExpiry)
 		b.mutex.Lock()
 		// Batch will only exist if expiry was reached without it being complete.
-		if _, exists := b.batches[id]; exists {
+		if batch, exists := b.batches[id]; exists {
 			rpcStoreFailureGauge.Inc(1)
 			delete(b.batches, id)
+			batch.writer.refund(batch.expectedSize)
 		}
 		b.mutex.Unlock()
 	}(id)
 	return id, nil
 }
 
-func (b *batchBuilder) add(id, idx uint64, data []byte) error {
+func (b *batchBuilder) add(id, idx uint64, data []byte, writer *writerKey) error {
 	b.mutex.Lock()
 	batch, ok := b.batches[id]
 	b.mutex.Unlock()
@@ -221,6 +234,10 @@ func (b *batchBuilder) add(id, idx uint64, data []byte) error {
 		return fmt.Errorf("unknown batch(%d)", id)
 	}
 
+	if batch.writer != writer {
+		return fmt.Errorf("batch(%d) was started by writer %v, not %v", id, batch.writer, writer)
+	}
+
 	if idx >= uint64(len(batch.chunks)) {
 		return fmt.Errorf("batch(%d): chunk(%d) out of range", id, idx)
 	}
@@ -238,9 +255,13 @@ func (b *batchBuilder) add(id, idx uint64, data []byte) error {
 	return nil
 }
 
-func (b *batchBuilder) close(id uint64) ([]byte, uint64, time.Time, error) {
+func (b *batchBuilder) close(id uint64, writer *writerKey) ([]byte, uint64, time.Time, error) {
 	b.mutex.Lock()
 	batch, ok := b.batches[id]
+	if ok && batch.writer != writer {
+		b.mutex.Unlock()
+		return nil, 0, time.Time{}, fmt.Errorf("batch(%d) was started by writer %v, not %v", id, batch.writer, writer)
+	}
 	delete(b.batches, id)
 	b.mutex.Unlock()
 	if !ok {
@@ -248,6 +269,7 @@ func (b *batchBuilder) close(id uint64) ([]byte, uint64, time.Time, error) {
 	}
 
 	if batch.expectedChunks != batch.seenChunks.Load() {
+		batch.writer.refund(batch.expectedSize)
 		return nil, 0, time.Time{}, fmt.Errorf("incomplete batch(%d): got %d/%d chunks", id, batch.seenChunks.Load(), batch.expectedChunks)
 	}
 
@@ -257,6 +279,7 @@ func (b *batchBuilder) close(id uint64) ([]byte, uint64, time.Time, error) {
 	}
 
 	if batch.expectedSize != uint64(len(flattened)) {
+		batch.writer.refund(batch.expectedSize)
 		return nil, 0, time.Time{}, fmt.Errorf("batch(%d) was not expected size %d, was %d", id, batch.expectedSize, len(flattened))
 	}
 
@@ -272,7 +295,8 @@ func (s *DASRPCServer) StartChunkedStore(ctx context.Context, timestamp, nChunks
 		} // success gague will be incremented on successful commit
 	}()
 
-	if err := s.signatureVerifier.verify(ctx, []byte{}, sig, uint64(timestamp), uint64(nChunks), uint64(chunkSize), uint64(totalSize), uint64(timeout)); err != nil {
+	writer, err := s.signatureVerifier.authenticate(ctx, []byte{}, sig, uint64(timestamp), uint64(nChunks), uint64(chunkSize), uint64(totalSize), uint64(timeout))
+	if err != nil {
 		return nil, err
 	}
 
@@ -282,8 +306,15 @@ func (s *DASRPCServer) StartChunkedStore(ctx context.Context, timestamp, nChunks
 		return nil, errors.New("too much time has elapsed since request was signed")
 	}
 
-	id, err := s.batches.assign(uint64(nChunks), uint64(timeout), uint64(chunkSize), uint64(totalSize))
+	if writer != nil {
+		if err := writer.admit(uint64(totalSize), uint64(timeout)); err != nil {
+			return nil, err
+		}
+	}
+
+	id, err := s.batches.assign(uint64(nChunks), uint64(timeout), uint64(chunkSize), uint64(totalSize), writer)
 	if err != nil {
+		writer.refund(uint64(totalSize))
 		return nil, err
 	}
 
@@ -304,11 +335,12 @@ func (s *DASRPCServer) SendChunk(ctx context.Context, batchId, chunkId hexutil.U
 		}
 	}()
 
-	if err := s.signatureVerifier.verify(ctx, message, sig, uint64(batchId), uint64(chunkId)); err != nil {
+	writer, err := s.signatureVerifier.authenticate(ctx, message, sig, uint64(batchId), uint64(chunkId))
+	if err != nil {
 		return err
 	}
 
-	if err := s.batches.add(uint64(batchId), uint64(chunkId), message); err != nil {
+	if err := s.batches.add(uint64(batchId), uint64(chunkId), message, writer); err != nil {
 		return err
 	}
 
@@ -317,11 +349,12 @@ func (s *DASRPCServer) SendChunk(ctx context.Context, batchId, chunkId hexutil.U
 }
 
 func (s *DASRPCServer) CommitChunkedStore(ctx context.Context, batchId hexutil.Uint64, sig hexutil.Bytes) (*StoreResult, error) {
-	if err := s.signatureVerifier.verify(ctx, []byte{}, sig, uint64(batchId)); err != nil {
+	writer, err := s.signatureVerifier.authenticate(ctx, []byte{}, sig, uint64(batchId))
+	if err != nil {
 		return nil, err
 	}
 
-	message, timeout, startTime, err := s.batches.close(uint64(batchId))
+	message, timeout, startTime, err := s.batches.close(uint64(batchId), writer)
 	if err != nil {
 		return nil, err
 	}
@@ -337,9 +370,14 @@ func (s *DASRPCServer) CommitChunkedStore(ctx context.Context, batchId hexutil.U
 		rpcStoreDurationHistogram.Update(time.Since(startTime).Nanoseconds())
 	}()
 	if err != nil {
+		// The batch was charged for its expected size, which close checked it has
+		writer.refund(uint64(len(message)))
 		return nil, err
 	}
 	rpcStoreStoredBytesGauge.Inc(int64(len(message)))
+	if writer != nil {
+		writer.audit(cert.DataHash[:], len(message), cert.Timeout)
+	}
 	success = true
 	return &StoreResult{
 		KeysetHash:  cert.KeysetHash[:],
@@ -172,9 +172,12 @@ func (dbs *DBStorageService) GetByHash(ctx context.Context, key common.Hash) ([]
 
 func (dbs *DBStorageService) Put(ctx context.Context, data []byte, timeout uint64) error {
 	logPut("das.DBStorageService.Put", data, timeout, dbs)
+	return dbs.putKeyValue(ctx, dastree.Hash(data), data, timeout)
+}
 
+func (dbs *DBStorageService) putKeyValue(ctx context.Context, key common.Hash, data []byte, timeout uint64) error {
 	return dbs.db.Update(func(txn *badger.Txn) error {
-		e := badger.NewEntry(dastree.HashBytes(data), data)
+		e := badger.NewEntry(key.Bytes(), data)
 		if dbs.discardAfterTimeout && timeout <= math.MaxInt64 {
 			// #nosec G115
 			e = e.WithTTL(time.Until(time.Unix(int64(timeout), 0)))
@@ -225,6 +228,51 @@ func (dbs *DBStorageService) migrateTo(ctx context.Context, s StorageService) er
 	})
 }
 
+// dbQuarantinePrefix is prepended to the keys of the batches the scrubber found corrupt.
+var dbQuarantinePrefix = []byte("quarantine-")
+
+func (dbs *DBStorageService) iterateEntries(ctx context.Context, visit func(key common.Hash, expiry uint64) error) error {
+	return dbs.db.View(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.PrefetchValues = false
+		it := txn.NewIterator(opts)
+		defer it.Close()
+		for it.Rewind(); it.Valid(); it.Next() {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			item := it.Item()
+			if len(item.Key()) != common.HashLength {
+				continue
+			}
+			if err := visit(common.BytesToHash(item.Key()), item.ExpiresAt()); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (dbs *DBStorageService) quarantine(ctx context.Context, key common.Hash, expiry uint64) error {
+	return dbs.db.Update(func(txn *badger.Txn) error {
+		item, err := txn.Get(key.Bytes())
+		if errors.Is(err, badger.ErrKeyNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		value, err := item.ValueCopy(nil)
+		if err != nil {
+			return err
+		}
+		if err := txn.Set(append(append([]byte{}, dbQuarantinePrefix...), key.Bytes()...), value); err != nil {
+			return err
+		}
+		return txn.Delete(key.Bytes())
+	})
+}
+
 func (dbs *DBStorageService) Sync(ctx context.Context) error {
 	return dbs.db.Sync()
 }
@@ -0,0 +1,195 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+// Package erasure implements a systematic Reed-Solomon code over GF(2^8).
+// Data is split into k data shards and extended with n-k parity shards, and any k of the n shards recover it.
+package erasure
+
+import (
+	"errors"
+	"fmt"
+)
+
+const MaxShards = 256
+
+var ErrTooFewShards = errors.New("too few shards to reconstruct the data")
+
+type Encoder struct {
+	dataShards  int
+	totalShards int
+	// matrix is the n x k encoding matrix; its first k rows are the identity, so data shards are stored as is.
+	matrix [][]byte
+}
+
+func NewEncoder(dataShards, parityShards int) (*Encoder, error) {
+	if dataShards < 1 {
+		return nil, errors.New("erasure code needs at least one data shard")
+	}
+	if parityShards < 0 {
+		return nil, errors.New("erasure code can't have a negative number of parity shards")
+	}
+	totalShards := dataShards + parityShards
+	if totalShards > MaxShards {
+		return nil, fmt.Errorf("erasure code can't have more than %v shards", MaxShards)
+	}
+	// Any k rows of a Vandermonde matrix with distinct evaluation points are linearly independent,
+	// and multiplying by the inverse of its top k x k square keeps that property.
+	vandermonde := make([][]byte, totalShards)
+	for i := range vandermonde {
+		vandermonde[i] = make([]byte, dataShards)
+		for j := range vandermonde[i] {
+			vandermonde[i][j] = gfPow(byte(i), j)
+		}
+	}
+	topInverse, err := invertMatrix(vandermonde[:dataShards])
+	if err != nil {
+		return nil, err
+	}
+	return &Encoder{
+		dataShards:  dataShards,
+		totalShards: totalShards,
+		matrix:      multiplyMatrices(vandermonde, topInverse),
+	}, nil
+}
+
+func (e *Encoder) DataShards() int {
+	return e.dataShards
+}
+
+func (e *Encoder) TotalShards() int {
+	return e.totalShards
+}
+
+// ShardSize returns the size of each shard for data of the given length.
+func (e *Encoder) ShardSize(dataLen int) int {
+	return max((dataLen+e.dataShards-1)/e.dataShards, 1)
+}
+
+// Split splits data into zero padded data shards, and allocates empty parity shards for Encode to fill.
+func (e *Encoder) Split(data []byte) [][]byte {
+	shardSize := e.ShardSize(len(data))
+	padded := make([]byte, shardSize*e.totalShards)
+	copy(padded, data)
+	shards := make([][]byte, e.totalShards)
+	for i := range shards {
+		shards[i] = padded[i*shardSize : (i+1)*shardSize : (i+1)*shardSize]
+	}
+	return shards
+}
+
+// Encode computes the parity shards from the data shards.
+func (e *Encoder) Encode(shards [][]byte) error {
+	if err := e.checkShards(shards, false); err != nil {
+		return err
+	}
+	for i := e.dataShards; i < e.totalShards; i++ {
+		e.codeShard(e.matrix[i], shards[:e.dataShards], shards[i])
+	}
+	return nil
+}
+
+// Reconstruct fills in the missing shards, which must be nil or empty, as long as at least k shards are present.
+func (e *Encoder) Reconstruct(shards [][]byte) error {
+	if err := e.checkShards(shards, true); err != nil {
+		return err
+	}
+	shardSize := 0
+	present := make([]int, 0, e.dataShards)
+	for i, shard := range shards {
+		if len(shard) == 0 {
+			continue
+		}
+		shardSize = len(shard)
+		if len(present) < e.dataShards {
+			present = append(present, i)
+		}
+	}
+	if len(present) < e.dataShards {
+		return ErrTooFewShards
+	}
+
+	dataMissing := false
+	for i := 0; i < e.dataShards; i++ {
+		if len(shards[i]) == 0 {
+			dataMissing = true
+		}
+	}
+	if dataMissing {
+		subMatrix := make([][]byte, e.dataShards)
+		subShards := make([][]byte, e.dataShards)
+		for i, index := range present {
+			subMatrix[i] = e.matrix[index]
+			subShards[i] = shards[index]
+		}
+		decodeMatrix, err := invertMatrix(subMatrix)
+		if err != nil {
+			return err
+		}
+		for i := 0; i < e.dataShards; i++ {
+			if len(shards[i]) == 0 {
+				shards[i] = make([]byte, shardSize)
+				e.codeShard(decodeMatrix[i], subShards, shards[i])
+			}
+		}
+	}
+	for i := e.dataShards; i < e.totalShards; i++ {
+		if len(shards[i]) == 0 {
+			shards[i] = make([]byte, shardSize)
+			e.codeShard(e.matrix[i], shards[:e.dataShards], shards[i])
+		}
+	}
+	return nil
+}
+
+// Join concatenates the data shards and strips the padding added by Split.
+func (e *Encoder) Join(shards [][]byte, dataLen int) ([]byte, error) {
+	if len(shards) < e.dataShards {
+		return nil, ErrTooFewShards
+	}
+	data := make([]byte, 0, dataLen)
+	for _, shard := range shards[:e.dataShards] {
+		if len(shard) == 0 {
+			return nil, ErrTooFewShards
+		}
+		data = append(data, shard...)
+		if len(data) >= dataLen {
+			return data[:dataLen], nil
+		}
+	}
+	return nil, errors.New("shards are too short for the data length")
+}
+
+func (e *Encoder) checkShards(shards [][]byte, allowMissing bool) error {
+	if len(shards) != e.totalShards {
+		return fmt.Errorf("expected %v shards but got %v", e.totalShards, len(shards))
+	}
+	shardSize := 0
+	for _, shard := range shards {
+		if len(shard) == 0 {
+			if !allowMissing {
+				return errors.New("missing shard")
+			}
+			continue
+		}
+		if shardSize == 0 {
+			shardSize = len(shard)
+		} else if len(shard) != shardSize {
+			return errors.New("shards have different sizes")
+		}
+	}
+	return nil
+}
+
+// codeShard sets output to the linear combination of the inputs with the given coefficients.
+func (e *Encoder) codeShard(coefficients []byte, inputs [][]byte, output []byte) {
+	clear(output)
+	for j, input := range inputs {
+		coefficient := coefficients[j]
+		if coefficient == 0 {
+			continue
+		}
+		for b := range output {
+			output[b] ^= gfMul(coefficient, input[b])
+		}
+	}
+}
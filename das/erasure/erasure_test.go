@@ -0,0 +1,72 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+package erasure
+
+import (
+	"bytes"
+	"errors"
+	"math/rand"
+	"testing"
+)
+
+func TestGaloisField(t *testing.T) {
+	for a := 1; a < 256; a++ {
+		if gfMul(byte(a), gfInverse(byte(a))) != 1 {
+			t.Fatal("bad inverse of", a)
+		}
+	}
+	if gfMul(0x80, 2) != 0x1d {
+		t.Fatal("multiplication doesn't reduce by the field polynomial")
+	}
+}
+
+func TestReconstruct(t *testing.T) {
+	for _, shape := range []struct{ data, parity int }{{1, 0}, {1, 2}, {2, 1}, {3, 2}, {4, 4}, {10, 4}} {
+		encoder, err := NewEncoder(shape.data, shape.parity)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, size := range []int{0, 1, 7, 1000} {
+			data := make([]byte, size)
+			rand.Read(data)
+			shards := encoder.Split(data)
+			if err := encoder.Encode(shards); err != nil {
+				t.Fatal(err)
+			}
+			original := make([][]byte, len(shards))
+			for i := range shards {
+				original[i] = bytes.Clone(shards[i])
+			}
+
+			// Drop as many shards as the parity allows, picked at random
+			for _, i := range rand.Perm(len(shards))[:shape.parity] {
+				shards[i] = nil
+			}
+			if err := encoder.Reconstruct(shards); err != nil {
+				t.Fatal(err)
+			}
+			for i := range shards {
+				if !bytes.Equal(shards[i], original[i]) {
+					t.Fatal("shard", i, "wasn't reconstructed for shape", shape, "and size", size)
+				}
+			}
+			joined, err := encoder.Join(shards, size)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(joined, data) {
+				t.Fatal("joined data doesn't match for shape", shape, "and size", size)
+			}
+
+			if shape.parity < len(shards) {
+				for _, i := range rand.Perm(len(shards))[:shape.parity+1] {
+					shards[i] = nil
+				}
+				if err := encoder.Reconstruct(shards); !errors.Is(err, ErrTooFewShards) {
+					t.Fatal("reconstructed from too few shards", err)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+package erasure
+
+import "errors"
+
+// GF(2^8) with the reducing polynomial x^8 + x^4 + x^3 + x^2 + 1, whose generator is 2.
+const gfPolynomial = 0x11d
+
+var (
+	gfExp [510]byte
+	gfLog [256]int
+)
+
+func init() {
+	x := 1
+	for i := 0; i < 255; i++ {
+		gfExp[i] = byte(x)
+		gfLog[x] = i
+		x <<= 1
+		if x&0x100 != 0 {
+			x ^= gfPolynomial
+		}
+	}
+	// Doubling the table lets gfMul skip the modulo
+	for i := 255; i < len(gfExp); i++ {
+		gfExp[i] = gfExp[i-255]
+	}
+}
+
+func gfMul(a, b byte) byte {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	return gfExp[gfLog[a]+gfLog[b]]
+}
+
+func gfInverse(a byte) byte {
+	return gfExp[255-gfLog[a]]
+}
+
+func gfPow(a byte, n int) byte {
+	if n == 0 {
+		return 1
+	}
+	if a == 0 {
+		return 0
+	}
+	return gfExp[(gfLog[a]*n)%255]
+}
+
+func multiplyMatrices(a, b [][]byte) [][]byte {
+	result := make([][]byte, len(a))
+	for i := range a {
+		result[i] = make([]byte, len(b[0]))
+		for j := range result[i] {
+			var sum byte
+			for k := range b {
+				sum ^= gfMul(a[i][k], b[k][j])
+			}
+			result[i][j] = sum
+		}
+	}
+	return result
+}
+
+// invertMatrix inverts a square matrix with Gauss-Jordan elimination.
+func invertMatrix(matrix [][]byte) ([][]byte, error) {
+	size := len(matrix)
+	// work is the matrix augmented with the identity
+	work := make([][]byte, size)
+	for i := range work {
+		work[i] = make([]byte, 2*size)
+		copy(work[i], matrix[i])
+		work[i][size+i] = 1
+	}
+	for column := 0; column < size; column++ {
+		pivot := column
+		for pivot < size && work[pivot][column] == 0 {
+			pivot++
+		}
+		if pivot == size {
+			return nil, errors.New("matrix is singular")
+		}
+		work[column], work[pivot] = work[pivot], work[column]
+		if scale := work[column][column]; scale != 1 {
+			inverse := gfInverse(scale)
+			for j := range work[column] {
+				work[column][j] = gfMul(work[column][j], inverse)
+			}
+		}
+		for row := 0; row < size; row++ {
+			factor := work[row][column]
+			if row == column || factor == 0 {
+				continue
+			}
+			for j := range work[row] {
+				work[row][j] ^= gfMul(factor, work[column][j])
+			}
+		}
+	}
+	inverse := make([][]byte, size)
+	for i := range inverse {
+		inverse[i] = work[i][size:]
+	}
+	return inverse, nil
+}
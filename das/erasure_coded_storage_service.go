@@ -0,0 +1,310 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+package das
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	flag "github.com/spf13/pflag"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+
+	"github.com/offchainlabs/nitro/arbstate/daprovider"
+	"github.com/offchainlabs/nitro/das/dastree"
+	"github.com/offchainlabs/nitro/das/erasure"
+	"github.com/offchainlabs/nitro/util/pretty"
+)
+
+var (
+	erasureMissingShardsCounter   = metrics.NewRegisteredCounter("arb/das/erasure/shards/missing", nil)
+	erasureCorruptShardsCounter   = metrics.NewRegisteredCounter("arb/das/erasure/shards/corrupt", nil)
+	erasureReconstructionsCounter = metrics.NewRegisteredCounter("arb/das/erasure/reconstructions", nil)
+	erasureHealthyBackendsGauge   = metrics.NewRegisteredGauge("arb/das/erasure/backends/healthy", nil)
+)
+
+type ErasureCodingConfig struct {
+	Enable     bool `koanf:"enable"`
+	DataShards int  `koanf:"data-shards"`
+}
+
+var DefaultErasureCodingConfig = ErasureCodingConfig{
+	Enable:     false,
+	DataShards: 1,
+}
+
+func ErasureCodingConfigAddOptions(prefix string, f *flag.FlagSet) {
+	f.Bool(prefix+".enable", DefaultErasureCodingConfig.Enable, "instead of a full copy of each batch in every storage backend, store one erasure coded shard in each backend")
+	f.Int(prefix+".data-shards", DefaultErasureCodingConfig.DataShards, "number of shards a batch is split into; the remaining backends hold parity shards, so batches survive losing that many backends")
+}
+
+// keyValueStorage is implemented by storage services that can store a value under a key other than its hash,
+// which lets the erasure coded storage service find a batch's shards from the batch's hash.
+type keyValueStorage interface {
+	StorageService
+	putKeyValue(ctx context.Context, key common.Hash, value []byte, expirationTime uint64) error
+}
+
+// Each shard is stored with a header binding it to its batch and position, and the hash of its contents to detect corruption.
+const (
+	erasureShardVersion    = 0
+	erasureShardHeaderSize = 1 + 1 + 1 + 1 + 8 + 32 + 32
+	erasureMaxShards       = 255
+)
+
+var erasureShardKeyPrefix = []byte("das erasure shard")
+
+// ErasureCodedStorageService Reed-Solomon encodes each batch into one shard per backend,
+// so batches can be read as long as any DataShards of the backends hold an intact shard.
+type ErasureCodedStorageService struct {
+	backends []keyValueStorage
+	encoder  *erasure.Encoder
+}
+
+func NewErasureCodedStorageService(config ErasureCodingConfig, services []StorageService) (*ErasureCodedStorageService, error) {
+	if len(services) > erasureMaxShards {
+		return nil, fmt.Errorf("erasure coding supports at most %v storage backends", erasureMaxShards)
+	}
+	if config.DataShards < 1 || config.DataShards > len(services) {
+		return nil, fmt.Errorf("erasure coding data-shards must be between 1 and the number of storage backends (%v), got %v", len(services), config.DataShards)
+	}
+	backends := make([]keyValueStorage, len(services))
+	for i, service := range services {
+		backend, ok := service.(keyValueStorage)
+		if !ok {
+			return nil, fmt.Errorf("%v doesn't support erasure coding", service)
+		}
+		backends[i] = backend
+	}
+	encoder, err := erasure.NewEncoder(config.DataShards, len(services)-config.DataShards)
+	if err != nil {
+		return nil, err
+	}
+	return &ErasureCodedStorageService{
+		backends: backends,
+		encoder:  encoder,
+	}, nil
+}
+
+func erasureShardKey(key common.Hash, index int) common.Hash {
+	// #nosec G115
+	return crypto.Keccak256Hash(erasureShardKeyPrefix, key.Bytes(), []byte{byte(index)})
+}
+
+func (e *ErasureCodedStorageService) encodeShard(key common.Hash, index int, dataLen int, shard []byte) []byte {
+	encoded := make([]byte, 0, erasureShardHeaderSize+len(shard))
+	// #nosec G115
+	encoded = append(encoded, erasureShardVersion, byte(index), byte(e.encoder.DataShards()), byte(e.encoder.TotalShards()))
+	// #nosec G115
+	encoded = binary.BigEndian.AppendUint64(encoded, uint64(dataLen))
+	encoded = append(encoded, key.Bytes()...)
+	encoded = append(encoded, crypto.Keccak256(shard)...)
+	return append(encoded, shard...)
+}
+
+// decodeShard returns the shard contents and the batch length, if the shard is intact and belongs at this index.
+func (e *ErasureCodedStorageService) decodeShard(key common.Hash, index int, encoded []byte) ([]byte, int, error) {
+	if len(encoded) < erasureShardHeaderSize {
+		return nil, 0, errors.New("shard too short")
+	}
+	if encoded[0] != erasureShardVersion || int(encoded[1]) != index || int(encoded[2]) != e.encoder.DataShards() || int(encoded[3]) != e.encoder.TotalShards() {
+		return nil, 0, fmt.Errorf("shard header %x doesn't match index %v of a %v of %v code", encoded[:4], index, e.encoder.DataShards(), e.encoder.TotalShards())
+	}
+	dataLen := binary.BigEndian.Uint64(encoded[4:12])
+	if common.BytesToHash(encoded[12:44]) != key {
+		return nil, 0, errors.New("shard belongs to another batch")
+	}
+	shard := encoded[erasureShardHeaderSize:]
+	if crypto.Keccak256Hash(shard) != common.BytesToHash(encoded[44:76]) {
+		return nil, 0, errors.New("shard contents don't match their hash")
+	}
+	// #nosec G115
+	if dataLen > uint64(len(shard)*e.encoder.DataShards()) || e.encoder.ShardSize(int(dataLen)) != len(shard) {
+		return nil, 0, errors.New("shard size doesn't match the batch length")
+	}
+	// #nosec G115
+	return shard, int(dataLen), nil
+}
+
+type erasureShardResponse struct {
+	index   int
+	shard   []byte
+	dataLen int
+	err     error
+}
+
+func (e *ErasureCodedStorageService) GetByHash(ctx context.Context, key common.Hash) ([]byte, error) {
+	log.Trace("das.ErasureCodedStorageService.GetByHash", "key", pretty.PrettyHash(key), "this", e)
+	subCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	responses := make(chan erasureShardResponse, len(e.backends))
+	for i, backend := range e.backends {
+		go func(index int, s keyValueStorage) {
+			encoded, err := s.GetByHash(subCtx, erasureShardKey(key, index))
+			if err != nil {
+				erasureMissingShardsCounter.Inc(1)
+				responses <- erasureShardResponse{index: index, err: err}
+				return
+			}
+			shard, dataLen, err := e.decodeShard(key, index, encoded)
+			if err != nil {
+				erasureCorruptShardsCounter.Inc(1)
+				log.Warn("Corrupt DAS erasure coded shard", "key", pretty.PrettyHash(key), "backend", s, "err", err)
+			}
+			responses <- erasureShardResponse{index, shard, dataLen, err}
+		}(i, backend)
+	}
+
+	shards := make([][]byte, len(e.backends))
+	found := 0
+	dataLen := -1
+	var anyError error
+	allNotFound := true
+	for range e.backends {
+		var resp erasureShardResponse
+		select {
+		case resp = <-responses:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		if resp.err != nil {
+			if !errors.Is(resp.err, ErrNotFound) {
+				allNotFound = false
+			}
+			anyError = resp.err
+			continue
+		}
+		allNotFound = false
+		if dataLen >= 0 && resp.dataLen != dataLen {
+			erasureCorruptShardsCounter.Inc(1)
+			anyError = errors.New("shards disagree on the batch length")
+			continue
+		}
+		dataLen = resp.dataLen
+		shards[resp.index] = resp.shard
+		found++
+		if found == e.encoder.DataShards() {
+			break
+		}
+	}
+	if found < e.encoder.DataShards() {
+		if allNotFound {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("%w: found %v of the %v shards needed, last error: %w", erasure.ErrTooFewShards, found, e.encoder.DataShards(), anyError)
+	}
+	if slices.ContainsFunc(shards[:e.encoder.DataShards()], func(shard []byte) bool { return shard == nil }) {
+		erasureReconstructionsCounter.Inc(1)
+	}
+	if err := e.encoder.Reconstruct(shards); err != nil {
+		return nil, err
+	}
+	data, err := e.encoder.Join(shards, dataLen)
+	if err != nil {
+		return nil, err
+	}
+	if !dastree.ValidHash(key, data) {
+		return nil, fmt.Errorf("erasure coded batch doesn't match its hash %v", key)
+	}
+	return data, nil
+}
+
+func (e *ErasureCodedStorageService) Put(ctx context.Context, data []byte, expirationTime uint64) error {
+	logPut("das.ErasureCodedStorageService.Store", data, expirationTime, e)
+	key := dastree.Hash(data)
+	shards := e.encoder.Split(data)
+	if err := e.encoder.Encode(shards); err != nil {
+		return err
+	}
+	var wg sync.WaitGroup
+	var errorMutex sync.Mutex
+	var anyError error
+	wg.Add(len(e.backends))
+	for i, backend := range e.backends {
+		go func(index int, s keyValueStorage) {
+			err := s.putKeyValue(ctx, erasureShardKey(key, index), e.encodeShard(key, index, len(data), shards[index]), expirationTime)
+			if err != nil {
+				errorMutex.Lock()
+				anyError = err
+				errorMutex.Unlock()
+			}
+			wg.Done()
+		}(i, backend)
+	}
+	wg.Wait()
+	return anyError
+}
+
+func (e *ErasureCodedStorageService) Sync(ctx context.Context) error {
+	var anyError error
+	for _, backend := range e.backends {
+		if err := backend.Sync(ctx); err != nil {
+			anyError = err
+		}
+	}
+	return anyError
+}
+
+func (e *ErasureCodedStorageService) Close(ctx context.Context) error {
+	var anyError error
+	for _, backend := range e.backends {
+		if err := backend.Close(ctx); err != nil {
+			anyError = err
+		}
+	}
+	return anyError
+}
+
+func (e *ErasureCodedStorageService) ExpirationPolicy(ctx context.Context) (daprovider.ExpirationPolicy, error) {
+	// A batch can be read as long as DataShards backends still hold it,
+	// so it's kept as long as the DataShards-th most durable backend keeps its shard.
+	policies := make([]daprovider.ExpirationPolicy, len(e.backends))
+	for i, backend := range e.backends {
+		policy, err := backend.ExpirationPolicy(ctx)
+		if err != nil {
+			return -1, err
+		}
+		policies[i] = policy
+	}
+	slices.Sort(policies)
+	return policies[e.encoder.DataShards()-1], nil
+}
+
+func (e *ErasureCodedStorageService) String() string {
+	names := make([]string, len(e.backends))
+	for i, backend := range e.backends {
+		names[i] = backend.String()
+	}
+	return fmt.Sprintf("ErasureCodedStorageService(%v of %v: %v)", e.encoder.DataShards(), e.encoder.TotalShards(), strings.Join(names, ","))
+}
+
+// HealthCheck fails if any backend is unhealthy, since new batches can't be stored then,
+// and reports whether the stored batches can still be read.
+func (e *ErasureCodedStorageService) HealthCheck(ctx context.Context) error {
+	var unhealthy []string
+	var lastErr error
+	for _, backend := range e.backends {
+		if err := backend.HealthCheck(ctx); err != nil {
+			unhealthy = append(unhealthy, backend.String())
+			lastErr = err
+		}
+	}
+	healthy := len(e.backends) - len(unhealthy)
+	erasureHealthyBackendsGauge.Update(int64(healthy))
+	if len(unhealthy) == 0 {
+		return nil
+	}
+	if healthy >= e.encoder.DataShards() {
+		return fmt.Errorf("%v of %v erasure coded storage backends are unhealthy (%v), batches can still be read but not stored: %w", len(unhealthy), len(e.backends), strings.Join(unhealthy, ","), lastErr)
+	}
+	return fmt.Errorf("%v of %v erasure coded storage backends are unhealthy (%v), too few are left to read batches: %w", len(unhealthy), len(e.backends), strings.Join(unhealthy, ","), lastErr)
+}
@@ -0,0 +1,88 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+package das
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/offchainlabs/nitro/arbstate/daprovider"
+	"github.com/offchainlabs/nitro/das/dastree"
+	"github.com/offchainlabs/nitro/das/erasure"
+)
+
+func TestErasureCodedStorageService(t *testing.T) {
+	ctx := context.Background()
+	// #nosec G115
+	timeout := uint64(time.Now().Add(time.Hour).Unix())
+	services := []StorageService{}
+	for i := 0; i < 5; i++ {
+		services = append(services, NewMemoryBackedStorageService(ctx))
+	}
+	erasureService, err := NewErasureCodedStorageService(ErasureCodingConfig{Enable: true, DataShards: 3}, services)
+	Require(t, err)
+
+	val1 := bytes.Repeat([]byte("The first value"), 100)
+	key1 := dastree.Hash(val1)
+
+	_, err = erasureService.GetByHash(ctx, key1)
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatal(err)
+	}
+
+	Require(t, erasureService.Put(ctx, val1, timeout))
+	for i, serv := range services {
+		shard, err := serv.GetByHash(ctx, erasureShardKey(key1, i))
+		Require(t, err)
+		if len(shard) >= len(val1) {
+			t.Fatal("backend", i, "holds more than a shard of the batch")
+		}
+	}
+
+	check := func() {
+		t.Helper()
+		val, err := erasureService.GetByHash(ctx, key1)
+		Require(t, err)
+		if !bytes.Equal(val, val1) {
+			t.Fatal(val, val1)
+		}
+	}
+	check()
+
+	// Lose a data shard and corrupt another one, which leaves exactly enough shards to rebuild the batch
+	memory := func(i int) *MemoryBackedStorageService {
+		return services[i].(*MemoryBackedStorageService)
+	}
+	delete(memory(0).contents, erasureShardKey(key1, 0))
+	memory(1).contents[erasureShardKey(key1, 1)][erasureShardHeaderSize] ^= 1
+	check()
+
+	delete(memory(4).contents, erasureShardKey(key1, 4))
+	_, err = erasureService.GetByHash(ctx, key1)
+	if !errors.Is(err, erasure.ErrTooFewShards) {
+		t.Fatal(err)
+	}
+
+	policy, err := erasureService.ExpirationPolicy(ctx)
+	Require(t, err)
+	if policy != daprovider.KeepForever {
+		t.Fatal(policy)
+	}
+
+	Require(t, erasureService.HealthCheck(ctx))
+	Require(t, erasureService.Close(ctx))
+}
+
+func TestErasureCodedStorageServiceConfig(t *testing.T) {
+	ctx := context.Background()
+	services := []StorageService{NewMemoryBackedStorageService(ctx), NewMemoryBackedStorageService(ctx)}
+	for _, dataShards := range []int{0, 3} {
+		if _, err := NewErasureCodedStorageService(ErasureCodingConfig{Enable: true, DataShards: dataShards}, services); err == nil {
+			t.Fatal("accepted", dataShards, "data shards for two backends")
+		}
+	}
+}
@@ -17,7 +17,8 @@ import (
 )
 
 // CreatePersistentStorageService creates any storage services that persist to files, database, cloud storage,
-// and group them together into a RedundantStorage instance if there is more than one.
+// and group them together into a RedundantStorage instance if there is more than one,
+// or into an ErasureCodedStorageService if erasure coding is enabled.
 func CreatePersistentStorageService(
 	ctx context.Context,
 	config *DataAvailabilityConfig,
@@ -57,7 +58,7 @@ func CreatePersistentStorageService(
 	}
 
 	if config.S3Storage.Enable {
-		s, err := NewS3StorageService(config.S3Storage)
+		s, err := NewS3StorageService(ctx, config.S3Storage)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -74,6 +75,13 @@ func CreatePersistentStorageService(
 		storageServices = append(storageServices, s)
 	}
 
+	if config.ErasureCoding.Enable && len(storageServices) > 0 {
+		s, err := NewErasureCodedStorageService(config.ErasureCoding, storageServices)
+		if err != nil {
+			return nil, nil, err
+		}
+		return s, &lifecycleManager, nil
+	}
 	if len(storageServices) > 1 {
 		s, err := NewRedundantStorageService(ctx, storageServices)
 		if err != nil {
@@ -178,6 +186,8 @@ func CreateDAComponentsForDaserver(
 	if err != nil {
 		return nil, nil, nil, nil, nil, err
 	}
+	// The scrubber checks what's actually persisted, so it bypasses the caches and the fallback
+	persistentStorage := storageService
 
 	storageService, err = WrapStorageWithCache(ctx, config, storageService, dasLifecycleManager)
 	if err != nil {
@@ -186,8 +196,9 @@ func CreateDAComponentsForDaserver(
 
 	// The REST aggregator is used as the fallback if requested data is not present
 	// in the storage service.
+	var restAgg *SimpleDASReaderAggregator
 	if config.RestAggregator.Enable {
-		restAgg, err := NewRestfulClientAggregator(ctx, &config.RestAggregator)
+		restAgg, err = NewRestfulClientAggregator(ctx, &config.RestAggregator)
 		if err != nil {
 			return nil, nil, nil, nil, nil, err
 		}
@@ -221,6 +232,22 @@ func CreateDAComponentsForDaserver(
 
 	}
 
+	if config.Scrubber.Enable {
+		if err := config.Scrubber.Validate(); err != nil {
+			return nil, nil, nil, nil, nil, err
+		}
+		var source DataAvailabilityServiceReader
+		if restAgg != nil {
+			source = restAgg
+		}
+		scrubber, err := NewScrubber(config.Scrubber, persistentStorage, source)
+		if err != nil {
+			return nil, nil, nil, nil, nil, err
+		}
+		scrubber.Start(ctx)
+		dasLifecycleManager.Register(scrubber)
+	}
+
 	var daWriter DataAvailabilityServiceWriter
 	var daReader DataAvailabilityServiceReader = storageService
 	var daHealthChecker DataAvailabilityServiceHealthChecker = storageService
@@ -252,6 +279,9 @@ func CreateDAComponentsForDaserver(
 		if err != nil {
 			return nil, nil, nil, nil, nil, err
 		}
+		if err := signatureVerifier.setWriterKeys(config.WriterKeys); err != nil {
+			return nil, nil, nil, nil, nil, err
+		}
 	}
 
 	return daReader, daWriter, signatureVerifier, daHealthChecker, dasLifecycleManager, nil
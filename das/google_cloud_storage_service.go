@@ -23,7 +23,7 @@ import (
 
 type GoogleCloudStorageOperator interface {
 	Bucket(name string) *googlestorage.BucketHandle
-	Upload(ctx context.Context, bucket, objectPrefix string, value []byte, discardAfterTimeout bool, timeout uint64) error
+	Upload(ctx context.Context, bucket, objectPrefix string, key common.Hash, value []byte, discardAfterTimeout bool, timeout uint64) error
 	Download(ctx context.Context, bucket, objectPrefix string, key common.Hash) ([]byte, error)
 	Close(ctx context.Context) error
 }
@@ -36,8 +36,8 @@ func (g *GoogleCloudStorageClient) Bucket(name string) *googlestorage.BucketHand
 	return g.client.Bucket(name)
 }
 
-func (g *GoogleCloudStorageClient) Upload(ctx context.Context, bucket, objectPrefix string, value []byte, discardAfterTimeout bool, timeout uint64) error {
-	obj := g.client.Bucket(bucket).Object(objectPrefix + EncodeStorageServiceKey(dastree.Hash(value)))
+func (g *GoogleCloudStorageClient) Upload(ctx context.Context, bucket, objectPrefix string, key common.Hash, value []byte, discardAfterTimeout bool, timeout uint64) error {
+	obj := g.client.Bucket(bucket).Object(objectPrefix + EncodeStorageServiceKey(key))
 	w := obj.NewWriter(ctx)
 
 	if discardAfterTimeout && timeout <= math.MaxInt64 {
@@ -47,7 +47,10 @@ func (g *GoogleCloudStorageClient) Upload(ctx context.Context, bucket, objectPre
 		}
 	}
 
-	if _, err := fmt.Fprintln(w, value); err != nil {
+	// Objects used to be written with fmt.Fprintln, which stores the bytes formatted as text. Those objects fail the
+	// hash check when read back, and need to be stored again from another backend, for example by syncing to
+	// storage, to be served from this bucket.
+	if _, err := w.Write(value); err != nil {
 		return err
 	}
 	return w.Close()
@@ -119,8 +122,11 @@ func NewGoogleCloudStorageService(config GoogleCloudStorageServiceConfig) (Stora
 
 func (gcs *GoogleCloudStorageService) Put(ctx context.Context, value []byte, timeout uint64) error {
 	logPut("das.GoogleCloudStorageService.Store", value, timeout, gcs)
+	return gcs.putKeyValue(ctx, dastree.Hash(value), value, timeout)
+}
 
-	if err := gcs.operator.Upload(ctx, gcs.bucket, gcs.objectPrefix, value, gcs.discardAfterTimeout, timeout); err != nil {
+func (gcs *GoogleCloudStorageService) putKeyValue(ctx context.Context, key common.Hash, value []byte, timeout uint64) error {
+	if err := gcs.operator.Upload(ctx, gcs.bucket, gcs.objectPrefix, key, value, gcs.discardAfterTimeout, timeout); err != nil {
 		log.Error("das.GoogleCloudStorageService.Store", "err", err)
 		return err
 	}
@@ -34,9 +34,8 @@ func (c *mockGCSClient) Close(ctx context.Context) error {
 	return nil
 }
 
-func (c *mockGCSClient) Upload(ctx context.Context, bucket, objectPrefix string, value []byte, discardAfterTimeout bool, timeout uint64) error {
-	key := objectPrefix + EncodeStorageServiceKey(dastree.Hash(value))
-	c.storage[key] = value
+func (c *mockGCSClient) Upload(ctx context.Context, bucket, objectPrefix string, key common.Hash, value []byte, discardAfterTimeout bool, timeout uint64) error {
+	c.storage[objectPrefix+EncodeStorageServiceKey(key)] = value
 	return nil
 }
 
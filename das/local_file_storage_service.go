@@ -136,6 +136,10 @@ func (s *LocalFileStorageService) GetByHash(ctx context.Context, key common.Hash
 
 func (s *LocalFileStorageService) Put(ctx context.Context, data []byte, expiry uint64) error {
 	logPut("das.LocalFileStorageService.Store", data, expiry, s)
+	return s.putKeyValue(ctx, dastree.Hash(data), data, expiry)
+}
+
+func (s *LocalFileStorageService) putKeyValue(ctx context.Context, key common.Hash, data []byte, expiry uint64) error {
 	if expiry > math.MaxInt64 {
 		return fmt.Errorf("request expiry time (%v) exceeds max int64", expiry)
 	}
@@ -146,7 +150,6 @@ func (s *LocalFileStorageService) Put(ctx context.Context, data []byte, expiry u
 		return fmt.Errorf("requested expiry time (%v) exceeds current time plus maximum allowed retention period(%v)", expiryTime, currentTimePlusRetention)
 	}
 
-	key := dastree.Hash(data)
 	var batchPath string
 	if !s.enableLegacyLayout {
 		s.layout.writeMutex.Lock()
@@ -258,6 +261,66 @@ func (s *LocalFileStorageService) HealthCheck(ctx context.Context) error {
 	return nil
 }
 
+// quarantineDir holds the batches the scrubber found corrupt, named by their key and when they were quarantined.
+const quarantineDir = "quarantine"
+
+func (s *LocalFileStorageService) iterateEntries(ctx context.Context, visit func(key common.Hash, expiry uint64) error) error {
+	if s.enableLegacyLayout {
+		return errors.New("scrubbing the legacy flat layout isn't supported")
+	}
+	// The by-expiry-timestamp index has an entry for every batch, whatever its expiry time,
+	// so it also finds batches whose by-data-hash file went missing.
+	it, err := s.layout.iterateBatchesByTimestamp(time.Unix(math.MaxInt64/2, 0))
+	if err != nil {
+		return err
+	}
+	for indexPath, err := it.next(); !errors.Is(err, io.EOF); indexPath, err = it.next() {
+		if err != nil {
+			return err
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		key, err := DecodeStorageServiceKey(path.Base(indexPath))
+		if err != nil {
+			return err
+		}
+		secondDir := path.Dir(indexPath)
+		expiry, err := strconv.ParseUint(path.Base(path.Dir(secondDir))+path.Base(secondDir), 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid by-expiry-timestamp index entry %s: %w", indexPath, err)
+		}
+		if err := visit(key, expiry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *LocalFileStorageService) quarantine(ctx context.Context, key common.Hash, expiry uint64) error {
+	s.layout.writeMutex.Lock()
+	defer s.layout.writeMutex.Unlock()
+	batchPath := s.layout.batchPath(key)
+	_, err := os.Stat(batchPath)
+	if err == nil {
+		quarantinePath := filepath.Join(s.config.DataDir, quarantineDir, fmt.Sprintf("%s-%d", EncodeStorageServiceKey(key), time.Now().Unix()))
+		if err := os.MkdirAll(path.Dir(quarantinePath), 0o700); err != nil {
+			return err
+		}
+		if err := os.Rename(batchPath, quarantinePath); err != nil {
+			return err
+		}
+		log.Warn("Quarantined DAS batch", "key", pretty.PrettyHash(key), "path", quarantinePath)
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+	// The index entry is a hard link to the same file, and would stop the batch from being stored again
+	if err := recursivelyDeleteUntil(s.layout.expiryPath(key, expiry), byExpiryTimestamp); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
 func listDir(dir string) ([]string, error) {
 	d, err := os.Open(dir)
 	if err != nil {
@@ -45,12 +45,36 @@ func (m *MemoryBackedStorageService) GetByHash(ctx context.Context, key common.H
 
 func (m *MemoryBackedStorageService) Put(ctx context.Context, data []byte, expirationTime uint64) error {
 	logPut("das.MemoryBackedStorageService.Store", data, expirationTime, m)
+	return m.putKeyValue(ctx, dastree.Hash(data), data, expirationTime)
+}
+
+func (m *MemoryBackedStorageService) putKeyValue(ctx context.Context, key common.Hash, data []byte, expirationTime uint64) error {
 	m.rwmutex.Lock()
 	defer m.rwmutex.Unlock()
 	if m.closed {
 		return ErrClosed
 	}
-	m.contents[dastree.Hash(data)] = append([]byte{}, data...)
+	m.contents[key] = append([]byte{}, data...)
+	return nil
+}
+
+func (m *MemoryBackedStorageService) iterateEntries(ctx context.Context, visit func(key common.Hash, expiry uint64) error) error {
+	m.rwmutex.RLock()
+	if m.closed {
+		m.rwmutex.RUnlock()
+		return ErrClosed
+	}
+	keys := make([]common.Hash, 0, len(m.contents))
+	for key := range m.contents {
+		keys = append(keys, key)
+	}
+	m.rwmutex.RUnlock()
+	for _, key := range keys {
+		// Expiry times aren't kept in memory
+		if err := visit(key, 0); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -4,16 +4,20 @@
 package das
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"encoding/base64"
+	"encoding/binary"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"strings"
+	"sync/atomic"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
 
 	"github.com/offchainlabs/nitro/arbstate/daprovider"
 	"github.com/offchainlabs/nitro/das/dastree"
@@ -22,6 +26,8 @@ import (
 // RestfulDasClient implements daprovider.DASReader
 type RestfulDasClient struct {
 	url string
+	// Set once the server turns out to predate the get-by-hashes endpoint
+	getByHashesUnsupported atomic.Bool
 }
 
 func NewRestfulDasClient(protocol string, host string, port int) *RestfulDasClient {
@@ -74,6 +80,94 @@ func (c *RestfulDasClient) GetByHash(ctx context.Context, hash common.Hash) ([]b
 	return decodedBytes, nil
 }
 
+// GetByHashes fetches several batches with as few requests as possible,
+// leaving nil the batches the server doesn't have or couldn't read.
+func (c *RestfulDasClient) GetByHashes(ctx context.Context, hashes []common.Hash) ([][]byte, error) {
+	results := make([][]byte, len(hashes))
+	for start := 0; start < len(hashes); start += maxGetByHashesCount {
+		end := min(start+maxGetByHashesCount, len(hashes))
+		if err := c.getByHashesChunk(ctx, hashes[start:end], results[start:end]); err != nil {
+			return nil, err
+		}
+	}
+	return results, nil
+}
+
+func (c *RestfulDasClient) getByHashesChunk(ctx context.Context, hashes []common.Hash, results [][]byte) error {
+	if c.getByHashesUnsupported.Load() {
+		return c.getByHashesOneByOne(ctx, hashes, results)
+	}
+	encodedHashes := make([]string, len(hashes))
+	for i, hash := range hashes {
+		encodedHashes[i] = EncodeStorageServiceKey(hash)
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", c.url+getByHashesRequestPath+strings.Join(encodedHashes, ","), nil)
+	if err != nil {
+		return err
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	switch res.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		// The get-by-hashes handler never answers 404, so the server doesn't have the route
+		log.Info("REST DAS server doesn't support get-by-hashes, fetching batches one by one", "url", c.url)
+		c.getByHashesUnsupported.Store(true)
+		return c.getByHashesOneByOne(ctx, hashes, results)
+	case http.StatusBadRequest:
+		// Servers from before get-by-hashes answer 400 to unknown routes, but so does a proxy or a server
+		// rejecting the hash list, so fall back for this request without giving up on the endpoint
+		log.Debug("REST DAS server rejected get-by-hashes request, fetching batches one by one", "url", c.url)
+		return c.getByHashesOneByOne(ctx, hashes, results)
+	default:
+		return fmt.Errorf("HTTP error with status %d returned by server: %s", res.StatusCode, http.StatusText(res.StatusCode))
+	}
+
+	body := bufio.NewReader(res.Body)
+	frameHeader := make([]byte, 32+1+4)
+	for i, hash := range hashes {
+		if _, err := io.ReadFull(body, frameHeader); err != nil {
+			return fmt.Errorf("reading get-by-hashes response: %w", err)
+		}
+		if common.BytesToHash(frameHeader[:32]) != hash {
+			return fmt.Errorf("get-by-hashes response has a frame for %v where %v was expected", common.BytesToHash(frameHeader[:32]), hash)
+		}
+		dataLen := binary.BigEndian.Uint32(frameHeader[33:])
+		if dataLen > maxGetByHashesFrameSize {
+			return fmt.Errorf("get-by-hashes response frame for %v is too large (%d bytes)", hash, dataLen)
+		}
+		data := make([]byte, dataLen)
+		if _, err := io.ReadFull(body, data); err != nil {
+			return fmt.Errorf("reading get-by-hashes response: %w", err)
+		}
+		if getByHashesStatus(frameHeader[32]) != getByHashesFound {
+			continue
+		}
+		if !dastree.ValidHash(hash, data) {
+			return daprovider.ErrHashMismatch
+		}
+		results[i] = data
+	}
+	return nil
+}
+
+func (c *RestfulDasClient) getByHashesOneByOne(ctx context.Context, hashes []common.Hash, results [][]byte) error {
+	for i, hash := range hashes {
+		data, err := c.GetByHash(ctx, hash)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err == nil {
+			results[i] = data
+		}
+	}
+	return nil
+}
+
 func (c *RestfulDasClient) HealthCheck(ctx context.Context) error {
 	res, err := http.Get(c.url + healthRequestPath)
 	if err != nil {
@@ -5,6 +5,7 @@ package das
 
 import (
 	"encoding/base64"
+	"encoding/binary"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -12,9 +13,12 @@ import (
 	"net/http"
 	"path"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/metrics"
 
@@ -29,6 +33,13 @@ var (
 	restGetByHashFailureGauge       = metrics.NewRegisteredGauge("arb/das/rest/getbyhash/failure", nil)
 	restGetByHashReturnedBytesGauge = metrics.NewRegisteredGauge("arb/das/rest/getbyhash/bytes", nil)
 	restGetByHashDurationHistogram  = metrics.NewRegisteredHistogram("arb/das/rest/getbyhash/duration", nil, metrics.NewBoundedHistogramSample())
+	restNotModifiedGauge            = metrics.NewRegisteredGauge("arb/das/rest/notmodified", nil)
+
+	restGetByHashesRequestGauge       = metrics.NewRegisteredGauge("arb/das/rest/getbyhashes/requests", nil)
+	restGetByHashesFailureGauge       = metrics.NewRegisteredGauge("arb/das/rest/getbyhashes/failure", nil)
+	restGetByHashesMissingGauge       = metrics.NewRegisteredGauge("arb/das/rest/getbyhashes/missing", nil)
+	restGetByHashesReturnedBytesGauge = metrics.NewRegisteredGauge("arb/das/rest/getbyhashes/bytes", nil)
+	restGetByHashesDurationHistogram  = metrics.NewRegisteredHistogram("arb/das/rest/getbyhashes/duration", nil, metrics.NewBoundedHistogramSample())
 )
 
 type RestfulDasServer struct {
@@ -80,12 +91,28 @@ type RestfulDasServerResponse struct {
 }
 
 var cacheControlKey = http.CanonicalHeaderKey("cache-control")
+var etagKey = http.CanonicalHeaderKey("etag")
 
 const cacheControlValueDefault = "public, max-age=1"                                 // cache for up to 1 second (Used to reduce DOS possibility)
 const cacheControlValueForSuccessfulGetByHash = "public, max-age=2419200, immutable" // cache for up to 28 days
 const healthRequestPath = "/health"
 const expirationPolicyRequestPath = "/expiration-policy/"
 const getByHashRequestPath = "/get-by-hash/"
+const getByHashesRequestPath = "/get-by-hashes/"
+
+// A get-by-hashes request lists up to maxGetByHashesCount comma separated hashes. The response has a frame per hash, in
+// request order: the hash (32 bytes), a getByHashesStatus byte, the length of the data (4 bytes, big endian) and the data.
+const maxGetByHashesCount = 64
+const maxGetByHashesFrameSize = 1 << 28
+const getByHashesContentType = "application/vnd.nitro.das-frames"
+
+type getByHashesStatus byte
+
+const (
+	getByHashesFound getByHashesStatus = iota
+	getByHashesNotFound
+	getByHashesError
+)
 
 func (rds *RestfulDasServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	w.Header()[cacheControlKey] = []string{cacheControlValueDefault}
@@ -96,11 +123,14 @@ func (rds *RestfulDasServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		rds.HealthHandler(w, r, requestPath)
 	case strings.HasPrefix(requestPath, expirationPolicyRequestPath):
 		rds.ExpirationPolicyHandler(w, r, requestPath)
+	case strings.HasPrefix(requestPath, getByHashesRequestPath):
+		rds.GetByHashesHandler(w, r, requestPath)
 	case strings.HasPrefix(requestPath, getByHashRequestPath):
 		rds.GetByHashHandler(w, r, requestPath)
 	default:
 		log.Warn("Unknown requestPath", "requestPath", requestPath)
-		w.WriteHeader(http.StatusBadRequest)
+		// A 404 lets clients tell a route this server doesn't have from a request it rejected
+		w.WriteHeader(http.StatusNotFound)
 		return
 	}
 }
@@ -164,7 +194,19 @@ func (rds *RestfulDasServer) GetByHashHandler(w http.ResponseWriter, r *http.Req
 		return
 	}
 
-	responseData, err := rds.daReader.GetByHash(r.Context(), common.BytesToHash(hashBytes[:32]))
+	hash := common.BytesToHash(hashBytes[:32])
+	// The data of a hash never changes, so a client that has it doesn't need it again
+	etag := etagForHashes(hash)
+	if etagMatches(r, etag) {
+		restNotModifiedGauge.Inc(1)
+		w.Header()[etagKey] = []string{etag}
+		w.Header()[cacheControlKey] = []string{cacheControlValueForSuccessfulGetByHash}
+		w.WriteHeader(http.StatusNotModified)
+		success = true
+		return
+	}
+
+	responseData, err := rds.daReader.GetByHash(r.Context(), hash)
 	if err != nil {
 		log.Warn("Unable to find data", "path", requestPath, "err", err, "remoteAddr", r.RemoteAddr)
 		w.WriteHeader(http.StatusNotFound)
@@ -178,16 +220,135 @@ func (rds *RestfulDasServer) GetByHashHandler(w http.ResponseWriter, r *http.Req
 	response.Data = string(encodedResponseData)
 	restGetByHashReturnedBytesGauge.Inc(int64(len(response.Data)))
 
+	// Headers must be set before the body is written
+	w.Header()[cacheControlKey] = []string{cacheControlValueForSuccessfulGetByHash}
+	w.Header()[etagKey] = []string{etag}
 	err = json.NewEncoder(w).Encode(response)
 	if err != nil {
 		log.Warn("Failed encoding and writing response", "path", requestPath, "err", err)
-		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
-	w.Header()[cacheControlKey] = []string{cacheControlValueForSuccessfulGetByHash}
 	success = true
 }
 
+func (rds *RestfulDasServer) GetByHashesHandler(w http.ResponseWriter, r *http.Request, requestPath string) {
+	restGetByHashesRequestGauge.Inc(1)
+	start := time.Now()
+	defer func() {
+		restGetByHashesDurationHistogram.Update(time.Since(start).Nanoseconds())
+	}()
+
+	hashes, err := decodeHashList(strings.TrimPrefix(requestPath, getByHashesRequestPath))
+	if err != nil {
+		log.Warn("Invalid get-by-hashes request", "path", requestPath, "err", err)
+		restGetByHashesFailureGauge.Inc(1)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	etag := etagForHashes(hashes...)
+	if etagMatches(r, etag) {
+		restNotModifiedGauge.Inc(1)
+		w.Header()[etagKey] = []string{etag}
+		w.Header()[cacheControlKey] = []string{cacheControlValueForSuccessfulGetByHash}
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	type result struct {
+		data   []byte
+		status getByHashesStatus
+	}
+	results := make([]result, len(hashes))
+	var wg sync.WaitGroup
+	for i, hash := range hashes {
+		wg.Add(1)
+		go func(i int, hash common.Hash) {
+			defer wg.Done()
+			data, err := rds.daReader.GetByHash(r.Context(), hash)
+			switch {
+			case err == nil:
+				results[i] = result{data, getByHashesFound}
+			case errors.Is(err, ErrNotFound):
+				results[i] = result{nil, getByHashesNotFound}
+			default:
+				log.Warn("Unable to get data for get-by-hashes request", "hash", pretty.PrettyHash(hash), "err", err, "remoteAddr", r.RemoteAddr)
+				results[i] = result{nil, getByHashesError}
+			}
+		}(i, hash)
+	}
+	wg.Wait()
+
+	allFound := true
+	for _, res := range results {
+		if res.status != getByHashesFound {
+			allFound = false
+			restGetByHashesMissingGauge.Inc(1)
+		}
+	}
+	// Only a complete response can't change, missing data may still turn up
+	if allFound {
+		w.Header()[cacheControlKey] = []string{cacheControlValueForSuccessfulGetByHash}
+		w.Header()[etagKey] = []string{etag}
+	}
+	w.Header().Set("Content-Type", getByHashesContentType)
+	for i, res := range results {
+		frame := make([]byte, 0, 32+1+4)
+		frame = append(frame, hashes[i].Bytes()...)
+		frame = append(frame, byte(res.status))
+		// #nosec G115
+		frame = binary.BigEndian.AppendUint32(frame, uint32(len(res.data)))
+		if _, err := w.Write(append(frame, res.data...)); err != nil {
+			log.Warn("Failed writing get-by-hashes response", "err", err, "remoteAddr", r.RemoteAddr)
+			restGetByHashesFailureGauge.Inc(1)
+			return
+		}
+		restGetByHashesReturnedBytesGauge.Inc(int64(len(res.data)))
+	}
+}
+
+func decodeHashList(list string) ([]common.Hash, error) {
+	encodedHashes := strings.Split(list, ",")
+	if len(encodedHashes) > maxGetByHashesCount {
+		return nil, fmt.Errorf("too many hashes requested (%d), at most %d are allowed", len(encodedHashes), maxGetByHashesCount)
+	}
+	hashes := make([]common.Hash, len(encodedHashes))
+	for i, encodedHash := range encodedHashes {
+		hashBytes, err := hexutil.Decode("0x" + strings.TrimPrefix(encodedHash, "0x"))
+		if err != nil {
+			return nil, err
+		}
+		if len(hashBytes) != common.HashLength {
+			return nil, fmt.Errorf("hash %s isn't %d bytes long", encodedHash, common.HashLength)
+		}
+		hashes[i] = common.BytesToHash(hashBytes)
+	}
+	return hashes, nil
+}
+
+func etagForHashes(hashes ...common.Hash) string {
+	if len(hashes) == 1 {
+		return `"` + EncodeStorageServiceKey(hashes[0]) + `"`
+	}
+	var joined []byte
+	for _, hash := range hashes {
+		joined = append(joined, hash.Bytes()...)
+	}
+	return `"` + EncodeStorageServiceKey(crypto.Keccak256Hash(joined)) + `"`
+}
+
+// etagMatches returns whether the request's If-None-Match header lists etag.
+func etagMatches(r *http.Request, etag string) bool {
+	for _, header := range r.Header.Values("If-None-Match") {
+		for _, candidate := range strings.Split(header, ",") {
+			candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+			if candidate == etag {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func (rds *RestfulDasServer) GetServerExitedChan() <-chan interface{} { // channel will close when server terminates
 	return rds.httpServerExitedChan
 }
@@ -9,10 +9,14 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/ethereum/go-ethereum/common"
+
 	"github.com/offchainlabs/nitro/cmd/genericconf"
 	"github.com/offchainlabs/nitro/das/dastree"
 )
@@ -69,3 +73,109 @@ func TestRestfulClientServer(t *testing.T) {
 	err = server.Shutdown()
 	Require(t, err)
 }
+
+func TestRestfulServerGetByHashes(t *testing.T) {
+	initTest(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	storage := NewMemoryBackedStorageService(ctx)
+	server, port, err := NewRestfulDasServerOnRandomPort(LocalServerAddressForTest, storage)
+	Require(t, err)
+
+	var hashes []common.Hash
+	var values [][]byte
+	for i := 0; i < maxGetByHashesCount+2; i++ {
+		value := []byte(fmt.Sprintf("value %d", i))
+		// #nosec G115
+		Require(t, storage.Put(ctx, value, uint64(time.Now().Add(time.Hour).Unix())))
+		hashes = append(hashes, dastree.Hash(value))
+		values = append(values, value)
+	}
+	absent := dastree.Hash([]byte("absent data"))
+	hashes = append(hashes, absent)
+	values = append(values, nil)
+
+	client := NewRestfulDasClient("http", LocalServerAddressForTest, port)
+	results, err := client.GetByHashes(ctx, hashes)
+	Require(t, err)
+	for i := range hashes {
+		if !bytes.Equal(results[i], values[i]) {
+			Fail(t, "unexpected result", i, results[i], values[i])
+		}
+	}
+	if client.getByHashesUnsupported.Load() {
+		Fail(t, "client fell back to single gets")
+	}
+
+	// Batches never change, so clients holding them can revalidate without getting them again
+	url := fmt.Sprintf("http://%s:%d%s%s", LocalServerAddressForTest, port, getByHashRequestPath, EncodeStorageServiceKey(hashes[0]))
+	res, err := http.Get(url)
+	Require(t, err)
+	res.Body.Close()
+	etag := res.Header.Get("ETag")
+	if res.StatusCode != http.StatusOK || etag == "" || !strings.Contains(res.Header.Get("Cache-Control"), "immutable") {
+		Fail(t, "unexpected response headers", res.StatusCode, res.Header)
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	Require(t, err)
+	req.Header.Set("If-None-Match", etag)
+	res, err = http.DefaultClient.Do(req)
+	Require(t, err)
+	res.Body.Close()
+	if res.StatusCode != http.StatusNotModified {
+		Fail(t, "expected 304 Not Modified, got", res.StatusCode)
+	}
+
+	// A response with missing batches may change, so isn't cached for long
+	url = fmt.Sprintf("http://%s:%d%s%s,%s", LocalServerAddressForTest, port, getByHashesRequestPath, EncodeStorageServiceKey(hashes[0]), EncodeStorageServiceKey(absent))
+	res, err = http.Get(url)
+	Require(t, err)
+	res.Body.Close()
+	if res.Header.Get("ETag") != "" || res.Header.Get("Cache-Control") != cacheControlValueDefault {
+		Fail(t, "incomplete get-by-hashes response is cacheable", res.Header)
+	}
+
+	Require(t, server.Shutdown())
+}
+
+func TestRestfulClientGetByHashesFallback(t *testing.T) {
+	initTest(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	storage := NewMemoryBackedStorageService(ctx)
+	server, _, err := NewRestfulDasServerOnRandomPort(LocalServerAddressForTest, storage)
+	Require(t, err)
+	value := []byte("fetched one by one")
+	// #nosec G115
+	Require(t, storage.Put(ctx, value, uint64(time.Now().Add(time.Hour).Unix())))
+	hashes := []common.Hash{dastree.Hash(value), dastree.Hash([]byte("absent data"))}
+
+	for _, status := range []int{http.StatusBadRequest, http.StatusNotFound} {
+		// A server without the get-by-hashes route
+		oldServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if strings.HasPrefix(r.URL.Path, getByHashesRequestPath) {
+				w.WriteHeader(status)
+				return
+			}
+			server.ServeHTTP(w, r)
+		}))
+		client, err := NewRestfulDasClientFromURL(oldServer.URL)
+		Require(t, err)
+		results, err := client.GetByHashes(ctx, hashes)
+		Require(t, err)
+		if !bytes.Equal(results[0], value) || results[1] != nil {
+			Fail(t, "unexpected results", status, results)
+		}
+		// Only a 404 shows the route is missing, a 400 may be about the request
+		if client.getByHashesUnsupported.Load() != (status == http.StatusNotFound) {
+			Fail(t, "unexpected fallback for status", status)
+		}
+		oldServer.Close()
+	}
+
+	Require(t, server.Shutdown())
+}
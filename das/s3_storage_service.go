@@ -6,13 +6,18 @@ package das
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"math"
+	"net/url"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 	flag "github.com/spf13/pflag"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -32,9 +37,22 @@ type S3StorageServiceConfig struct {
 	Region              string `koanf:"region"`
 	SecretKey           string `koanf:"secret-key"`
 	DiscardAfterTimeout bool   `koanf:"discard-after-timeout"`
+
+	Endpoint           string `koanf:"endpoint"`
+	UsePathStyle       bool   `koanf:"use-path-style"`
+	Profile            string `koanf:"profile"`
+	RoleARN            string `koanf:"role-arn"`
+	RootCA             string `koanf:"root-ca"`
+	InsecureSkipVerify bool   `koanf:"insecure-skip-verify"`
+
+	TagExpiry            bool          `koanf:"tag-expiry"`
+	ManageLifecycleRules bool          `koanf:"manage-lifecycle-rules"`
+	MaxRetention         time.Duration `koanf:"max-retention"`
 }
 
-var DefaultS3StorageServiceConfig = S3StorageServiceConfig{}
+var DefaultS3StorageServiceConfig = S3StorageServiceConfig{
+	MaxRetention: defaultStorageRetention,
+}
 
 func S3ConfigAddOptions(prefix string, f *flag.FlagSet) {
 	f.Bool(prefix+".enable", DefaultS3StorageServiceConfig.Enable, "enable storage/retrieval of sequencer batch data from an AWS S3 bucket")
@@ -44,6 +62,41 @@ func S3ConfigAddOptions(prefix string, f *flag.FlagSet) {
 	f.String(prefix+".region", DefaultS3StorageServiceConfig.Region, "S3 region")
 	f.String(prefix+".secret-key", DefaultS3StorageServiceConfig.SecretKey, "S3 secret key")
 	f.Bool(prefix+".discard-after-timeout", DefaultS3StorageServiceConfig.DiscardAfterTimeout, "discard data after its expiry timeout")
+	f.String(prefix+".endpoint", DefaultS3StorageServiceConfig.Endpoint, "URL of an S3 compatible object store to use instead of AWS, e.g. MinIO or Ceph RGW (region must still be set, us-east-1 works for most)")
+	f.Bool(prefix+".use-path-style", DefaultS3StorageServiceConfig.UsePathStyle, "address buckets as endpoint/bucket instead of bucket.endpoint, which most S3 compatible object stores need")
+	f.String(prefix+".profile", DefaultS3StorageServiceConfig.Profile, "profile of the shared AWS config and credentials files to use")
+	f.String(prefix+".role-arn", DefaultS3StorageServiceConfig.RoleARN, "IAM role to assume with the configured credentials")
+	f.String(prefix+".root-ca", DefaultS3StorageServiceConfig.RootCA, "PEM file of the certificate authorities to trust, for endpoints with self-signed certificates")
+	f.Bool(prefix+".insecure-skip-verify", DefaultS3StorageServiceConfig.InsecureSkipVerify, "skip TLS certificate verification of the endpoint (DANGEROUS, FOR TESTING ONLY)")
+	f.Bool(prefix+".tag-expiry", DefaultS3StorageServiceConfig.TagExpiry, "tag objects with the number of days until their expiry, so bucket lifecycle rules can delete them")
+	f.Bool(prefix+".manage-lifecycle-rules", DefaultS3StorageServiceConfig.ManageLifecycleRules, "on startup, install the bucket lifecycle rules that delete tagged objects once they expire, keeping the bucket's other rules")
+	f.Duration(prefix+".max-retention", DefaultS3StorageServiceConfig.MaxRetention, "with tag-expiry, store requests with expiry times farther in the future than max-retention will be rejected")
+}
+
+func (c *S3StorageServiceConfig) Validate() error {
+	if c.ManageLifecycleRules && !c.TagExpiry {
+		return errors.New("s3-storage.manage-lifecycle-rules requires s3-storage.tag-expiry")
+	}
+	if c.TagExpiry && c.MaxRetention < 24*time.Hour {
+		return errors.New("s3-storage.max-retention must be at least a day when s3-storage.tag-expiry is set")
+	}
+	if c.ManageLifecycleRules && s3MaxExpiryDays(c.MaxRetention) > s3MaxLifecycleRules {
+		return fmt.Errorf("s3-storage.max-retention can be at most %v days when s3-storage.manage-lifecycle-rules is set", s3MaxLifecycleRules)
+	}
+	return nil
+}
+
+const (
+	// s3ExpiryTagKey tags objects with the number of days after their creation that they expire,
+	// since lifecycle rules can only match tags exactly.
+	s3ExpiryTagKey           = "nitro-das-expiry-days"
+	s3LifecycleRuleIDPrefix  = "nitro-das-expiry-"
+	s3MaxLifecycleRules      = 1000
+	s3NoSuchLifecycleConfErr = "NoSuchLifecycleConfiguration"
+)
+
+func s3MaxExpiryDays(maxRetention time.Duration) int {
+	return int((maxRetention + 24*time.Hour - 1) / (24 * time.Hour))
 }
 
 type S3StorageService struct {
@@ -51,19 +104,90 @@ type S3StorageService struct {
 	bucket              string
 	objectPrefix        string
 	discardAfterTimeout bool
+	tagExpiry           bool
+	maxRetention        time.Duration
 }
 
-func NewS3StorageService(config S3StorageServiceConfig) (StorageService, error) {
-	client, err := s3client.NewS3FullClient(config.AccessKey, config.SecretKey, config.Region)
+func NewS3StorageService(ctx context.Context, config S3StorageServiceConfig) (StorageService, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	client, err := s3client.NewS3FullClientWithOptions(s3client.Options{
+		AccessKey:          config.AccessKey,
+		SecretKey:          config.SecretKey,
+		Region:             config.Region,
+		Endpoint:           config.Endpoint,
+		UsePathStyle:       config.UsePathStyle,
+		Profile:            config.Profile,
+		RoleARN:            config.RoleARN,
+		RootCA:             config.RootCA,
+		InsecureSkipVerify: config.InsecureSkipVerify,
+	})
 	if err != nil {
 		return nil, err
 	}
-	return &S3StorageService{
+	s3s := &S3StorageService{
 		client:              client,
 		bucket:              config.Bucket,
 		objectPrefix:        config.ObjectPrefix,
 		discardAfterTimeout: config.DiscardAfterTimeout,
-	}, nil
+		tagExpiry:           config.TagExpiry,
+		maxRetention:        config.MaxRetention,
+	}
+	if config.ManageLifecycleRules {
+		if err := s3s.ensureLifecycleRules(ctx); err != nil {
+			return nil, fmt.Errorf("installing S3 lifecycle rules: %w", err)
+		}
+	}
+	return s3s, nil
+}
+
+// ensureLifecycleRules installs a lifecycle rule for each possible value of the expiry tag,
+// replacing the rules it installed before and keeping the bucket's other rules.
+func (s3s *S3StorageService) ensureLifecycleRules(ctx context.Context) error {
+	client := s3s.client.Client()
+	var rules []types.LifecycleRule
+	existing, err := client.GetBucketLifecycleConfiguration(ctx, &s3.GetBucketLifecycleConfigurationInput{
+		Bucket: aws.String(s3s.bucket),
+	})
+	var apiErr interface{ ErrorCode() string }
+	switch {
+	case err == nil:
+		for _, rule := range existing.Rules {
+			if !strings.HasPrefix(aws.ToString(rule.ID), s3LifecycleRuleIDPrefix) {
+				rules = append(rules, rule)
+			}
+		}
+	case errors.As(err, &apiErr) && apiErr.ErrorCode() == s3NoSuchLifecycleConfErr:
+		// The bucket has no lifecycle rules yet
+	default:
+		return err
+	}
+	for days := 1; days <= s3MaxExpiryDays(s3s.maxRetention); days++ {
+		tag := types.Tag{Key: aws.String(s3ExpiryTagKey), Value: aws.String(strconv.Itoa(days))}
+		var filter types.LifecycleRuleFilter = &types.LifecycleRuleFilterMemberTag{Value: tag}
+		if s3s.objectPrefix != "" {
+			filter = &types.LifecycleRuleFilterMemberAnd{Value: types.LifecycleRuleAndOperator{
+				Prefix: aws.String(s3s.objectPrefix),
+				Tags:   []types.Tag{tag},
+			}}
+		}
+		rules = append(rules, types.LifecycleRule{
+			ID:     aws.String(fmt.Sprintf("%s%d", s3LifecycleRuleIDPrefix, days)),
+			Status: types.ExpirationStatusEnabled,
+			Filter: filter,
+			// #nosec G115
+			Expiration: &types.LifecycleExpiration{Days: aws.Int32(int32(days))},
+		})
+	}
+	if len(rules) > s3MaxLifecycleRules {
+		return fmt.Errorf("bucket %v would have %v lifecycle rules, more than the %v S3 allows", s3s.bucket, len(rules), s3MaxLifecycleRules)
+	}
+	_, err = client.PutBucketLifecycleConfiguration(ctx, &s3.PutBucketLifecycleConfigurationInput{
+		Bucket:                 aws.String(s3s.bucket),
+		LifecycleConfiguration: &types.BucketLifecycleConfiguration{Rules: rules},
+	})
+	return err
 }
 
 func (s3s *S3StorageService) GetByHash(ctx context.Context, key common.Hash) ([]byte, error) {
@@ -74,20 +198,42 @@ func (s3s *S3StorageService) GetByHash(ctx context.Context, key common.Hash) ([]
 		Bucket: aws.String(s3s.bucket),
 		Key:    aws.String(s3s.objectPrefix + EncodeStorageServiceKey(key)),
 	})
+	var noSuchKey *types.NoSuchKey
+	if errors.As(err, &noSuchKey) {
+		return nil, ErrNotFound
+	}
 	return buf.Bytes(), err
 }
 
 func (s3s *S3StorageService) Put(ctx context.Context, value []byte, timeout uint64) error {
 	logPut("das.S3StorageService.Store", value, timeout, s3s)
+	return s3s.putKeyValue(ctx, dastree.Hash(value), value, timeout)
+}
+
+func (s3s *S3StorageService) putKeyValue(ctx context.Context, key common.Hash, value []byte, timeout uint64) error {
 	putObjectInput := s3.PutObjectInput{
 		Bucket: aws.String(s3s.bucket),
-		Key:    aws.String(s3s.objectPrefix + EncodeStorageServiceKey(dastree.Hash(value))),
+		Key:    aws.String(s3s.objectPrefix + EncodeStorageServiceKey(key)),
 		Body:   bytes.NewReader(value)}
 	if s3s.discardAfterTimeout && timeout <= math.MaxInt64 {
 		// #nosec G115
 		expires := time.Unix(int64(timeout), 0)
 		putObjectInput.Expires = &expires
 	}
+	if s3s.tagExpiry {
+		if timeout > math.MaxInt64 {
+			return fmt.Errorf("request expiry time (%v) exceeds max int64", timeout)
+		}
+		// #nosec G115
+		expiryTime := time.Unix(int64(timeout), 0)
+		currentTimePlusRetention := time.Now().Add(s3s.maxRetention)
+		if expiryTime.After(currentTimePlusRetention) {
+			return fmt.Errorf("requested expiry time (%v) exceeds current time plus maximum allowed retention period(%v)", expiryTime, currentTimePlusRetention)
+		}
+		// Lifecycle rules count whole days from the object's creation, so round up to keep the object at least until its expiry
+		days := max(1, int((time.Until(expiryTime)+24*time.Hour-1)/(24*time.Hour)))
+		putObjectInput.Tagging = aws.String(url.Values{s3ExpiryTagKey: []string{strconv.Itoa(days)}}.Encode())
+	}
 	_, err := s3s.client.Upload(ctx, &putObjectInput)
 	if err != nil {
 		log.Error("das.S3StorageService.Store", "err", err)
@@ -95,6 +241,65 @@ func (s3s *S3StorageService) Put(ctx context.Context, value []byte, timeout uint
 	return err
 }
 
+// s3QuarantinePrefix is prepended to the object names of the batches the scrubber found corrupt.
+const s3QuarantinePrefix = "quarantine/"
+
+func (s3s *S3StorageService) iterateEntries(ctx context.Context, visit func(key common.Hash, expiry uint64) error) error {
+	client := s3s.client.Client()
+	if client == nil {
+		return errors.New("S3 client can't list objects")
+	}
+	paginator := s3.NewListObjectsV2Paginator(client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(s3s.bucket),
+		Prefix: aws.String(s3s.objectPrefix),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return err
+		}
+		for _, object := range page.Contents {
+			name := strings.TrimPrefix(aws.ToString(object.Key), s3s.objectPrefix)
+			if !isStorageServiceKey(name) {
+				continue
+			}
+			key, err := DecodeStorageServiceKey(name)
+			if err != nil {
+				return err
+			}
+			if err := visit(key, 0); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (s3s *S3StorageService) quarantine(ctx context.Context, key common.Hash, expiry uint64) error {
+	client := s3s.client.Client()
+	if client == nil {
+		return errors.New("S3 client can't move objects")
+	}
+	objectKey := s3s.objectPrefix + EncodeStorageServiceKey(key)
+	_, err := client.CopyObject(ctx, &s3.CopyObjectInput{
+		Bucket:     aws.String(s3s.bucket),
+		CopySource: aws.String(s3s.bucket + "/" + objectKey),
+		Key:        aws.String(s3s.objectPrefix + s3QuarantinePrefix + EncodeStorageServiceKey(key)),
+	})
+	var noSuchKey *types.NoSuchKey
+	if errors.As(err, &noSuchKey) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	_, err = client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(s3s.bucket),
+		Key:    aws.String(objectKey),
+	})
+	return err
+}
+
 func (s3s *S3StorageService) Sync(ctx context.Context) error {
 	return nil
 }
@@ -104,7 +309,7 @@ func (s3s *S3StorageService) Close(ctx context.Context) error {
 }
 
 func (s3s *S3StorageService) ExpirationPolicy(ctx context.Context) (daprovider.ExpirationPolicy, error) {
-	if s3s.discardAfterTimeout {
+	if s3s.discardAfterTimeout || s3s.tagExpiry {
 		return daprovider.DiscardAfterDataTimeout, nil
 	}
 	return daprovider.KeepForever, nil
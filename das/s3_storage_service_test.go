@@ -7,12 +7,18 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"fmt"
 	"io"
+	"os"
 	"testing"
 	"time"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+
+	"github.com/ethereum/go-ethereum/common"
 
 	"github.com/offchainlabs/nitro/cmd/genericconf"
 	"github.com/offchainlabs/nitro/das/dastree"
@@ -20,6 +26,7 @@ import (
 
 type mockS3FullClient struct {
 	mockStorageService StorageService
+	lastTagging        string
 }
 
 func (m *mockS3FullClient) Client() *s3.Client {
@@ -27,6 +34,7 @@ func (m *mockS3FullClient) Client() *s3.Client {
 }
 
 func (m *mockS3FullClient) Upload(ctx context.Context, input *s3.PutObjectInput, opts ...func(*manager.Uploader)) (*manager.UploadOutput, error) {
+	m.lastTagging = aws.ToString(input.Tagging)
 	buf := new(bytes.Buffer)
 	_, err := buf.ReadFrom(input.Body)
 	if err != nil {
@@ -56,7 +64,7 @@ func (m *mockS3FullClient) Download(ctx context.Context, w io.WriterAt, input *s
 
 func NewTestS3StorageService(ctx context.Context, s3Config genericconf.S3Config) (StorageService, error) {
 	mockStorageService := NewMemoryBackedStorageService(ctx)
-	s3FullClient := &mockS3FullClient{mockStorageService}
+	s3FullClient := &mockS3FullClient{mockStorageService: mockStorageService}
 	return &S3StorageService{
 		bucket: s3Config.Bucket,
 		client: s3FullClient,
@@ -92,3 +100,129 @@ func TestS3StorageService(t *testing.T) {
 		t.Fatal(val, val1)
 	}
 }
+
+func TestS3StorageServiceTagExpiry(t *testing.T) {
+	ctx := context.Background()
+	client := &mockS3FullClient{mockStorageService: NewMemoryBackedStorageService(ctx)}
+	s3Service := &S3StorageService{
+		client:       client,
+		tagExpiry:    true,
+		maxRetention: 7 * 24 * time.Hour,
+	}
+
+	// #nosec G115
+	timeout := uint64(time.Now().Add(36 * time.Hour).Unix())
+	Require(t, s3Service.Put(ctx, []byte("value"), timeout))
+	if client.lastTagging != s3ExpiryTagKey+"=2" {
+		t.Fatal("unexpected tagging", client.lastTagging)
+	}
+
+	// #nosec G115
+	tooLate := uint64(time.Now().Add(8 * 24 * time.Hour).Unix())
+	if err := s3Service.Put(ctx, []byte("value"), tooLate); err == nil {
+		t.Fatal("stored a batch expiring after max-retention")
+	}
+}
+
+func TestS3StorageServiceConfigValidate(t *testing.T) {
+	config := DefaultS3StorageServiceConfig
+	config.ManageLifecycleRules = true
+	if config.Validate() == nil {
+		t.Fatal("accepted manage-lifecycle-rules without tag-expiry")
+	}
+	config.TagExpiry = true
+	Require(t, config.Validate())
+	config.MaxRetention = 2000 * 24 * time.Hour
+	if config.Validate() == nil {
+		t.Fatal("accepted more lifecycle rules than S3 allows")
+	}
+}
+
+// TestS3StorageServiceEmulator runs against an S3 compatible object store, such as a local MinIO started with
+// docker run -p 9000:9000 minio/minio server /data
+// DAS_S3_TEST_ENDPOINT=http://localhost:9000 go test ./das -run TestS3StorageServiceEmulator
+func TestS3StorageServiceEmulator(t *testing.T) {
+	endpoint := os.Getenv("DAS_S3_TEST_ENDPOINT")
+	if endpoint == "" {
+		t.Skip("DAS_S3_TEST_ENDPOINT isn't set")
+	}
+	accessKey, secretKey := os.Getenv("DAS_S3_TEST_ACCESS_KEY"), os.Getenv("DAS_S3_TEST_SECRET_KEY")
+	if accessKey == "" && secretKey == "" {
+		accessKey, secretKey = "minioadmin", "minioadmin"
+	}
+	ctx := context.Background()
+	config := DefaultS3StorageServiceConfig
+	config.Enable = true
+	config.Endpoint = endpoint
+	config.UsePathStyle = true
+	config.Region = "us-east-1"
+	config.AccessKey = accessKey
+	config.SecretKey = secretKey
+	config.Bucket = "nitro-das-test"
+	config.ObjectPrefix = fmt.Sprintf("test-%d/", time.Now().UnixNano())
+	config.TagExpiry = true
+	config.ManageLifecycleRules = true
+
+	bootstrap, err := NewS3StorageService(ctx, S3StorageServiceConfig{
+		Endpoint:     endpoint,
+		UsePathStyle: true,
+		Region:       config.Region,
+		AccessKey:    accessKey,
+		SecretKey:    secretKey,
+	})
+	Require(t, err)
+	_, err = bootstrap.(*S3StorageService).client.Client().CreateBucket(ctx, &s3.CreateBucketInput{Bucket: aws.String(config.Bucket)})
+	var alreadyOwned *types.BucketAlreadyOwnedByYou
+	if err != nil && !errors.As(err, &alreadyOwned) {
+		t.Fatal(err)
+	}
+
+	storage, err := NewS3StorageService(ctx, config)
+	Require(t, err)
+	s3Service := storage.(*S3StorageService)
+	Require(t, s3Service.HealthCheck(ctx))
+
+	lifecycle, err := s3Service.client.Client().GetBucketLifecycleConfiguration(ctx, &s3.GetBucketLifecycleConfigurationInput{Bucket: aws.String(config.Bucket)})
+	Require(t, err)
+	if len(lifecycle.Rules) < s3MaxExpiryDays(config.MaxRetention) {
+		t.Fatal("expected a lifecycle rule per expiry day, found", len(lifecycle.Rules))
+	}
+
+	val := []byte("The value")
+	key := dastree.Hash(val)
+	_, err = s3Service.GetByHash(ctx, key)
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatal(err)
+	}
+	// #nosec G115
+	Require(t, s3Service.Put(ctx, val, uint64(time.Now().Add(time.Hour).Unix())))
+	got, err := s3Service.GetByHash(ctx, key)
+	Require(t, err)
+	if !bytes.Equal(got, val) {
+		t.Fatal(got, val)
+	}
+
+	tagging, err := s3Service.client.Client().GetObjectTagging(ctx, &s3.GetObjectTaggingInput{
+		Bucket: aws.String(config.Bucket),
+		Key:    aws.String(config.ObjectPrefix + EncodeStorageServiceKey(key)),
+	})
+	Require(t, err)
+	if len(tagging.TagSet) != 1 || aws.ToString(tagging.TagSet[0].Key) != s3ExpiryTagKey || aws.ToString(tagging.TagSet[0].Value) != "1" {
+		t.Fatal("unexpected tags", tagging.TagSet)
+	}
+
+	var listed []common.Hash
+	Require(t, s3Service.iterateEntries(ctx, func(key common.Hash, expiry uint64) error {
+		listed = append(listed, key)
+		return nil
+	}))
+	if len(listed) != 1 || listed[0] != key {
+		t.Fatal("unexpected listing", listed)
+	}
+
+	Require(t, s3Service.quarantine(ctx, key, 0))
+	_, err = s3Service.GetByHash(ctx, key)
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatal(err)
+	}
+}
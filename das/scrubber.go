@@ -0,0 +1,257 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+package das
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	flag "github.com/spf13/pflag"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+
+	"github.com/offchainlabs/nitro/arbstate/daprovider"
+	"github.com/offchainlabs/nitro/das/dastree"
+	"github.com/offchainlabs/nitro/util/pretty"
+	"github.com/offchainlabs/nitro/util/stopwaiter"
+)
+
+var (
+	scrubberCheckedCounter      = metrics.NewRegisteredCounter("arb/das/scrubber/checked", nil)
+	scrubberMismatchCounter     = metrics.NewRegisteredCounter("arb/das/scrubber/mismatched", nil)
+	scrubberMissingCounter      = metrics.NewRegisteredCounter("arb/das/scrubber/missing", nil)
+	scrubberRepairedCounter     = metrics.NewRegisteredCounter("arb/das/scrubber/repaired", nil)
+	scrubberRepairFailedCounter = metrics.NewRegisteredCounter("arb/das/scrubber/repair_failed", nil)
+	scrubberPassCheckedGauge    = metrics.NewRegisteredGauge("arb/das/scrubber/pass/checked", nil)
+	scrubberPassDurationGauge   = metrics.NewRegisteredGauge("arb/das/scrubber/pass/duration", nil)
+)
+
+type ScrubberConfig struct {
+	Enable              bool          `koanf:"enable"`
+	Interval            time.Duration `koanf:"interval"`
+	MaxBytesPerSecond   uint64        `koanf:"max-bytes-per-second"`
+	Repair              bool          `koanf:"repair"`
+	RepairRetentionTime time.Duration `koanf:"repair-retention-time"`
+}
+
+var DefaultScrubberConfig = ScrubberConfig{
+	Enable:              false,
+	Interval:            24 * time.Hour,
+	MaxBytesPerSecond:   10 * 1024 * 1024,
+	Repair:              true,
+	RepairRetentionTime: defaultStorageRetention,
+}
+
+func ScrubberConfigAddOptions(prefix string, f *flag.FlagSet) {
+	f.Bool(prefix+".enable", DefaultScrubberConfig.Enable, "periodically check that every stored batch still matches its hash, and quarantine the ones that don't")
+	f.Duration(prefix+".interval", DefaultScrubberConfig.Interval, "time to wait between the end of a scrub and the start of the next one")
+	f.Uint64(prefix+".max-bytes-per-second", DefaultScrubberConfig.MaxBytesPerSecond, "maximum rate at which the scrubber reads stored batches (0 for unlimited)")
+	f.Bool(prefix+".repair", DefaultScrubberConfig.Repair, "re-fetch missing and corrupt batches from the REST aggregator, if it's enabled")
+	f.Duration(prefix+".repair-retention-time", DefaultScrubberConfig.RepairRetentionTime, "retention time of repaired batches whose original expiry time isn't known")
+}
+
+func (c *ScrubberConfig) Validate() error {
+	if c.Enable && c.Interval <= 0 {
+		return errors.New("scrubber interval must be positive")
+	}
+	return nil
+}
+
+// scrubbableStorage is implemented by storage services whose entries the scrubber can walk and quarantine.
+type scrubbableStorage interface {
+	iterableStorage
+	// quarantine moves a batch out of the way, so a good copy can be stored under its key.
+	quarantine(ctx context.Context, key common.Hash, expiry uint64) error
+}
+
+type ScrubStats struct {
+	Checked    uint64
+	Mismatched uint64
+	Missing    uint64
+	Repaired   uint64
+}
+
+// Scrubber walks the batches of the storage backends, checks they still hash to their keys,
+// quarantines the ones that don't, and optionally re-fetches them.
+type Scrubber struct {
+	stopwaiter.StopWaiter
+	config   ScrubberConfig
+	storages []scrubbableStorage
+	source   daprovider.DASReader // where to re-fetch batches from, nil to only quarantine them
+
+	nextRead time.Time
+}
+
+func NewScrubber(config ScrubberConfig, storageService StorageService, source daprovider.DASReader) (*Scrubber, error) {
+	var services []StorageService
+	switch s := storageService.(type) {
+	case *RedundantStorageService:
+		services = s.innerServices
+	case *ErasureCodedStorageService:
+		return nil, errors.New("the scrubber can't check erasure coded shards, which aren't stored under their hash")
+	default:
+		services = []StorageService{storageService}
+	}
+	var storages []scrubbableStorage
+	for _, service := range services {
+		storage, ok := service.(scrubbableStorage)
+		if !ok {
+			log.Warn("DAS scrubber can't check storage backend", "backend", service)
+			continue
+		}
+		storages = append(storages, storage)
+	}
+	if len(storages) == 0 {
+		return nil, errors.New("none of the DAS storage backends can be scrubbed")
+	}
+	if !config.Repair {
+		source = nil
+	}
+	return &Scrubber{
+		config:   config,
+		storages: storages,
+		source:   source,
+	}, nil
+}
+
+func (s *Scrubber) Start(ctxIn context.Context) {
+	s.StopWaiter.Start(ctxIn, s)
+	s.CallIteratively(func(ctx context.Context) time.Duration {
+		if _, err := s.ScrubOnce(ctx); err != nil && ctx.Err() == nil {
+			log.Error("DAS scrub failed", "err", err)
+		}
+		return s.config.Interval
+	})
+}
+
+func (s *Scrubber) Close(ctx context.Context) error {
+	s.StopOnly()
+	waitChan, err := s.GetWaitChannel()
+	if err != nil {
+		return err
+	}
+	select {
+	case <-waitChan:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *Scrubber) String() string {
+	return fmt.Sprintf("das.Scrubber{%v}", s.storages)
+}
+
+// ScrubOnce checks every batch of every storage backend once.
+func (s *Scrubber) ScrubOnce(ctx context.Context) (ScrubStats, error) {
+	var stats ScrubStats
+	start := time.Now()
+	scrubberPassCheckedGauge.Update(0)
+	for _, storage := range s.storages {
+		log.Info("Scrubbing DAS storage backend", "backend", storage)
+		err := storage.iterateEntries(ctx, func(key common.Hash, expiry uint64) error {
+			// #nosec G115
+			if expiry != 0 && expiry < uint64(time.Now().Unix()) {
+				// Expired batches are left for pruning
+				return nil
+			}
+			return s.scrubEntry(ctx, storage, key, expiry, &stats)
+		})
+		if err != nil {
+			return stats, fmt.Errorf("scrubbing %v: %w", storage, err)
+		}
+	}
+	scrubberPassDurationGauge.Update(time.Since(start).Milliseconds())
+	log.Info("DAS scrub complete", "checked", stats.Checked, "mismatched", stats.Mismatched, "missing", stats.Missing, "repaired", stats.Repaired, "duration", time.Since(start))
+	return stats, nil
+}
+
+func (s *Scrubber) scrubEntry(ctx context.Context, storage scrubbableStorage, key common.Hash, expiry uint64, stats *ScrubStats) error {
+	data, err := storage.GetByHash(ctx, key)
+	missing := errors.Is(err, ErrNotFound)
+	if err != nil && !missing {
+		return err
+	}
+	if err := s.throttle(ctx, len(data)); err != nil {
+		return err
+	}
+	stats.Checked++
+	scrubberCheckedCounter.Inc(1)
+	scrubberPassCheckedGauge.Inc(1)
+	if missing {
+		stats.Missing++
+		scrubberMissingCounter.Inc(1)
+		log.Warn("DAS scrubber found a missing batch", "key", pretty.PrettyHash(key), "backend", storage)
+		if s.source == nil {
+			return nil
+		}
+	} else if dastree.ValidHash(key, data) {
+		return nil
+	} else {
+		stats.Mismatched++
+		scrubberMismatchCounter.Inc(1)
+		log.Error("DAS scrubber found a batch that doesn't match its hash", "key", pretty.PrettyHash(key), "backend", storage)
+	}
+
+	// Both missing and corrupt batches may have stale index entries, which need to go before the batch can be stored again
+	if err := storage.quarantine(ctx, key, expiry); err != nil {
+		return fmt.Errorf("quarantining batch %v: %w", key, err)
+	}
+	if s.source == nil {
+		return nil
+	}
+	if err := s.repair(ctx, storage, key, expiry); err != nil {
+		scrubberRepairFailedCounter.Inc(1)
+		log.Error("DAS scrubber couldn't repair batch", "key", pretty.PrettyHash(key), "backend", storage, "err", err)
+		return nil
+	}
+	stats.Repaired++
+	scrubberRepairedCounter.Inc(1)
+	log.Info("DAS scrubber repaired batch", "key", pretty.PrettyHash(key), "backend", storage)
+	return nil
+}
+
+func (s *Scrubber) repair(ctx context.Context, storage scrubbableStorage, key common.Hash, expiry uint64) error {
+	data, err := s.source.GetByHash(ctx, key)
+	if err != nil {
+		return err
+	}
+	if !dastree.ValidHash(key, data) {
+		return errors.New("fetched batch doesn't match its hash")
+	}
+	if expiry == 0 {
+		// #nosec G115
+		expiry = uint64(time.Now().Add(s.config.RepairRetentionTime).Unix())
+	}
+	return storage.Put(ctx, data, expiry)
+}
+
+// throttle waits until reading size more bytes keeps the scrubber within its rate limit.
+func (s *Scrubber) throttle(ctx context.Context, size int) error {
+	if s.config.MaxBytesPerSecond == 0 {
+		return nil
+	}
+	now := time.Now()
+	if s.nextRead.Before(now) {
+		s.nextRead = now
+	}
+	// #nosec G115
+	s.nextRead = s.nextRead.Add(time.Duration(uint64(size) * uint64(time.Second) / s.config.MaxBytesPerSecond))
+	wait := time.Until(s.nextRead)
+	if wait <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
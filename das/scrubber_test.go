@@ -0,0 +1,94 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+package das
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/offchainlabs/nitro/das/dastree"
+)
+
+func TestScrubberLocalFileStorage(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	s, err := NewLocalFileStorageService(LocalFileStorageConfig{
+		Enable:       true,
+		DataDir:      dir,
+		EnableExpiry: true,
+		MaxRetention: time.Hour * 24 * 30,
+	})
+	Require(t, err)
+	Require(t, s.start(ctx))
+
+	// #nosec G115
+	timeout := uint64(time.Now().Add(time.Hour).Unix())
+	source := NewMemoryBackedStorageService(ctx)
+	for _, val := range []string{"a", "b", "c"} {
+		Require(t, s.Put(ctx, []byte(val), timeout))
+		Require(t, source.Put(ctx, []byte(val), timeout))
+	}
+
+	// Corrupt one batch and lose another
+	Require(t, os.WriteFile(s.layout.batchPath(dastree.Hash([]byte("a"))), []byte("not a"), 0o600))
+	Require(t, os.Remove(s.layout.batchPath(dastree.Hash([]byte("b")))))
+
+	config := DefaultScrubberConfig
+	config.MaxBytesPerSecond = 0
+	scrubber, err := NewScrubber(config, s, source)
+	Require(t, err)
+	stats, err := scrubber.ScrubOnce(ctx)
+	Require(t, err)
+	if stats != (ScrubStats{Checked: 3, Mismatched: 1, Missing: 1, Repaired: 2}) {
+		Fail(t, "unexpected scrub stats", stats)
+	}
+	getByHashAndCheck(t, s, "a", "b", "c")
+
+	quarantined, err := os.ReadDir(filepath.Join(dir, quarantineDir))
+	Require(t, err)
+	if len(quarantined) != 1 {
+		Fail(t, "expected the corrupt batch to be quarantined, found", len(quarantined))
+	}
+
+	stats, err = scrubber.ScrubOnce(ctx)
+	Require(t, err)
+	if stats != (ScrubStats{Checked: 3}) {
+		Fail(t, "unexpected scrub stats after repair", stats)
+	}
+}
+
+func TestScrubberWithoutRepair(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewLocalFileStorageService(LocalFileStorageConfig{
+		Enable:       true,
+		DataDir:      t.TempDir(),
+		MaxRetention: time.Hour * 24 * 30,
+	})
+	Require(t, err)
+	Require(t, s.start(ctx))
+
+	// #nosec G115
+	timeout := uint64(time.Now().Add(time.Hour).Unix())
+	Require(t, s.Put(ctx, []byte("a"), timeout))
+	key := dastree.Hash([]byte("a"))
+	Require(t, os.WriteFile(s.layout.batchPath(key), []byte("not a"), 0o600))
+
+	config := DefaultScrubberConfig
+	config.MaxBytesPerSecond = 0
+	config.Repair = false
+	scrubber, err := NewScrubber(config, s, NewMemoryBackedStorageService(ctx))
+	Require(t, err)
+	stats, err := scrubber.ScrubOnce(ctx)
+	Require(t, err)
+	if stats != (ScrubStats{Checked: 1, Mismatched: 1}) {
+		Fail(t, "unexpected scrub stats", stats)
+	}
+	if _, err := s.GetByHash(ctx, key); !errors.Is(err, ErrNotFound) {
+		Fail(t, "corrupt batch wasn't quarantined", err)
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
 
 	"github.com/offchainlabs/nitro/solgen/go/bridgegen"
@@ -24,6 +25,9 @@ type SignatureVerifier struct {
 	// Extra batch poster verifier, for local installations to have their
 	// own way of testing Stores.
 	extraBpVerifier func(message []byte, sig []byte, extraFields ...uint64) bool
+
+	// Additional keys allowed to store data, subject to their own quotas.
+	writers map[common.Address]*writerKey
 }
 
 func NewSignatureVerifier(ctx context.Context, config DataAvailabilityConfig) (*SignatureVerifier, error) {
@@ -93,6 +97,33 @@ func NewSignatureVerifierWithSeqInboxCaller(
 
 }
 
+func (v *SignatureVerifier) setWriterKeys(configs WriterKeyConfigList) error {
+	writers, err := newWriterKeys(configs)
+	if err != nil {
+		return err
+	}
+	v.writers = writers
+	return nil
+}
+
+// authenticate verifies the request was signed by one of the writer keys or by the batch poster,
+// returning the writer key, or nil for the batch poster.
+func (v *SignatureVerifier) authenticate(
+	ctx context.Context, message []byte, sig []byte, extraFields ...uint64) (*writerKey, error) {
+	if len(v.writers) > 0 {
+		signer, err := DasRecoverSigner(message, sig, extraFields...)
+		if err == nil {
+			if writer, ok := v.writers[signer]; ok {
+				return writer, nil
+			}
+		}
+		if v.extraBpVerifier == nil && v.addrVerifier == nil {
+			return nil, errors.New("request not signed by a known writer key")
+		}
+	}
+	return nil, v.verify(ctx, message, sig, extraFields...)
+}
+
 func (v *SignatureVerifier) verify(
 	ctx context.Context, message []byte, sig []byte, extraFields ...uint64) error {
 	if v.extraBpVerifier == nil && v.addrVerifier == nil {
@@ -123,5 +154,5 @@ func (v *SignatureVerifier) verify(
 func (v *SignatureVerifier) String() string {
 	hasAddrVerifier := v.addrVerifier != nil
 	hasExtraBpVerifier := v.extraBpVerifier != nil
-	return fmt.Sprintf("SignatureVerifier{hasAddrVerifier:%v,hasExtraBpVerifier:%v}", hasAddrVerifier, hasExtraBpVerifier)
+	return fmt.Sprintf("SignatureVerifier{hasAddrVerifier:%v,hasExtraBpVerifier:%v,writerKeys:%v}", hasAddrVerifier, hasExtraBpVerifier, len(v.writers))
 }
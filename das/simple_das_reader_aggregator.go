@@ -271,6 +271,147 @@ func (a *SimpleDASReaderAggregator) tryGetByHash(
 	return result, err
 }
 
+// multiGetReader is implemented by readers that can fetch several batches in one request.
+type multiGetReader interface {
+	GetByHashes(ctx context.Context, hashes []common.Hash) ([][]byte, error)
+}
+
+// GetByHashes fetches several batches, asking the readers in parallel in the strategy's order, each set of readers
+// for the batches still missing. The batches none of the readers have are left nil.
+func (a *SimpleDASReaderAggregator) GetByHashes(ctx context.Context, hashes []common.Hash) ([][]byte, error) {
+	a.readersMutex.RLock()
+	defer a.readersMutex.RUnlock()
+	log.Trace("das.SimpleDASReaderAggregator.GetByHashes", "count", len(hashes), "this", a)
+
+	results := make([][]byte, len(hashes))
+	var resultsMutex sync.Mutex
+	missing := func() []int {
+		resultsMutex.Lock()
+		defer resultsMutex.Unlock()
+		var indexes []int
+		for i, result := range results {
+			if result == nil {
+				indexes = append(indexes, i)
+			}
+		}
+		return indexes
+	}
+
+	subCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	done := make(chan interface{})
+
+	go func() {
+		allReaders := sync.WaitGroup{}
+		defer close(done)
+		defer allReaders.Wait()
+		si := a.strategy.newInstance()
+		for readers := si.nextReaders(); len(readers) != 0 && subCtx.Err() == nil; readers = si.nextReaders() {
+			indexes := missing()
+			if len(indexes) == 0 {
+				return
+			}
+			wanted := make([]common.Hash, len(indexes))
+			for i, index := range indexes {
+				wanted[i] = hashes[index]
+			}
+			wg := sync.WaitGroup{}
+			waitChan := make(chan interface{})
+			for _, reader := range readers {
+				wg.Add(1)
+				allReaders.Add(1)
+				go func(reader daprovider.DASReader) {
+					defer allReaders.Done()
+					defer wg.Done()
+					fetched, err := a.tryGetByHashes(subCtx, wanted, reader)
+					if err != nil {
+						return
+					}
+					resultsMutex.Lock()
+					defer resultsMutex.Unlock()
+					for i, index := range indexes {
+						if fetched[i] != nil {
+							results[index] = fetched[i]
+						}
+					}
+					for _, result := range results {
+						if result == nil {
+							return
+						}
+					}
+					// Every batch was found, stop the readers still working on them
+					cancel()
+				}(reader)
+			}
+			go func() {
+				wg.Wait()
+				close(waitChan)
+			}()
+			select {
+			case <-subCtx.Done():
+				return
+			case <-time.After(a.config.WaitBeforeTryNext):
+			case <-waitChan:
+			}
+		}
+	}()
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-done:
+		return results, nil
+	}
+}
+
+// tryGetByHashes returns the batches reader has, leaving nil the ones it doesn't have or returned with the wrong hash.
+// It fails only if the reader couldn't be asked at all.
+func (a *SimpleDASReaderAggregator) tryGetByHashes(
+	ctx context.Context, hashes []common.Hash, reader daprovider.DASReader,
+) ([][]byte, error) {
+	stat := readerStatMessage{reader: reader}
+
+	start := time.Now()
+	var fetched [][]byte
+	var err error
+	if multiReader, ok := reader.(multiGetReader); ok {
+		fetched, err = multiReader.GetByHashes(ctx, hashes)
+	} else {
+		fetched = make([][]byte, len(hashes))
+		for i, hash := range hashes {
+			if data, err := reader.GetByHash(ctx, hash); err == nil {
+				fetched[i] = data
+			}
+		}
+	}
+	if err != nil && errors.Is(ctx.Err(), context.Canceled) {
+		// Don't record a stats data point when other readers already returned every batch
+		return nil, err
+	}
+	// Record the latency per batch, so it compares with single GetByHash calls
+	// #nosec G115
+	stat.latency = time.Since(start) / time.Duration(len(hashes))
+
+	if err != nil {
+		log.Warn("SimpleDASReaderAggregator couldn't get batches from reader", "reader", reader, "err", err)
+	} else {
+		stat.success = true
+		for i, hash := range hashes {
+			if fetched[i] != nil && !dastree.ValidHash(hash, fetched[i]) {
+				fetched[i] = nil
+			}
+		}
+	}
+
+	select {
+	case a.statMessages <- stat:
+		// Non-blocking write to stat channel
+	default:
+		log.Warn("SimpleDASReaderAggregator stats processing goroutine is backed up, dropping", "dropped stats", stat)
+	}
+	return fetched, err
+}
+
 func (a *SimpleDASReaderAggregator) Start(ctx context.Context) {
 	a.StopWaiter.Start(ctx, a)
 	onlineUrlsChan := StartRestfulServerListFetchDaemon(a.StopWaiter.GetContext(), a.config.OnlineUrlList, a.config.OnlineUrlListFetchInterval)
@@ -7,11 +7,15 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"strconv"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/ethereum/go-ethereum/common"
+
 	"github.com/offchainlabs/nitro/das/dastree"
 )
 
@@ -88,3 +92,81 @@ func TestSimpleDASReaderAggregator(t *testing.T) { //nolint
 	Require(t, err)
 
 }
+
+func TestSimpleDASReaderAggregatorGetByHashes(t *testing.T) {
+	initTest(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	storage1, storage2 := NewMemoryBackedStorageService(ctx), NewMemoryBackedStorageService(ctx)
+	server1, port1, err := NewRestfulDasServerOnRandomPort(LocalServerAddressForTest, storage1)
+	Require(t, err)
+	server2, port2, err := NewRestfulDasServerOnRandomPort(LocalServerAddressForTest, storage2)
+	Require(t, err)
+
+	data1, data2 := []byte("only on the first endpoint"), []byte("only on the second endpoint")
+	// #nosec G115
+	timeout := uint64(time.Now().Add(time.Hour).Unix())
+	Require(t, storage1.Put(ctx, data1, timeout))
+	Require(t, storage2.Put(ctx, data2, timeout))
+
+	config := RestfulClientAggregatorConfig{
+		Urls:                   []string{"http://localhost:" + strconv.Itoa(port1), "http://localhost:" + strconv.Itoa(port2)},
+		Strategy:               "testing-sequential",
+		StrategyUpdateInterval: time.Second,
+		WaitBeforeTryNext:      500 * time.Millisecond,
+		MaxPerEndpointStats:    10,
+	}
+	agg, err := NewRestfulClientAggregator(ctx, &config)
+	Require(t, err)
+
+	hashes := []common.Hash{dastree.Hash(data1), dastree.Hash(data2), dastree.Hash([]byte("absent data"))}
+	results, err := agg.GetByHashes(ctx, hashes)
+	Require(t, err)
+	if !bytes.Equal(results[0], data1) || !bytes.Equal(results[1], data2) || results[2] != nil {
+		Fail(t, "unexpected results", results)
+	}
+
+	Require(t, server1.Shutdown())
+	Require(t, server2.Shutdown())
+}
+
+func TestSimpleDASReaderAggregatorGetByHashesSlowReader(t *testing.T) {
+	initTest(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// The first endpoint doesn't answer until the aggregator gives up on it
+	slowServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer slowServer.Close()
+	storage := NewMemoryBackedStorageService(ctx)
+	server, port, err := NewRestfulDasServerOnRandomPort(LocalServerAddressForTest, storage)
+	Require(t, err)
+	data := []byte("only on the second endpoint")
+	// #nosec G115
+	Require(t, storage.Put(ctx, data, uint64(time.Now().Add(time.Hour).Unix())))
+
+	config := RestfulClientAggregatorConfig{
+		Urls:                   []string{slowServer.URL, "http://localhost:" + strconv.Itoa(port)},
+		Strategy:               "testing-sequential",
+		StrategyUpdateInterval: time.Second,
+		WaitBeforeTryNext:      100 * time.Millisecond,
+		MaxPerEndpointStats:    10,
+	}
+	agg, err := NewRestfulClientAggregator(ctx, &config)
+	Require(t, err)
+
+	getCtx, getCancel := context.WithTimeout(ctx, 10*time.Second)
+	defer getCancel()
+	results, err := agg.GetByHashes(getCtx, []common.Hash{dastree.Hash(data)})
+	Require(t, err)
+	if !bytes.Equal(results[0], data) {
+		Fail(t, "unexpected results", results)
+	}
+
+	Require(t, server.Shutdown())
+}
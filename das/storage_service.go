@@ -27,6 +27,13 @@ type StorageService interface {
 	HealthCheck(ctx context.Context) error
 }
 
+// iterableStorage is implemented by storage services that can list the batches they hold.
+type iterableStorage interface {
+	StorageService
+	// iterateEntries calls visit with the key of every stored batch and its expiry time, or 0 if it isn't known.
+	iterateEntries(ctx context.Context, visit func(key common.Hash, expiry uint64) error) error
+}
+
 const defaultStorageRetention = time.Hour * 24 * 21 // 6 days longer than the batch poster default
 
 func EncodeStorageServiceKey(key common.Hash) string {
@@ -4,6 +4,7 @@
 package das
 
 import (
+	"bytes"
 	"context"
 	"encoding/binary"
 	"fmt"
@@ -24,6 +25,7 @@ import (
 
 	"github.com/offchainlabs/nitro/arbstate/daprovider"
 	"github.com/offchainlabs/nitro/arbutil"
+	"github.com/offchainlabs/nitro/das/dastree"
 	"github.com/offchainlabs/nitro/solgen/go/bridgegen"
 	"github.com/offchainlabs/nitro/util/arbmath"
 	"github.com/offchainlabs/nitro/util/headerreader"
@@ -38,6 +40,9 @@ var sequencerBatchDataABI abi.Event
 const sequencerBatchDataEvent = "SequencerBatchData"
 const sequencerBatchDeliveredEvent = "SequencerBatchDelivered"
 
+// The most batches sync-to-storage fetches ahead of storing them
+const maxPrefetchedBatches = 16
+
 // TODO: can we use the generated ABI for BatchDataLocation enum?
 type batchDataLocation uint8
 
@@ -189,24 +194,33 @@ func newl1SyncService(config *SyncToStorageConfig, syncTo StorageService, dataSo
 	}, nil
 }
 
-func (s *l1SyncService) processBatchDelivered(ctx context.Context, batchDeliveredLog types.Log) error {
+// deliveredBatch is a batch with DAS data that sync-to-storage has yet to store.
+type deliveredBatch struct {
+	log        types.Log
+	event      *bridgegen.SequencerInboxSequencerBatchDelivered
+	data       []byte
+	storeUntil uint64
+}
+
+// findBatchDelivered returns the batch delivered by the log, or nil if it has nothing to store.
+func (s *l1SyncService) findBatchDelivered(ctx context.Context, batchDeliveredLog types.Log) (*deliveredBatch, error) {
 	deliveredEvent, err := s.inboxContract.ParseSequencerBatchDelivered(batchDeliveredLog)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	log.Info("BatchDelivered", "log", batchDeliveredLog, "event", deliveredEvent)
 	storeUntil := arbmath.SaturatingUAdd(deliveredEvent.TimeBounds.MaxTimestamp, uint64(s.config.RetentionPeriod.Seconds()))
 	// #nosec G115
 	if !s.config.SyncExpiredData && storeUntil < uint64(time.Now().Unix()) {
 		// old batch - no need to store
-		return nil
+		return nil, nil
 	}
 	data, err := FindDASDataFromLog(ctx, s.inboxContract, deliveredEvent, s.inboxAddr, s.l1Reader.Client(), batchDeliveredLog)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if data == nil {
-		return nil
+		return nil, nil
 	}
 
 	header := make([]byte, 40)
@@ -216,31 +230,93 @@ func (s *l1SyncService) processBatchDelivered(ctx context.Context, batchDelivere
 	binary.BigEndian.PutUint64(header[24:32], deliveredEvent.TimeBounds.MaxBlockNumber)
 	binary.BigEndian.PutUint64(header[32:40], deliveredEvent.AfterDelayedMessagesRead.Uint64())
 
-	data = append(header, data...)
-	var payload []byte
-	if payload, err = daprovider.RecoverPayloadFromDasBatch(ctx, deliveredEvent.BatchSequenceNumber.Uint64(), data, s.dataSource, s.keysetFetcher, nil, true); err != nil {
-		log.Error("recover payload failed", "txhash", batchDeliveredLog.TxHash, "data", data)
+	return &deliveredBatch{
+		log:        batchDeliveredLog,
+		event:      deliveredEvent,
+		data:       append(header, data...),
+		storeUntil: storeUntil,
+	}, nil
+}
+
+func (s *l1SyncService) storeBatchDelivered(ctx context.Context, batch *deliveredBatch, dataSource daprovider.DASReader) error {
+	payload, err := daprovider.RecoverPayloadFromDasBatch(ctx, batch.event.BatchSequenceNumber.Uint64(), batch.data, dataSource, s.keysetFetcher, nil, true)
+	if err != nil {
+		log.Error("recover payload failed", "txhash", batch.log.TxHash, "data", batch.data)
 		return err
 	}
 
 	if payload != nil {
-		if err := s.syncTo.Put(ctx, payload, storeUntil); err != nil {
+		if err := s.syncTo.Put(ctx, payload, batch.storeUntil); err != nil {
 			return err
 		}
 	}
 
-	seqNumber := deliveredEvent.BatchSequenceNumber
+	seqNumber := batch.event.BatchSequenceNumber
 	if seqNumber == nil {
 		seqNumber = common.Big0
 	}
 	updatedBatchCount := new(big.Int).Add(seqNumber, common.Big1)
 	if s.lastBatchCount.Cmp(updatedBatchCount) <= 0 {
 		s.lastBatchCount.Set(seqNumber)
-		s.lastBatchAcc = deliveredEvent.AfterAcc
+		s.lastBatchAcc = batch.event.AfterAcc
 	}
 	return nil
 }
 
+// prefetchedDASReader serves the batches prefetched from its DASReader, and gets the others from it one by one.
+type prefetchedDASReader struct {
+	daprovider.DASReader
+	prefetched map[common.Hash][]byte
+}
+
+func (r *prefetchedDASReader) GetByHash(ctx context.Context, hash common.Hash) ([]byte, error) {
+	if data, ok := r.prefetched[hash]; ok {
+		return data, nil
+	}
+	return r.DASReader.GetByHash(ctx, hash)
+}
+
+// prefetchBatches gets the data of the batches from the data source in as few requests as it can, so catching up
+// doesn't cost a round trip per batch. RecoverPayloadFromDasBatch still checks the data against the certificates.
+func (s *l1SyncService) prefetchBatches(ctx context.Context, batches []*deliveredBatch) daprovider.DASReader {
+	multiReader, ok := s.dataSource.(multiGetReader)
+	if !ok || len(batches) < 2 {
+		return s.dataSource
+	}
+	var hashes []common.Hash
+	for _, batch := range batches {
+		cert, err := daprovider.DeserializeDASCertFrom(bytes.NewReader(batch.data[40:]))
+		if err != nil {
+			// Left for RecoverPayloadFromDasBatch to report
+			continue
+		}
+		switch cert.Version {
+		case 0:
+			hashes = append(hashes, dastree.FlatHashToTreeHash(cert.DataHash))
+		case 1:
+			hashes = append(hashes, cert.DataHash)
+		}
+	}
+	if len(hashes) == 0 {
+		return s.dataSource
+	}
+	fetched, err := multiReader.GetByHashes(ctx, hashes)
+	if err != nil {
+		log.Warn("sync-to-storage couldn't prefetch batches, getting them one by one", "count", len(hashes), "err", err)
+		return s.dataSource
+	}
+	reader := &prefetchedDASReader{
+		DASReader:  s.dataSource,
+		prefetched: make(map[common.Hash][]byte, len(hashes)),
+	}
+	for i, data := range fetched {
+		if data != nil {
+			reader.prefetched[hashes[i]] = data
+		}
+	}
+	return reader
+}
+
 func FindDASDataFromLog(
 	ctx context.Context,
 	inboxContract *bridgegen.SequencerInbox,
@@ -306,10 +382,25 @@ func (s *l1SyncService) processBlockRange(ctx context.Context, lowerBound, highe
 	if err != nil {
 		return err
 	}
+	var batches []*deliveredBatch
 	for _, deliveredLog := range logs {
-		if err := s.processBatchDelivered(ctx, deliveredLog); err != nil {
+		batch, err := s.findBatchDelivered(ctx, deliveredLog)
+		if err != nil {
 			return err
 		}
+		if batch != nil {
+			batches = append(batches, batch)
+		}
+	}
+	// Prefetching is chunked to bound the memory held by batches waiting to be stored
+	for start := 0; start < len(batches); start += maxPrefetchedBatches {
+		chunk := batches[start:min(start+maxPrefetchedBatches, len(batches))]
+		dataSource := s.prefetchBatches(ctx, chunk)
+		for _, batch := range chunk {
+			if err := s.storeBatchDelivered(ctx, batch, dataSource); err != nil {
+				return err
+			}
+		}
 	}
 	return nil
 }
@@ -0,0 +1,91 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+package das
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/offchainlabs/nitro/arbstate/daprovider"
+	"github.com/offchainlabs/nitro/blsSignatures"
+	"github.com/offchainlabs/nitro/das/dastree"
+)
+
+type countingMultiGetReader struct {
+	*MemoryBackedStorageService
+	singleGets int
+	multiGets  int
+}
+
+func (r *countingMultiGetReader) GetByHash(ctx context.Context, hash common.Hash) ([]byte, error) {
+	r.singleGets++
+	return r.MemoryBackedStorageService.GetByHash(ctx, hash)
+}
+
+func (r *countingMultiGetReader) GetByHashes(ctx context.Context, hashes []common.Hash) ([][]byte, error) {
+	r.multiGets++
+	results := make([][]byte, len(hashes))
+	for i, hash := range hashes {
+		results[i], _ = r.MemoryBackedStorageService.GetByHash(ctx, hash)
+	}
+	return results, nil
+}
+
+func TestSyncPrefetchBatches(t *testing.T) {
+	initTest(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	_, privKey, err := blsSignatures.GenerateKeys()
+	Require(t, err)
+	sig, err := blsSignatures.SignMessage(privKey, []byte("batch"))
+	Require(t, err)
+
+	reader := &countingMultiGetReader{MemoryBackedStorageService: NewMemoryBackedStorageService(ctx)}
+	// #nosec G115
+	timeout := uint64(time.Now().Add(time.Hour).Unix())
+	var batches []*deliveredBatch
+	var values [][]byte
+	for _, value := range [][]byte{[]byte("first batch"), []byte("second batch"), []byte("missing batch")} {
+		if !bytes.Equal(value, []byte("missing batch")) {
+			Require(t, reader.Put(ctx, value, timeout))
+		}
+		cert := &daprovider.DataAvailabilityCertificate{
+			DataHash: dastree.Hash(value),
+			Timeout:  timeout,
+			Sig:      sig,
+			Version:  1,
+		}
+		batches = append(batches, &deliveredBatch{data: append(make([]byte, 40), daprovider.Serialize(cert)...)})
+		values = append(values, value)
+	}
+
+	s := &l1SyncService{dataSource: reader}
+	dataSource := s.prefetchBatches(ctx, batches)
+	if reader.multiGets != 1 {
+		Fail(t, "expected the batches to be fetched in one request, got", reader.multiGets)
+	}
+	for _, value := range values[:2] {
+		data, err := dataSource.GetByHash(ctx, dastree.Hash(value))
+		Require(t, err)
+		if !bytes.Equal(data, value) {
+			Fail(t, "unexpected prefetched data", data, value)
+		}
+	}
+	if reader.singleGets != 0 {
+		Fail(t, "prefetched batches were fetched again")
+	}
+	// Batches the data source didn't have are asked for again
+	if _, err := dataSource.GetByHash(ctx, dastree.Hash(values[2])); err == nil {
+		Fail(t, "got a batch the data source doesn't have")
+	}
+	if reader.singleGets != 1 {
+		Fail(t, "missing batch wasn't asked for again")
+	}
+}
@@ -0,0 +1,193 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+package das
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/knadh/koanf"
+	"github.com/knadh/koanf/providers/confmap"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+// WriterKeyConfig names a key, other than the batch poster's, that may store batches through the RPC server,
+// and limits how much it may store. Zero limits are unlimited.
+type WriterKeyConfig struct {
+	Name               string `koanf:"name" json:"name"`
+	Address            string `koanf:"address" json:"address"`
+	MaxBytesPerHour    uint64 `koanf:"max-bytes-per-hour" json:"max-bytes-per-hour"`
+	MaxRequestsPerHour uint64 `koanf:"max-requests-per-hour" json:"max-requests-per-hour"`
+	// MaxRetention is a duration such as "720h", the furthest in the future the key may ask batches to be kept until.
+	MaxRetention string `koanf:"max-retention" json:"max-retention"`
+}
+
+type WriterKeyConfigList []WriterKeyConfig
+
+func (l *WriterKeyConfigList) String() string {
+	b, _ := json.Marshal(*l)
+	return string(b)
+}
+
+func (l *WriterKeyConfigList) Set(value string) error {
+	return l.UnmarshalJSON([]byte(value))
+}
+
+func (l *WriterKeyConfigList) UnmarshalJSON(data []byte) error {
+	var tmp []WriterKeyConfig
+	if err := json.Unmarshal(data, &tmp); err != nil {
+		return err
+	}
+	*l = tmp
+	return nil
+}
+
+func (l *WriterKeyConfigList) Type() string {
+	return "writerKeyConfigList"
+}
+
+var parsedWriterKeysConf WriterKeyConfigList
+
+func FixWriterKeysCLIParsing(path string, k *koanf.Koanf) error {
+	rawWriterKeys := k.Get(path)
+	if wk, ok := rawWriterKeys.(string); ok {
+		err := parsedWriterKeysConf.UnmarshalJSON([]byte(wk))
+		if err != nil {
+			return err
+		}
+
+		tempMap := map[string]interface{}{
+			path: parsedWriterKeysConf,
+		}
+		if err = k.Load(confmap.Provider(tempMap, "."), nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var writerKeyNameRegex = regexp.MustCompile("^[a-z0-9_-]+$")
+
+// writerKey enforces the quotas of a writer key, with token buckets refilled over an hour.
+type writerKey struct {
+	name         string
+	maxBytes     float64
+	maxRequests  float64
+	maxRetention time.Duration
+
+	mutex         sync.Mutex
+	bytesLeft     float64
+	requestsLeft  float64
+	lastRefilled  time.Time
+	requestsCount *metrics.Counter
+	bytesCount    *metrics.Counter
+	rejectedCount *metrics.Counter
+}
+
+func newWriterKeys(configs WriterKeyConfigList) (map[common.Address]*writerKey, error) {
+	keys := make(map[common.Address]*writerKey, len(configs))
+	names := make(map[string]bool, len(configs))
+	for _, config := range configs {
+		if !writerKeyNameRegex.MatchString(config.Name) {
+			return nil, fmt.Errorf("writer key name %q must only have lowercase letters, digits, - and _", config.Name)
+		}
+		if names[config.Name] {
+			return nil, fmt.Errorf("duplicate writer key name %q", config.Name)
+		}
+		names[config.Name] = true
+		if !common.IsHexAddress(config.Address) {
+			return nil, fmt.Errorf("writer key %v has an invalid address %q", config.Name, config.Address)
+		}
+		address := common.HexToAddress(config.Address)
+		if _, exists := keys[address]; exists {
+			return nil, fmt.Errorf("writer key %v has the same address as another writer key", config.Name)
+		}
+		var maxRetention time.Duration
+		if config.MaxRetention != "" {
+			var err error
+			maxRetention, err = time.ParseDuration(config.MaxRetention)
+			if err != nil {
+				return nil, fmt.Errorf("writer key %v has an invalid max-retention: %w", config.Name, err)
+			}
+		}
+		metricsPrefix := "arb/das/rpc/writer/" + config.Name
+		keys[address] = &writerKey{
+			name:          config.Name,
+			maxBytes:      float64(config.MaxBytesPerHour),
+			maxRequests:   float64(config.MaxRequestsPerHour),
+			maxRetention:  maxRetention,
+			bytesLeft:     float64(config.MaxBytesPerHour),
+			requestsLeft:  float64(config.MaxRequestsPerHour),
+			lastRefilled:  time.Now(),
+			requestsCount: metrics.NewRegisteredCounter(metricsPrefix+"/requests", nil),
+			bytesCount:    metrics.NewRegisteredCounter(metricsPrefix+"/bytes", nil),
+			rejectedCount: metrics.NewRegisteredCounter(metricsPrefix+"/rejected", nil),
+		}
+	}
+	return keys, nil
+}
+
+// admit checks a store request of size bytes, kept until timeout, is within the key's quotas, and charges it if so.
+// Charging up front reserves the quota for concurrent requests; a store that fails or is abandoned must be refunded.
+func (w *writerKey) admit(size uint64, timeout uint64) error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.requestsCount.Inc(1)
+
+	now := time.Now()
+	// #nosec G115
+	if w.maxRetention != 0 && time.Unix(int64(timeout), 0).After(now.Add(w.maxRetention)) {
+		return w.reject("retention", fmt.Errorf("writer %v may only store batches for %v", w.name, w.maxRetention))
+	}
+
+	hoursElapsed := now.Sub(w.lastRefilled).Hours()
+	w.lastRefilled = now
+	w.bytesLeft = min(w.maxBytes, w.bytesLeft+hoursElapsed*w.maxBytes)
+	w.requestsLeft = min(w.maxRequests, w.requestsLeft+hoursElapsed*w.maxRequests)
+	if w.maxRequests != 0 && w.requestsLeft < 1 {
+		return w.reject("requests", fmt.Errorf("writer %v exceeded its quota of %v requests per hour", w.name, w.maxRequests))
+	}
+	if w.maxBytes != 0 && w.bytesLeft < float64(size) {
+		return w.reject("bytes", fmt.Errorf("writer %v exceeded its quota of %v bytes per hour", w.name, w.maxBytes))
+	}
+	w.requestsLeft--
+	w.bytesLeft -= float64(size)
+	return nil
+}
+
+// refund gives back what admit charged for a store of size bytes that didn't succeed.
+func (w *writerKey) refund(size uint64) {
+	if w == nil {
+		return
+	}
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	w.requestsLeft = min(w.maxRequests, w.requestsLeft+1)
+	w.bytesLeft = min(w.maxBytes, w.bytesLeft+float64(size))
+}
+
+func (w *writerKey) audit(dataHash []byte, size int, timeout uint64) {
+	w.bytesCount.Inc(int64(size))
+	// #nosec G115
+	log.Info("DAS writer audit: stored batch", "writer", w.name, "dataHash", common.BytesToHash(dataHash), "bytes", size, "timeout", time.Unix(int64(timeout), 0))
+}
+
+func (w *writerKey) reject(quota string, err error) error {
+	w.rejectedCount.Inc(1)
+	log.Warn("DAS writer audit: store rejected", "writer", w.name, "quota", quota, "err", err)
+	return err
+}
+
+func (w *writerKey) String() string {
+	if w == nil {
+		return "batch-poster"
+	}
+	return w.name
+}
@@ -0,0 +1,155 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+package das
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/offchainlabs/nitro/arbstate/daprovider"
+	"github.com/offchainlabs/nitro/util/signature"
+)
+
+func TestWriterKeyQuotas(t *testing.T) {
+	writers, err := newWriterKeys(WriterKeyConfigList{{
+		Name:               "test-quotas",
+		Address:            "0x0000000000000000000000000000000000000001",
+		MaxBytesPerHour:    100,
+		MaxRequestsPerHour: 2,
+		MaxRetention:       "1h",
+	}})
+	Require(t, err)
+	var writer *writerKey
+	for _, w := range writers {
+		writer = w
+	}
+
+	// #nosec G115
+	now := uint64(time.Now().Unix())
+	Require(t, writer.admit(10, now+60))
+	if writer.admit(200, now+60) == nil {
+		Fail(t, "admitted a store over the byte quota")
+	}
+	if writer.admit(10, now+7200) == nil {
+		Fail(t, "admitted a store over the retention ceiling")
+	}
+	Require(t, writer.admit(10, now+60))
+	if writer.admit(10, now+60) == nil {
+		Fail(t, "admitted a store over the request quota")
+	}
+	if writer.rejectedCount.Snapshot().Count() != 3 {
+		Fail(t, "unexpected rejected count", writer.rejectedCount.Snapshot().Count())
+	}
+}
+
+type failingStoreWriter struct{}
+
+func (failingStoreWriter) Store(context.Context, []byte, uint64) (*daprovider.DataAvailabilityCertificate, error) {
+	return nil, errors.New("store failed")
+}
+
+func (failingStoreWriter) String() string {
+	return "failingStoreWriter"
+}
+
+func TestWriterKeyRefunds(t *testing.T) {
+	ctx := context.Background()
+	writerPrivateKey, err := crypto.GenerateKey()
+	Require(t, err)
+	verifier, err := NewSignatureVerifierWithSeqInboxCaller(nil, "")
+	Require(t, err)
+	Require(t, verifier.setWriterKeys(WriterKeyConfigList{{
+		Name:               "test-refunds",
+		Address:            crypto.PubkeyToAddress(writerPrivateKey.PublicKey).Hex(),
+		MaxBytesPerHour:    100,
+		MaxRequestsPerHour: 1,
+	}}))
+	server := &DASRPCServer{
+		daWriter:          failingStoreWriter{},
+		signatureVerifier: verifier,
+		batches:           newBatchBuilder(),
+	}
+	signer := signature.DataSignerFromPrivateKey(writerPrivateKey)
+	// #nosec G115
+	now := uint64(time.Now().Unix())
+
+	// Failed stores don't use up the quota
+	message := make([]byte, 80)
+	for i := 0; i < 3; i++ {
+		sig, err := applyDasSigner(signer, message, now+60)
+		Require(t, err)
+		if _, err := server.Store(ctx, message, hexutil.Uint64(now+60), sig); err == nil || err.Error() != "store failed" {
+			Fail(t, "unexpected store result", err)
+		}
+	}
+
+	// Neither do chunked stores that fail to commit
+	for i := 0; i < 3; i++ {
+		sig, err := applyDasSigner(signer, []byte{}, now, 1, 80, 80, now+60)
+		Require(t, err)
+		started, err := server.StartChunkedStore(ctx, hexutil.Uint64(now), 1, 80, 80, hexutil.Uint64(now+60), sig)
+		Require(t, err)
+		if i > 0 {
+			sig, err = applyDasSigner(signer, message, uint64(started.BatchId), 0)
+			Require(t, err)
+			Require(t, server.SendChunk(ctx, started.BatchId, 0, message, sig))
+		}
+		sig, err = applyDasSigner(signer, []byte{}, uint64(started.BatchId))
+		Require(t, err)
+		// The first batch is incomplete, and the others fail to store
+		if _, err := server.CommitChunkedStore(ctx, started.BatchId, sig); err == nil {
+			Fail(t, "committed a chunked store that should have failed")
+		}
+	}
+}
+
+func TestWriterKeyAuthentication(t *testing.T) {
+	ctx := context.Background()
+	writerPrivateKey, err := crypto.GenerateKey()
+	Require(t, err)
+	otherPrivateKey, err := crypto.GenerateKey()
+	Require(t, err)
+
+	verifier, err := NewSignatureVerifierWithSeqInboxCaller(nil, "")
+	Require(t, err)
+	Require(t, verifier.setWriterKeys(WriterKeyConfigList{{
+		Name:    "test-authentication",
+		Address: crypto.PubkeyToAddress(writerPrivateKey.PublicKey).Hex(),
+	}}))
+
+	message := []byte("message")
+	sig, err := applyDasSigner(signature.DataSignerFromPrivateKey(writerPrivateKey), message, 100)
+	Require(t, err)
+	writer, err := verifier.authenticate(ctx, message, sig, 100)
+	Require(t, err)
+	if writer == nil || writer.name != "test-authentication" {
+		Fail(t, "authenticated as the wrong writer", writer)
+	}
+
+	sig, err = applyDasSigner(signature.DataSignerFromPrivateKey(otherPrivateKey), message, 100)
+	Require(t, err)
+	if _, err := verifier.authenticate(ctx, message, sig, 100); err == nil {
+		Fail(t, "authenticated an unknown key")
+	}
+}
+
+func TestWriterKeyConfigValidation(t *testing.T) {
+	address := "0x0000000000000000000000000000000000000002"
+	for _, configs := range []WriterKeyConfigList{
+		{{Name: "Bad Name", Address: address}},
+		{{Name: "bad-address", Address: "0x1234"}},
+		{{Name: "bad-retention", Address: address, MaxRetention: "a month"}},
+		{{Name: "first", Address: address}, {Name: "second", Address: address}},
+		{{Name: "same", Address: address}, {Name: "same", Address: "0x0000000000000000000000000000000000000003"}},
+	} {
+		if _, err := newWriterKeys(configs); err == nil {
+			Fail(t, "accepted invalid writer keys", configs)
+		}
+	}
+}
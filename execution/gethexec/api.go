@@ -48,6 +48,18 @@ func (a *ArbAPI) CheckPublisherHealth(ctx context.Context) error {
 	return a.txPublisher.CheckHealth(ctx)
 }
 
+// SendBundle submits transactions to be sequenced back to back in a single block, or not at all.
+func (a *ArbAPI) SendBundle(ctx context.Context, args BundleArgs) ([]common.Hash, error) {
+	bundle, err := args.ToBundle()
+	if err != nil {
+		return nil, err
+	}
+	if err := a.txPublisher.PublishBundle(ctx, bundle); err != nil {
+		return nil, err
+	}
+	return bundle.Hashes(), nil
+}
+
 func (a *ArbAPI) GetRawBlockMetadata(ctx context.Context, fromBlock, toBlock rpc.BlockNumber) ([]NumberAndBlockMetadata, error) {
 	if a.bulkBlockMetadataFetcher == nil {
 		return nil, errors.New("arb_getRawBlockMetadata is not available")
@@ -83,6 +95,16 @@ func (a *ArbTimeboostAPI) SendExpressLaneTransaction(ctx context.Context, msg *t
 	return a.txPublisher.PublishExpressLaneTransaction(ctx, goMsg)
 }
 
+// GetSubmissionStatus reports whether the express lane submission is buffered, sequenced, failed or dropped, asking
+// the sequencer if this node isn't it. The sequence namespace defaults to 0, the controller's.
+func (a *ArbTimeboostAPI) GetSubmissionStatus(ctx context.Context, round, sequenceNumber hexutil.Uint64, namespace *hexutil.Uint64) (*ExpressLaneSubmissionStatus, error) {
+	var ns uint64
+	if namespace != nil {
+		ns = uint64(*namespace)
+	}
+	return a.txPublisher.ExpressLaneSubmissionStatus(ctx, uint64(round), ns, uint64(sequenceNumber))
+}
+
 type ArbDebugAPI struct {
 	blockchain        *core.BlockChain
 	blockRangeBound   uint64
@@ -17,6 +17,8 @@ type TransactionPublisher interface {
 	PublishAuctionResolutionTransaction(ctx context.Context, tx *types.Transaction) error
 	PublishExpressLaneTransaction(ctx context.Context, msg *timeboost.ExpressLaneSubmission) error
 	PublishTransaction(ctx context.Context, tx *types.Transaction, options *arbitrum_types.ConditionalOptions) error
+	PublishBundle(ctx context.Context, bundle *TxBundle) error
+	ExpressLaneSubmissionStatus(ctx context.Context, round, namespace, sequenceNumber uint64) (*ExpressLaneSubmissionStatus, error)
 	CheckHealth(ctx context.Context) error
 	Initialize(context.Context) error
 	Start(context.Context) error
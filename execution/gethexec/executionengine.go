@@ -625,6 +625,30 @@ func (s *ExecutionEngine) sequenceTransactionsWithBlockMutex(header *arbostypes.
 	return block, nil
 }
 
+// SimulateNextBlockTxs executes txes in the block that would follow the current head, without committing the block
+// or its state. It runs in replay mode, so it doesn't change the Stylus program cache.
+func (s *ExecutionEngine) SimulateNextBlockTxs(header *arbostypes.L1IncomingMessageHeader, txes types.Transactions, hooks *arbos.SequencingHooks) (*types.Block, types.Receipts, error) {
+	lastBlockHeader, err := s.getCurrentHeader()
+	if err != nil {
+		return nil, nil, err
+	}
+	statedb, err := s.bc.StateAt(lastBlockHeader.Root)
+	if err != nil {
+		return nil, nil, err
+	}
+	return arbos.ProduceBlockAdvanced(
+		header,
+		txes,
+		lastBlockHeader.Nonce.Uint64(),
+		lastBlockHeader,
+		statedb,
+		s.bc,
+		hooks,
+		true,
+		core.MessageReplayMode,
+	)
+}
+
 // blockMetadataFromBlock returns timeboosted byte array which says whether a transaction in the block was timeboosted
 // or not. The first byte of blockMetadata byte array is reserved to indicate the version,
 // starting from the second byte, (N)th bit would represent if (N)th tx is timeboosted or not, 1 means yes and 0 means no
@@ -0,0 +1,73 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+package gethexec
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/offchainlabs/nitro/timeboost"
+)
+
+// RegisterExpressLaneDelegation lets the delegate of a delegation signed by the express lane controller of the
+// current or next round send express lane submissions for the round while the delegation is active.
+func (s *Sequencer) RegisterExpressLaneDelegation(ctx context.Context, delegation *timeboost.ExpressLaneDelegation) error {
+	if !s.config().Dangerous.Timeboost.Enable {
+		return errors.New("timeboost not enabled")
+	}
+
+	forwarder, err := s.getForwarder(ctx)
+	if err != nil {
+		return err
+	}
+	if forwarder != nil {
+		return forwarder.RegisterExpressLaneDelegation(ctx, delegation)
+	}
+
+	if s.expressLaneService == nil {
+		return errors.New("express lane service not enabled")
+	}
+	return s.expressLaneService.registerDelegation(delegation)
+}
+
+func (es *expressLaneService) registerDelegation(delegation *timeboost.ExpressLaneDelegation) error {
+	if err := es.tracker.RegisterDelegation(delegation); err != nil {
+		return err
+	}
+	if es.redisCoordinator != nil {
+		// Persist the delegation to redis so that the delegate's submissions are accepted after a sequencer switch
+		if err := es.redisCoordinator.AddDelegation(delegation); err != nil {
+			log.Error("Error adding ExpressLaneDelegation to redis. Delegate's submissions will be rejected if sequencer switch happens", "round", delegation.Round, "delegate", delegation.Delegate, "err", err)
+		}
+	}
+	return nil
+}
+
+func (f *TxForwarder) RegisterExpressLaneDelegation(inctx context.Context, delegation *timeboost.ExpressLaneDelegation) error {
+	if !f.enabled.Load() {
+		return ErrNoSequencer
+	}
+	ctx, cancelFunc := f.ctxWithTimeout()
+	defer cancelFunc()
+	for pos, rpcClient := range f.rpcClients {
+		err := rpcClient.CallContext(ctx, nil, "timeboost_registerExpressLaneDelegation", delegation.ToJson())
+		if err != nil {
+			log.Warn("error forwarding express lane delegation to a backup target", "target", f.targets[pos], "err", err)
+		}
+		if err == nil || !f.tryNewForwarderErrors.MatchString(err.Error()) {
+			return err
+		}
+	}
+	return errors.New("failed to register express lane delegation with any of the forwarding targets")
+}
+
+// RegisterExpressLaneDelegation registers a delegation signed by the express lane controller of a round.
+func (a *ArbTimeboostSequencerAPI) RegisterExpressLaneDelegation(ctx context.Context, delegation *timeboost.JsonExpressLaneDelegation) error {
+	if delegation == nil || delegation.ChainId == nil {
+		return timeboost.ErrMalformedData
+	}
+	return a.sequencer.RegisterExpressLaneDelegation(ctx, timeboost.JsonDelegationToGo(delegation))
+}
@@ -41,6 +41,12 @@ type expressLaneRoundInfo struct {
 	msgBySequenceNumber map[uint64]*timeboost.ExpressLaneSubmission
 }
 
+// roundNamespace identifies the sequence of a delegate with a sequence namespace of its own in a round.
+type roundNamespace struct {
+	round     uint64
+	namespace uint64
+}
+
 type expressLaneService struct {
 	stopwaiter.StopWaiter
 	transactionPublisher transactionPublisher
@@ -48,10 +54,13 @@ type expressLaneService struct {
 	roundTimingInfo      timeboost.RoundTimingInfo
 	redisCoordinator     *timeboost.RedisCoordinator
 
-	roundInfoMutex sync.Mutex
-	roundInfo      *containers.LruCache[uint64, *expressLaneRoundInfo]
+	roundInfoMutex     sync.Mutex
+	roundInfo          *containers.LruCache[uint64, *expressLaneRoundInfo]         // sequences of controllers
+	namespaceRoundInfo *containers.LruCache[roundNamespace, *expressLaneRoundInfo] // sequences of delegates with a namespace, created lazily with room for every namespace of the rounds in roundInfo
 
 	tracker *ExpressLaneTracker
+
+	submissionStatuses *submissionStatusTracker
 }
 
 func NewExpressLaneAuctionFromInternalAPI(
@@ -113,6 +122,7 @@ func newExpressLaneService(
 		redisCoordinator:     redisCoordinator,
 		roundInfo:            containers.NewLruCache[uint64, *expressLaneRoundInfo](8),
 		tracker:              expressLaneTracker,
+		submissionStatuses:   newSubmissionStatusTracker(),
 	}, nil
 }
 
@@ -122,6 +132,20 @@ func (es *expressLaneService) Start(ctxIn context.Context) {
 	if es.redisCoordinator != nil {
 		es.redisCoordinator.Start(ctxIn)
 	}
+
+	if es.submissionStatuses != nil {
+		es.LaunchThread(func(ctx context.Context) {
+			for {
+				round := es.roundTimingInfo.RoundNumber()
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(es.roundTimingInfo.TimeTilNextRound()):
+				}
+				es.dropUnsequencedSubmissions(round)
+			}
+		})
+	}
 }
 
 func (es *expressLaneService) StopAndWait() {
@@ -141,26 +165,18 @@ func (es *expressLaneService) sequenceExpressLaneSubmission(msg *timeboost.Expre
 	defer es.roundInfoMutex.Unlock()
 
 	// Below code block isn't a repetition, it prevents stale messages to be accepted during control transfer within or after the round ends!
-	controller, err := es.tracker.RoundController(msg.Round)
+	sender, err := msg.Sender() // Doesn't recompute sender address
 	if err != nil {
 		return err
 	}
-	sender, err := msg.Sender() // Doesn't recompute sender address
+	namespace, err := es.tracker.SequenceNamespace(msg.Round, sender)
 	if err != nil {
 		return err
 	}
-	if sender != controller {
-		return timeboost.ErrNotExpressLaneController
-	}
 
 	// If expressLaneRoundInfo for current round doesn't exist yet, we'll add it to the cache
-	if !es.roundInfo.Contains(msg.Round) {
-		es.roundInfo.Add(msg.Round, &expressLaneRoundInfo{
-			0,
-			make(map[uint64]*timeboost.ExpressLaneSubmission),
-		})
-	}
-	roundInfo, _ := es.roundInfo.Get(msg.Round)
+	roundInfo := es.getRoundInfo(msg.Round, namespace)
+	trackStatus := es.submissionStatuses != nil
 
 	prev, exists := roundInfo.msgBySequenceNumber[msg.SequenceNumber]
 
@@ -192,10 +208,13 @@ func (es *expressLaneService) sequenceExpressLaneSubmission(msg *timeboost.Expre
 
 	// Put into the sequence number map.
 	roundInfo.msgBySequenceNumber[msg.SequenceNumber] = msg
+	if trackStatus && msg.SequenceNumber > roundInfo.sequence {
+		es.submissionStatuses.set(msg.Round, namespace, msg.SequenceNumber, msg.Transaction.Hash(), SubmissionBuffered, "", roundInfo.sequence)
+	}
 
 	if es.redisCoordinator != nil {
 		// Persist accepted expressLane txs to redis
-		if err := es.redisCoordinator.AddAcceptedTx(msg); err != nil {
+		if err := es.redisCoordinator.AddNamespaceAcceptedTx(msg, namespace); err != nil {
 			log.Error("Error adding accepted ExpressLaneSubmission to redis. Loss of msg possible if sequencer switch happens", "seqNum", msg.SequenceNumber, "txHash", msg.Transaction.Hash(), "err", err)
 		}
 	}
@@ -212,20 +231,25 @@ func (es *expressLaneService) sequenceExpressLaneSubmission(msg *timeboost.Expre
 		// use es.GetContext(). Txs sequenced this round shouldn't be processed by sequencer into next round, to enforce this, queueCtx has a timeout = min(TimeTilNextRound, queueTimeout)
 		timeout := min(es.roundTimingInfo.TimeTilNextRound(), queueTimeout)
 		queueCtx, _ := ctxWithTimeout(es.GetContext(), timeout)
+		status, reason := SubmissionSequenced, ""
 		if err := es.transactionPublisher.PublishTimeboostedTransaction(queueCtx, nextMsg.Transaction, nextMsg.Options); err != nil {
 			log.Error("Error queuing expressLane transaction", "seqNum", nextMsg.SequenceNumber, "txHash", nextMsg.Transaction.Hash(), "err", err)
 			if nextMsg.SequenceNumber == msg.SequenceNumber {
 				retErr = err
 			}
+			status, reason = SubmissionFailed, err.Error()
 		}
 		// Increase the global round sequence number.
 		roundInfo.sequence += 1
+		if trackStatus {
+			es.submissionStatuses.set(msg.Round, namespace, nextMsg.SequenceNumber, nextMsg.Transaction.Hash(), status, reason, roundInfo.sequence)
+		}
 	}
-	es.roundInfo.Add(msg.Round, roundInfo)
+	es.addRoundInfo(msg.Round, namespace, roundInfo)
 
 	if es.redisCoordinator != nil {
 		// We update the sequence count in redis after we were able to queue the txs up until roundInfo.sequence
-		if redisErr := es.redisCoordinator.UpdateSequenceCount(msg.Round, roundInfo.sequence); redisErr != nil {
+		if redisErr := es.redisCoordinator.UpdateNamespaceSequenceCount(msg.Round, namespace, roundInfo.sequence); redisErr != nil {
 			log.Error("Error updating round's sequence count in redis", "err", redisErr) // this shouldn't be a problem if future msgs succeed in updating the count
 		}
 	}
@@ -233,35 +257,85 @@ func (es *expressLaneService) sequenceExpressLaneSubmission(msg *timeboost.Expre
 	return retErr
 }
 
+// peekRoundInfo returns the expressLaneRoundInfo of a sequence namespace of the round if it's cached. It should be
+// called with the roundInfo lock held.
+func (es *expressLaneService) peekRoundInfo(round, namespace uint64) (*expressLaneRoundInfo, bool) {
+	if namespace == 0 {
+		return es.roundInfo.Get(round)
+	}
+	if es.namespaceRoundInfo == nil {
+		return nil, false
+	}
+	return es.namespaceRoundInfo.Get(roundNamespace{round, namespace})
+}
+
+// getRoundInfo returns the expressLaneRoundInfo of a sequence namespace of the round, adding it to the cache if it
+// doesn't exist yet. It should be called with the roundInfo lock held.
+func (es *expressLaneService) getRoundInfo(round, namespace uint64) *expressLaneRoundInfo {
+	roundInfo, exists := es.peekRoundInfo(round, namespace)
+	if !exists {
+		roundInfo = &expressLaneRoundInfo{0, make(map[uint64]*timeboost.ExpressLaneSubmission)}
+		es.addRoundInfo(round, namespace, roundInfo)
+	}
+	return roundInfo
+}
+
+// addRoundInfo should be called with the roundInfo lock held.
+func (es *expressLaneService) addRoundInfo(round, namespace uint64, roundInfo *expressLaneRoundInfo) {
+	if namespace == 0 {
+		es.roundInfo.Add(round, roundInfo)
+		return
+	}
+	if es.namespaceRoundInfo == nil {
+		// The tracker caps the namespaces of a round, so a namespace's sequence isn't evicted while its round is cached
+		es.namespaceRoundInfo = containers.NewLruCache[roundNamespace, *expressLaneRoundInfo](es.roundInfo.Size() * maxSequenceNamespacesPerRound)
+	}
+	es.namespaceRoundInfo.Add(roundNamespace{round, namespace}, roundInfo)
+}
+
 func (es *expressLaneService) syncFromRedis() {
 	if es.redisCoordinator == nil {
 		return
 	}
 
 	currentRound := es.roundTimingInfo.RoundNumber()
-	redisSeqCount, err := es.redisCoordinator.GetSequenceCount(currentRound)
+	// Delegations have to be known before the pending txs of delegates can be sequenced
+	for _, round := range []uint64{currentRound, currentRound + 1} {
+		delegations, err := es.redisCoordinator.GetDelegations(round)
+		if err != nil {
+			log.Error("error fetching express lane delegations from redis", "round", round, "err", err)
+		}
+		for _, delegation := range delegations {
+			if err := es.tracker.RegisterDelegation(delegation); err != nil {
+				log.Warn("Express lane delegation from redis is no longer valid", "round", round, "delegate", delegation.Delegate, "err", err)
+			}
+		}
+	}
+
+	for _, namespace := range append([]uint64{0}, es.tracker.SequenceNamespaces(currentRound)...) {
+		es.syncNamespaceFromRedis(currentRound, namespace)
+	}
+}
+
+func (es *expressLaneService) syncNamespaceFromRedis(currentRound, namespace uint64) {
+	redisSeqCount, err := es.redisCoordinator.GetNamespaceSequenceCount(currentRound, namespace)
 	if err != nil {
-		log.Error("error fetching current round's global sequence count from redis", "err", err)
+		log.Error("error fetching current round's global sequence count from redis", "namespace", namespace, "err", err)
 	}
 
 	es.roundInfoMutex.Lock()
-	roundInfo, exists := es.roundInfo.Get(currentRound)
-	if !exists {
-		// If expressLaneRoundInfo for current round doesn't exist yet, we'll add it to the cache
-		roundInfo = &expressLaneRoundInfo{0, make(map[uint64]*timeboost.ExpressLaneSubmission)}
-	}
+	roundInfo := es.getRoundInfo(currentRound, namespace)
 	if redisSeqCount > roundInfo.sequence {
 		roundInfo.sequence = redisSeqCount
 	}
-	es.roundInfo.Add(currentRound, roundInfo)
 	sequenceCount := roundInfo.sequence
 	es.roundInfoMutex.Unlock()
 
-	pendingMsgs := es.redisCoordinator.GetAcceptedTxs(currentRound, sequenceCount, sequenceCount+es.seqConfig().Dangerous.Timeboost.MaxFutureSequenceDistance)
-	log.Info("Attempting to sequence pending expressLane transactions from redis", "count", len(pendingMsgs))
+	pendingMsgs := es.redisCoordinator.GetNamespaceAcceptedTxs(currentRound, namespace, sequenceCount, sequenceCount+es.seqConfig().Dangerous.Timeboost.MaxFutureSequenceDistance)
+	log.Info("Attempting to sequence pending expressLane transactions from redis", "namespace", namespace, "count", len(pendingMsgs))
 	for _, msg := range pendingMsgs {
 		if err := es.sequenceExpressLaneSubmission(msg); err != nil {
-			log.Error("Untracked expressLaneSubmission returned an error while sequencing", "round", msg.Round, "seqNum", msg.SequenceNumber, "txHash", msg.Transaction.Hash(), "err", err)
+			log.Error("Untracked expressLaneSubmission returned an error while sequencing", "round", msg.Round, "namespace", namespace, "seqNum", msg.SequenceNumber, "txHash", msg.Transaction.Hash(), "err", err)
 		}
 	}
 }
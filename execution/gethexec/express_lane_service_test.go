@@ -17,6 +17,7 @@ import (
 
 	"github.com/ethereum/go-ethereum/arbitrum_types"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/params"
@@ -452,6 +453,58 @@ func Test_expressLaneService_sequenceExpressLaneSubmission_erroredTx(t *testing.
 	require.Equal(t, 3, len(stubPublisher.publishedTxOrder))
 }
 
+func Test_expressLaneService_submissionStatus(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	timingInfo := defaultTestRoundTimingInfo(time.Now())
+	tr := &ExpressLaneTracker{}
+	tr.roundControl.Store(0, crypto.PubkeyToAddress(testPriv.PublicKey))
+	els := &expressLaneService{
+		roundInfo:          containers.NewLruCache[uint64, *expressLaneRoundInfo](8),
+		roundTimingInfo:    timingInfo,
+		seqConfig:          func() *SequencerConfig { return &DefaultSequencerConfig },
+		tracker:            tr,
+		submissionStatuses: newSubmissionStatusTracker(),
+	}
+	els.roundInfo.Add(0, &expressLaneRoundInfo{1, make(map[uint64]*timeboost.ExpressLaneSubmission)})
+	els.StopWaiter.Start(ctx, els)
+	els.transactionPublisher = makeStubPublisher(els)
+	updates, unsubscribe := els.submissionStatuses.subscribe()
+	defer unsubscribe()
+
+	badTx := types.NewTransaction(0, common.MaxAddress, big.NewInt(0), 0, big.NewInt(0), []byte{1})
+	require.NoError(t, els.sequenceExpressLaneSubmission(buildValidSubmissionWithSeqAndTx(t, 0, 3, emptyTx)))
+	require.ErrorContains(t, els.sequenceExpressLaneSubmission(buildValidSubmissionWithSeqAndTx(t, 0, 1, badTx)), "oops, bad tx")
+
+	status, err := els.submissionStatus(0, 0, 1)
+	require.NoError(t, err)
+	require.Equal(t, SubmissionFailed, status.Status)
+	require.Equal(t, "oops, bad tx", status.Reason)
+	require.Equal(t, badTx.Hash(), *status.TxHash)
+	require.Equal(t, hexutil.Uint64(2), status.NextSequenceNumber)
+
+	status, err = els.submissionStatus(0, 0, 3)
+	require.NoError(t, err)
+	require.Equal(t, SubmissionBuffered, status.Status)
+
+	status, err = els.submissionStatus(0, 0, 7)
+	require.NoError(t, err)
+	require.Equal(t, SubmissionUnknown, status.Status)
+
+	els.dropUnsequencedSubmissions(0)
+	status, err = els.submissionStatus(0, 0, 3)
+	require.NoError(t, err)
+	require.Equal(t, SubmissionDropped, status.Status)
+	require.Equal(t, "round ended while waiting for sequence number 2", status.Reason)
+
+	var received []string
+	for len(received) < 3 {
+		update := <-updates
+		received = append(received, fmt.Sprintf("%d:%s", update.SequenceNumber, update.Status))
+	}
+	require.Equal(t, []string{"3:buffered", "1:failed", "3:dropped"}, received)
+}
+
 func Test_expressLaneService_syncFromRedis(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -550,6 +603,120 @@ func Test_expressLaneService_syncFromRedis(t *testing.T) {
 	els2.roundInfoMutex.Unlock()
 }
 
+func Test_expressLaneService_delegation(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	redisUrl := redisutil.CreateTestRedis(ctx, t)
+	timingInfo := defaultTestRoundTimingInfo(time.Now())
+	auctionContractAddr := common.HexToAddress("0x2Aef36410182881a4b13664a1E079762D7F716e6")
+	newTracker := func() *ExpressLaneTracker {
+		tr := &ExpressLaneTracker{
+			auctionContractAddr: auctionContractAddr,
+			roundTimingInfo:     timingInfo,
+			chainConfig: &params.ChainConfig{
+				ChainID: big.NewInt(1),
+			},
+		}
+		tr.roundControl.Store(0, crypto.PubkeyToAddress(testPriv.PublicKey))
+		return tr
+	}
+	newService := func(tr *ExpressLaneTracker) *expressLaneService {
+		els := &expressLaneService{
+			roundInfo:          containers.NewLruCache[uint64, *expressLaneRoundInfo](8),
+			roundTimingInfo:    timingInfo,
+			seqConfig:          func() *SequencerConfig { return &DefaultSequencerConfig },
+			tracker:            tr,
+			submissionStatuses: newSubmissionStatusTracker(),
+		}
+		var err error
+		els.redisCoordinator, err = timeboost.NewRedisCoordinator(redisUrl, &timingInfo, 50)
+		require.NoError(t, err)
+		els.redisCoordinator.Start(ctx)
+		els.StopWaiter.Start(ctx, els)
+		return els
+	}
+	delegate := crypto.PubkeyToAddress(testPriv2.PublicKey)
+	// #nosec G115
+	now := uint64(time.Now().Unix())
+
+	tr1 := newTracker()
+	els1 := newService(tr1)
+	stubPublisher1 := makeStubPublisher(els1)
+	els1.transactionPublisher = stubPublisher1
+
+	// Only the controller can delegate, and only to a delegation that can still become active
+	err := els1.registerDelegation(buildDelegation(t, testPriv2, delegate, 0, 1, 1, now-10, now+60))
+	require.ErrorIs(t, err, timeboost.ErrNotExpressLaneController)
+	err = els1.registerDelegation(buildDelegation(t, testPriv, delegate, 0, 1, 1, now-10, now-1))
+	require.ErrorIs(t, err, timeboost.ErrInvalidDelegation)
+	original := buildDelegation(t, testPriv, delegate, 0, 1, 1, now-10, now+60)
+	require.NoError(t, els1.registerDelegation(original))
+	// Changing the namespace would mix up the delegate's sequence numbers
+	err = els1.registerDelegation(buildDelegation(t, testPriv, delegate, 0, 2, 2, now-10, now+120))
+	require.ErrorIs(t, err, timeboost.ErrInvalidDelegation)
+
+	require.NoError(t, tr1.ValidateExpressLaneTx(buildSubmissionBy(t, testPriv2, 0, 0)))
+	otherPriv, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	require.ErrorIs(t, tr1.ValidateExpressLaneTx(buildSubmissionBy(t, otherPriv, 0, 0)), timeboost.ErrNotExpressLaneController)
+
+	// The controller revokes the delegation with a higher nonce, and the delegate can't restore the original
+	require.NoError(t, els1.registerDelegation(buildDelegation(t, testPriv, delegate, 0, 1, 2, now-10, now-1)))
+	require.ErrorIs(t, tr1.ValidateExpressLaneTx(buildSubmissionBy(t, testPriv2, 0, 0)), timeboost.ErrNotExpressLaneController)
+	require.ErrorIs(t, els1.registerDelegation(original), timeboost.ErrInvalidDelegation)
+	require.NoError(t, els1.registerDelegation(buildDelegation(t, testPriv, delegate, 0, 1, 3, now-10, now+60)))
+	require.NoError(t, tr1.ValidateExpressLaneTx(buildSubmissionBy(t, testPriv2, 0, 0)))
+
+	// The controller and the delegate number their submissions independently
+	require.NoError(t, els1.sequenceExpressLaneSubmission(buildSubmissionBy(t, testPriv, 0, 0)))
+	require.NoError(t, els1.sequenceExpressLaneSubmission(buildSubmissionBy(t, testPriv2, 0, 0)))
+	require.NoError(t, els1.sequenceExpressLaneSubmission(buildSubmissionBy(t, testPriv2, 0, 2)))
+	require.Equal(t, 2, len(stubPublisher1.publishedTxOrder))
+
+	time.Sleep(time.Second) // wait for parallel redis update threads to complete
+
+	// Another sequencer taking over picks up the delegation and the delegate's buffered submission
+	tr2 := newTracker()
+	els2 := newService(tr2)
+	stubPublisher2 := makeStubPublisher(els2)
+	els2.transactionPublisher = stubPublisher2
+	els2.syncFromRedis()
+	require.Equal(t, []uint64{1}, tr2.SequenceNamespaces(0))
+
+	require.NoError(t, els2.sequenceExpressLaneSubmission(buildSubmissionBy(t, testPriv2, 0, 1)))
+	require.Equal(t, 2, len(stubPublisher2.publishedTxOrder))
+	els2.roundInfoMutex.Lock()
+	roundInfo := els2.getRoundInfo(0, 1)
+	require.Equal(t, uint64(3), roundInfo.sequence)
+	roundInfo = els2.getRoundInfo(0, 0)
+	require.Equal(t, uint64(1), roundInfo.sequence)
+	els2.roundInfoMutex.Unlock()
+	status, err := els2.submissionStatus(0, 1, 2)
+	require.NoError(t, err)
+	require.Equal(t, SubmissionSequenced, status.Status)
+	require.Equal(t, hexutil.Uint64(1), status.SequenceNamespace)
+	require.Equal(t, hexutil.Uint64(3), status.NextSequenceNumber)
+
+	// A round has a limited number of namespaces, which delegates can share
+	for namespace := uint64(2); namespace <= maxSequenceNamespacesPerRound; namespace++ {
+		require.NoError(t, tr2.RegisterDelegation(buildDelegation(t, testPriv, common.BigToAddress(new(big.Int).SetUint64(namespace)), 0, namespace, 1, now-10, now+60)))
+	}
+	err = tr2.RegisterDelegation(buildDelegation(t, testPriv, common.Address{'x'}, 0, maxSequenceNamespacesPerRound+1, 1, now-10, now+60))
+	require.ErrorIs(t, err, timeboost.ErrInvalidDelegation)
+	require.NoError(t, tr2.RegisterDelegation(buildDelegation(t, testPriv, common.Address{'x'}, 0, 1, 1, now-10, now+60)))
+
+	// Delegations only authorize the delegate while their delegator controls the round
+	otherController := crypto.PubkeyToAddress(otherPriv.PublicKey)
+	tr2.roundControl.Store(0, otherController)
+	_, err = tr2.SequenceNamespace(0, delegate)
+	require.ErrorIs(t, err, timeboost.ErrNotExpressLaneController)
+	tr2.roundControl.Store(0, crypto.PubkeyToAddress(testPriv.PublicKey))
+	_, err = tr2.SequenceNamespace(0, delegate)
+	require.NoError(t, err)
+	tr2.setRoundController(0, otherController)
+	require.Empty(t, tr2.SequenceNamespaces(0))
+}
+
 func TestIsWithinAuctionCloseWindow(t *testing.T) {
 	initialTimestamp := time.Date(2024, 8, 8, 15, 0, 0, 0, time.UTC)
 	roundTimingInfo := defaultTestRoundTimingInfo(initialTimestamp)
@@ -695,3 +862,51 @@ func buildValidSubmissionWithSeqAndTx(
 	b.Signature = signature
 	return b
 }
+
+func buildSubmissionBy(
+	t testing.TB,
+	privKey *ecdsa.PrivateKey,
+	round uint64,
+	seq uint64,
+) *timeboost.ExpressLaneSubmission {
+	b := &timeboost.ExpressLaneSubmission{
+		ChainId:                big.NewInt(1),
+		AuctionContractAddress: common.HexToAddress("0x2Aef36410182881a4b13664a1E079762D7F716e6"),
+		Transaction:            emptyTx,
+		Signature:              make([]byte, 65),
+		Round:                  round,
+		SequenceNumber:         seq,
+	}
+	data, err := b.ToMessageBytes()
+	require.NoError(t, err)
+	signature, err := buildSignature(privKey, data)
+	require.NoError(t, err)
+	b.Signature = signature
+	return b
+}
+
+func buildDelegation(
+	t testing.TB,
+	privKey *ecdsa.PrivateKey,
+	delegate common.Address,
+	round uint64,
+	namespace uint64,
+	nonce uint64,
+	validAfter uint64,
+	validUntil uint64,
+) *timeboost.ExpressLaneDelegation {
+	d := &timeboost.ExpressLaneDelegation{
+		ChainId:                big.NewInt(1),
+		AuctionContractAddress: common.HexToAddress("0x2Aef36410182881a4b13664a1E079762D7F716e6"),
+		Round:                  round,
+		Delegate:               delegate,
+		ValidAfter:             validAfter,
+		ValidUntil:             validUntil,
+		SequenceNamespace:      namespace,
+		Nonce:                  nonce,
+	}
+	signature, err := buildSignature(privKey, d.ToMessageBytes())
+	require.NoError(t, err)
+	d.Signature = signature
+	return d
+}
@@ -0,0 +1,298 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+package gethexec
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/offchainlabs/nitro/util/containers"
+)
+
+const (
+	// SubmissionBuffered is a submission accepted ahead of its turn, waiting for the gap before its sequence number to be filled.
+	SubmissionBuffered = "buffered"
+	// SubmissionSequenced is a submission whose transaction was sequenced.
+	SubmissionSequenced = "sequenced"
+	// SubmissionFailed is a submission whose turn came, but whose transaction the sequencer rejected. Its sequence number is used up.
+	SubmissionFailed = "failed"
+	// SubmissionDropped is a submission still buffered when its round ended.
+	SubmissionDropped = "dropped"
+	// SubmissionUnknown is a submission the sequencer has no record of, because it never accepted it or the round is too old.
+	SubmissionUnknown = "unknown"
+)
+
+// submissionStatusRounds is how many rounds of submission statuses are kept.
+const submissionStatusRounds = 8
+
+// submissionStatusSubscriberBuffer is how many status updates a subscriber can lag behind before it's dropped.
+const submissionStatusSubscriberBuffer = 1024
+
+type ExpressLaneSubmissionStatus struct {
+	Round hexutil.Uint64 `json:"round"`
+	// SequenceNamespace is the sequence of the submission, 0 for the controller's and the nonzero namespace of a
+	// delegate otherwise.
+	SequenceNamespace hexutil.Uint64 `json:"sequenceNamespace"`
+	SequenceNumber    hexutil.Uint64 `json:"sequenceNumber"`
+	Status            string         `json:"status"`
+	TxHash            *common.Hash   `json:"txHash,omitempty"`
+	// Reason is why the submission failed or was dropped.
+	Reason string `json:"reason,omitempty"`
+	// CurrentRound and NextSequenceNumber are the round when the status was read, and the sequence number expected
+	// next in the submission's sequence namespace of that round.
+	CurrentRound       hexutil.Uint64 `json:"currentRound"`
+	NextSequenceNumber hexutil.Uint64 `json:"nextSequenceNumber"`
+}
+
+// submissionStatusTracker records the status of the submissions of the recent rounds, and sends the status
+// updates to the subscribers. It's guarded by the express lane service's roundInfoMutex.
+type submissionStatusTracker struct {
+	statuses *containers.LruCache[uint64, map[uint64]map[uint64]*ExpressLaneSubmissionStatus] // round -> namespace -> sequence number -> status
+
+	subscribersMutex sync.Mutex
+	nextSubscriberId uint64
+	subscribers      map[uint64]chan *ExpressLaneSubmissionStatus
+}
+
+func newSubmissionStatusTracker() *submissionStatusTracker {
+	return &submissionStatusTracker{
+		statuses:    containers.NewLruCache[uint64, map[uint64]map[uint64]*ExpressLaneSubmissionStatus](submissionStatusRounds),
+		subscribers: make(map[uint64]chan *ExpressLaneSubmissionStatus),
+	}
+}
+
+func (t *submissionStatusTracker) set(round, namespace, sequenceNumber uint64, txHash common.Hash, status, reason string, nextSequenceNumber uint64) {
+	roundStatuses, ok := t.statuses.Get(round)
+	if !ok {
+		roundStatuses = make(map[uint64]map[uint64]*ExpressLaneSubmissionStatus)
+		t.statuses.Add(round, roundStatuses)
+	}
+	if roundStatuses[namespace] == nil {
+		roundStatuses[namespace] = make(map[uint64]*ExpressLaneSubmissionStatus)
+	}
+	update := &ExpressLaneSubmissionStatus{
+		Round:              hexutil.Uint64(round),
+		SequenceNamespace:  hexutil.Uint64(namespace),
+		SequenceNumber:     hexutil.Uint64(sequenceNumber),
+		Status:             status,
+		TxHash:             &txHash,
+		Reason:             reason,
+		CurrentRound:       hexutil.Uint64(round),
+		NextSequenceNumber: hexutil.Uint64(nextSequenceNumber),
+	}
+	roundStatuses[namespace][sequenceNumber] = update
+	t.notify(update)
+}
+
+func (t *submissionStatusTracker) get(round, namespace, sequenceNumber uint64) (ExpressLaneSubmissionStatus, bool) {
+	roundStatuses, ok := t.statuses.Get(round)
+	if !ok {
+		return ExpressLaneSubmissionStatus{}, false
+	}
+	status, ok := roundStatuses[namespace][sequenceNumber]
+	if !ok {
+		return ExpressLaneSubmissionStatus{}, false
+	}
+	return *status, true
+}
+
+// dropBuffered marks the submissions of the round still buffered as dropped. nextSequenceNumber returns the sequence
+// number each namespace was waiting for.
+func (t *submissionStatusTracker) dropBuffered(round uint64, nextSequenceNumber func(namespace uint64) uint64) {
+	roundStatuses, ok := t.statuses.Get(round)
+	if !ok {
+		return
+	}
+	for namespace, statuses := range roundStatuses {
+		next := nextSequenceNumber(namespace)
+		reason := fmt.Sprintf("round ended while waiting for sequence number %d", next)
+		for sequenceNumber, status := range statuses {
+			if status.Status != SubmissionBuffered {
+				continue
+			}
+			t.set(round, namespace, sequenceNumber, *status.TxHash, SubmissionDropped, reason, next)
+		}
+	}
+}
+
+// notify sends the update to the subscribers without blocking, dropping those that fell too far behind.
+func (t *submissionStatusTracker) notify(update *ExpressLaneSubmissionStatus) {
+	t.subscribersMutex.Lock()
+	defer t.subscribersMutex.Unlock()
+	for id, ch := range t.subscribers {
+		select {
+		case ch <- update:
+		default:
+			log.Warn("Dropping express lane submission status subscriber that fell behind", "id", id)
+			delete(t.subscribers, id)
+			close(ch)
+		}
+	}
+}
+
+// subscribe returns the channel the status updates are sent to, which is closed if the subscriber falls behind,
+// and the function to unsubscribe.
+func (t *submissionStatusTracker) subscribe() (<-chan *ExpressLaneSubmissionStatus, func()) {
+	t.subscribersMutex.Lock()
+	defer t.subscribersMutex.Unlock()
+	id := t.nextSubscriberId
+	t.nextSubscriberId++
+	ch := make(chan *ExpressLaneSubmissionStatus, submissionStatusSubscriberBuffer)
+	t.subscribers[id] = ch
+	return ch, func() {
+		t.subscribersMutex.Lock()
+		defer t.subscribersMutex.Unlock()
+		if ch, ok := t.subscribers[id]; ok {
+			delete(t.subscribers, id)
+			close(ch)
+		}
+	}
+}
+
+// submissionStatus returns the status of the submission, with the next sequence number of its namespace in the
+// current round.
+func (es *expressLaneService) submissionStatus(round, namespace, sequenceNumber uint64) (*ExpressLaneSubmissionStatus, error) {
+	if es.submissionStatuses == nil {
+		return nil, errors.New("express lane submission statuses aren't tracked")
+	}
+	currentRound := es.roundTimingInfo.RoundNumber()
+	es.roundInfoMutex.Lock()
+	defer es.roundInfoMutex.Unlock()
+	var nextSequenceNumber uint64
+	if roundInfo, ok := es.peekRoundInfo(currentRound, namespace); ok {
+		nextSequenceNumber = roundInfo.sequence
+	}
+	status, found := es.submissionStatuses.get(round, namespace, sequenceNumber)
+	if !found {
+		status = ExpressLaneSubmissionStatus{
+			Round:             hexutil.Uint64(round),
+			SequenceNamespace: hexutil.Uint64(namespace),
+			SequenceNumber:    hexutil.Uint64(sequenceNumber),
+			Status:            SubmissionUnknown,
+		}
+	} else if status.Status == SubmissionBuffered && round < currentRound {
+		// The round ended but the submissions weren't marked as dropped yet.
+		status.Status = SubmissionDropped
+		status.Reason = fmt.Sprintf("round ended while waiting for sequence number %d", status.NextSequenceNumber)
+	}
+	status.CurrentRound = hexutil.Uint64(currentRound)
+	status.NextSequenceNumber = hexutil.Uint64(nextSequenceNumber)
+	return &status, nil
+}
+
+// dropUnsequencedSubmissions marks the submissions still buffered at the end of the round as dropped.
+func (es *expressLaneService) dropUnsequencedSubmissions(round uint64) {
+	es.roundInfoMutex.Lock()
+	defer es.roundInfoMutex.Unlock()
+	es.submissionStatuses.dropBuffered(round, func(namespace uint64) uint64 {
+		roundInfo, ok := es.peekRoundInfo(round, namespace)
+		if !ok {
+			return 0
+		}
+		return roundInfo.sequence
+	})
+}
+
+func (s *Sequencer) ExpressLaneSubmissionStatus(ctx context.Context, round, namespace, sequenceNumber uint64) (*ExpressLaneSubmissionStatus, error) {
+	if !s.config().Dangerous.Timeboost.Enable {
+		return nil, errors.New("timeboost not enabled")
+	}
+	pauseChan, forwarder := s.GetPauseAndForwarder()
+	if forwarder != nil {
+		return forwarder.ExpressLaneSubmissionStatus(ctx, round, namespace, sequenceNumber)
+	}
+	if pauseChan != nil {
+		return nil, ErrNoSequencer
+	}
+	if s.expressLaneService == nil {
+		return nil, errors.New("express lane service not enabled")
+	}
+	return s.expressLaneService.submissionStatus(round, namespace, sequenceNumber)
+}
+
+// SubscribeExpressLaneSubmissionStatus subscribes to the submission status updates of this sequencer, which are
+// only sent while it's the active sequencer.
+func (s *Sequencer) SubscribeExpressLaneSubmissionStatus() (<-chan *ExpressLaneSubmissionStatus, func(), error) {
+	if !s.config().Dangerous.Timeboost.Enable {
+		return nil, nil, errors.New("timeboost not enabled")
+	}
+	if s.expressLaneService == nil || s.expressLaneService.submissionStatuses == nil {
+		return nil, nil, errors.New("express lane service not enabled")
+	}
+	ch, unsubscribe := s.expressLaneService.submissionStatuses.subscribe()
+	return ch, unsubscribe, nil
+}
+
+func (f *TxForwarder) ExpressLaneSubmissionStatus(inctx context.Context, round, namespace, sequenceNumber uint64) (*ExpressLaneSubmissionStatus, error) {
+	if !f.enabled.Load() {
+		return nil, ErrNoSequencer
+	}
+	ctx, cancelFunc := f.ctxWithTimeout()
+	defer cancelFunc()
+	for pos, rpcClient := range f.rpcClients {
+		var status ExpressLaneSubmissionStatus
+		err := rpcClient.CallContext(ctx, &status, "timeboost_getSubmissionStatus", hexutil.Uint64(round), hexutil.Uint64(sequenceNumber), hexutil.Uint64(namespace))
+		if err == nil {
+			return &status, nil
+		}
+		log.Warn("error forwarding express lane submission status request to a backup target", "target", f.targets[pos], "err", err)
+		if !f.tryNewForwarderErrors.MatchString(err.Error()) {
+			return nil, err
+		}
+	}
+	return nil, errors.New("failed to get express lane submission status from any of the forwarding targets")
+}
+
+// ArbTimeboostSequencerAPI is the part of the timeboost API answered by the sequencer itself.
+type ArbTimeboostSequencerAPI struct {
+	sequencer *Sequencer
+}
+
+func NewArbTimeboostSequencerAPI(sequencer *Sequencer) *ArbTimeboostSequencerAPI {
+	return &ArbTimeboostSequencerAPI{sequencer}
+}
+
+// SubmissionStatus subscribes to the status updates of the express lane submissions, of every round or of the
+// given round. Subscribers aren't authenticated, so the updates, including the hashes of the transactions buffered
+// ahead of their turn, are sent to anyone with access to the sequencer's timeboost API, whoever the round's
+// controller is. Only the sequencer serves it, full nodes don't forward subscriptions.
+func (a *ArbTimeboostSequencerAPI) SubmissionStatus(ctx context.Context, round *hexutil.Uint64) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+	updates, unsubscribe, err := a.sequencer.SubscribeExpressLaneSubmissionStatus()
+	if err != nil {
+		return nil, err
+	}
+	rpcSub := notifier.CreateSubscription()
+	go func() {
+		defer unsubscribe()
+		for {
+			select {
+			case update, ok := <-updates:
+				if !ok {
+					return
+				}
+				if round != nil && update.Round != *round {
+					continue
+				}
+				if err := notifier.Notify(rpcSub.ID, update); err != nil {
+					log.Debug("Error notifying express lane submission status", "err", err)
+					return
+				}
+			case <-rpcSub.Err():
+				return
+			}
+		}
+	}()
+	return rpcSub, nil
+}
@@ -5,6 +5,7 @@ package gethexec
 
 import (
 	"context"
+	"sync"
 	"time"
 
 	"github.com/pkg/errors"
@@ -22,6 +23,9 @@ import (
 	"github.com/offchainlabs/nitro/util/stopwaiter"
 )
 
+// maxSequenceNamespacesPerRound is how many distinct nonzero sequence namespaces the delegates of a round can have.
+const maxSequenceNamespacesPerRound = 16
+
 type RoundListener interface {
 	NextRound(round uint64, controller common.Address)
 }
@@ -40,6 +44,9 @@ type ExpressLaneTracker struct {
 	earlySubmissionGrace time.Duration
 
 	roundControl containers.SyncMap[uint64, common.Address] // thread safe
+
+	delegationsMutex sync.RWMutex
+	delegations      map[uint64]map[common.Address]*timeboost.ExpressLaneDelegation // round -> delegate -> delegation
 }
 
 func NewExpressLaneTracker(
@@ -122,7 +129,7 @@ func (t *ExpressLaneTracker) Start(ctxIn context.Context) {
 					"timeSinceAuctionClose", timeSinceAuctionClose,
 				)
 
-				t.roundControl.Store(it.Event.Round, it.Event.FirstPriceExpressLaneController)
+				t.setRoundController(it.Event.Round, it.Event.FirstPriceExpressLaneController)
 
 			}
 			fromBlock = toBlock + 1
@@ -161,10 +168,24 @@ func (t *ExpressLaneTracker) Start(ctxIn context.Context) {
 
 			// Cleanup previous round controller data
 			t.roundControl.Delete(round - 1)
+			t.delegationsMutex.Lock()
+			delete(t.delegations, round-1)
+			t.delegationsMutex.Unlock()
 		}
 	})
 }
 
+// setRoundController sets the controller of the round, and drops the delegations of the previous controller if it
+// changed.
+func (t *ExpressLaneTracker) setRoundController(round uint64, controller common.Address) {
+	prev, existed := t.roundControl.Swap(round, controller)
+	if existed && prev != controller {
+		t.delegationsMutex.Lock()
+		delete(t.delegations, round)
+		t.delegationsMutex.Unlock()
+	}
+}
+
 func (t *ExpressLaneTracker) RoundController(round uint64) (common.Address, error) {
 	controller, ok := t.roundControl.Load(round)
 	if !ok {
@@ -197,18 +218,121 @@ func (t *ExpressLaneTracker) ValidateExpressLaneTx(msg *timeboost.ExpressLaneSub
 		}
 	}
 
-	controller, ok := t.roundControl.Load(msg.Round)
+	// Extract sender address and cache it to be later used by sequenceExpressLaneSubmission
+	sender, err := msg.Sender()
+	if err != nil {
+		return err
+	}
+	_, err = t.SequenceNamespace(msg.Round, sender)
+	return err
+}
+
+// SequenceNamespace returns the sequence namespace that submissions of sender are numbered in for the round, which is
+// 0 for the round's controller and its delegates sharing the controller's sequence. It errors if sender is neither the
+// controller nor a delegate with an active delegation.
+func (t *ExpressLaneTracker) SequenceNamespace(round uint64, sender common.Address) (uint64, error) {
+	controller, ok := t.roundControl.Load(round)
+	if !ok {
+		return 0, timeboost.ErrNoOnchainController
+	}
+	if sender == controller {
+		return 0, nil
+	}
+	t.delegationsMutex.RLock()
+	delegation, ok := t.delegations[round][sender]
+	t.delegationsMutex.RUnlock()
+	if !ok || !delegation.IsActiveAt(time.Now()) {
+		return 0, timeboost.ErrNotExpressLaneController
+	}
+	// Delegations are dropped when the controller changes, but the delegator is cached so checking it again is cheap
+	if delegator, err := delegation.Delegator(); err != nil || delegator != controller {
+		return 0, timeboost.ErrNotExpressLaneController
+	}
+	return delegation.SequenceNamespace, nil
+}
+
+// SequenceNamespaces returns the nonzero sequence namespaces of the delegates of the round.
+func (t *ExpressLaneTracker) SequenceNamespaces(round uint64) []uint64 {
+	t.delegationsMutex.RLock()
+	defer t.delegationsMutex.RUnlock()
+	seen := make(map[uint64]bool)
+	var namespaces []uint64
+	for _, delegation := range t.delegations[round] {
+		if delegation.SequenceNamespace != 0 && !seen[delegation.SequenceNamespace] {
+			seen[delegation.SequenceNamespace] = true
+			namespaces = append(namespaces, delegation.SequenceNamespace)
+		}
+	}
+	return namespaces
+}
+
+// RegisterDelegation validates a delegation signed by the controller of the current or next round and stores it, so
+// that the delegate's submissions are accepted while the delegation is active. A delegation may be replaced by one
+// with a higher nonce and the same sequence namespace, which is how the controller extends or revokes it.
+func (t *ExpressLaneTracker) RegisterDelegation(delegation *timeboost.ExpressLaneDelegation) error {
+	if delegation == nil || delegation.ChainId == nil || delegation.Signature == nil {
+		return timeboost.ErrMalformedData
+	}
+	if delegation.ChainId.Cmp(t.chainConfig.ChainID) != 0 {
+		return errors.Wrapf(timeboost.ErrWrongChainId, "delegation chain ID %d does not match current chain ID %d", delegation.ChainId, t.chainConfig.ChainID)
+	}
+	if delegation.AuctionContractAddress != t.auctionContractAddr {
+		return errors.Wrapf(timeboost.ErrWrongAuctionContract, "delegation auction contract address %#x does not match sequencer auction contract address %#x", delegation.AuctionContractAddress, t.auctionContractAddr)
+	}
+	currentRound := t.roundTimingInfo.RoundNumber()
+	if delegation.Round != currentRound && delegation.Round != currentRound+1 {
+		return errors.Wrapf(timeboost.ErrBadRoundNumber, "delegation round %d is neither the current round %d nor the next one", delegation.Round, currentRound)
+	}
+	controller, ok := t.roundControl.Load(delegation.Round)
 	if !ok {
 		return timeboost.ErrNoOnchainController
 	}
-	// Extract sender address and cache it to be later used by sequenceExpressLaneSubmission
-	sender, err := msg.Sender()
+	delegator, err := delegation.Delegator()
 	if err != nil {
 		return err
 	}
-	if sender != controller {
+	if delegator != controller {
 		return timeboost.ErrNotExpressLaneController
 	}
+	if delegation.Delegate == (common.Address{}) || delegation.Delegate == controller {
+		return errors.Wrapf(timeboost.ErrInvalidDelegation, "can't delegate to %#x", delegation.Delegate)
+	}
+
+	t.delegationsMutex.Lock()
+	defer t.delegationsMutex.Unlock()
+	if t.delegations == nil {
+		t.delegations = make(map[uint64]map[common.Address]*timeboost.ExpressLaneDelegation)
+	}
+	if t.delegations[delegation.Round] == nil {
+		t.delegations[delegation.Round] = make(map[common.Address]*timeboost.ExpressLaneDelegation)
+	}
+	prev, exists := t.delegations[delegation.Round][delegation.Delegate]
+	if exists {
+		if delegation.Nonce <= prev.Nonce {
+			return errors.Wrapf(timeboost.ErrInvalidDelegation, "delegation nonce %d of delegate %#x isn't higher than the registered %d", delegation.Nonce, delegation.Delegate, prev.Nonce)
+		}
+		if prev.SequenceNamespace != delegation.SequenceNamespace {
+			return errors.Wrapf(timeboost.ErrInvalidDelegation, "delegate %#x already has sequence namespace %d in round %d", delegation.Delegate, prev.SequenceNamespace, delegation.Round)
+		}
+	} else {
+		// A delegation that can never become active is only useful to revoke a registered one
+		// #nosec G115
+		if delegation.ValidUntil <= delegation.ValidAfter || delegation.ValidUntil <= uint64(time.Now().Unix()) {
+			return errors.Wrapf(timeboost.ErrInvalidDelegation, "delegation valid after %d and until %d is never active from now on", delegation.ValidAfter, delegation.ValidUntil)
+		}
+		if delegation.SequenceNamespace != 0 {
+			namespaces := make(map[uint64]bool)
+			for _, other := range t.delegations[delegation.Round] {
+				if other.SequenceNamespace != 0 {
+					namespaces[other.SequenceNamespace] = true
+				}
+			}
+			if !namespaces[delegation.SequenceNamespace] && len(namespaces) >= maxSequenceNamespacesPerRound {
+				return errors.Wrapf(timeboost.ErrInvalidDelegation, "round %d already has the maximum of %d sequence namespaces", delegation.Round, maxSequenceNamespacesPerRound)
+			}
+		}
+	}
+	t.delegations[delegation.Round][delegation.Delegate] = delegation
 	return nil
 }
 
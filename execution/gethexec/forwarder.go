@@ -199,6 +199,32 @@ func sendAuctionResolutionTransactionRPC(ctx context.Context, rpcClient *rpc.Cli
 	return rpcClient.CallContext(ctx, nil, "auctioneer_submitAuctionResolutionTransaction", tx)
 }
 
+func (f *TxForwarder) PublishBundle(inctx context.Context, bundle *TxBundle) error {
+	if !f.enabled.Load() {
+		return ErrNoSequencer
+	}
+	ctx, cancelFunc := f.ctxWithTimeout()
+	defer cancelFunc()
+	for pos, rpcClient := range f.rpcClients {
+		err := sendBundleRPC(ctx, rpcClient, bundle)
+		if err != nil {
+			log.Warn("error forwarding bundle to a backup target", "target", f.targets[pos], "err", err)
+		}
+		if err == nil || !f.tryNewForwarderErrors.MatchString(err.Error()) {
+			return err
+		}
+	}
+	return errors.New("failed to publish bundle to any of the forwarding targets")
+}
+
+func sendBundleRPC(ctx context.Context, rpcClient *rpc.Client, bundle *TxBundle) error {
+	args, err := bundle.ToArgs()
+	if err != nil {
+		return err
+	}
+	return rpcClient.CallContext(ctx, nil, "arb_sendBundle", args)
+}
+
 const cacheUpstreamHealth = 2 * time.Second
 const maxHealthTimeout = 10 * time.Second
 
@@ -303,6 +329,14 @@ func (f *TxDropper) PublishAuctionResolutionTransaction(ctx context.Context, tx
 	return txDropperErr
 }
 
+func (f *TxDropper) PublishBundle(ctx context.Context, bundle *TxBundle) error {
+	return txDropperErr
+}
+
+func (f *TxDropper) ExpressLaneSubmissionStatus(ctx context.Context, round, namespace, sequenceNumber uint64) (*ExpressLaneSubmissionStatus, error) {
+	return nil, txDropperErr
+}
+
 func (f *TxDropper) CheckHealth(ctx context.Context) error {
 	return txDropperErr
 }
@@ -362,6 +396,22 @@ func (f *RedisTxForwarder) PublishAuctionResolutionTransaction(ctx context.Conte
 	return forwarder.PublishAuctionResolutionTransaction(ctx, tx)
 }
 
+func (f *RedisTxForwarder) PublishBundle(ctx context.Context, bundle *TxBundle) error {
+	forwarder := f.getForwarder()
+	if forwarder == nil {
+		return ErrNoSequencer
+	}
+	return forwarder.PublishBundle(ctx, bundle)
+}
+
+func (f *RedisTxForwarder) ExpressLaneSubmissionStatus(ctx context.Context, round, namespace, sequenceNumber uint64) (*ExpressLaneSubmissionStatus, error) {
+	forwarder := f.getForwarder()
+	if forwarder == nil {
+		return nil, ErrNoSequencer
+	}
+	return forwarder.ExpressLaneSubmissionStatus(ctx, round, namespace, sequenceNumber)
+}
+
 func (f *RedisTxForwarder) CheckHealth(ctx context.Context) error {
 	forwarder := f.getForwarder()
 	if forwarder == nil {
@@ -294,6 +294,27 @@ func CreateExecutionNode(
 		Service:   NewArbTimeboostAPI(txPublisher),
 		Public:    false,
 	})
+	if sequencer != nil {
+		apis = append(apis, rpc.API{
+			Namespace:     "sequenceradmin",
+			Version:       "1.0",
+			Service:       NewSequencerAdminAPI(sequencer),
+			Public:        false,
+			Authenticated: true,
+		})
+		apis = append(apis, rpc.API{
+			Namespace: "arb",
+			Version:   "1.0",
+			Service:   NewArbSequencerAPI(sequencer),
+			Public:    false,
+		})
+		apis = append(apis, rpc.API{
+			Namespace: "timeboost",
+			Version:   "1.0",
+			Service:   NewArbTimeboostSequencerAPI(sequencer),
+			Public:    false,
+		})
+	}
 	apis = append(apis, rpc.API{
 		Namespace: "arbdebug",
 		Version:   "1.0",
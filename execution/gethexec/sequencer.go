@@ -62,22 +62,26 @@ var (
 )
 
 type SequencerConfig struct {
-	Enable                       bool            `koanf:"enable"`
-	MaxBlockSpeed                time.Duration   `koanf:"max-block-speed" reload:"hot"`
-	MaxRevertGasReject           uint64          `koanf:"max-revert-gas-reject" reload:"hot"`
-	MaxAcceptableTimestampDelta  time.Duration   `koanf:"max-acceptable-timestamp-delta" reload:"hot"`
-	SenderWhitelist              []string        `koanf:"sender-whitelist"`
-	Forwarder                    ForwarderConfig `koanf:"forwarder"`
-	QueueSize                    int             `koanf:"queue-size"`
-	QueueTimeout                 time.Duration   `koanf:"queue-timeout" reload:"hot"`
-	NonceCacheSize               int             `koanf:"nonce-cache-size" reload:"hot"`
-	MaxTxDataSize                int             `koanf:"max-tx-data-size" reload:"hot"`
-	NonceFailureCacheSize        int             `koanf:"nonce-failure-cache-size" reload:"hot"`
-	NonceFailureCacheExpiry      time.Duration   `koanf:"nonce-failure-cache-expiry" reload:"hot"`
-	ExpectedSurplusSoftThreshold string          `koanf:"expected-surplus-soft-threshold" reload:"hot"`
-	ExpectedSurplusHardThreshold string          `koanf:"expected-surplus-hard-threshold" reload:"hot"`
-	EnableProfiling              bool            `koanf:"enable-profiling" reload:"hot"`
-	Dangerous                    DangerousConfig `koanf:"dangerous"`
+	Enable                       bool                  `koanf:"enable"`
+	MaxBlockSpeed                time.Duration         `koanf:"max-block-speed" reload:"hot"`
+	MaxRevertGasReject           uint64                `koanf:"max-revert-gas-reject" reload:"hot"`
+	MaxAcceptableTimestampDelta  time.Duration         `koanf:"max-acceptable-timestamp-delta" reload:"hot"`
+	SenderWhitelist              []string              `koanf:"sender-whitelist"`
+	AdmissionPolicy              AdmissionPolicyConfig `koanf:"admission-policy"`
+	QueueJournal                 QueueJournalConfig    `koanf:"queue-journal"`
+	Forwarder                    ForwarderConfig       `koanf:"forwarder"`
+	QueueSize                    int                   `koanf:"queue-size"`
+	QueueTimeout                 time.Duration         `koanf:"queue-timeout" reload:"hot"`
+	NonceCacheSize               int                   `koanf:"nonce-cache-size" reload:"hot"`
+	MaxTxDataSize                int                   `koanf:"max-tx-data-size" reload:"hot"`
+	NonceFailureCacheSize        int                   `koanf:"nonce-failure-cache-size" reload:"hot"`
+	NonceFailureCacheExpiry      time.Duration         `koanf:"nonce-failure-cache-expiry" reload:"hot"`
+	ExpectedSurplusSoftThreshold string                `koanf:"expected-surplus-soft-threshold" reload:"hot"`
+	ExpectedSurplusHardThreshold string                `koanf:"expected-surplus-hard-threshold" reload:"hot"`
+	EnableProfiling              bool                  `koanf:"enable-profiling" reload:"hot"`
+	Ordering                     string                `koanf:"ordering" reload:"hot"`
+	MaxBundleTxs                 int                   `koanf:"max-bundle-txs" reload:"hot"`
+	Dangerous                    DangerousConfig       `koanf:"dangerous"`
 	expectedSurplusSoftThreshold int
 	expectedSurplusHardThreshold int
 }
@@ -138,6 +142,15 @@ func (c *SequencerConfig) Validate() error {
 	if c.MaxTxDataSize > arbostypes.MaxL2MessageSize-50000 {
 		return errors.New("max-tx-data-size too large for MaxL2MessageSize")
 	}
+	if _, err := getSequencerOrderingPolicy(c.Ordering); err != nil {
+		return err
+	}
+	if err := c.AdmissionPolicy.Validate(); err != nil {
+		return err
+	}
+	if err := c.QueueJournal.Validate(); err != nil {
+		return err
+	}
 	return c.Dangerous.Timeboost.Validate()
 }
 
@@ -168,6 +181,8 @@ var DefaultSequencerConfig = SequencerConfig{
 	MaxRevertGasReject:          0,
 	MaxAcceptableTimestampDelta: time.Hour,
 	SenderWhitelist:             []string{},
+	AdmissionPolicy:             DefaultAdmissionPolicyConfig,
+	QueueJournal:                DefaultQueueJournalConfig,
 	Forwarder:                   DefaultSequencerForwarderConfig,
 	QueueSize:                   1024,
 	QueueTimeout:                time.Second * 12,
@@ -180,6 +195,8 @@ var DefaultSequencerConfig = SequencerConfig{
 	ExpectedSurplusSoftThreshold: "default",
 	ExpectedSurplusHardThreshold: "default",
 	EnableProfiling:              false,
+	Ordering:                     FifoOrderingPolicyName,
+	MaxBundleTxs:                 0,
 	Dangerous:                    DefaultDangerousConfig,
 }
 
@@ -193,6 +210,8 @@ func SequencerConfigAddOptions(prefix string, f *flag.FlagSet) {
 	f.Uint64(prefix+".max-revert-gas-reject", DefaultSequencerConfig.MaxRevertGasReject, "maximum gas executed in a revert for the sequencer to reject the transaction instead of posting it (anti-DOS)")
 	f.Duration(prefix+".max-acceptable-timestamp-delta", DefaultSequencerConfig.MaxAcceptableTimestampDelta, "maximum acceptable time difference between the local time and the latest L1 block's timestamp")
 	f.StringSlice(prefix+".sender-whitelist", DefaultSequencerConfig.SenderWhitelist, "comma separated whitelist of authorized senders (if empty, everyone is allowed)")
+	AdmissionPolicyConfigAddOptions(prefix+".admission-policy", f)
+	QueueJournalConfigAddOptions(prefix+".queue-journal", f)
 	AddOptionsForSequencerForwarderConfig(prefix+".forwarder", f)
 	DangerousAddOptions(prefix+".dangerous", f)
 
@@ -205,6 +224,8 @@ func SequencerConfigAddOptions(prefix string, f *flag.FlagSet) {
 	f.String(prefix+".expected-surplus-soft-threshold", DefaultSequencerConfig.ExpectedSurplusSoftThreshold, "if expected surplus is lower than this value, warnings are posted")
 	f.String(prefix+".expected-surplus-hard-threshold", DefaultSequencerConfig.ExpectedSurplusHardThreshold, "if expected surplus is lower than this value, new incoming transactions will be denied")
 	f.Bool(prefix+".enable-profiling", DefaultSequencerConfig.EnableProfiling, "enable CPU profiling and tracing")
+	f.Int(prefix+".max-bundle-txs", DefaultSequencerConfig.MaxBundleTxs, "maximum number of transactions in a bundle submitted through arb_sendBundle, each bundle getting a block of its own (0 = bundles disabled)")
+	f.String(prefix+".ordering", DefaultSequencerConfig.Ordering, "policy used to order the transactions of each block (fifo, priority-fee or per-sender-fair); senders' nonce order is always kept")
 }
 
 func TimeboostAddOptions(prefix string, f *flag.FlagSet) {
@@ -233,7 +254,9 @@ type txQueueItem struct {
 	ctx             context.Context
 	firstAppearance time.Time
 	isTimeboosted   bool
-	blockStamp      uint64 // block number at which timeboosted tx was added to the txQueue
+	blockStamp      uint64    // block number at which timeboosted tx was added to the txQueue
+	bundle          *TxBundle // if set, tx is the first tx of the bundle and txSize covers the whole bundle
+	untrack         func()    // if set, removes the item from the sequencer's queuedTxs
 }
 
 func (i *txQueueItem) returnResult(err error) {
@@ -241,6 +264,9 @@ func (i *txQueueItem) returnResult(err error) {
 		log.Error("attempting to return result to already finished queue item", "err", err)
 		return
 	}
+	if i.untrack != nil {
+		i.untrack()
+	}
 	i.resultChan <- err
 	close(i.resultChan)
 }
@@ -397,9 +423,12 @@ type Sequencer struct {
 	txRetryQueue       synchronizedTxQueue
 	l1Reader           *headerreader.HeaderReader
 	config             SequencerConfigFetcher
-	senderWhitelist    map[common.Address]struct{}
+	admissionPolicy    *AdmissionPolicy
+	queueJournal       QueueJournal
+	journalRecords     chan queueJournalWrite
 	nonceCache         *nonceCache
 	nonceFailures      *nonceFailureCache
+	queuedTxs          queuedTxs
 	expressLaneService *expressLaneService
 	onForwarderSet     chan struct{}
 
@@ -426,19 +455,38 @@ func NewSequencer(execEngine *ExecutionEngine, l1Reader *headerreader.HeaderRead
 	if err := config.Validate(); err != nil {
 		return nil, err
 	}
-	senderWhitelist := make(map[common.Address]struct{})
+	var senderWhitelist []common.Address
 	for _, address := range config.SenderWhitelist {
 		if len(address) == 0 {
 			continue
 		}
-		senderWhitelist[common.HexToAddress(address)] = struct{}{}
+		senderWhitelist = append(senderWhitelist, common.HexToAddress(address))
+	}
+	admissionPolicy, err := NewAdmissionPolicy(senderWhitelist)
+	if err != nil {
+		return nil, err
+	}
+	if config.AdmissionPolicy.RulesFile != "" {
+		if err := admissionPolicy.ReloadFromFile(config.AdmissionPolicy.RulesFile, true); err != nil {
+			return nil, fmt.Errorf("failed to load sequencer admission rules: %w", err)
+		}
+	}
+	queueJournal, err := NewQueueJournal(&config.QueueJournal)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sequencer queue journal: %w", err)
+	}
+	var journalRecords chan queueJournalWrite
+	if queueJournal != nil {
+		journalRecords = make(chan queueJournalWrite, queueJournalBufferSize)
 	}
 	s := &Sequencer{
 		execEngine:                        execEngine,
 		txQueue:                           make(chan txQueueItem, config.QueueSize),
 		l1Reader:                          l1Reader,
 		config:                            configFetcher,
-		senderWhitelist:                   senderWhitelist,
+		admissionPolicy:                   admissionPolicy,
+		queueJournal:                      queueJournal,
+		journalRecords:                    journalRecords,
 		nonceCache:                        newNonceCache(config.NonceCacheSize),
 		l1Timestamp:                       0,
 		pauseChan:                         nil,
@@ -509,7 +557,10 @@ func (s *Sequencer) PublishTransaction(parentCtx context.Context, tx *types.Tran
 	if err != nil {
 		return err
 	}
+	return waitForQueueResult(parentCtx, queueTimeout, resultChan, tx.Hash())
+}
 
+func waitForQueueResult(parentCtx context.Context, queueTimeout time.Duration, resultChan <-chan error, txHash common.Hash) error {
 	now := time.Now()
 	// Just to be safe, make sure we don't run over twice the queue timeout
 	abortCtx, cancel := ctxWithTimeout(parentCtx, queueTimeout*2)
@@ -524,7 +575,7 @@ func (s *Sequencer) PublishTransaction(parentCtx context.Context, tx *types.Tran
 		err := abortCtx.Err()
 		if parentCtx.Err() == nil {
 			// If we've hit the abort deadline (as opposed to parentCtx being canceled), something went wrong.
-			log.Warn("Transaction sequencing hit abort deadline", "err", err, "submittedAt", now, "queueTimeout", queueTimeout*2, "txHash", tx.Hash())
+			log.Warn("Transaction sequencing hit abort deadline", "err", err, "submittedAt", now, "queueTimeout", queueTimeout*2, "txHash", txHash)
 		}
 		return err
 	}
@@ -622,31 +673,35 @@ func (s *Sequencer) PublishTimeboostedTransaction(queueCtx context.Context, tx *
 	return s.publishTransactionToQueue(queueCtx, tx, options, resultChan, true)
 }
 
-func (s *Sequencer) publishTransactionToQueue(queueCtx context.Context, tx *types.Transaction, options *arbitrum_types.ConditionalOptions, resultChan chan error, isExpressLaneController bool) error {
-	config := s.config()
+func (s *Sequencer) checkExpectedSurplus(config *SequencerConfig) error {
 	// Only try to acquire Rlock and check for hard threshold if l1reader is not nil
 	// And hard threshold was enabled, this prevents spamming of read locks when not needed
 	if s.l1Reader != nil && config.ExpectedSurplusHardThreshold != "default" {
 		s.expectedSurplusMutex.RLock()
+		defer s.expectedSurplusMutex.RUnlock()
 		if s.expectedSurplusUpdated && s.expectedSurplus < int64(config.expectedSurplusHardThreshold) {
 			return errors.New("currently not accepting transactions due to expected surplus being below threshold")
 		}
-		s.expectedSurplusMutex.RUnlock()
+	}
+	return nil
+}
+
+func (s *Sequencer) publishTransactionToQueue(queueCtx context.Context, tx *types.Transaction, options *arbitrum_types.ConditionalOptions, resultChan chan error, isExpressLaneController bool) error {
+	config := s.config()
+	if err := s.checkExpectedSurplus(config); err != nil {
+		return err
 	}
 
 	sequencerBacklogGauge.Inc(1)
 	defer sequencerBacklogGauge.Dec(1)
 
-	if len(s.senderWhitelist) > 0 {
-		signer := types.LatestSigner(s.execEngine.bc.Config())
-		sender, err := types.Sender(signer, tx)
-		if err != nil {
-			return err
-		}
-		_, authorized := s.senderWhitelist[sender]
-		if !authorized {
-			return errors.New("transaction sender is not on the whitelist")
-		}
+	signer := types.LatestSigner(s.execEngine.bc.Config())
+	sender, err := types.Sender(signer, tx)
+	if err != nil {
+		return err
+	}
+	if err := s.admissionPolicy.Check(tx, sender); err != nil {
+		return err
 	}
 	if tx.Type() >= types.ArbitrumDepositTxType || tx.Type() == types.BlobTxType {
 		// Should be unreachable for Arbitrum types due to UnmarshalBinary not accepting Arbitrum internal txs
@@ -680,10 +735,31 @@ func (s *Sequencer) publishTransactionToQueue(queueCtx context.Context, tx *type
 		time.Now(),
 		isExpressLaneController,
 		blockStamp,
+		nil,
+		nil,
+	}
+	// The rate limit is only charged for txs that passed every check, and refunded if they don't make it into the queue.
+	if err := s.admissionPolicy.Charge(sender); err != nil {
+		return err
 	}
+	// Transactions are journaled before entering the queue, and rejected if they can't be
+	if err := s.journalAdd(queueCtx, tx, options); err != nil {
+		s.admissionPolicy.Refund(sender)
+		return err
+	}
+	if s.queueJournal != nil {
+		queueItem.resultChan = s.journalResult(tx.Hash(), resultChan)
+	}
+	queueItem.untrack = s.queuedTxs.add(queuedTx{tx, options})
 	select {
 	case s.txQueue <- queueItem:
 	case <-queueCtx.Done():
+		s.admissionPolicy.Refund(sender)
+		queueItem.untrack()
+		if s.queueJournal != nil {
+			// Removes the journal entry
+			close(queueItem.resultChan)
+		}
 		return queueCtx.Err()
 	}
 
@@ -805,6 +881,9 @@ func (s *Sequencer) Activate() {
 			s.expressLaneService.syncFromRedis()
 		})
 	}
+	if s.queueJournal != nil {
+		s.LaunchThread(s.replayQueueJournal)
+	}
 }
 
 func (s *Sequencer) Pause() {
@@ -857,7 +936,12 @@ func (s *Sequencer) handleInactive(ctx context.Context, queueItems []txQueueItem
 	for _, item := range queueItems {
 		item := item
 		go func() {
-			res := forwarder.PublishTransaction(item.ctx, item.tx, item.options)
+			var res error
+			if item.bundle != nil {
+				res = forwarder.PublishBundle(item.ctx, item.bundle)
+			} else {
+				res = forwarder.PublishTransaction(item.ctx, item.tx, item.options)
+			}
 			if errors.Is(res, ErrNoSequencer) {
 				publishResults <- &item
 			} else {
@@ -1100,6 +1184,14 @@ func (s *Sequencer) createBlock(ctx context.Context) (returnValue bool) {
 			queueItem.returnResult(txpool.ErrOversizedData)
 			continue
 		}
+		if queueItem.bundle != nil {
+			if len(queueItems) > 0 {
+				// Bundles get a block of their own, so finish this one first
+				s.txRetryQueue.Push(queueItem)
+				break
+			}
+			return s.sequenceBundle(ctx, queueItem)
+		}
 		if queueItem.isTimeboosted &&
 			queueItem.blockStamp != 0 &&
 			lastBlock.Number.Uint64() >= queueItem.blockStamp+config.Dangerous.Timeboost.QueueTimeoutInBlocks {
@@ -1130,6 +1222,7 @@ func (s *Sequencer) createBlock(ctx context.Context) (returnValue bool) {
 	s.nonceCache.Resize(config.NonceCacheSize) // Would probably be better in a config hook but this is basically free
 	s.nonceCache.BeginNewBlock()
 	queueItems = s.precheckNonces(queueItems, totalBlockSize)
+	queueItems = s.orderQueueItems(config.Ordering, queueItems, lastBlock.BaseFee)
 	txes := make([]*types.Transaction, len(queueItems))
 	timeboostedTxs := make(map[common.Hash]struct{})
 	hooks := s.makeSequencingHooks()
@@ -1161,35 +1254,14 @@ func (s *Sequencer) createBlock(ctx context.Context) (returnValue bool) {
 		return false
 	}
 
-	timestamp := time.Now().Unix()
-	s.L1BlockAndTimeMutex.Lock()
-	l1Block := s.l1BlockNumber.Load()
-	l1Timestamp := s.l1Timestamp
-	s.L1BlockAndTimeMutex.Unlock()
-
-	if s.l1Reader != nil && (l1Block == 0 || math.Abs(float64(l1Timestamp)-float64(timestamp)) > config.MaxAcceptableTimestampDelta.Seconds()) {
+	header := s.nextL2MessageHeader(config)
+	if header == nil {
 		for _, queueItem := range queueItems {
 			s.txRetryQueue.Push(queueItem)
 		}
-		// #nosec G115
-		log.Error(
-			"cannot sequence: unknown L1 block or L1 timestamp too far from local clock time",
-			"l1Block", l1Block,
-			"l1Timestamp", time.Unix(int64(l1Timestamp), 0),
-			"localTimestamp", time.Unix(timestamp, 0),
-		)
 		return true
 	}
 
-	header := &arbostypes.L1IncomingMessageHeader{
-		Kind:        arbostypes.L1MessageType_L2Message,
-		Poster:      l1pricing.BatchPosterAddress,
-		BlockNumber: l1Block,
-		Timestamp:   arbmath.SaturatingUCast[uint64](timestamp),
-		RequestId:   nil,
-		L1BaseFee:   nil,
-	}
-
 	start := time.Now()
 	var (
 		block *types.Block
@@ -1273,6 +1345,36 @@ func (s *Sequencer) createBlock(ctx context.Context) (returnValue bool) {
 	return madeBlock
 }
 
+// nextL2MessageHeader returns the header for the next sequenced message,
+// or nil if the sequencer's view of the parent chain is too far from the local clock.
+func (s *Sequencer) nextL2MessageHeader(config *SequencerConfig) *arbostypes.L1IncomingMessageHeader {
+	timestamp := time.Now().Unix()
+	s.L1BlockAndTimeMutex.Lock()
+	l1Block := s.l1BlockNumber.Load()
+	l1Timestamp := s.l1Timestamp
+	s.L1BlockAndTimeMutex.Unlock()
+
+	if s.l1Reader != nil && (l1Block == 0 || math.Abs(float64(l1Timestamp)-float64(timestamp)) > config.MaxAcceptableTimestampDelta.Seconds()) {
+		// #nosec G115
+		log.Error(
+			"cannot sequence: unknown L1 block or L1 timestamp too far from local clock time",
+			"l1Block", l1Block,
+			"l1Timestamp", time.Unix(int64(l1Timestamp), 0),
+			"localTimestamp", time.Unix(timestamp, 0),
+		)
+		return nil
+	}
+
+	return &arbostypes.L1IncomingMessageHeader{
+		Kind:        arbostypes.L1MessageType_L2Message,
+		Poster:      l1pricing.BatchPosterAddress,
+		BlockNumber: l1Block,
+		Timestamp:   arbmath.SaturatingUCast[uint64](timestamp),
+		RequestId:   nil,
+		L1BaseFee:   nil,
+	}
+}
+
 func (s *Sequencer) updateLatestParentChainBlock(header *types.Header) {
 	s.L1BlockAndTimeMutex.Lock()
 	defer s.L1BlockAndTimeMutex.Unlock()
@@ -1373,6 +1475,9 @@ func (s *Sequencer) StartExpressLaneService(ctx context.Context) {
 
 func (s *Sequencer) Start(ctxIn context.Context) error {
 	s.StopWaiter.Start(ctxIn, s)
+	if s.queueJournal != nil {
+		s.LaunchThread(s.writeQueueJournal)
+	}
 	config := s.config()
 	if (config.ExpectedSurplusHardThreshold != "default" || config.ExpectedSurplusSoftThreshold != "default") && s.l1Reader == nil {
 		return errors.New("expected surplus soft/hard thresholds are enabled but l1Reader is nil")
@@ -1426,6 +1531,21 @@ func (s *Sequencer) Start(ctxIn context.Context) error {
 		})
 	}
 
+	if config.AdmissionPolicy.RulesFile != "" {
+		s.CallIteratively(func(ctx context.Context) time.Duration {
+			if err := s.admissionPolicy.ReloadFromFile(config.AdmissionPolicy.RulesFile, false); err != nil {
+				log.Error("failed to reload sequencer admission rules, keeping previous rules", "file", config.AdmissionPolicy.RulesFile, "err", err)
+			}
+			s.admissionPolicy.pruneRateWindows(s.admissionPolicy.maxRateWindow())
+			return config.AdmissionPolicy.ReloadInterval
+		})
+	} else {
+		s.CallIteratively(func(ctx context.Context) time.Duration {
+			s.admissionPolicy.pruneRateWindows(s.admissionPolicy.maxRateWindow())
+			return time.Minute
+		})
+	}
+
 	s.CallIteratively(func(ctx context.Context) time.Duration {
 		nextBlock := time.Now().Add(s.config().MaxBlockSpeed)
 		if s.createBlock(ctx) {
@@ -1462,6 +1582,16 @@ func (s *Sequencer) StopAndWait() {
 	if s.config().Dangerous.Timeboost.Enable && s.expressLaneService != nil {
 		s.expressLaneService.StopAndWait()
 	}
+	if s.queueJournal != nil {
+		defer func() {
+			for len(s.journalRecords) > 0 {
+				s.flushQueueJournal()
+			}
+			if err := s.queueJournal.Close(); err != nil {
+				log.Warn("error closing sequencer queue journal", "err", err)
+			}
+		}()
+	}
 	if s.txRetryQueue.Len() == 0 &&
 		len(s.txQueue) == 0 &&
 		s.nonceFailures.Len() == 0 &&
@@ -1506,6 +1636,8 @@ func (s *Sequencer) StopAndWait() {
 				var err error
 				if source == TimeboostAuctionResolutionTxQueue {
 					err = forwarder.PublishAuctionResolutionTransaction(item.ctx, item.tx)
+				} else if item.bundle != nil {
+					err = forwarder.PublishBundle(item.ctx, item.bundle)
 				} else {
 					err = forwarder.PublishTransaction(item.ctx, item.tx, item.options)
 				}
@@ -0,0 +1,418 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+package gethexec
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	flag "github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+var (
+	admissionRejectedCounter = metrics.NewRegisteredCounter("arb/sequencer/admission/rejected", nil)
+	admissionReloadCounter   = metrics.NewRegisteredCounter("arb/sequencer/admission/reloads", nil)
+)
+
+type AdmissionPolicyConfig struct {
+	RulesFile      string        `koanf:"rules-file"`
+	ReloadInterval time.Duration `koanf:"reload-interval"`
+}
+
+var DefaultAdmissionPolicyConfig = AdmissionPolicyConfig{
+	RulesFile:      "",
+	ReloadInterval: time.Second * 10,
+}
+
+func AdmissionPolicyConfigAddOptions(prefix string, f *flag.FlagSet) {
+	f.String(prefix+".rules-file", DefaultAdmissionPolicyConfig.RulesFile, "path to a JSON or YAML file of transaction admission rules, reloaded whenever it changes (if empty, only sender-whitelist is applied)")
+	f.Duration(prefix+".reload-interval", DefaultAdmissionPolicyConfig.ReloadInterval, "how often to check the admission rules file for changes")
+}
+
+func (c *AdmissionPolicyConfig) Validate() error {
+	if c.RulesFile != "" && c.ReloadInterval <= 0 {
+		return errors.New("sequencer admission-policy reload-interval must be positive when a rules-file is set")
+	}
+	return nil
+}
+
+// AdmissionRateLimit allows at most MaxTxs transactions per sender in each Window.
+type AdmissionRateLimit struct {
+	MaxTxs uint64 `json:"maxTxs" yaml:"maxTxs"`
+	Window string `json:"window" yaml:"window"`
+}
+
+// AdmissionRules is the on-disk and RPC representation of the sequencer's admission policy.
+// Empty allow lists allow everything; deny lists always take precedence over allow lists.
+// Selector rules only apply to calls with at least 4 bytes of calldata.
+type AdmissionRules struct {
+	AllowSenders             []common.Address                      `json:"allowSenders,omitempty" yaml:"allowSenders"`
+	DenySenders              []common.Address                      `json:"denySenders,omitempty" yaml:"denySenders"`
+	AllowRecipients          []common.Address                      `json:"allowRecipients,omitempty" yaml:"allowRecipients"`
+	DenyRecipients           []common.Address                      `json:"denyRecipients,omitempty" yaml:"denyRecipients"`
+	AllowSelectors           []hexutil.Bytes                       `json:"allowSelectors,omitempty" yaml:"allowSelectors"`
+	DenySelectors            []hexutil.Bytes                       `json:"denySelectors,omitempty" yaml:"denySelectors"`
+	SenderRateLimit          *AdmissionRateLimit                   `json:"senderRateLimit,omitempty" yaml:"senderRateLimit"`
+	SenderRateLimitOverrides map[common.Address]AdmissionRateLimit `json:"senderRateLimitOverrides,omitempty" yaml:"senderRateLimitOverrides"`
+}
+
+const AdmissionRejectedErrorCode = -32003
+
+const (
+	AdmissionSenderDenied        = "sender-denied"
+	AdmissionSenderNotAllowed    = "sender-not-allowed"
+	AdmissionRecipientDenied     = "recipient-denied"
+	AdmissionRecipientNotAllowed = "recipient-not-allowed"
+	AdmissionSelectorDenied      = "selector-denied"
+	AdmissionSelectorNotAllowed  = "selector-not-allowed"
+	AdmissionRateLimited         = "rate-limited"
+)
+
+// AdmissionError is returned to RPC callers when the admission policy rejects a transaction.
+// It carries its own error code and a machine readable reason in the error data,
+// so that it can be told apart from nonce or gas errors.
+type AdmissionError struct {
+	Reason string
+	Detail string
+}
+
+func (e *AdmissionError) Error() string {
+	return fmt.Sprintf("transaction rejected by sequencer admission policy (%v): %v", e.Reason, e.Detail)
+}
+
+func (e *AdmissionError) ErrorCode() int {
+	return AdmissionRejectedErrorCode
+}
+
+func (e *AdmissionError) ErrorData() interface{} {
+	return map[string]string{"reason": e.Reason}
+}
+
+type admissionRateLimit struct {
+	maxTxs uint64
+	window time.Duration
+}
+
+type compiledAdmissionRules struct {
+	source          AdmissionRules
+	allowSenders    map[common.Address]struct{}
+	denySenders     map[common.Address]struct{}
+	allowRecipients map[common.Address]struct{}
+	denyRecipients  map[common.Address]struct{}
+	allowSelectors  map[[4]byte]struct{}
+	denySelectors   map[[4]byte]struct{}
+	rateLimit       *admissionRateLimit
+	rateOverrides   map[common.Address]admissionRateLimit
+}
+
+func addressSet(addresses ...[]common.Address) map[common.Address]struct{} {
+	set := make(map[common.Address]struct{})
+	for _, list := range addresses {
+		for _, address := range list {
+			set[address] = struct{}{}
+		}
+	}
+	return set
+}
+
+func selectorSet(selectors []hexutil.Bytes) (map[[4]byte]struct{}, error) {
+	set := make(map[[4]byte]struct{})
+	for _, selector := range selectors {
+		if len(selector) != 4 {
+			return nil, fmt.Errorf("selector %v is not 4 bytes long", selector)
+		}
+		set[[4]byte(selector)] = struct{}{}
+	}
+	return set, nil
+}
+
+func compileRateLimit(limit AdmissionRateLimit) (admissionRateLimit, error) {
+	window, err := time.ParseDuration(limit.Window)
+	if err != nil {
+		return admissionRateLimit{}, fmt.Errorf("invalid rate limit window \"%v\": %w", limit.Window, err)
+	}
+	if window <= 0 {
+		return admissionRateLimit{}, fmt.Errorf("rate limit window \"%v\" must be positive", limit.Window)
+	}
+	return admissionRateLimit{maxTxs: limit.MaxTxs, window: window}, nil
+}
+
+func compileAdmissionRules(rules AdmissionRules, senderWhitelist []common.Address) (*compiledAdmissionRules, error) {
+	compiled := &compiledAdmissionRules{
+		source:          rules,
+		allowSenders:    addressSet(rules.AllowSenders, senderWhitelist),
+		denySenders:     addressSet(rules.DenySenders),
+		allowRecipients: addressSet(rules.AllowRecipients),
+		denyRecipients:  addressSet(rules.DenyRecipients),
+		rateOverrides:   make(map[common.Address]admissionRateLimit),
+	}
+	var err error
+	if compiled.allowSelectors, err = selectorSet(rules.AllowSelectors); err != nil {
+		return nil, err
+	}
+	if compiled.denySelectors, err = selectorSet(rules.DenySelectors); err != nil {
+		return nil, err
+	}
+	if rules.SenderRateLimit != nil {
+		limit, err := compileRateLimit(*rules.SenderRateLimit)
+		if err != nil {
+			return nil, err
+		}
+		compiled.rateLimit = &limit
+	}
+	for sender, override := range rules.SenderRateLimitOverrides {
+		limit, err := compileRateLimit(override)
+		if err != nil {
+			return nil, fmt.Errorf("sender %v: %w", sender, err)
+		}
+		compiled.rateOverrides[sender] = limit
+	}
+	return compiled, nil
+}
+
+// ParseAdmissionRules parses rules in YAML if the file name has a YAML extension, and JSON otherwise.
+func ParseAdmissionRules(fileName string, data []byte) (AdmissionRules, error) {
+	var rules AdmissionRules
+	ext := strings.ToLower(filepath.Ext(fileName))
+	if ext == ".yaml" || ext == ".yml" {
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		// An empty document is empty rules.
+		if err := decoder.Decode(&rules); err != nil && !errors.Is(err, io.EOF) {
+			return AdmissionRules{}, fmt.Errorf("error parsing admission rules yaml: %w", err)
+		}
+		return rules, nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&rules); err != nil {
+		return AdmissionRules{}, fmt.Errorf("error parsing admission rules json: %w", err)
+	}
+	return rules, nil
+}
+
+type rateWindow struct {
+	start time.Time
+	count uint64
+}
+
+// AdmissionPolicy decides whether the sequencer accepts a transaction into its queue.
+// Its rules can be swapped at any time, either from the rules file or over the admin RPC.
+type AdmissionPolicy struct {
+	senderWhitelist []common.Address
+	rules           atomic.Pointer[compiledAdmissionRules]
+
+	fileMutex   sync.Mutex
+	fileModTime time.Time
+
+	rateMutex   sync.Mutex
+	rateWindows map[common.Address]*rateWindow
+}
+
+func NewAdmissionPolicy(senderWhitelist []common.Address) (*AdmissionPolicy, error) {
+	p := &AdmissionPolicy{
+		senderWhitelist: senderWhitelist,
+		rateWindows:     make(map[common.Address]*rateWindow),
+	}
+	if err := p.SetRules(AdmissionRules{}); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *AdmissionPolicy) SetRules(rules AdmissionRules) error {
+	compiled, err := compileAdmissionRules(rules, p.senderWhitelist)
+	if err != nil {
+		return err
+	}
+	p.rules.Store(compiled)
+	admissionReloadCounter.Inc(1)
+	return nil
+}
+
+func (p *AdmissionPolicy) Rules() AdmissionRules {
+	return p.rules.Load().source
+}
+
+// ReloadFromFile loads the rules file if it was modified since it was last loaded, or unconditionally if force is set.
+func (p *AdmissionPolicy) ReloadFromFile(fileName string, force bool) error {
+	p.fileMutex.Lock()
+	defer p.fileMutex.Unlock()
+	info, err := os.Stat(fileName)
+	if err != nil {
+		return err
+	}
+	if !force && info.ModTime().Equal(p.fileModTime) {
+		return nil
+	}
+	data, err := os.ReadFile(fileName)
+	if err != nil {
+		return err
+	}
+	rules, err := ParseAdmissionRules(fileName, data)
+	if err != nil {
+		return err
+	}
+	if err := p.SetRules(rules); err != nil {
+		return err
+	}
+	p.fileModTime = info.ModTime()
+	log.Info("loaded sequencer admission rules", "file", fileName)
+	return nil
+}
+
+func (p *AdmissionPolicy) reject(reason string, format string, args ...any) error {
+	admissionRejectedCounter.Inc(1)
+	metrics.GetOrRegisterCounter("arb/sequencer/admission/rejected/"+reason, nil).Inc(1)
+	return &AdmissionError{Reason: reason, Detail: fmt.Sprintf(format, args...)}
+}
+
+// Check returns an *AdmissionError if the rules don't admit tx from sender. It doesn't count towards the sender's rate
+// limit, which Charge does once the tx passed every other check.
+func (p *AdmissionPolicy) Check(tx *types.Transaction, sender common.Address) error {
+	rules := p.rules.Load()
+	if _, denied := rules.denySenders[sender]; denied {
+		return p.reject(AdmissionSenderDenied, "sender %v is denied", sender)
+	}
+	if len(rules.allowSenders) > 0 {
+		if _, allowed := rules.allowSenders[sender]; !allowed {
+			return p.reject(AdmissionSenderNotAllowed, "transaction sender %v is not on the whitelist", sender)
+		}
+	}
+	to := tx.To()
+	if to != nil {
+		if _, denied := rules.denyRecipients[*to]; denied {
+			return p.reject(AdmissionRecipientDenied, "recipient %v is denied", *to)
+		}
+	}
+	if len(rules.allowRecipients) > 0 {
+		if to == nil {
+			return p.reject(AdmissionRecipientNotAllowed, "contract creation is not allowed")
+		}
+		if _, allowed := rules.allowRecipients[*to]; !allowed {
+			return p.reject(AdmissionRecipientNotAllowed, "recipient %v is not allowed", *to)
+		}
+	}
+	if to != nil && len(tx.Data()) >= 4 {
+		selector := [4]byte(tx.Data()[:4])
+		if _, denied := rules.denySelectors[selector]; denied {
+			return p.reject(AdmissionSelectorDenied, "selector %v is denied", hexutil.Bytes(selector[:]))
+		}
+		if len(rules.allowSelectors) > 0 {
+			if _, allowed := rules.allowSelectors[selector]; !allowed {
+				return p.reject(AdmissionSelectorNotAllowed, "selector %v is not allowed", hexutil.Bytes(selector[:]))
+			}
+		}
+	}
+	return nil
+}
+
+func (p *AdmissionPolicy) senderRateLimit(sender common.Address) (admissionRateLimit, bool) {
+	rules := p.rules.Load()
+	if limit, ok := rules.rateOverrides[sender]; ok {
+		return limit, true
+	}
+	if rules.rateLimit == nil {
+		return admissionRateLimit{}, false
+	}
+	return *rules.rateLimit, true
+}
+
+// Charge counts a tx of the sender towards its rate limit, returning an *AdmissionError if the sender has none left.
+func (p *AdmissionPolicy) Charge(sender common.Address) error {
+	limit, ok := p.senderRateLimit(sender)
+	if !ok {
+		return nil
+	}
+	now := time.Now()
+	p.rateMutex.Lock()
+	defer p.rateMutex.Unlock()
+	window, ok := p.rateWindows[sender]
+	if !ok || now.Sub(window.start) >= limit.window {
+		window = &rateWindow{start: now}
+		p.rateWindows[sender] = window
+	}
+	if window.count >= limit.maxTxs {
+		return p.reject(AdmissionRateLimited, "sender %v exceeded %v transactions per %v", sender, limit.maxTxs, limit.window)
+	}
+	window.count++
+	return nil
+}
+
+// Refund gives back the rate limit charged for a tx of the sender that didn't make it into the queue after all.
+func (p *AdmissionPolicy) Refund(sender common.Address) {
+	p.rateMutex.Lock()
+	defer p.rateMutex.Unlock()
+	if window, ok := p.rateWindows[sender]; ok && window.count > 0 {
+		window.count--
+	}
+}
+
+// pruneRateWindows forgets senders whose rate limit window has long passed.
+func (p *AdmissionPolicy) pruneRateWindows(maxAge time.Duration) {
+	now := time.Now()
+	p.rateMutex.Lock()
+	defer p.rateMutex.Unlock()
+	for sender, window := range p.rateWindows {
+		if now.Sub(window.start) > maxAge {
+			delete(p.rateWindows, sender)
+		}
+	}
+}
+
+// maxRateWindow returns the longest rate limit window of the current rules.
+func (p *AdmissionPolicy) maxRateWindow() time.Duration {
+	rules := p.rules.Load()
+	var longest time.Duration
+	if rules.rateLimit != nil {
+		longest = rules.rateLimit.window
+	}
+	for _, limit := range rules.rateOverrides {
+		if limit.window > longest {
+			longest = limit.window
+		}
+	}
+	return longest
+}
+
+type SequencerAdminAPI struct {
+	sequencer *Sequencer
+}
+
+func NewSequencerAdminAPI(sequencer *Sequencer) *SequencerAdminAPI {
+	return &SequencerAdminAPI{sequencer}
+}
+
+func (a *SequencerAdminAPI) GetAdmissionRules() AdmissionRules {
+	return a.sequencer.admissionPolicy.Rules()
+}
+
+// SetAdmissionRules replaces the admission rules until the rules file next changes.
+func (a *SequencerAdminAPI) SetAdmissionRules(rules AdmissionRules) error {
+	return a.sequencer.admissionPolicy.SetRules(rules)
+}
+
+func (a *SequencerAdminAPI) ReloadAdmissionRules() error {
+	rulesFile := a.sequencer.config().AdmissionPolicy.RulesFile
+	if rulesFile == "" {
+		return errors.New("no admission rules file configured")
+	}
+	return a.sequencer.admissionPolicy.ReloadFromFile(rulesFile, true)
+}
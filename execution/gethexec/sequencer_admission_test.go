@@ -0,0 +1,146 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+package gethexec
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func makeAdmissionTx(to *common.Address, data []byte) *types.Transaction {
+	return types.NewTx(&types.DynamicFeeTx{
+		To:   to,
+		Data: data,
+		Gas:  100000,
+	})
+}
+
+func requireAdmissionReason(t *testing.T, err error, reason string) {
+	t.Helper()
+	var admissionErr *AdmissionError
+	require.True(t, errors.As(err, &admissionErr), "expected admission error, got %v", err)
+	require.Equal(t, reason, admissionErr.Reason)
+	require.Equal(t, AdmissionRejectedErrorCode, admissionErr.ErrorCode())
+}
+
+// admit checks the tx against the rules, and charges the sender's rate limit if they admit it, like the sequencer.
+func admit(policy *AdmissionPolicy, tx *types.Transaction, sender common.Address) error {
+	if err := policy.Check(tx, sender); err != nil {
+		return err
+	}
+	return policy.Charge(sender)
+}
+
+func TestAdmissionPolicyRules(t *testing.T) {
+	whitelisted := common.Address{1}
+	denied := common.Address{2}
+	other := common.Address{3}
+	token := common.Address{10}
+	blocked := common.Address{11}
+	transfer := []byte{0xa9, 0x05, 0x9c, 0xbb, 0x00}
+	approve := []byte{0x09, 0x5e, 0xa7, 0xb3}
+
+	policy, err := NewAdmissionPolicy([]common.Address{whitelisted})
+	require.NoError(t, err)
+	require.NoError(t, admit(policy, makeAdmissionTx(&token, nil), whitelisted))
+	requireAdmissionReason(t, admit(policy, makeAdmissionTx(&token, nil), other), AdmissionSenderNotAllowed)
+
+	require.NoError(t, policy.SetRules(AdmissionRules{
+		AllowSenders:   []common.Address{denied, other},
+		DenySenders:    []common.Address{denied},
+		DenyRecipients: []common.Address{blocked},
+		DenySelectors:  []hexutil.Bytes{approve},
+		SenderRateLimit: &AdmissionRateLimit{
+			MaxTxs: 2,
+			Window: "1h",
+		},
+		SenderRateLimitOverrides: map[common.Address]AdmissionRateLimit{
+			whitelisted: {MaxTxs: 100, Window: "1h"},
+		},
+	}))
+	requireAdmissionReason(t, admit(policy, makeAdmissionTx(&token, nil), denied), AdmissionSenderDenied)
+	requireAdmissionReason(t, admit(policy, makeAdmissionTx(&blocked, nil), other), AdmissionRecipientDenied)
+	requireAdmissionReason(t, admit(policy, makeAdmissionTx(&token, approve), other), AdmissionSelectorDenied)
+	// Rejected txs don't count towards the rate limit.
+	for i := 0; i < 5; i++ {
+		requireAdmissionReason(t, admit(policy, makeAdmissionTx(&blocked, nil), other), AdmissionRecipientDenied)
+	}
+	require.NoError(t, admit(policy, makeAdmissionTx(&token, transfer), other))
+	require.NoError(t, admit(policy, makeAdmissionTx(nil, approve), other))
+	requireAdmissionReason(t, admit(policy, makeAdmissionTx(&token, transfer), other), AdmissionRateLimited)
+	// Neither do txs refunded because they didn't make it into the queue.
+	policy.Refund(other)
+	require.NoError(t, admit(policy, makeAdmissionTx(&token, transfer), other))
+	requireAdmissionReason(t, admit(policy, makeAdmissionTx(&token, transfer), other), AdmissionRateLimited)
+	for i := 0; i < 10; i++ {
+		require.NoError(t, admit(policy, makeAdmissionTx(&token, transfer), whitelisted))
+	}
+
+	policy.pruneRateWindows(0)
+	require.NoError(t, policy.SetRules(AdmissionRules{
+		AllowRecipients: []common.Address{token},
+		AllowSelectors:  []hexutil.Bytes{transfer[:4]},
+	}))
+	require.NoError(t, admit(policy, makeAdmissionTx(&token, transfer), whitelisted))
+	require.NoError(t, admit(policy, makeAdmissionTx(&token, nil), whitelisted))
+	requireAdmissionReason(t, admit(policy, makeAdmissionTx(&token, approve), whitelisted), AdmissionSelectorNotAllowed)
+	requireAdmissionReason(t, admit(policy, makeAdmissionTx(&blocked, transfer), whitelisted), AdmissionRecipientNotAllowed)
+	requireAdmissionReason(t, admit(policy, makeAdmissionTx(nil, nil), whitelisted), AdmissionRecipientNotAllowed)
+
+	require.Error(t, policy.SetRules(AdmissionRules{DenySelectors: []hexutil.Bytes{{0x01}}}))
+	require.Error(t, policy.SetRules(AdmissionRules{SenderRateLimit: &AdmissionRateLimit{MaxTxs: 1, Window: "soon"}}))
+}
+
+func TestAdmissionPolicyReloadFromFile(t *testing.T) {
+	sender := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	recipient := common.HexToAddress("0x00000000000000000000000000000000000000bb")
+	dir := t.TempDir()
+
+	jsonFile := filepath.Join(dir, "rules.json")
+	require.NoError(t, os.WriteFile(jsonFile, []byte(`{"denySenders": ["0x00000000000000000000000000000000000000aa"]}`), 0600))
+	policy, err := NewAdmissionPolicy(nil)
+	require.NoError(t, err)
+	require.NoError(t, policy.ReloadFromFile(jsonFile, false))
+	requireAdmissionReason(t, policy.Check(makeAdmissionTx(&recipient, nil), sender), AdmissionSenderDenied)
+
+	require.NoError(t, os.WriteFile(jsonFile, []byte(`{"denySenders": []}`), 0600))
+	require.NoError(t, os.Chtimes(jsonFile, time.Now(), time.Now().Add(time.Minute)))
+	require.NoError(t, policy.ReloadFromFile(jsonFile, false))
+	require.NoError(t, policy.Check(makeAdmissionTx(&recipient, nil), sender))
+
+	require.NoError(t, os.WriteFile(jsonFile, []byte(`{"unknownField": 1}`), 0600))
+	require.Error(t, policy.ReloadFromFile(jsonFile, true))
+	require.NoError(t, policy.Check(makeAdmissionTx(&recipient, nil), sender))
+
+	yamlFile := filepath.Join(dir, "rules.yaml")
+	yamlRules := `
+denyRecipients:
+  - "0x00000000000000000000000000000000000000bb"
+senderRateLimit:
+  maxTxs: 5
+  window: 1m
+`
+	require.NoError(t, os.WriteFile(yamlFile, []byte(yamlRules), 0600))
+	require.NoError(t, policy.ReloadFromFile(yamlFile, true))
+	requireAdmissionReason(t, policy.Check(makeAdmissionTx(&recipient, nil), sender), AdmissionRecipientDenied)
+	require.Equal(t, time.Minute, policy.maxRateWindow())
+
+	// Misspelled fields are rejected rather than ignored, keeping the previous rules.
+	require.NoError(t, os.WriteFile(yamlFile, []byte("denyRecipient:\n  - \"0x00000000000000000000000000000000000000bb\"\n"), 0600))
+	require.Error(t, policy.ReloadFromFile(yamlFile, true))
+	requireAdmissionReason(t, policy.Check(makeAdmissionTx(&recipient, nil), sender), AdmissionRecipientDenied)
+
+	require.NoError(t, os.WriteFile(yamlFile, nil, 0600))
+	require.NoError(t, policy.ReloadFromFile(yamlFile, true))
+	require.NoError(t, policy.Check(makeAdmissionTx(&recipient, nil), sender))
+}
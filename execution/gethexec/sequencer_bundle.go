@@ -0,0 +1,295 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+package gethexec
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+
+	"github.com/offchainlabs/nitro/arbos/arbosState"
+	"github.com/offchainlabs/nitro/execution"
+)
+
+var (
+	bundleAcceptedCounter = metrics.NewRegisteredCounter("arb/sequencer/bundle/accepted", nil)
+	bundleRejectedCounter = metrics.NewRegisteredCounter("arb/sequencer/bundle/rejected", nil)
+)
+
+var (
+	ErrEmptyBundle      = errors.New("bundle has no transactions")
+	ErrBundlesDisabled  = errors.New("bundles are not enabled on this sequencer")
+	ErrBundleTxReverted = errors.New("transaction reverted in revert protected bundle")
+)
+
+// TxBundle is an ordered list of transactions that are sequenced back to back in a single block, or not at all.
+// With RevertProtect set, a reverted transaction fails the bundle as well.
+type TxBundle struct {
+	Txs           types.Transactions
+	RevertProtect bool
+}
+
+// BundleArgs is the arb_sendBundle representation of a TxBundle.
+type BundleArgs struct {
+	Txs           []hexutil.Bytes `json:"txs"`
+	RevertProtect bool            `json:"revertProtect,omitempty"`
+}
+
+func (a *BundleArgs) ToBundle() (*TxBundle, error) {
+	bundle := &TxBundle{
+		Txs:           make(types.Transactions, 0, len(a.Txs)),
+		RevertProtect: a.RevertProtect,
+	}
+	for i, encoded := range a.Txs {
+		tx := new(types.Transaction)
+		if err := tx.UnmarshalBinary(encoded); err != nil {
+			return nil, fmt.Errorf("failed to decode bundle transaction %d: %w", i, err)
+		}
+		bundle.Txs = append(bundle.Txs, tx)
+	}
+	return bundle, nil
+}
+
+func (b *TxBundle) ToArgs() (*BundleArgs, error) {
+	args := &BundleArgs{
+		Txs:           make([]hexutil.Bytes, 0, len(b.Txs)),
+		RevertProtect: b.RevertProtect,
+	}
+	for _, tx := range b.Txs {
+		encoded, err := tx.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		args.Txs = append(args.Txs, encoded)
+	}
+	return args, nil
+}
+
+func (b *TxBundle) Hashes() []common.Hash {
+	hashes := make([]common.Hash, len(b.Txs))
+	for i, tx := range b.Txs {
+		hashes[i] = tx.Hash()
+	}
+	return hashes
+}
+
+// BundleTxError is returned when a bundle is dropped because one of its transactions failed.
+type BundleTxError struct {
+	Index  int
+	TxHash common.Hash
+	Err    error
+}
+
+func (e *BundleTxError) Error() string {
+	return fmt.Sprintf("bundle dropped: transaction %d (%v) failed: %v", e.Index, e.TxHash, e.Err)
+}
+
+func (e *BundleTxError) Unwrap() error {
+	return e.Err
+}
+
+func (c *TxPreChecker) PublishBundle(ctx context.Context, bundle *TxBundle) error {
+	block := c.bc.CurrentBlock()
+	statedb, err := c.bc.StateAt(block.Root)
+	if err != nil {
+		return err
+	}
+	arbos, err := arbosState.OpenSystemArbosState(statedb, nil, true)
+	if err != nil {
+		return err
+	}
+	// Later transactions of a bundle may depend on earlier ones (e.g. consecutive nonces or funding),
+	// so only run the checks which never reject a transaction that would succeed.
+	config := *c.config()
+	if config.Strictness > TxPreCheckerStrictnessAlwaysCompatible {
+		config.Strictness = TxPreCheckerStrictnessAlwaysCompatible
+	}
+	for i, tx := range bundle.Txs {
+		if err := PreCheckTx(c.bc, c.bc.Config(), block, statedb, arbos, tx, nil, &config); err != nil {
+			return &BundleTxError{Index: i, TxHash: tx.Hash(), Err: err}
+		}
+	}
+	return c.TransactionPublisher.PublishBundle(ctx, bundle)
+}
+
+func (s *Sequencer) PublishBundle(parentCtx context.Context, bundle *TxBundle) error {
+	_, forwarder := s.GetPauseAndForwarder()
+	if forwarder != nil {
+		err := forwarder.PublishBundle(parentCtx, bundle)
+		if !errors.Is(err, ErrNoSequencer) {
+			return err
+		}
+	}
+
+	config := s.config()
+	if config.MaxBundleTxs <= 0 {
+		return ErrBundlesDisabled
+	}
+	if len(bundle.Txs) == 0 {
+		return ErrEmptyBundle
+	}
+	if len(bundle.Txs) > config.MaxBundleTxs {
+		return fmt.Errorf("bundle has %d transactions, more than the maximum of %d", len(bundle.Txs), config.MaxBundleTxs)
+	}
+	if err := s.checkExpectedSurplus(config); err != nil {
+		return err
+	}
+
+	signer := types.LatestSigner(s.execEngine.bc.Config())
+	var totalSize int
+	senders := make([]common.Address, 0, len(bundle.Txs))
+	for i, tx := range bundle.Txs {
+		if tx.Type() >= types.ArbitrumDepositTxType || tx.Type() == types.BlobTxType {
+			return &BundleTxError{Index: i, TxHash: tx.Hash(), Err: types.ErrTxTypeNotSupported}
+		}
+		sender, err := types.Sender(signer, tx)
+		if err != nil {
+			return &BundleTxError{Index: i, TxHash: tx.Hash(), Err: err}
+		}
+		if err := s.admissionPolicy.Check(tx, sender); err != nil {
+			return err
+		}
+		txBytes, err := tx.MarshalBinary()
+		if err != nil {
+			return err
+		}
+		totalSize += len(txBytes)
+		senders = append(senders, sender)
+	}
+	refund := func(senders []common.Address) {
+		for _, sender := range senders {
+			s.admissionPolicy.Refund(sender)
+		}
+	}
+	// The rate limits are only charged once the whole bundle passed the checks.
+	for i, sender := range senders {
+		if err := s.admissionPolicy.Charge(sender); err != nil {
+			refund(senders[:i])
+			return err
+		}
+	}
+
+	sequencerBacklogGauge.Inc(1)
+	defer sequencerBacklogGauge.Dec(1)
+
+	queueTimeout := config.QueueTimeout
+	queueCtx, cancelFunc := ctxWithTimeout(parentCtx, queueTimeout)
+	defer cancelFunc()
+
+	resultChan := make(chan error, 1)
+	queueItem := txQueueItem{
+		tx:              bundle.Txs[0],
+		txSize:          totalSize,
+		resultChan:      resultChan,
+		returnedResult:  &atomic.Bool{},
+		ctx:             queueCtx,
+		firstAppearance: time.Now(),
+		bundle:          bundle,
+	}
+	bundleTxs := make([]queuedTx, len(bundle.Txs))
+	for i, tx := range bundle.Txs {
+		bundleTxs[i] = queuedTx{tx: tx}
+	}
+	queueItem.untrack = s.queuedTxs.add(bundleTxs...)
+	// Bundles aren't written to the queue journal: replaying their transactions one by one would break the
+	// bundle's atomicity, so a bundle queued during a crash is lost and its submitter sees the request fail.
+	select {
+	case s.txQueue <- queueItem:
+	case <-queueCtx.Done():
+		refund(senders)
+		queueItem.untrack()
+		return queueCtx.Err()
+	}
+	return waitForQueueResult(parentCtx, queueTimeout, resultChan, bundle.Txs[0].Hash())
+}
+
+// sequenceBundle sequences a bundle in a block of its own.
+// If any of its transactions fails, the block filter rejects the whole block so none of the bundle is included.
+func (s *Sequencer) sequenceBundle(ctx context.Context, queueItem txQueueItem) bool {
+	config := s.config()
+	bundle := queueItem.bundle
+
+	s.nonceCache.Resize(config.NonceCacheSize)
+	s.nonceCache.BeginNewBlock()
+
+	if s.handleInactive(ctx, []txQueueItem{queueItem}) {
+		return false
+	}
+
+	header := s.nextL2MessageHeader(config)
+	if header == nil {
+		s.txRetryQueue.Push(queueItem)
+		return true
+	}
+
+	bundleIndices := make(map[common.Hash]int, len(bundle.Txs))
+	for i, tx := range bundle.Txs {
+		bundleIndices[tx.Hash()] = i
+	}
+	hooks := s.makeSequencingHooks()
+	hooks.BlockFilter = func(_ *types.Header, _ *state.StateDB, txs types.Transactions, receipts types.Receipts) error {
+		for i, err := range hooks.TxErrors {
+			if err != nil {
+				return &BundleTxError{Index: i, TxHash: bundle.Txs[i].Hash(), Err: err}
+			}
+		}
+		if bundle.RevertProtect {
+			for i, tx := range txs {
+				idx, inBundle := bundleIndices[tx.Hash()]
+				if inBundle && receipts[i].Status != types.ReceiptStatusSuccessful {
+					return &BundleTxError{Index: idx, TxHash: tx.Hash(), Err: ErrBundleTxReverted}
+				}
+			}
+		}
+		return nil
+	}
+
+	start := time.Now()
+	block, err := s.execEngine.SequenceTransactions(header, bundle.Txs, hooks, nil)
+	blockCreationTimer.Update(time.Since(start))
+	if errors.Is(err, execution.ErrRetrySequencer) {
+		log.Warn("error sequencing bundle", "err", err)
+		if s.handleInactive(ctx, []txQueueItem{queueItem}) {
+			return false
+		}
+		s.txRetryQueue.Push(queueItem)
+		return false
+	}
+	if errors.Is(err, context.Canceled) {
+		s.txRetryQueue.Push(queueItem)
+		return true
+	}
+	var bundleErr *BundleTxError
+	if errors.As(err, &bundleErr) {
+		bundleRejectedCounter.Inc(1)
+		queueItem.returnResult(bundleErr)
+		return false
+	}
+	if err != nil {
+		log.Error("error sequencing bundle", "err", err)
+		bundleRejectedCounter.Inc(1)
+		queueItem.returnResult(err)
+		return false
+	}
+	if block == nil {
+		// Unreachable as the block filter rejects bundles where every transaction failed
+		bundleRejectedCounter.Inc(1)
+		queueItem.returnResult(errors.New("bundle was not sequenced"))
+		return false
+	}
+	successfulBlocksCounter.Inc(1)
+	bundleAcceptedCounter.Inc(1)
+	s.nonceCache.Finalize(block)
+	queueItem.returnResult(nil)
+	return true
+}
@@ -0,0 +1,562 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+package gethexec
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	flag "github.com/spf13/pflag"
+
+	"github.com/ethereum/go-ethereum/arbitrum_types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+
+	"github.com/offchainlabs/nitro/util/redisutil"
+)
+
+var (
+	queueJournalReplayedCounter = metrics.NewRegisteredCounter("arb/sequencer/journal/replayed", nil)
+	queueJournalDroppedCounter  = metrics.NewRegisteredCounter("arb/sequencer/journal/dropped", nil)
+	queueJournalErrorCounter    = metrics.NewRegisteredCounter("arb/sequencer/journal/errors", nil)
+)
+
+const SEQUENCER_QUEUE_JOURNAL_KEY string = "sequencer.queueJournal" // Only written by the active sequencer
+
+const queueJournalFileName = "sequencer-queue.journal"
+
+// queueJournalBufferSize is the number of records waiting to be written to the journal before submissions block,
+// and queueJournalMaxBatch the most records written (and synced) at once.
+const (
+	queueJournalBufferSize = 16 * 1024
+	queueJournalMaxBatch   = 1024
+)
+
+type QueueJournalConfig struct {
+	Enable    bool          `koanf:"enable"`
+	Directory string        `koanf:"directory"`
+	RedisUrl  string        `koanf:"redis-url"`
+	Fsync     bool          `koanf:"fsync"`
+	Timeout   time.Duration `koanf:"timeout"`
+}
+
+var DefaultQueueJournalConfig = QueueJournalConfig{
+	Enable:    false,
+	Directory: "",
+	RedisUrl:  "",
+	Fsync:     true,
+	Timeout:   time.Second,
+}
+
+func QueueJournalConfigAddOptions(prefix string, f *flag.FlagSet) {
+	f.Bool(prefix+".enable", DefaultQueueJournalConfig.Enable, "journal transactions before they enter the sequencer queue so they are replayed when the sequencer is next activated; transactions are rejected if they can't be journaled, and arb_sendBundle bundles aren't journaled")
+	f.String(prefix+".directory", DefaultQueueJournalConfig.Directory, "directory to keep the queue journal in (exclusive with redis-url)")
+	f.String(prefix+".redis-url", DefaultQueueJournalConfig.RedisUrl, "Redis URL to keep the queue journal in, allowing the next chosen sequencer to replay it (exclusive with directory)")
+	f.Bool(prefix+".fsync", DefaultQueueJournalConfig.Fsync, "sync the on-disk journal after every batch of writes, before the transactions in it enter the queue; transactions submitted at the same time share a sync, and without it an OS crash can lose recently journaled transactions")
+	f.Duration(prefix+".timeout", DefaultQueueJournalConfig.Timeout, "timeout for a single Redis journal operation")
+}
+
+func (c *QueueJournalConfig) Validate() error {
+	if !c.Enable {
+		return nil
+	}
+	if (c.Directory == "") == (c.RedisUrl == "") {
+		return errors.New("sequencer queue journal is enabled but exactly one of directory and redis-url must be set")
+	}
+	return nil
+}
+
+// QueueJournalEntry is a transaction that was accepted into the sequencer queue but might not have been sequenced yet.
+type QueueJournalEntry struct {
+	Hash     common.Hash                        `json:"hash"`
+	Tx       hexutil.Bytes                      `json:"tx"`
+	Options  *arbitrum_types.ConditionalOptions `json:"options,omitempty"`
+	Accepted time.Time                          `json:"accepted"`
+}
+
+func NewQueueJournalEntry(tx *types.Transaction, options *arbitrum_types.ConditionalOptions) (*QueueJournalEntry, error) {
+	encoded, err := tx.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return &QueueJournalEntry{
+		Hash:     tx.Hash(),
+		Tx:       encoded,
+		Options:  options,
+		Accepted: time.Now(),
+	}, nil
+}
+
+// QueueJournalRecord adds or removes a journal entry.
+type QueueJournalRecord struct {
+	Add    *QueueJournalEntry `json:"add,omitempty"`
+	Remove *common.Hash       `json:"remove,omitempty"`
+}
+
+// QueueJournal persists the sequencer queue. Records are applied in order, and adding an entry with a hash
+// that's already present replaces it.
+type QueueJournal interface {
+	Write(ctx context.Context, records []QueueJournalRecord) error
+	Entries(ctx context.Context) ([]*QueueJournalEntry, error)
+	Close() error
+}
+
+func NewQueueJournal(config *QueueJournalConfig) (QueueJournal, error) {
+	if !config.Enable {
+		return nil, nil
+	}
+	if config.RedisUrl != "" {
+		return NewRedisQueueJournal(config.RedisUrl)
+	}
+	return NewDiskQueueJournal(config.Directory, config.Fsync)
+}
+
+// DiskQueueJournal is an append-only log of additions and removals, compacted when it is opened
+// and whenever removed records make up most of the file.
+type DiskQueueJournal struct {
+	mutex   sync.Mutex
+	path    string
+	fsync   bool
+	file    *os.File
+	entries map[common.Hash]*QueueJournalEntry
+	records int
+}
+
+func NewDiskQueueJournal(dir string, fsync bool) (*DiskQueueJournal, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	j := &DiskQueueJournal{
+		path:    filepath.Join(dir, queueJournalFileName),
+		fsync:   fsync,
+		entries: make(map[common.Hash]*QueueJournalEntry),
+	}
+	if err := j.load(); err != nil {
+		return nil, err
+	}
+	if err := j.compact(); err != nil {
+		return nil, err
+	}
+	return j, nil
+}
+
+func (j *DiskQueueJournal) load() error {
+	file, err := os.Open(j.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		var record QueueJournalRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			// Most likely the last record was only partially written before a crash
+			log.Warn("skipping corrupt sequencer queue journal record", "path", j.path, "line", line, "err", err)
+			continue
+		}
+		if record.Add != nil {
+			j.entries[record.Add.Hash] = record.Add
+		}
+		if record.Remove != nil {
+			delete(j.entries, *record.Remove)
+		}
+	}
+	return scanner.Err()
+}
+
+// compact rewrites the journal with only its live entries. The caller must hold the mutex or have exclusive access.
+func (j *DiskQueueJournal) compact() error {
+	tmpPath := j.path + ".tmp"
+	tmp, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	writer := bufio.NewWriter(tmp)
+	for _, entry := range j.entries {
+		data, err := json.Marshal(QueueJournalRecord{Add: entry})
+		if err != nil {
+			tmp.Close()
+			return err
+		}
+		if _, err := writer.Write(append(data, '\n')); err != nil {
+			tmp.Close()
+			return err
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if j.file != nil {
+		if err := j.file.Close(); err != nil {
+			log.Warn("error closing old sequencer queue journal", "err", err)
+		}
+		j.file = nil
+	}
+	if err := os.Rename(tmpPath, j.path); err != nil {
+		return err
+	}
+	j.file, err = os.OpenFile(j.path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	j.records = len(j.entries)
+	return nil
+}
+
+func (j *DiskQueueJournal) Write(_ context.Context, records []QueueJournalRecord) error {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	if j.file == nil {
+		return errors.New("sequencer queue journal is closed")
+	}
+	var data []byte
+	for _, record := range records {
+		if record.Add != nil {
+			j.entries[record.Add.Hash] = record.Add
+		} else if record.Remove != nil {
+			if _, ok := j.entries[*record.Remove]; !ok {
+				continue
+			}
+			delete(j.entries, *record.Remove)
+		} else {
+			continue
+		}
+		encoded, err := json.Marshal(record)
+		if err != nil {
+			return err
+		}
+		data = append(append(data, encoded...), '\n')
+		j.records++
+	}
+	if len(data) == 0 {
+		return nil
+	}
+	if _, err := j.file.Write(data); err != nil {
+		return err
+	}
+	if j.fsync {
+		if err := j.file.Sync(); err != nil {
+			return err
+		}
+	}
+	if j.records > 2*len(j.entries)+1024 {
+		return j.compact()
+	}
+	return nil
+}
+
+func (j *DiskQueueJournal) Entries(_ context.Context) ([]*QueueJournalEntry, error) {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	entries := make([]*QueueJournalEntry, 0, len(j.entries))
+	for _, entry := range j.entries {
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+func (j *DiskQueueJournal) Close() error {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	if j.file == nil {
+		return nil
+	}
+	err := j.file.Close()
+	j.file = nil
+	return err
+}
+
+// RedisQueueJournal keeps the journal in a Redis hash shared by all sequencers, so whichever becomes chosen next replays it.
+type RedisQueueJournal struct {
+	client redis.UniversalClient
+}
+
+func NewRedisQueueJournal(redisUrl string) (*RedisQueueJournal, error) {
+	client, err := redisutil.RedisClientFromURL(redisUrl)
+	if err != nil {
+		return nil, err
+	}
+	return &RedisQueueJournal{client: client}, nil
+}
+
+func (j *RedisQueueJournal) Write(ctx context.Context, records []QueueJournalRecord) error {
+	_, err := j.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, record := range records {
+			if record.Add != nil {
+				data, err := json.Marshal(record.Add)
+				if err != nil {
+					return err
+				}
+				pipe.HSet(ctx, SEQUENCER_QUEUE_JOURNAL_KEY, record.Add.Hash.Hex(), data)
+			} else if record.Remove != nil {
+				pipe.HDel(ctx, SEQUENCER_QUEUE_JOURNAL_KEY, record.Remove.Hex())
+			}
+		}
+		return nil
+	})
+	return err
+}
+
+func (j *RedisQueueJournal) Entries(ctx context.Context) ([]*QueueJournalEntry, error) {
+	values, err := j.client.HGetAll(ctx, SEQUENCER_QUEUE_JOURNAL_KEY).Result()
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]*QueueJournalEntry, 0, len(values))
+	for field, value := range values {
+		var entry QueueJournalEntry
+		if err := json.Unmarshal([]byte(value), &entry); err != nil {
+			log.Warn("skipping corrupt sequencer queue journal entry", "field", field, "err", err)
+			continue
+		}
+		entries = append(entries, &entry)
+	}
+	return entries, nil
+}
+
+func (j *RedisQueueJournal) Close() error {
+	return j.client.Close()
+}
+
+// queueJournalWrite is a record waiting to be written to the journal, with the channel to report the write's
+// result on, if anyone waits for it.
+type queueJournalWrite struct {
+	record QueueJournalRecord
+	done   chan error
+}
+
+// journalAdd journals a transaction about to enter the queue, and returns once the record is written, so that every
+// queued transaction is replayed after a crash. Transactions submitted at the same time share a single write and sync.
+//
+// Every transaction entering the queue through publishTransactionToQueue is journaled, including express lane
+// transactions once the express lane service releases them in sequence order, and they're replayed as regular
+// transactions. These aren't journaled:
+//   - arb_sendBundle bundles, as replaying their transactions one by one would break the bundle's atomicity.
+//     Bundles queued during a crash are lost, and their submitters see the request fail.
+//   - express lane submissions buffered until the submissions before them arrive. The express lane service
+//     persists those to Redis when it's configured with one.
+func (s *Sequencer) journalAdd(ctx context.Context, tx *types.Transaction, options *arbitrum_types.ConditionalOptions) error {
+	if s.queueJournal == nil {
+		return nil
+	}
+	entry, err := NewQueueJournalEntry(tx, options)
+	if err != nil {
+		queueJournalErrorCounter.Inc(1)
+		return fmt.Errorf("error encoding transaction for sequencer queue journal: %w", err)
+	}
+	done := make(chan error, 1)
+	select {
+	case s.journalRecords <- queueJournalWrite{record: QueueJournalRecord{Add: entry}, done: done}:
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-s.GetContext().Done():
+		return errors.New("sequencer is stopping")
+	}
+	select {
+	case err := <-done:
+		if err != nil {
+			return fmt.Errorf("error writing sequencer queue journal: %w", err)
+		}
+		return nil
+	case <-ctx.Done():
+		// The record might still be written, so remove it again
+		s.journalDone(tx.Hash())
+		return ctx.Err()
+	}
+}
+
+// journalResult returns the result channel for the queue item of a journaled transaction. Once the item gets its
+// result, or the channel is closed without one, the journal entry is removed and the result passed on to resultChan.
+func (s *Sequencer) journalResult(txHash common.Hash, resultChan chan error) chan error {
+	itemResultChan := make(chan error, 1)
+	s.LaunchUntrackedThread(func() {
+		err, ok := <-itemResultChan
+		s.journalDone(txHash)
+		if ok {
+			resultChan <- err
+		}
+		close(resultChan)
+	})
+	return itemResultChan
+}
+
+// journalDone removes a transaction whose queue item got a result. Removals don't wait for the write, as a lost
+// removal only causes a redundant replay, which is dropped by its nonce.
+// While the sequencer is shutting down the entry is kept so it's replayed on the next activation.
+func (s *Sequencer) journalDone(txHash common.Hash) {
+	if s.queueJournal == nil || s.Stopped() {
+		return
+	}
+	select {
+	case s.journalRecords <- queueJournalWrite{record: QueueJournalRecord{Remove: &txHash}}:
+	case <-s.GetContext().Done():
+	}
+}
+
+func (s *Sequencer) writeQueueJournal(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case write := <-s.journalRecords:
+			s.flushQueueJournal(write)
+		}
+	}
+}
+
+// flushQueueJournal writes the given records along with the ones waiting to be written, so that transactions
+// submitted at the same time share a single write and sync, and reports the result to the writes waiting for it.
+func (s *Sequencer) flushQueueJournal(writes ...queueJournalWrite) {
+collect:
+	for len(writes) < queueJournalMaxBatch {
+		select {
+		case write := <-s.journalRecords:
+			writes = append(writes, write)
+		default:
+			break collect
+		}
+	}
+	if len(writes) == 0 {
+		return
+	}
+	records := make([]QueueJournalRecord, len(writes))
+	for i, write := range writes {
+		records[i] = write.record
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), s.config().QueueJournal.Timeout)
+	defer cancel()
+	err := s.queueJournal.Write(ctx, records)
+	if err != nil {
+		queueJournalErrorCounter.Inc(1)
+		log.Error("error writing sequencer queue journal, rejecting the transactions in the write", "records", len(records), "err", err)
+	}
+	for _, write := range writes {
+		if write.done != nil {
+			write.done <- err
+		}
+	}
+}
+
+type journaledTx struct {
+	tx      *types.Transaction
+	options *arbitrum_types.ConditionalOptions
+}
+
+// planQueueJournalReplay returns the journaled transactions to replay in the order they were first accepted,
+// and the hashes of the entries to drop because they're invalid or their nonce was already used.
+func planQueueJournalReplay(entries []*QueueJournalEntry, signer types.Signer, nonceAt func(common.Address) uint64) ([]journaledTx, []common.Hash) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Accepted.Before(entries[j].Accepted)
+	})
+	var replay []journaledTx
+	var dropped []common.Hash
+	seen := make(map[common.Hash]struct{}, len(entries))
+	for _, entry := range entries {
+		tx := new(types.Transaction)
+		err := tx.UnmarshalBinary(entry.Tx)
+		if err == nil && tx.Hash() != entry.Hash {
+			err = fmt.Errorf("journaled hash %v doesn't match transaction hash %v", entry.Hash, tx.Hash())
+		}
+		var sender common.Address
+		if err == nil {
+			sender, err = types.Sender(signer, tx)
+		}
+		if err != nil {
+			log.Warn("dropping invalid sequencer queue journal entry", "txHash", entry.Hash, "err", err)
+			dropped = append(dropped, entry.Hash)
+			continue
+		}
+		if _, ok := seen[entry.Hash]; ok {
+			continue
+		}
+		seen[entry.Hash] = struct{}{}
+		if nonceAt(sender) > tx.Nonce() {
+			dropped = append(dropped, entry.Hash)
+			continue
+		}
+		replay = append(replay, journaledTx{tx: tx, options: entry.Options})
+	}
+	return replay, dropped
+}
+
+// replayQueueJournal requeues the journaled transactions in the order they were first accepted.
+// Transactions whose nonce was already used are dropped from the journal.
+func (s *Sequencer) replayQueueJournal(ctx context.Context) {
+	config := s.config()
+	readCtx, cancel := context.WithTimeout(ctx, config.QueueJournal.Timeout)
+	entries, err := s.queueJournal.Entries(readCtx)
+	cancel()
+	if err != nil {
+		queueJournalErrorCounter.Inc(1)
+		log.Error("error reading sequencer queue journal", "err", err)
+		return
+	}
+	if len(entries) == 0 {
+		return
+	}
+	header := s.execEngine.bc.CurrentBlock()
+	statedb, err := s.execEngine.bc.StateAt(header.Root)
+	if err != nil {
+		log.Error("error opening state to replay sequencer queue journal", "err", err)
+		return
+	}
+	signer := types.LatestSigner(s.execEngine.bc.Config())
+	replay, dropped := planQueueJournalReplay(entries, signer, statedb.GetNonce)
+	for _, txHash := range dropped {
+		queueJournalDroppedCounter.Inc(1)
+		s.journalDone(txHash)
+	}
+	log.Info("replaying sequencer queue journal", "entries", len(replay), "dropped", len(dropped))
+	for _, journaled := range replay {
+		if ctx.Err() != nil {
+			return
+		}
+		tx := journaled.tx
+		queueTimeout := config.QueueTimeout
+		queueCtx, cancelFunc := ctxWithTimeout(ctx, queueTimeout)
+		resultChan := make(chan error, 1)
+		if err := s.publishTransactionToQueue(queueCtx, tx, journaled.options, resultChan, false); err != nil {
+			cancelFunc()
+			log.Warn("failed to replay journaled transaction", "txHash", tx.Hash(), "err", err)
+			queueJournalDroppedCounter.Inc(1)
+			s.journalDone(tx.Hash())
+			continue
+		}
+		queueJournalReplayedCounter.Inc(1)
+		s.LaunchUntrackedThread(func() {
+			defer cancelFunc()
+			// The journal entry is removed once the transaction gets its result
+			err := waitForQueueResult(ctx, queueTimeout, resultChan, tx.Hash())
+			if err != nil {
+				log.Debug("replayed journaled transaction failed", "txHash", tx.Hash(), "err", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,221 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+package gethexec
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/offchainlabs/nitro/util/redisutil"
+)
+
+func makeJournalEntry(t *testing.T, nonce uint64) *QueueJournalEntry {
+	t.Helper()
+	to := common.Address{1}
+	tx := types.NewTx(&types.LegacyTx{
+		Nonce:    nonce,
+		To:       &to,
+		Gas:      21000,
+		GasPrice: big.NewInt(1),
+	})
+	entry, err := NewQueueJournalEntry(tx, nil)
+	require.NoError(t, err)
+	return entry
+}
+
+func requireJournalHashes(t *testing.T, journal QueueJournal, expected ...common.Hash) {
+	t.Helper()
+	entries, err := journal.Entries(context.Background())
+	require.NoError(t, err)
+	var hashes []common.Hash
+	for _, entry := range entries {
+		hashes = append(hashes, entry.Hash)
+	}
+	require.ElementsMatch(t, expected, hashes)
+}
+
+func testQueueJournal(t *testing.T, journal QueueJournal, reopen func() QueueJournal) {
+	ctx := context.Background()
+	first := makeJournalEntry(t, 0)
+	second := makeJournalEntry(t, 1)
+	third := makeJournalEntry(t, 2)
+
+	require.NoError(t, journal.Write(ctx, []QueueJournalRecord{{Add: first}, {Add: second}}))
+	require.NoError(t, journal.Write(ctx, []QueueJournalRecord{{Add: second}, {Add: third}, {Remove: &first.Hash}}))
+	require.NoError(t, journal.Write(ctx, []QueueJournalRecord{{Remove: &common.Hash{}}}))
+	requireJournalHashes(t, journal, second.Hash, third.Hash)
+
+	require.NoError(t, journal.Close())
+	journal = reopen()
+	requireJournalHashes(t, journal, second.Hash, third.Hash)
+	entries, err := journal.Entries(ctx)
+	require.NoError(t, err)
+	for _, entry := range entries {
+		tx := new(types.Transaction)
+		require.NoError(t, tx.UnmarshalBinary(entry.Tx))
+		require.Equal(t, entry.Hash, tx.Hash())
+	}
+	require.NoError(t, journal.Close())
+}
+
+func TestDiskQueueJournal(t *testing.T) {
+	dir := t.TempDir()
+	open := func() QueueJournal {
+		journal, err := NewDiskQueueJournal(dir, true)
+		require.NoError(t, err)
+		return journal
+	}
+	testQueueJournal(t, open(), open)
+
+	// A record cut short by a crash is skipped
+	entry := makeJournalEntry(t, 3)
+	journal := open()
+	require.NoError(t, journal.Write(context.Background(), []QueueJournalRecord{{Add: entry}}))
+	require.NoError(t, journal.Close())
+	file, err := os.OpenFile(filepath.Join(dir, queueJournalFileName), os.O_APPEND|os.O_WRONLY, 0644)
+	require.NoError(t, err)
+	_, err = file.WriteString(`{"add":{"hash":"0x12`)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+	journal = open()
+	entries, err := journal.Entries(context.Background())
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	require.NoError(t, journal.Close())
+}
+
+func TestRedisQueueJournal(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	redisUrl := redisutil.CreateTestRedis(ctx, t)
+	open := func() QueueJournal {
+		journal, err := NewRedisQueueJournal(redisUrl)
+		require.NoError(t, err)
+		return journal
+	}
+	testQueueJournal(t, open(), open)
+}
+
+type failingQueueJournal struct {
+	QueueJournal
+	err error
+}
+
+func (j *failingQueueJournal) Write(ctx context.Context, records []QueueJournalRecord) error {
+	if j.err != nil {
+		return j.err
+	}
+	return j.QueueJournal.Write(ctx, records)
+}
+
+func TestFlushQueueJournalReportsWrites(t *testing.T) {
+	inner, err := NewDiskQueueJournal(t.TempDir(), true)
+	require.NoError(t, err)
+	defer inner.Close()
+	journal := &failingQueueJournal{QueueJournal: inner}
+	config := DefaultSequencerConfig
+	s := &Sequencer{
+		config:         func() *SequencerConfig { return &config },
+		queueJournal:   journal,
+		journalRecords: make(chan queueJournalWrite, queueJournalBufferSize),
+	}
+	write := func(nonce uint64) chan error {
+		done := make(chan error, 1)
+		s.journalRecords <- queueJournalWrite{record: QueueJournalRecord{Add: makeJournalEntry(t, nonce)}, done: done}
+		return done
+	}
+
+	// Every waiting submission learns its record was written
+	first, second := write(0), write(1)
+	s.flushQueueJournal()
+	require.NoError(t, <-first)
+	require.NoError(t, <-second)
+	requireJournalHashes(t, inner, makeJournalEntry(t, 0).Hash, makeJournalEntry(t, 1).Hash)
+
+	// A failed write is reported to the submissions so they're rejected rather than silently not journaled
+	journal.err = errors.New("disk full")
+	third := write(2)
+	s.flushQueueJournal()
+	require.ErrorIs(t, <-third, journal.err)
+	require.Empty(t, s.journalRecords)
+}
+
+func TestQueueJournalConfigValidate(t *testing.T) {
+	config := DefaultQueueJournalConfig
+	require.NoError(t, config.Validate())
+	config.Enable = true
+	require.Error(t, config.Validate())
+	config.Directory = t.TempDir()
+	require.NoError(t, config.Validate())
+	config.RedisUrl = "redis://localhost:6379"
+	require.Error(t, config.Validate())
+}
+
+func TestQueueJournalReplayOrder(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	journal, err := NewDiskQueueJournal(dir, false)
+	require.NoError(t, err)
+
+	signer := types.LatestSignerForChainID(big.NewInt(412346))
+	keys := make([]*ecdsa.PrivateKey, 2)
+	for i := range keys {
+		keys[i], err = crypto.GenerateKey()
+		require.NoError(t, err)
+	}
+	accepted := time.Now()
+	journalTx := func(key *ecdsa.PrivateKey, nonce uint64, acceptedAfter time.Duration) *types.Transaction {
+		t.Helper()
+		to := common.Address{1}
+		tx, err := types.SignNewTx(key, signer, &types.DynamicFeeTx{
+			ChainID:   big.NewInt(412346),
+			Nonce:     nonce,
+			To:        &to,
+			Gas:       21000,
+			GasFeeCap: big.NewInt(1),
+		})
+		require.NoError(t, err)
+		entry, err := NewQueueJournalEntry(tx, nil)
+		require.NoError(t, err)
+		entry.Accepted = accepted.Add(acceptedAfter)
+		require.NoError(t, journal.Write(ctx, []QueueJournalRecord{{Add: entry}}))
+		return tx
+	}
+	// Journaled out of the order they were accepted in
+	third := journalTx(keys[0], 6, 3*time.Second)
+	first := journalTx(keys[1], 0, time.Second)
+	sequenced := journalTx(keys[0], 4, 0)
+	second := journalTx(keys[0], 5, 2*time.Second)
+	corrupt := &QueueJournalEntry{Hash: common.Hash{2}, Tx: []byte{1, 2, 3}, Accepted: accepted}
+	require.NoError(t, journal.Write(ctx, []QueueJournalRecord{{Add: corrupt}}))
+
+	// Restart
+	require.NoError(t, journal.Close())
+	journal, err = NewDiskQueueJournal(dir, false)
+	require.NoError(t, err)
+	defer journal.Close()
+	entries, err := journal.Entries(ctx)
+	require.NoError(t, err)
+
+	nonces := map[common.Address]uint64{crypto.PubkeyToAddress(keys[0].PublicKey): 5}
+	replay, dropped := planQueueJournalReplay(entries, signer, func(addr common.Address) uint64 { return nonces[addr] })
+	var replayed []common.Hash
+	for _, journaled := range replay {
+		replayed = append(replayed, journaled.tx.Hash())
+	}
+	require.Equal(t, []common.Hash{first.Hash(), second.Hash(), third.Hash()}, replayed)
+	require.ElementsMatch(t, []common.Hash{sequenced.Hash(), corrupt.Hash}, dropped)
+}
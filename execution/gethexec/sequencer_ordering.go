@@ -0,0 +1,269 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+package gethexec
+
+import (
+	"container/heap"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+const (
+	FifoOrderingPolicyName          = "fifo"
+	PriorityFeeOrderingPolicyName   = "priority-fee"
+	PerSenderFairOrderingPolicyName = "per-sender-fair"
+)
+
+// OrderingTx is the view of a pending transaction given to a SequencerOrderingPolicy.
+type OrderingTx struct {
+	Tx              *types.Transaction
+	Sender          common.Address
+	FirstAppearance time.Time
+}
+
+// SequencerOrderingPolicy decides the order in which the batch of transactions
+// pulled from the queue for a single block is sequenced.
+// Order must return a permutation of the indices of txs. Transactions sharing a
+// sender must keep their relative order, otherwise nonce ordering is broken.
+type SequencerOrderingPolicy interface {
+	Name() string
+	Order(txs []OrderingTx, baseFee *big.Int) []int
+}
+
+var (
+	orderingPoliciesMutex sync.RWMutex
+	orderingPolicies      = map[string]SequencerOrderingPolicy{}
+)
+
+// RegisterSequencerOrderingPolicy makes a policy selectable through the sequencer's ordering option.
+func RegisterSequencerOrderingPolicy(policy SequencerOrderingPolicy) {
+	orderingPoliciesMutex.Lock()
+	defer orderingPoliciesMutex.Unlock()
+	orderingPolicies[policy.Name()] = policy
+}
+
+func getSequencerOrderingPolicy(name string) (SequencerOrderingPolicy, error) {
+	orderingPoliciesMutex.RLock()
+	defer orderingPoliciesMutex.RUnlock()
+	policy, ok := orderingPolicies[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown sequencer ordering policy \"%v\"", name)
+	}
+	return policy, nil
+}
+
+func init() {
+	RegisterSequencerOrderingPolicy(fifoOrderingPolicy{})
+	RegisterSequencerOrderingPolicy(priorityFeeOrderingPolicy{})
+	RegisterSequencerOrderingPolicy(perSenderFairOrderingPolicy{})
+}
+
+func orderingQueueWaitHistogram(policy string) metrics.Histogram {
+	return metrics.GetOrRegisterHistogram("arb/sequencer/ordering/"+policy+"/queuewait", nil, metrics.NewBoundedHistogramSample())
+}
+
+type fifoOrderingPolicy struct{}
+
+func (fifoOrderingPolicy) Name() string {
+	return FifoOrderingPolicyName
+}
+
+func (fifoOrderingPolicy) Order(txs []OrderingTx, _ *big.Int) []int {
+	order := make([]int, len(txs))
+	for i := range order {
+		order[i] = i
+	}
+	return order
+}
+
+// groupBySender returns, for each sender in order of first appearance, the indices of its txs.
+func groupBySender(txs []OrderingTx) [][]int {
+	senderIdx := make(map[common.Address]int)
+	var groups [][]int
+	for i, tx := range txs {
+		idx, ok := senderIdx[tx.Sender]
+		if !ok {
+			idx = len(groups)
+			senderIdx[tx.Sender] = idx
+			groups = append(groups, nil)
+		}
+		groups[idx] = append(groups[idx], i)
+	}
+	return groups
+}
+
+// priorityFeeOrderingPolicy orders txs by descending effective tip, only
+// ever considering the earliest remaining tx of each sender.
+type priorityFeeOrderingPolicy struct{}
+
+func (priorityFeeOrderingPolicy) Name() string {
+	return PriorityFeeOrderingPolicyName
+}
+
+type senderHead struct {
+	group []int
+	pos   int
+}
+
+type senderHeadHeap struct {
+	heads []*senderHead
+	tips  []*big.Int
+	txs   []OrderingTx
+}
+
+func (h *senderHeadHeap) Len() int { return len(h.heads) }
+
+func (h *senderHeadHeap) Less(i, j int) bool {
+	a := h.heads[i].group[h.heads[i].pos]
+	b := h.heads[j].group[h.heads[j].pos]
+	if cmp := h.tips[a].Cmp(h.tips[b]); cmp != 0 {
+		return cmp > 0
+	}
+	if !h.txs[a].FirstAppearance.Equal(h.txs[b].FirstAppearance) {
+		return h.txs[a].FirstAppearance.Before(h.txs[b].FirstAppearance)
+	}
+	return a < b
+}
+
+func (h *senderHeadHeap) Swap(i, j int) { h.heads[i], h.heads[j] = h.heads[j], h.heads[i] }
+
+func (h *senderHeadHeap) Push(x any) { h.heads = append(h.heads, x.(*senderHead)) }
+
+func (h *senderHeadHeap) Pop() any {
+	n := len(h.heads)
+	head := h.heads[n-1]
+	h.heads = h.heads[:n-1]
+	return head
+}
+
+func (priorityFeeOrderingPolicy) Order(txs []OrderingTx, baseFee *big.Int) []int {
+	tips := make([]*big.Int, len(txs))
+	for i, tx := range txs {
+		tip, err := tx.Tx.EffectiveGasTip(baseFee)
+		if err != nil {
+			tip = new(big.Int)
+		}
+		tips[i] = tip
+	}
+	h := &senderHeadHeap{tips: tips, txs: txs}
+	for _, group := range groupBySender(txs) {
+		h.heads = append(h.heads, &senderHead{group: group})
+	}
+	heap.Init(h)
+	order := make([]int, 0, len(txs))
+	for h.Len() > 0 {
+		head := h.heads[0]
+		order = append(order, head.group[head.pos])
+		head.pos++
+		if head.pos < len(head.group) {
+			heap.Fix(h, 0)
+		} else {
+			heap.Pop(h)
+		}
+	}
+	return order
+}
+
+// perSenderFairOrderingPolicy round-robins between senders, visiting them in
+// order of their earliest queued tx.
+type perSenderFairOrderingPolicy struct{}
+
+func (perSenderFairOrderingPolicy) Name() string {
+	return PerSenderFairOrderingPolicyName
+}
+
+func (perSenderFairOrderingPolicy) Order(txs []OrderingTx, _ *big.Int) []int {
+	groups := groupBySender(txs)
+	sort.SliceStable(groups, func(i, j int) bool {
+		return txs[groups[i][0]].FirstAppearance.Before(txs[groups[j][0]].FirstAppearance)
+	})
+	order := make([]int, 0, len(txs))
+	for round := 0; len(order) < len(txs); round++ {
+		for _, group := range groups {
+			if round < len(group) {
+				order = append(order, group[round])
+			}
+		}
+	}
+	return order
+}
+
+// orderQueueItems applies the configured ordering policy to the queue items of a block.
+func (s *Sequencer) orderQueueItems(policyName string, queueItems []txQueueItem, baseFee *big.Int) []txQueueItem {
+	policy, err := getSequencerOrderingPolicy(policyName)
+	if err != nil {
+		// Validate should've caught this
+		policy = fifoOrderingPolicy{}
+	}
+	now := time.Now()
+	queueWait := orderingQueueWaitHistogram(policy.Name())
+	for _, item := range queueItems {
+		queueWait.Update(now.Sub(item.firstAppearance).Microseconds())
+	}
+	if policy.Name() == FifoOrderingPolicyName || len(queueItems) < 2 {
+		return queueItems
+	}
+	bc := s.execEngine.bc
+	latestHeader := bc.CurrentBlock()
+	signer := types.MakeSigner(bc.Config(), new(big.Int).Add(latestHeader.Number, common.Big1), latestHeader.Time)
+	return applyOrderingPolicy(policy, queueItems, baseFee, signer)
+}
+
+// applyOrderingPolicy orders queue items with policy. Timeboosted items keep their place ahead of everything else
+// so the express lane advantage is preserved, unless the same sender has an earlier item that isn't timeboosted:
+// then the sender's later items are ordered by the policy too, so they stay in nonce order.
+func applyOrderingPolicy(policy SequencerOrderingPolicy, queueItems []txQueueItem, baseFee *big.Int, signer types.Signer) []txQueueItem {
+	ordered := make([]txQueueItem, 0, len(queueItems))
+	var rest []txQueueItem
+	var orderingTxs []OrderingTx
+	hasUnhoisted := make(map[common.Address]bool)
+	for _, item := range queueItems {
+		sender, err := types.Sender(signer, item.tx)
+		if err != nil {
+			// precheckNonces already filtered out txs with bad signatures
+			sender = common.Address{}
+		}
+		if item.isTimeboosted && !hasUnhoisted[sender] {
+			ordered = append(ordered, item)
+			continue
+		}
+		hasUnhoisted[sender] = true
+		rest = append(rest, item)
+		orderingTxs = append(orderingTxs, OrderingTx{
+			Tx:              item.tx,
+			Sender:          sender,
+			FirstAppearance: item.firstAppearance,
+		})
+	}
+	order := policy.Order(orderingTxs, baseFee)
+	if len(order) != len(rest) {
+		log.Error("sequencer ordering policy returned wrong number of txs, falling back to fifo", "policy", policy.Name(), "expected", len(rest), "got", len(order))
+		return queueItems
+	}
+	seen := make([]bool, len(rest))
+	lastBySender := make(map[common.Address]int)
+	for _, idx := range order {
+		if idx < 0 || idx >= len(rest) || seen[idx] {
+			log.Error("sequencer ordering policy returned an invalid permutation, falling back to fifo", "policy", policy.Name())
+			return queueItems
+		}
+		sender := orderingTxs[idx].Sender
+		if last, ok := lastBySender[sender]; ok && last > idx {
+			log.Error("sequencer ordering policy reordered txs of the same sender, falling back to fifo", "policy", policy.Name(), "sender", sender)
+			return queueItems
+		}
+		lastBySender[sender] = idx
+		seen[idx] = true
+		ordered = append(ordered, rest[idx])
+	}
+	return ordered
+}
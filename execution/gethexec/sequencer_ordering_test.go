@@ -0,0 +1,119 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+package gethexec
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func makeOrderingTx(sender byte, nonce uint64, tipGwei int64, appearance time.Time) OrderingTx {
+	tx := types.NewTx(&types.DynamicFeeTx{
+		Nonce:     nonce,
+		GasTipCap: big.NewInt(tipGwei * 1e9),
+		GasFeeCap: big.NewInt(100e9),
+		Gas:       21000,
+	})
+	return OrderingTx{
+		Tx:              tx,
+		Sender:          common.Address{sender},
+		FirstAppearance: appearance,
+	}
+}
+
+func requireSenderOrderKept(t *testing.T, txs []OrderingTx, order []int) {
+	t.Helper()
+	require.Len(t, order, len(txs))
+	lastNonce := make(map[common.Address]uint64)
+	for _, idx := range order {
+		tx := txs[idx]
+		if last, ok := lastNonce[tx.Sender]; ok {
+			require.Greater(t, tx.Tx.Nonce(), last, "nonce order broken for sender %v", tx.Sender)
+		}
+		lastNonce[tx.Sender] = tx.Tx.Nonce()
+	}
+}
+
+func TestSequencerOrderingPolicies(t *testing.T) {
+	now := time.Now()
+	txs := []OrderingTx{
+		makeOrderingTx(1, 0, 1, now),
+		makeOrderingTx(1, 1, 50, now.Add(time.Millisecond)),
+		makeOrderingTx(2, 0, 10, now.Add(2*time.Millisecond)),
+		makeOrderingTx(3, 0, 5, now.Add(3*time.Millisecond)),
+		makeOrderingTx(1, 2, 2, now.Add(4*time.Millisecond)),
+		makeOrderingTx(3, 1, 5, now.Add(5*time.Millisecond)),
+	}
+	baseFee := big.NewInt(1e9)
+
+	fifo, err := getSequencerOrderingPolicy(FifoOrderingPolicyName)
+	require.NoError(t, err)
+	require.Equal(t, []int{0, 1, 2, 3, 4, 5}, fifo.Order(txs, baseFee))
+
+	priorityFee, err := getSequencerOrderingPolicy(PriorityFeeOrderingPolicyName)
+	require.NoError(t, err)
+	order := priorityFee.Order(txs, baseFee)
+	requireSenderOrderKept(t, txs, order)
+	// sender 1's high tip tx can't jump ahead of its own low tip predecessor
+	require.Equal(t, []int{2, 3, 5, 0, 1, 4}, order)
+
+	fair, err := getSequencerOrderingPolicy(PerSenderFairOrderingPolicyName)
+	require.NoError(t, err)
+	order = fair.Order(txs, baseFee)
+	requireSenderOrderKept(t, txs, order)
+	require.Equal(t, []int{0, 2, 3, 1, 5, 4}, order)
+
+	_, err = getSequencerOrderingPolicy("lifo")
+	require.Error(t, err)
+}
+
+func TestApplyOrderingPolicyTimeboosted(t *testing.T) {
+	signer := types.LatestSignerForChainID(big.NewInt(412346))
+	now := time.Now()
+	var queueItems []txQueueItem
+	queue := func(key *ecdsa.PrivateKey, nonce uint64, tipGwei int64, isTimeboosted bool) *types.Transaction {
+		tx := types.MustSignNewTx(key, signer, &types.DynamicFeeTx{
+			Nonce:     nonce,
+			GasTipCap: big.NewInt(tipGwei * 1e9),
+			GasFeeCap: big.NewInt(100e9),
+			Gas:       21000,
+		})
+		queueItems = append(queueItems, txQueueItem{
+			tx:              tx,
+			returnedResult:  &atomic.Bool{},
+			firstAppearance: now.Add(time.Duration(len(queueItems)) * time.Millisecond),
+			isTimeboosted:   isTimeboosted,
+		})
+		return tx
+	}
+	mixedKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	boostedKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	otherKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	mixedNormal := queue(mixedKey, 0, 1, false)
+	boosted := queue(boostedKey, 0, 1, true)
+	other := queue(otherKey, 0, 50, false)
+	// Sent through the express lane after the sender's earlier tx was queued normally
+	mixedBoosted := queue(mixedKey, 1, 1, true)
+
+	priorityFee, err := getSequencerOrderingPolicy(PriorityFeeOrderingPolicyName)
+	require.NoError(t, err)
+	var order []common.Hash
+	for _, item := range applyOrderingPolicy(priorityFee, queueItems, big.NewInt(1e9), signer) {
+		order = append(order, item.tx.Hash())
+	}
+	// The timeboosted tx of the sender with an earlier normal tx isn't hoisted above it
+	require.Equal(t, []common.Hash{boosted.Hash(), other.Hash(), mixedNormal.Hash(), mixedBoosted.Hash()}, order)
+}
@@ -0,0 +1,246 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+package gethexec
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/arbitrum"
+	"github.com/ethereum/go-ethereum/arbitrum_types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+
+	"github.com/offchainlabs/nitro/arbos"
+	"github.com/offchainlabs/nitro/arbos/arbosState"
+)
+
+// SimulatePendingResult describes what would happen if a transaction was sequenced right now.
+type SimulatePendingResult struct {
+	// Error is set if the sequencer would reject the transaction instead of including it.
+	Error         string         `json:"error,omitempty"`
+	Status        hexutil.Uint64 `json:"status"`
+	GasUsed       hexutil.Uint64 `json:"gasUsed"`
+	GasUsedForL1  hexutil.Uint64 `json:"gasUsedForL1"`
+	Logs          []*types.Log   `json:"logs"`
+	ReturnData    hexutil.Bytes  `json:"returnData,omitempty"`
+	RevertReason  string         `json:"revertReason,omitempty"`
+	BlockNumber   hexutil.Uint64 `json:"blockNumber"`
+	BaseFee       *hexutil.Big   `json:"baseFee"`
+	L1BlockNumber hexutil.Uint64 `json:"l1BlockNumber"`
+	// QueuePosition is the number of queued items the transaction would currently wait behind.
+	QueuePosition hexutil.Uint64 `json:"queuePosition"`
+	// QueuedTxsExecuted is the number of queued transactions executed ahead of the transaction.
+	QueuedTxsExecuted hexutil.Uint64 `json:"queuedTxsExecuted"`
+	// ExpressLaneDelayMs is how long the transaction would wait before being queued, because the current round
+	// has an express lane controller. Express lane transactions submitted meanwhile are sequenced before it.
+	ExpressLaneDelayMs hexutil.Uint64 `json:"expressLaneDelayMs,omitempty"`
+}
+
+type queuedTx struct {
+	tx      *types.Transaction
+	options *arbitrum_types.ConditionalOptions
+}
+
+// queuedTxs keeps the transactions admitted to the queue until their queue item gets a result, in the order they
+// were admitted, so that simulations can execute them ahead of the simulated transaction.
+type queuedTxs struct {
+	mutex sync.Mutex
+	items list.List // of []queuedTx, one element per queue item
+}
+
+// add tracks the transactions of a queue item, and returns the function to stop tracking them.
+func (q *queuedTxs) add(txs ...queuedTx) func() {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	elem := q.items.PushBack(txs)
+	return func() {
+		q.mutex.Lock()
+		defer q.mutex.Unlock()
+		q.items.Remove(elem)
+	}
+}
+
+func (q *queuedTxs) snapshot() []queuedTx {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	var txs []queuedTx
+	for elem := q.items.Front(); elem != nil; elem = elem.Next() {
+		txs = append(txs, elem.Value.([]queuedTx)...)
+	}
+	return txs
+}
+
+// queuePosition returns the number of items a transaction published now would be sequenced after, and how long
+// it would wait for the express lane before being queued. Auction resolution and retried transactions are
+// sequenced before the rest of the queue, and are all ahead of a new transaction.
+func (s *Sequencer) queuePosition(config *SequencerConfig) (int, time.Duration) {
+	position := len(s.timeboostAuctionResolutionTxQueue) + s.txRetryQueue.Len() + len(s.txQueue)
+	var expressLaneDelay time.Duration
+	if config.Dangerous.Timeboost.Enable && s.expressLaneService != nil && s.expressLaneService.currentRoundHasController() {
+		expressLaneDelay = config.Dangerous.Timeboost.ExpressLaneAdvantage
+	}
+	return position, expressLaneDelay
+}
+
+// SimulatePending estimates what would happen if tx was sequenced now. It runs tx in the block the sequencer would
+// build next, with the same header, parent chain block and L1 pricing as real sequencing, on the state of the latest
+// block. The transactions queued ahead of it, including the ones being sequenced right now, are executed first in
+// the order they were admitted, with the same checks as real sequencing, so their effects are included. The
+// sequencer might order a block's transactions differently and spread them over several blocks, so the result is
+// still an estimate: a tx that doesn't fit in the block after the queued ones fails with core.ErrGasLimitReached,
+// where the sequencer would move it to the next block.
+// It runs in replay mode like other speculative executions: the results are the same as in the sequencer's commit
+// mode, which would also update the node's Stylus program cache.
+func (s *Sequencer) SimulatePending(ctx context.Context, tx *types.Transaction) (*SimulatePendingResult, error) {
+	pauseChan, forwarder := s.GetPauseAndForwarder()
+	if forwarder != nil {
+		return forwarder.SimulatePending(ctx, tx)
+	}
+	if pauseChan != nil {
+		return nil, ErrNoSequencer
+	}
+
+	config := s.config()
+	if tx.Type() >= types.ArbitrumDepositTxType || tx.Type() == types.BlobTxType {
+		return nil, types.ErrTxTypeNotSupported
+	}
+	l1Header := s.nextL2MessageHeader(config)
+	if l1Header == nil {
+		return nil, errors.New("cannot simulate: sequencer's parent chain block is unknown or stale")
+	}
+
+	var txes types.Transactions
+	var options []*arbitrum_types.ConditionalOptions
+	for _, queued := range s.queuedTxs.snapshot() {
+		if queued.tx.Hash() == tx.Hash() {
+			// tx was already submitted, and is simulated where it is in the queue
+			break
+		}
+		txes = append(txes, queued.tx)
+		options = append(options, queued.options)
+	}
+	queuedTxsExecuted := len(txes)
+	txes = append(txes, tx)
+	options = append(options, nil)
+
+	var execResult *core.ExecutionResult
+	hooks := arbos.NoopSequencingHooks()
+	hooks.DiscardInvalidTxsEarly = true
+	hooks.ConditionalOptionsForTx = options
+	hooks.PreTxFilter = func(_ *params.ChainConfig, header *types.Header, statedb *state.StateDB, _ *arbosState.ArbosState, _ *types.Transaction, txOptions *arbitrum_types.ConditionalOptions, _ common.Address, l1Info *arbos.L1Info) error {
+		if txOptions != nil {
+			return txOptions.Check(l1Info.L1BlockNumber(), header.Time, statedb)
+		}
+		return nil
+	}
+	hooks.PostTxFilter = func(header *types.Header, statedb *state.StateDB, _ *arbosState.ArbosState, postTx *types.Transaction, _ common.Address, dataGas uint64, result *core.ExecutionResult) error {
+		if postTx.Hash() == tx.Hash() {
+			execResult = result
+		}
+		if statedb.IsTxFiltered() {
+			return state.ErrArbTxFilter
+		}
+		if result.Err != nil && result.UsedGas > dataGas && result.UsedGas-dataGas <= config.MaxRevertGasReject {
+			return arbitrum.NewRevertReason(result)
+		}
+		return nil
+	}
+
+	block, receipts, err := s.execEngine.SimulateNextBlockTxs(l1Header, txes, hooks)
+	if err != nil {
+		return nil, err
+	}
+	queuePosition, expressLaneDelay := s.queuePosition(config)
+	result := &SimulatePendingResult{
+		Logs:          []*types.Log{},
+		BlockNumber:   hexutil.Uint64(block.NumberU64()),
+		BaseFee:       (*hexutil.Big)(block.BaseFee()),
+		L1BlockNumber: hexutil.Uint64(l1Header.BlockNumber),
+		// #nosec G115
+		QueuePosition: hexutil.Uint64(queuePosition),
+		// #nosec G115
+		QueuedTxsExecuted: hexutil.Uint64(queuedTxsExecuted),
+		// #nosec G115
+		ExpressLaneDelayMs: hexutil.Uint64(expressLaneDelay.Milliseconds()),
+	}
+	if len(hooks.TxErrors) != len(txes) {
+		return nil, fmt.Errorf("unexpected number of error results: %v vs number of txes %v", len(hooks.TxErrors), len(txes))
+	}
+	// The queued transactions that fail are left out of the block just like in real sequencing
+	if txErr := hooks.TxErrors[len(txes)-1]; txErr != nil {
+		result.Error = txErr.Error()
+		return result, nil
+	}
+	for _, receipt := range receipts {
+		if receipt.TxHash != tx.Hash() {
+			continue
+		}
+		result.Status = hexutil.Uint64(receipt.Status)
+		result.GasUsed = hexutil.Uint64(receipt.GasUsed)
+		result.GasUsedForL1 = hexutil.Uint64(receipt.GasUsedForL1)
+		result.Logs = receipt.Logs
+	}
+	if execResult != nil && execResult.Err != nil {
+		result.ReturnData = execResult.Revert()
+		if reason, err := abi.UnpackRevert(execResult.Revert()); err == nil {
+			result.RevertReason = reason
+		} else {
+			result.RevertReason = execResult.Err.Error()
+		}
+	}
+	return result, nil
+}
+
+func (f *TxForwarder) SimulatePending(inctx context.Context, tx *types.Transaction) (*SimulatePendingResult, error) {
+	if !f.enabled.Load() {
+		return nil, ErrNoSequencer
+	}
+	encodedTx, err := tx.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancelFunc := f.ctxWithTimeout()
+	defer cancelFunc()
+	for pos, rpcClient := range f.rpcClients {
+		var result SimulatePendingResult
+		err := rpcClient.CallContext(ctx, &result, "arb_simulatePending", hexutil.Bytes(encodedTx))
+		if err == nil {
+			return &result, nil
+		}
+		log.Warn("error forwarding simulation to a backup target", "target", f.targets[pos], "err", err)
+		if !f.tryNewForwarderErrors.MatchString(err.Error()) {
+			return nil, err
+		}
+	}
+	return nil, errors.New("failed to simulate transaction on any of the forwarding targets")
+}
+
+type ArbSequencerAPI struct {
+	sequencer *Sequencer
+}
+
+func NewArbSequencerAPI(sequencer *Sequencer) *ArbSequencerAPI {
+	return &ArbSequencerAPI{sequencer}
+}
+
+// SimulatePending executes a signed transaction in the block the active sequencer is about to build, after the
+// transactions queued ahead of it.
+func (a *ArbSequencerAPI) SimulatePending(ctx context.Context, encodedTx hexutil.Bytes) (*SimulatePendingResult, error) {
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(encodedTx); err != nil {
+		return nil, err
+	}
+	return a.sequencer.SimulatePending(ctx, tx)
+}
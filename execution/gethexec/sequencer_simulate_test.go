@@ -0,0 +1,44 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+package gethexec
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func TestQueuedTxs(t *testing.T) {
+	makeTx := func(nonce uint64) *types.Transaction {
+		to := common.Address{1}
+		return types.NewTx(&types.LegacyTx{Nonce: nonce, To: &to, Gas: 21000, GasPrice: big.NewInt(1)})
+	}
+	hashes := func(txs []queuedTx) []common.Hash {
+		var hashes []common.Hash
+		for _, queued := range txs {
+			hashes = append(hashes, queued.tx.Hash())
+		}
+		return hashes
+	}
+	var queued queuedTxs
+	require.Empty(t, queued.snapshot())
+
+	first, bundle, last := makeTx(0), []*types.Transaction{makeTx(1), makeTx(2)}, makeTx(3)
+	untrackFirst := queued.add(queuedTx{tx: first})
+	untrackBundle := queued.add(queuedTx{tx: bundle[0]}, queuedTx{tx: bundle[1]})
+	untrackLast := queued.add(queuedTx{tx: last})
+	require.Equal(t, []common.Hash{first.Hash(), bundle[0].Hash(), bundle[1].Hash(), last.Hash()}, hashes(queued.snapshot()))
+
+	// Items are removed as they get their results, in any order, and removing one twice is harmless
+	untrackBundle()
+	untrackBundle()
+	require.Equal(t, []common.Hash{first.Hash(), last.Hash()}, hashes(queued.snapshot()))
+	untrackLast()
+	untrackFirst()
+	require.Empty(t, queued.snapshot())
+}
@@ -192,6 +192,9 @@ func ParseRelay(_ context.Context, args []string) (*Config, error) {
 	if err := confighelpers.EndCommonParse(k, &relayConfig); err != nil {
 		return nil, err
 	}
+	if err := relayConfig.Node.Feed.Validate(); err != nil {
+		return nil, err
+	}
 
 	if relayConfig.Conf.Dump {
 		err = confighelpers.DumpConfig(k, map[string]interface{}{})
@@ -208,6 +208,8 @@ var TestSequencerConfig = gethexec.SequencerConfig{
 	ExpectedSurplusSoftThreshold: "default",
 	ExpectedSurplusHardThreshold: "default",
 	EnableProfiling:              false,
+	Ordering:                     gethexec.FifoOrderingPolicyName,
+	MaxBundleTxs:                 16,
 }
 
 func ExecConfigDefaultNonSequencerTest(t *testing.T) *gethexec.Config {
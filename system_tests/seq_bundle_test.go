@@ -0,0 +1,139 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+package arbtest
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+
+	"github.com/offchainlabs/nitro/execution/gethexec"
+)
+
+func sendBundle(ctx context.Context, builder *NodeBuilder, revertProtect bool, txs ...*types.Transaction) ([]common.Hash, error) {
+	args := gethexec.BundleArgs{RevertProtect: revertProtect}
+	for _, tx := range txs {
+		encoded, err := tx.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		args.Txs = append(args.Txs, encoded)
+	}
+	var hashes []common.Hash
+	err := builder.L2.Client.Client().CallContext(ctx, &hashes, "arb_sendBundle", args)
+	return hashes, err
+}
+
+func requireBundleNotIncluded(t *testing.T, ctx context.Context, builder *NodeBuilder, txs ...*types.Transaction) {
+	t.Helper()
+	for _, tx := range txs {
+		_, err := builder.L2.Client.TransactionReceipt(ctx, tx.Hash())
+		if !errors.Is(err, ethereum.NotFound) {
+			Fatal(t, "transaction of dropped bundle has a receipt", tx.Hash(), err)
+		}
+	}
+}
+
+func TestSequencerBundle(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	builder := NewNodeBuilder(ctx).DefaultConfig(t, false)
+	// Let reverted txs through so revert protection can be tested
+	builder.execConfig.Sequencer.MaxRevertGasReject = 0
+	cleanup := builder.Build(t)
+	defer cleanup()
+
+	builder.L2Info.GenerateAccount("User")
+	builder.L2Info.GenerateAccount("Broke")
+	builder.L2.TransferBalance(t, "Owner", "User", big.NewInt(params.Ether), builder.L2Info)
+
+	// A successful bundle lands back to back in a single block
+	tx1 := builder.L2Info.PrepareTx("Owner", "User", builder.L2Info.TransferGas, big.NewInt(1e12), nil)
+	tx2 := builder.L2Info.PrepareTx("User", "Owner", builder.L2Info.TransferGas, big.NewInt(1e12), nil)
+	tx3 := builder.L2Info.PrepareTx("Owner", "User", builder.L2Info.TransferGas, big.NewInt(1e12), nil)
+	hashes, err := sendBundle(ctx, builder, false, tx1, tx2, tx3)
+	Require(t, err)
+	if len(hashes) != 3 || hashes[0] != tx1.Hash() || hashes[2] != tx3.Hash() {
+		Fatal(t, "unexpected bundle hashes", hashes)
+	}
+	var blockNumber *big.Int
+	for i, tx := range []*types.Transaction{tx1, tx2, tx3} {
+		receipt, err := builder.L2.EnsureTxSucceeded(tx)
+		Require(t, err)
+		if blockNumber == nil {
+			blockNumber = receipt.BlockNumber
+		} else if receipt.BlockNumber.Cmp(blockNumber) != 0 {
+			Fatal(t, "bundle split across blocks", blockNumber, receipt.BlockNumber)
+		}
+		// index 0 is the internal start block tx
+		if receipt.TransactionIndex != uint(i+1) {
+			Fatal(t, "bundle transaction", i, "has index", receipt.TransactionIndex)
+		}
+	}
+
+	// If a tx in the middle of the bundle fails, none of the bundle is included
+	ownerNonce := builder.L2Info.GetInfoWithPrivKey("Owner").Nonce.Load()
+	brokeNonce := builder.L2Info.GetInfoWithPrivKey("Broke").Nonce.Load()
+	good := builder.L2Info.PrepareTx("Owner", "User", builder.L2Info.TransferGas, big.NewInt(1e12), nil)
+	broke := builder.L2Info.PrepareTx("Broke", "User", builder.L2Info.TransferGas, big.NewInt(1e12), nil)
+	alsoGood := builder.L2Info.PrepareTx("Owner", "User", builder.L2Info.TransferGas, big.NewInt(1e12), nil)
+	_, err = sendBundle(ctx, builder, false, good, broke, alsoGood)
+	if err == nil {
+		Fatal(t, "bundle with an unfunded transaction was accepted")
+	}
+	if !strings.Contains(err.Error(), "transaction 1") {
+		Fatal(t, "unexpected bundle error", err)
+	}
+	requireBundleNotIncluded(t, ctx, builder, good, broke, alsoGood)
+	stateNonce, err := builder.L2.Client.NonceAt(ctx, builder.L2Info.GetAddress("Owner"), nil)
+	Require(t, err)
+	if stateNonce != ownerNonce {
+		Fatal(t, "dropped bundle changed owner nonce from", ownerNonce, "to", stateNonce)
+	}
+	builder.L2Info.GetInfoWithPrivKey("Owner").Nonce.Store(ownerNonce)
+	builder.L2Info.GetInfoWithPrivKey("Broke").Nonce.Store(brokeNonce)
+
+	// Calling a precompile with an unknown selector reverts
+	arbSys := types.ArbSysAddress
+	revertData := hexutil.MustDecode("0xdeadbeef")
+
+	// Without revert protection, the reverted tx is included alongside the rest of the bundle
+	good = builder.L2Info.PrepareTx("Owner", "User", builder.L2Info.TransferGas, big.NewInt(1e12), nil)
+	reverting := builder.L2Info.PrepareTxTo("Owner", &arbSys, 1_000_000, common.Big0, revertData)
+	_, err = sendBundle(ctx, builder, false, good, reverting)
+	Require(t, err)
+	_, err = builder.L2.EnsureTxSucceeded(good)
+	Require(t, err)
+	receipt, err := builder.L2.Client.TransactionReceipt(ctx, reverting.Hash())
+	Require(t, err)
+	if receipt.Status != types.ReceiptStatusFailed {
+		Fatal(t, "expected call to revert")
+	}
+
+	// With revert protection, the whole bundle is dropped
+	ownerNonce = builder.L2Info.GetInfoWithPrivKey("Owner").Nonce.Load()
+	good = builder.L2Info.PrepareTx("Owner", "User", builder.L2Info.TransferGas, big.NewInt(1e12), nil)
+	reverting = builder.L2Info.PrepareTxTo("Owner", &arbSys, 1_000_000, common.Big0, revertData)
+	_, err = sendBundle(ctx, builder, true, good, reverting)
+	if err == nil {
+		Fatal(t, "revert protected bundle with a reverting transaction was accepted")
+	}
+	if !strings.Contains(err.Error(), gethexec.ErrBundleTxReverted.Error()) {
+		Fatal(t, "unexpected bundle error", err)
+	}
+	requireBundleNotIncluded(t, ctx, builder, good, reverting)
+	builder.L2Info.GetInfoWithPrivKey("Owner").Nonce.Store(ownerNonce)
+
+	// The sequencer keeps working normally afterwards
+	builder.L2.TransferBalance(t, "Owner", "User", big.NewInt(1e12), builder.L2Info)
+}
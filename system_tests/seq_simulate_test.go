@@ -0,0 +1,92 @@
+// Copyright 2025, Offchain Labs, Inc.
+// For license information, see https://github.com/nitro/blob/master/LICENSE
+
+package arbtest
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/offchainlabs/nitro/execution/gethexec"
+)
+
+func simulatePending(t *testing.T, ctx context.Context, builder *NodeBuilder, tx *types.Transaction) *gethexec.SimulatePendingResult {
+	t.Helper()
+	encoded, err := tx.MarshalBinary()
+	Require(t, err)
+	var result gethexec.SimulatePendingResult
+	err = builder.L2.Client.Client().CallContext(ctx, &result, "arb_simulatePending", hexutil.Bytes(encoded))
+	Require(t, err)
+	return &result
+}
+
+func TestSequencerSimulatePending(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	builder := NewNodeBuilder(ctx).DefaultConfig(t, false)
+	builder.execConfig.Sequencer.MaxRevertGasReject = 0
+	cleanup := builder.Build(t)
+	defer cleanup()
+
+	builder.L2Info.GenerateAccount("User")
+	builder.L2.TransferBalance(t, "Owner", "User", big.NewInt(1e18), builder.L2Info)
+
+	head, err := builder.L2.Client.BlockNumber(ctx)
+	Require(t, err)
+	tx := builder.L2Info.PrepareTx("Owner", "User", builder.L2Info.TransferGas, big.NewInt(1e12), nil)
+	simulated := simulatePending(t, ctx, builder, tx)
+	if simulated.Error != "" {
+		Fatal(t, "simulation rejected transaction", simulated.Error)
+	}
+	if simulated.Status != hexutil.Uint64(types.ReceiptStatusSuccessful) {
+		Fatal(t, "simulated transfer failed")
+	}
+	if uint64(simulated.BlockNumber) != head+1 {
+		Fatal(t, "simulated in block", simulated.BlockNumber, "expected", head+1)
+	}
+
+	// Simulation must not change any state
+	_, err = builder.L2.Client.TransactionReceipt(ctx, tx.Hash())
+	if err == nil {
+		Fatal(t, "simulated transaction was included")
+	}
+	newHead, err := builder.L2.Client.BlockNumber(ctx)
+	Require(t, err)
+	if newHead != head {
+		Fatal(t, "simulation produced a block")
+	}
+
+	err = builder.L2.Client.SendTransaction(ctx, tx)
+	Require(t, err)
+	receipt, err := builder.L2.EnsureTxSucceeded(tx)
+	Require(t, err)
+	if receipt.GasUsed != uint64(simulated.GasUsed) {
+		Fatal(t, "simulated gas", simulated.GasUsed, "but transaction used", receipt.GasUsed)
+	}
+
+	// Calling a precompile with an unknown selector reverts
+	arbSys := types.ArbSysAddress
+	reverting := builder.L2Info.PrepareTxTo("Owner", &arbSys, 1_000_000, common.Big0, hexutil.MustDecode("0xdeadbeef"))
+	simulated = simulatePending(t, ctx, builder, reverting)
+	if simulated.Error != "" {
+		Fatal(t, "simulation rejected transaction", simulated.Error)
+	}
+	if simulated.Status != hexutil.Uint64(types.ReceiptStatusFailed) {
+		Fatal(t, "expected simulated call to revert")
+	}
+	if simulated.RevertReason == "" {
+		Fatal(t, "missing revert reason")
+	}
+
+	// A nonce that's too low is reported as an error instead of a result
+	simulated = simulatePending(t, ctx, builder, tx)
+	if simulated.Error == "" {
+		Fatal(t, "expected simulating an included transaction to fail")
+	}
+}
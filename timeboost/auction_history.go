@@ -0,0 +1,596 @@
+package timeboost
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/offchainlabs/nitro/cmd/genericconf"
+)
+
+const AuctionHistoryNamespace = "auctionhistory"
+
+type AuctionHistoryConfig struct {
+	Enable                 bool                                `koanf:"enable"`
+	Addr                   string                              `koanf:"addr"`
+	Port                   uint64                              `koanf:"port"`
+	DefaultPageSize        int                                 `koanf:"default-page-size"`
+	MaxPageSize            int                                 `koanf:"max-page-size"`
+	S3Fallback             bool                                `koanf:"s3-fallback"`
+	ArchiveRefreshInterval time.Duration                       `koanf:"archive-refresh-interval"`
+	ServerTimeouts         genericconf.HTTPServerTimeoutConfig `koanf:"server-timeouts"`
+}
+
+var DefaultAuctionHistoryConfig = AuctionHistoryConfig{
+	Enable:                 false,
+	Addr:                   "localhost",
+	Port:                   9374,
+	DefaultPageSize:        100,
+	MaxPageSize:            1000,
+	S3Fallback:             false,
+	ArchiveRefreshInterval: 5 * time.Minute,
+	ServerTimeouts:         genericconf.HTTPServerTimeoutConfigDefault,
+}
+
+func AuctionHistoryConfigAddOptions(prefix string, f *pflag.FlagSet) {
+	f.Bool(prefix+".enable", DefaultAuctionHistoryConfig.Enable, "enable the read-only auction history JSON-RPC and HTTP API")
+	f.String(prefix+".addr", DefaultAuctionHistoryConfig.Addr, "address the auction history API listens on")
+	f.Uint64(prefix+".port", DefaultAuctionHistoryConfig.Port, "port the auction history API listens on")
+	f.Int(prefix+".default-page-size", DefaultAuctionHistoryConfig.DefaultPageSize, "number of rounds returned when the request doesn't set a limit")
+	f.Int(prefix+".max-page-size", DefaultAuctionHistoryConfig.MaxPageSize, "maximum number of rounds returned by a request")
+	f.Bool(prefix+".s3-fallback", DefaultAuctionHistoryConfig.S3Fallback, "serve rounds older than the auction results in the database from the validated bids archived to S3")
+	f.Duration(prefix+".archive-refresh-interval", DefaultAuctionHistoryConfig.ArchiveRefreshInterval, "how long the list of batches archived to S3 is cached for")
+	genericconf.HTTPServerTimeoutConfigAddOptions(prefix+".server-timeouts", f)
+}
+
+func (c *AuctionHistoryConfig) Validate() error {
+	if !c.Enable {
+		return nil
+	}
+	if c.DefaultPageSize <= 0 || c.MaxPageSize <= 0 {
+		return errors.New("auction history page sizes must be positive")
+	}
+	if c.DefaultPageSize > c.MaxPageSize {
+		return fmt.Errorf("auction history default-page-size %d is above max-page-size %d", c.DefaultPageSize, c.MaxPageSize)
+	}
+	return nil
+}
+
+// AuctionRound is the outcome of the auction for a round. Rounds resolved before the auctioneer recorded auction
+// results are rebuilt from their validated bids, in the database or else the S3 archive, so the controller and bids
+// are those the auctioneer would have resolved the auction with, and the clearing price is only known if there was a
+// second bid. Archived is set for the rounds read from the S3 archive.
+type AuctionRound struct {
+	Round                       hexutil.Uint64  `json:"round"`
+	FirstBidder                 common.Address  `json:"firstBidder"`
+	ExpressLaneController       common.Address  `json:"expressLaneController"`
+	FirstBid                    *hexutil.Big    `json:"firstBid"`
+	SecondBidder                *common.Address `json:"secondBidder,omitempty"`
+	SecondExpressLaneController *common.Address `json:"secondExpressLaneController,omitempty"`
+	SecondBid                   *hexutil.Big    `json:"secondBid,omitempty"`
+	ClearingPrice               *hexutil.Big    `json:"clearingPrice,omitempty"`
+	BidCount                    hexutil.Uint64  `json:"bidCount"`
+	TxHash                      *common.Hash    `json:"txHash,omitempty"`
+	Archived                    bool            `json:"archived"`
+}
+
+type AuctionRoundsPage struct {
+	Rounds []*AuctionRound `json:"rounds"`
+	// Next is the round to request from to get the next page, if there are more rounds in the range.
+	Next *hexutil.Uint64 `json:"next,omitempty"`
+}
+
+// auctionArchive is where validated bids are archived once deleted from the database.
+type auctionArchive interface {
+	listBatches(ctx context.Context) ([]archivedBatch, error)
+	readBatch(ctx context.Context, key string) ([]*SqliteDatabaseBid, error)
+}
+
+// AuctionHistory serves the auction results recorded by the auctioneer, falling back to the validated bids in the
+// database and the S3 archive for the rounds before them.
+type AuctionHistory struct {
+	config          *AuctionHistoryConfig
+	database        *SqliteDatabase
+	archive         auctionArchive
+	domainSeparator [32]byte
+	roundTimingInfo RoundTimingInfo
+
+	archiveMutex    sync.Mutex
+	archiveBatches  []archivedBatch
+	archiveListedAt time.Time
+}
+
+func NewAuctionHistory(config *AuctionHistoryConfig, database *SqliteDatabase, s3StorageService *S3StorageService, domainSeparator [32]byte, roundTimingInfo RoundTimingInfo) (*AuctionHistory, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	h := &AuctionHistory{
+		config:          config,
+		database:        database,
+		domainSeparator: domainSeparator,
+		roundTimingInfo: roundTimingInfo,
+	}
+	if config.S3Fallback {
+		if s3StorageService == nil {
+			return nil, errors.New("auction history s3-fallback needs the auctioneer's s3-storage to be enabled")
+		}
+		h.archive = s3StorageService
+	}
+	return h, nil
+}
+
+func (h *AuctionHistory) pageSize(limit int) int {
+	if limit <= 0 {
+		return h.config.DefaultPageSize
+	}
+	return min(limit, h.config.MaxPageSize)
+}
+
+// Rounds returns the auction rounds from fromRound to toRound, in round order, up to the limit.
+func (h *AuctionHistory) Rounds(ctx context.Context, fromRound, toRound uint64, limit int) (*AuctionRoundsPage, error) {
+	if fromRound > toRound {
+		return nil, fmt.Errorf("invalid round range, from %d is after to %d", fromRound, toRound)
+	}
+	limit = h.pageSize(limit)
+	rounds := []*AuctionRound{}
+	firstResult, found, err := h.database.FirstAuctionResultRound()
+	if err != nil {
+		return nil, err
+	}
+	// The rounds before the first recorded result are rebuilt from their bids. The auction of the current round is
+	// closed, but bids for the next one may still be coming in.
+	rebuildTo := min(toRound, h.roundTimingInfo.RoundNumber())
+	if found {
+		rebuildTo = min(rebuildTo, firstResult-1)
+	}
+	if (!found || firstResult > 0) && fromRound <= rebuildTo {
+		rebuilt, err := h.rebuiltRounds(ctx, fromRound, rebuildTo, limit+1)
+		if err != nil {
+			return nil, err
+		}
+		rounds = append(rounds, rebuilt...)
+	}
+	if len(rounds) <= limit {
+		results, err := h.database.GetAuctionResults(fromRound, toRound, limit+1-len(rounds))
+		if err != nil {
+			return nil, err
+		}
+		for _, result := range results {
+			round, err := auctionRoundFromResult(result)
+			if err != nil {
+				return nil, err
+			}
+			rounds = append(rounds, round)
+		}
+	}
+	page := &AuctionRoundsPage{Rounds: rounds}
+	if len(rounds) > limit {
+		next := rounds[limit].Round
+		page.Next = &next
+		page.Rounds = rounds[:limit]
+	}
+	return page, nil
+}
+
+// Round returns the auction round, or nil if there's no record of it.
+func (h *AuctionHistory) Round(ctx context.Context, round uint64) (*AuctionRound, error) {
+	page, err := h.Rounds(ctx, round, round, 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(page.Rounds) == 0 {
+		return nil, nil
+	}
+	return page.Rounds[0], nil
+}
+
+// RoundBids returns the validated bids of the round, from the database or else the S3 archive.
+func (h *AuctionHistory) RoundBids(ctx context.Context, round uint64) ([]*JsonValidatedBid, error) {
+	bids, err := h.database.GetRoundBids(round)
+	if err != nil {
+		return nil, err
+	}
+	if len(bids) == 0 && h.archive != nil {
+		bids, err = h.archivedBids(ctx, round, round)
+		if err != nil {
+			return nil, fmt.Errorf("error reading bids from the S3 archive: %w", err)
+		}
+	}
+	jsonBids := make([]*JsonValidatedBid, 0, len(bids))
+	for _, bid := range bids {
+		validated, err := validatedBidFromSqlite(bid)
+		if err != nil {
+			return nil, err
+		}
+		jsonBids = append(jsonBids, validated.ToJson())
+	}
+	return jsonBids, nil
+}
+
+func (h *AuctionHistory) batches(ctx context.Context) ([]archivedBatch, error) {
+	h.archiveMutex.Lock()
+	defer h.archiveMutex.Unlock()
+	if h.archiveBatches != nil && time.Since(h.archiveListedAt) < h.config.ArchiveRefreshInterval {
+		return h.archiveBatches, nil
+	}
+	batches, err := h.archive.listBatches(ctx)
+	if err != nil {
+		return nil, err
+	}
+	h.archiveBatches = batches
+	h.archiveListedAt = time.Now()
+	return batches, nil
+}
+
+// archivedBidsUntil reads the archived bids with rounds from fromRound to toRound, a batch at a time, until stop
+// returns true.
+func (h *AuctionHistory) archivedBidsUntil(ctx context.Context, fromRound, toRound uint64, stop func([]*SqliteDatabaseBid) bool) error {
+	batches, err := h.batches(ctx)
+	if err != nil {
+		return err
+	}
+	for _, batch := range batches {
+		if batch.lastRound < fromRound || batch.firstRound > toRound {
+			continue
+		}
+		bids, err := h.archive.readBatch(ctx, batch.key)
+		if err != nil {
+			return fmt.Errorf("error reading batch %s: %w", batch.key, err)
+		}
+		inRange := make([]*SqliteDatabaseBid, 0, len(bids))
+		for _, bid := range bids {
+			if bid.Round >= fromRound && bid.Round <= toRound {
+				inRange = append(inRange, bid)
+			}
+		}
+		if stop(inRange) {
+			return nil
+		}
+	}
+	return nil
+}
+
+func (h *AuctionHistory) archivedBids(ctx context.Context, fromRound, toRound uint64) ([]*SqliteDatabaseBid, error) {
+	var bids []*SqliteDatabaseBid
+	err := h.archivedBidsUntil(ctx, fromRound, toRound, func(batchBids []*SqliteDatabaseBid) bool {
+		bids = append(bids, batchBids...)
+		return false
+	})
+	return bids, err
+}
+
+// rebuiltRounds rebuilds up to limit auction rounds from the validated bids in the S3 archive and the database.
+func (h *AuctionHistory) rebuiltRounds(ctx context.Context, fromRound, toRound uint64, limit int) ([]*AuctionRound, error) {
+	var rounds []*AuctionRound
+	if h.archive != nil {
+		archived, err := h.archivedRounds(ctx, fromRound, toRound, limit)
+		if err != nil {
+			return nil, fmt.Errorf("error reading auction rounds from the S3 archive: %w", err)
+		}
+		rounds = archived
+	}
+	databaseRounds, err := h.databaseRounds(fromRound, toRound, limit)
+	if err != nil {
+		return nil, err
+	}
+	// Bids whose deletion from the database failed after being archived are in both.
+	archived := make(map[hexutil.Uint64]struct{}, len(rounds))
+	for _, round := range rounds {
+		archived[round.Round] = struct{}{}
+	}
+	for _, round := range databaseRounds {
+		if _, ok := archived[round.Round]; !ok {
+			rounds = append(rounds, round)
+		}
+	}
+	sort.Slice(rounds, func(i, j int) bool { return rounds[i].Round < rounds[j].Round })
+	if len(rounds) > limit {
+		rounds = rounds[:limit]
+	}
+	return rounds, nil
+}
+
+// databaseRounds rebuilds up to limit auction rounds from the bids in the database that haven't been archived yet.
+func (h *AuctionHistory) databaseRounds(fromRound, toRound uint64, limit int) ([]*AuctionRound, error) {
+	bidRounds, err := h.database.GetBidRounds(fromRound, toRound, limit)
+	if err != nil || len(bidRounds) == 0 {
+		return nil, err
+	}
+	bids, err := h.database.GetBidsInRounds(bidRounds[0], bidRounds[len(bidRounds)-1])
+	if err != nil {
+		return nil, err
+	}
+	return h.roundsFromBids(bids, false, limit)
+}
+
+// archivedRounds rebuilds the auction rounds from the archived bids.
+func (h *AuctionHistory) archivedRounds(ctx context.Context, fromRound, toRound uint64, limit int) ([]*AuctionRound, error) {
+	var rounds []*AuctionRound
+	var convertErr error
+	err := h.archivedBidsUntil(ctx, fromRound, toRound, func(bids []*SqliteDatabaseBid) bool {
+		batchRounds, err := h.roundsFromBids(bids, true, limit-len(rounds))
+		if err != nil {
+			convertErr = err
+			return true
+		}
+		rounds = append(rounds, batchRounds...)
+		return len(rounds) >= limit
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rounds, convertErr
+}
+
+// roundsFromBids resolves the auctions of up to limit rounds of the bids like the auctioneer did, in round order.
+func (h *AuctionHistory) roundsFromBids(bids []*SqliteDatabaseBid, archived bool, limit int) ([]*AuctionRound, error) {
+	byRound := make(map[uint64]*bidCache)
+	for _, bid := range bids {
+		validated, err := validatedBidFromSqlite(bid)
+		if err != nil {
+			return nil, err
+		}
+		cache, ok := byRound[bid.Round]
+		if !ok {
+			cache = newBidCache(h.domainSeparator)
+			byRound[bid.Round] = cache
+		}
+		cache.add(validated)
+	}
+	bidRounds := make([]uint64, 0, len(byRound))
+	for round := range byRound {
+		bidRounds = append(bidRounds, round)
+	}
+	sort.Slice(bidRounds, func(i, j int) bool { return bidRounds[i] < bidRounds[j] })
+	if len(bidRounds) > limit {
+		bidRounds = bidRounds[:limit]
+	}
+	rounds := make([]*AuctionRound, 0, len(bidRounds))
+	for _, round := range bidRounds {
+		auctionRound := auctionRoundFromBids(round, byRound[round])
+		auctionRound.Archived = archived
+		rounds = append(rounds, auctionRound)
+	}
+	return rounds, nil
+}
+
+func auctionRoundFromBids(round uint64, cache *bidCache) *AuctionRound {
+	result := cache.topTwoBids()
+	first, second := result.firstPlace, result.secondPlace
+	auctionRound := &AuctionRound{
+		Round:                 hexutil.Uint64(round),
+		FirstBidder:           first.Bidder,
+		ExpressLaneController: first.ExpressLaneController,
+		FirstBid:              (*hexutil.Big)(first.Amount),
+		// #nosec G115
+		BidCount: hexutil.Uint64(cache.validatedBids()),
+	}
+	if second != nil {
+		auctionRound.SecondBidder = &second.Bidder
+		auctionRound.SecondExpressLaneController = &second.ExpressLaneController
+		auctionRound.SecondBid = (*hexutil.Big)(second.Amount)
+		auctionRound.ClearingPrice = (*hexutil.Big)(second.Amount)
+	}
+	return auctionRound
+}
+
+func auctionRoundFromResult(r *SqliteDatabaseAuctionResult) (*AuctionRound, error) {
+	firstBid, err := parseAmount(r.FirstAmount)
+	if err != nil {
+		return nil, err
+	}
+	price, err := parseAmount(r.Price)
+	if err != nil {
+		return nil, err
+	}
+	txHash := common.HexToHash(r.TxHash)
+	round := &AuctionRound{
+		Round:                 hexutil.Uint64(r.Round),
+		FirstBidder:           common.HexToAddress(r.FirstBidder),
+		ExpressLaneController: common.HexToAddress(r.FirstExpressLaneController),
+		FirstBid:              (*hexutil.Big)(firstBid),
+		ClearingPrice:         (*hexutil.Big)(price),
+		BidCount:              hexutil.Uint64(r.BidCount),
+		TxHash:                &txHash,
+	}
+	if r.SecondAmount != "" {
+		secondBid, err := parseAmount(r.SecondAmount)
+		if err != nil {
+			return nil, err
+		}
+		secondBidder := common.HexToAddress(r.SecondBidder)
+		secondController := common.HexToAddress(r.SecondExpressLaneController)
+		round.SecondBidder = &secondBidder
+		round.SecondExpressLaneController = &secondController
+		round.SecondBid = (*hexutil.Big)(secondBid)
+	}
+	return round, nil
+}
+
+func parseAmount(amount string) (*big.Int, error) {
+	value, ok := new(big.Int).SetString(amount, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid amount %q", amount)
+	}
+	return value, nil
+}
+
+func validatedBidFromSqlite(b *SqliteDatabaseBid) (*ValidatedBid, error) {
+	chainId, err := parseAmount(b.ChainId)
+	if err != nil {
+		return nil, fmt.Errorf("invalid chain id of bid: %w", err)
+	}
+	amount, err := parseAmount(b.Amount)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := hex.DecodeString(b.Signature)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signature of bid: %w", err)
+	}
+	return &ValidatedBid{
+		ChainId:                chainId,
+		AuctionContractAddress: common.HexToAddress(b.AuctionContractAddress),
+		Signature:              signature,
+		Bidder:                 common.HexToAddress(b.Bidder),
+		ExpressLaneController:  common.HexToAddress(b.ExpressLaneController),
+		Round:                  b.Round,
+		Amount:                 amount,
+	}, nil
+}
+
+// AuctionHistoryAPI is the read-only JSON-RPC API of the auction history, in the "auctionhistory" namespace.
+type AuctionHistoryAPI struct {
+	history *AuctionHistory
+}
+
+// Rounds lists the auction rounds in the range, both ends included and optional, up to the limit.
+func (a *AuctionHistoryAPI) Rounds(ctx context.Context, fromRound, toRound *hexutil.Uint64, limit *int) (*AuctionRoundsPage, error) {
+	from, to, pageLimit := uint64(0), uint64(math.MaxUint64), 0
+	if fromRound != nil {
+		from = uint64(*fromRound)
+	}
+	if toRound != nil {
+		to = uint64(*toRound)
+	}
+	if limit != nil {
+		pageLimit = *limit
+	}
+	return a.history.Rounds(ctx, from, to, pageLimit)
+}
+
+func (a *AuctionHistoryAPI) Round(ctx context.Context, round hexutil.Uint64) (*AuctionRound, error) {
+	return a.history.Round(ctx, uint64(round))
+}
+
+func (a *AuctionHistoryAPI) RoundBids(ctx context.Context, round hexutil.Uint64) ([]*JsonValidatedBid, error) {
+	return a.history.RoundBids(ctx, uint64(round))
+}
+
+// StartAuctionHistoryServer serves the JSON-RPC API at the root, and the same queries over plain HTTP:
+//
+//	GET /rounds?from=<round>&to=<round>&limit=<count>
+//	GET /rounds/<round>
+//	GET /rounds/<round>/bids
+func StartAuctionHistoryServer(ctx context.Context, history *AuctionHistory) (*http.Server, error) {
+	config := history.config
+	listener, err := net.Listen("tcp", fmt.Sprintf("%s:%d", config.Addr, config.Port))
+	if err != nil {
+		return nil, err
+	}
+	rpcServer := rpc.NewServer()
+	if err := rpcServer.RegisterName(AuctionHistoryNamespace, &AuctionHistoryAPI{history}); err != nil {
+		return nil, err
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/", rpcServer)
+	mux.HandleFunc("GET /rounds", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		from, err := parseRoundParam(query.Get("from"), 0)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		to, err := parseRoundParam(query.Get("to"), math.MaxUint64)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if from > to {
+			http.Error(w, "invalid round range", http.StatusBadRequest)
+			return
+		}
+		limit := 0
+		if limitParam := query.Get("limit"); limitParam != "" {
+			limit, err = strconv.Atoi(limitParam)
+			if err != nil {
+				http.Error(w, "invalid limit", http.StatusBadRequest)
+				return
+			}
+		}
+		page, err := history.Rounds(r.Context(), from, to, limit)
+		writeAuctionHistoryResponse(w, page, err)
+	})
+	mux.HandleFunc("GET /rounds/{round}", func(w http.ResponseWriter, r *http.Request) {
+		round, err := strconv.ParseUint(r.PathValue("round"), 10, 64)
+		if err != nil {
+			http.Error(w, "invalid round", http.StatusBadRequest)
+			return
+		}
+		auctionRound, err := history.Round(r.Context(), round)
+		if err == nil && auctionRound == nil {
+			http.Error(w, "round not found", http.StatusNotFound)
+			return
+		}
+		writeAuctionHistoryResponse(w, auctionRound, err)
+	})
+	mux.HandleFunc("GET /rounds/{round}/bids", func(w http.ResponseWriter, r *http.Request) {
+		round, err := strconv.ParseUint(r.PathValue("round"), 10, 64)
+		if err != nil {
+			http.Error(w, "invalid round", http.StatusBadRequest)
+			return
+		}
+		bids, err := history.RoundBids(r.Context(), round)
+		writeAuctionHistoryResponse(w, bids, err)
+	})
+
+	srv := &http.Server{
+		Handler:           mux,
+		ReadTimeout:       config.ServerTimeouts.ReadTimeout,
+		ReadHeaderTimeout: config.ServerTimeouts.ReadHeaderTimeout,
+		WriteTimeout:      config.ServerTimeouts.WriteTimeout,
+		IdleTimeout:       config.ServerTimeouts.IdleTimeout,
+	}
+	go func() {
+		if err := srv.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Error("Auction history server stopped", "err", err)
+		}
+	}()
+	go func() {
+		<-ctx.Done()
+		_ = srv.Shutdown(context.Background())
+	}()
+	log.Info("Auction history API listening", "addr", listener.Addr())
+	return srv, nil
+}
+
+func parseRoundParam(param string, defaultRound uint64) (uint64, error) {
+	if param == "" {
+		return defaultRound, nil
+	}
+	round, err := strconv.ParseUint(param, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid round %q", param)
+	}
+	return round, nil
+}
+
+func writeAuctionHistoryResponse(w http.ResponseWriter, response any, err error) {
+	if err != nil {
+		log.Warn("Error serving auction history request", "err", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Warn("Error writing auction history response", "err", err)
+	}
+}
@@ -0,0 +1,174 @@
+package timeboost
+
+import (
+	"context"
+	"math"
+	"math/big"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// testAuctionArchive lists the batches of the mock S3 client, which can't list objects.
+type testAuctionArchive struct {
+	*S3StorageService
+	mockClient *mockS3FullClient
+}
+
+func (a *testAuctionArchive) listBatches(_ context.Context) ([]archivedBatch, error) {
+	var batches []archivedBatch
+	for key := range a.mockClient.data {
+		if batch, ok := parseBatchName(key); ok {
+			batches = append(batches, batch)
+		}
+	}
+	sort.Slice(batches, func(i, j int) bool { return batches[i].firstRound < batches[j].firstRound })
+	return batches, nil
+}
+
+func TestAuctionHistory(t *testing.T) {
+	ctx := context.Background()
+	db, err := NewDatabase(t.TempDir())
+	require.NoError(t, err)
+
+	controllerA := common.HexToAddress("0x000000000000000000000000000000000000000a")
+	controllerB := common.HexToAddress("0x000000000000000000000000000000000000000b")
+	controllerC := common.HexToAddress("0x000000000000000000000000000000000000000c")
+	insertBid := func(round uint64, controller common.Address, amount int64) {
+		require.NoError(t, db.InsertBid(&ValidatedBid{
+			ChainId:                big.NewInt(1),
+			ExpressLaneController:  controller,
+			AuctionContractAddress: common.HexToAddress("0x0000000000000000000000000000000000000001"),
+			Bidder:                 controller,
+			Round:                  round,
+			Amount:                 big.NewInt(amount),
+			Signature:              []byte("signature"),
+		}))
+	}
+	insertBid(1, controllerA, 100)
+	insertBid(1, controllerB, 200)
+	insertBid(2, controllerC, 50)
+	insertBid(3, controllerA, 250)
+
+	// Archive the bids of rounds 1 and 2 to S3, which deletes them from the database.
+	mockClient := newmockS3FullClient()
+	s3StorageService := &S3StorageService{
+		client: mockClient,
+		config: &S3StorageServiceConfig{MaxBatchSize: 0},
+		sqlDB:  db,
+	}
+	s3StorageService.uploadBatches(ctx)
+	bids, err := db.GetRoundBids(1)
+	require.NoError(t, err)
+	require.Empty(t, bids)
+	insertBid(3, controllerA, 300)
+	insertBid(6, controllerB, 400)
+
+	// The auctioneer recorded the results from round 4 on, the bids of round 3 are still in the database.
+	for round := uint64(4); round <= 5; round++ {
+		require.NoError(t, db.InsertAuctionResult(&SqliteDatabaseAuctionResult{
+			Round:                      round,
+			FirstBidder:                controllerA.Hex(),
+			FirstExpressLaneController: controllerA.Hex(),
+			FirstAmount:                "300",
+			Price:                      "10",
+			BidCount:                   1,
+			TxHash:                     common.HexToHash("0x01").Hex(),
+		}))
+	}
+
+	config := DefaultAuctionHistoryConfig
+	config.Enable = true
+	history := &AuctionHistory{
+		config:   &config,
+		database: db,
+		archive:  &testAuctionArchive{S3StorageService: s3StorageService, mockClient: mockClient},
+		// Round 5 is the current round, so the bid for round 6 is in an auction that isn't closed yet.
+		roundTimingInfo: RoundTimingInfo{
+			Offset: time.Now().Add(-5*time.Minute - 30*time.Second),
+			Round:  time.Minute,
+		},
+	}
+
+	page, err := history.Rounds(ctx, 0, math.MaxUint64, 0)
+	require.NoError(t, err)
+	require.Nil(t, page.Next)
+	require.Len(t, page.Rounds, 5)
+
+	archived := page.Rounds[0]
+	require.Equal(t, hexutil.Uint64(1), archived.Round)
+	require.True(t, archived.Archived)
+	require.Equal(t, controllerB, archived.ExpressLaneController)
+	require.Equal(t, big.NewInt(200), archived.FirstBid.ToInt())
+	require.Equal(t, controllerA, *archived.SecondExpressLaneController)
+	require.Equal(t, big.NewInt(100), archived.ClearingPrice.ToInt())
+	require.Equal(t, hexutil.Uint64(2), archived.BidCount)
+
+	single := page.Rounds[1]
+	require.Equal(t, controllerC, single.ExpressLaneController)
+	require.Nil(t, single.SecondBid)
+	require.Nil(t, single.ClearingPrice, "the reserve price paid for a single bid isn't archived")
+
+	unarchived := page.Rounds[2]
+	require.Equal(t, hexutil.Uint64(3), unarchived.Round)
+	require.False(t, unarchived.Archived)
+	require.Nil(t, unarchived.TxHash)
+	require.Equal(t, big.NewInt(300), unarchived.FirstBid.ToInt())
+	require.Equal(t, hexutil.Uint64(2), unarchived.BidCount, "every validated bid counts, not only the last of each controller")
+
+	recorded := page.Rounds[3]
+	require.Equal(t, hexutil.Uint64(4), recorded.Round)
+	require.False(t, recorded.Archived)
+	require.Equal(t, big.NewInt(10), recorded.ClearingPrice.ToInt())
+	require.NotNil(t, recorded.TxHash)
+
+	// Pagination goes from the archive to the bids in the database and then the recorded results.
+	page, err = history.Rounds(ctx, 0, math.MaxUint64, 2)
+	require.NoError(t, err)
+	require.Len(t, page.Rounds, 2)
+	require.NotNil(t, page.Next)
+	require.Equal(t, hexutil.Uint64(3), *page.Next)
+	page, err = history.Rounds(ctx, uint64(*page.Next), math.MaxUint64, 2)
+	require.NoError(t, err)
+	require.Len(t, page.Rounds, 2)
+	require.NotNil(t, page.Next)
+	require.Equal(t, hexutil.Uint64(5), *page.Next)
+	page, err = history.Rounds(ctx, uint64(*page.Next), math.MaxUint64, 2)
+	require.NoError(t, err)
+	require.Len(t, page.Rounds, 1)
+	require.Nil(t, page.Next)
+
+	// Round ranges
+	page, err = history.Rounds(ctx, 2, 4, 0)
+	require.NoError(t, err)
+	require.Len(t, page.Rounds, 3)
+	_, err = history.Rounds(ctx, 3, 2, 0)
+	require.Error(t, err)
+
+	round, err := history.Round(ctx, 5)
+	require.NoError(t, err)
+	require.Equal(t, hexutil.Uint64(5), round.Round)
+	round, err = history.Round(ctx, 6)
+	require.NoError(t, err)
+	require.Nil(t, round)
+
+	// Bids come from the database, or else the archive.
+	jsonBids, err := history.RoundBids(ctx, 3)
+	require.NoError(t, err)
+	require.Len(t, jsonBids, 2)
+	jsonBids, err = history.RoundBids(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, jsonBids, 2)
+
+	// Without the S3 fallback only the bids in the database and the recorded results are served.
+	history.archive = nil
+	page, err = history.Rounds(ctx, 0, math.MaxUint64, 0)
+	require.NoError(t, err)
+	require.Len(t, page.Rounds, 3)
+	require.Equal(t, hexutil.Uint64(3), page.Rounds[0].Round)
+}
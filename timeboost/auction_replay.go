@@ -0,0 +1,343 @@
+package timeboost
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/offchainlabs/nitro/util/arbmath"
+)
+
+const (
+	// TieBreakingHash breaks ties between bids of the same amount by BigIntHash, like the auction contract.
+	TieBreakingHash = "hash"
+	// TieBreakingFirstSeen breaks ties in favor of the bid validated first.
+	TieBreakingFirstSeen = "first-seen"
+	// TieBreakingLastSeen breaks ties in favor of the bid validated last.
+	TieBreakingLastSeen = "last-seen"
+)
+
+// AuctionReplayRules are the auction parameters auctions are replayed under.
+type AuctionReplayRules struct {
+	RoundTimingInfo      RoundTimingInfo
+	ReservePrice         *big.Int
+	ExpressLaneAdvantage time.Duration
+	TieBreaking          string
+	// ScaleBids scales the bid amounts by how much the round duration times the express lane advantage changes
+	// from production.
+	ScaleBids bool
+}
+
+func (r *AuctionReplayRules) Validate() error {
+	if r.ReservePrice == nil || r.ReservePrice.Sign() < 0 {
+		return errors.New("replay reserve price must be non-negative")
+	}
+	if r.ExpressLaneAdvantage <= 0 {
+		return errors.New("replay express lane advantage must be positive")
+	}
+	switch r.TieBreaking {
+	case TieBreakingHash, TieBreakingFirstSeen, TieBreakingLastSeen:
+	default:
+		return fmt.Errorf("unknown tie-breaking %q, expected one of %s, %s or %s", r.TieBreaking, TieBreakingHash, TieBreakingFirstSeen, TieBreakingLastSeen)
+	}
+	return nil
+}
+
+// AuctionReplayReport is the outcome of the auctions replayed under some rules.
+type AuctionReplayReport struct {
+	RoundDuration        string `json:"roundDuration"`
+	AuctionClosing       string `json:"auctionClosing"`
+	ReservePrice         string `json:"reservePrice"`
+	ExpressLaneAdvantage string `json:"expressLaneAdvantage"`
+	TieBreaking          string `json:"tieBreaking"`
+	ScaleBids            bool   `json:"scaleBids"`
+
+	Bids uint64 `json:"bids"`
+	// RejectedBids counts the bids the rules rejected, by the reason the bid validator gave.
+	RejectedBids    map[string]uint64 `json:"rejectedBids"`
+	AuctionedRounds uint64            `json:"auctionedRounds"`
+	SingleBidRounds uint64            `json:"singleBidRounds"`
+	// Revenue is what the winners of the auctions paid, in wei of the bidding token.
+	Revenue     *big.Int                   `json:"revenue"`
+	Controllers []*AuctionReplayController `json:"controllers"`
+}
+
+// AuctionReplayController is the share of the auctioned rounds an express lane controller won.
+type AuctionReplayController struct {
+	ExpressLaneController common.Address `json:"expressLaneController"`
+	RoundsWon             uint64         `json:"roundsWon"`
+	Share                 float64        `json:"share"`
+	Paid                  *big.Int       `json:"paid"`
+}
+
+// AuctionReplayer resolves historical bids again, under the production rules or alternative ones, with the bid
+// validation and auction resolution logic of the auctioneer.
+//
+// The archive doesn't record when bids arrived, so bids are replayed as if they arrived in the middle of the bidding
+// window of their round under the production round timing. Under a different round timing, a bid is for the round
+// upcoming at that time, and is rejected if that round's auction is closed by then. If a controller has several bids
+// for a round, the last one validated counts, like in the auctioneer.
+//
+// How much the express lane is worth to bidders isn't recorded either, so bids are replayed with the amounts they
+// were placed with. If the rules opt into ScaleBids, bid amounts are assumed to be proportional to the round
+// duration times the express lane advantage, and are scaled by how much those change from production.
+type AuctionReplayer struct {
+	chainId             *big.Int
+	auctionContractAddr common.Address
+	domainSeparator     [32]byte
+	production          AuctionReplayRules
+	balanceCheckerFn    func(opts *bind.CallOpts, account common.Address) (*big.Int, error)
+}
+
+// NewAuctionReplayer makes a replayer for the auctions of the contract, under the given production rules. The
+// balance checker is called with the bidder of each bid, and may be nil to skip checking deposits.
+func NewAuctionReplayer(
+	chainId *big.Int,
+	auctionContractAddr common.Address,
+	domainSeparator [32]byte,
+	production AuctionReplayRules,
+	balanceCheckerFn func(opts *bind.CallOpts, account common.Address) (*big.Int, error),
+) (*AuctionReplayer, error) {
+	if err := production.Validate(); err != nil {
+		return nil, err
+	}
+	if balanceCheckerFn == nil {
+		balanceCheckerFn = func(_ *bind.CallOpts, _ common.Address) (*big.Int, error) {
+			return abi.MaxUint256, nil
+		}
+	}
+	return &AuctionReplayer{
+		chainId:             chainId,
+		auctionContractAddr: auctionContractAddr,
+		domainSeparator:     domainSeparator,
+		production:          production,
+		balanceCheckerFn:    balanceCheckerFn,
+	}, nil
+}
+
+// LoadReplayBids reads the validated bids with rounds from fromRound to toRound, from the batches archived to S3
+// if s3StorageService isn't nil and from the database. The bids are in round order.
+func LoadReplayBids(ctx context.Context, database *SqliteDatabase, s3StorageService *S3StorageService, fromRound, toRound uint64) ([]*SqliteDatabaseBid, error) {
+	var bids []*SqliteDatabaseBid
+	if s3StorageService != nil {
+		batches, err := s3StorageService.listBatches(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error listing archived batches: %w", err)
+		}
+		for _, batch := range batches {
+			if batch.lastRound < fromRound || batch.firstRound > toRound {
+				continue
+			}
+			batchBids, err := s3StorageService.readBatch(ctx, batch.key)
+			if err != nil {
+				return nil, fmt.Errorf("error reading batch %s: %w", batch.key, err)
+			}
+			for _, bid := range batchBids {
+				if bid.Round >= fromRound && bid.Round <= toRound {
+					bids = append(bids, bid)
+				}
+			}
+		}
+	}
+	if database != nil {
+		dbBids, err := database.GetBidsInRounds(fromRound, toRound)
+		if err != nil {
+			return nil, err
+		}
+		bids = append(bids, dbBids...)
+	}
+	// Bids whose deletion from the database failed after being archived are in both.
+	type bidKey struct {
+		round     uint64
+		signature string
+	}
+	seen := make(map[bidKey]struct{}, len(bids))
+	unique := make([]*SqliteDatabaseBid, 0, len(bids))
+	for _, bid := range bids {
+		key := bidKey{bid.Round, bid.Signature}
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		unique = append(unique, bid)
+	}
+	sort.SliceStable(unique, func(i, j int) bool { return unique[i].Round < unique[j].Round })
+	return unique, nil
+}
+
+// Replay resolves the auctions of the bids, which must be in round order, under the rules.
+func (r *AuctionReplayer) Replay(bids []*SqliteDatabaseBid, rules *AuctionReplayRules) (*AuctionReplayReport, error) {
+	if err := rules.Validate(); err != nil {
+		return nil, err
+	}
+	// The bid amounts are scaled by num/den.
+	num, den := big.NewInt(1), big.NewInt(1)
+	if rules.ScaleBids {
+		num.Mul(big.NewInt(int64(rules.RoundTimingInfo.Round)), big.NewInt(int64(rules.ExpressLaneAdvantage)))
+		den.Mul(big.NewInt(int64(r.production.RoundTimingInfo.Round)), big.NewInt(int64(r.production.ExpressLaneAdvantage)))
+	}
+	// A scaled amount meets the reserve price if the amount meets the reserve price scaled by den/num, rounded up.
+	unscaledReservePrice := new(big.Int).Mul(rules.ReservePrice, den)
+	unscaledReservePrice.Add(unscaledReservePrice, new(big.Int).Sub(num, common.Big1))
+	unscaledReservePrice.Div(unscaledReservePrice, num)
+
+	validator := &BidValidator{
+		chainId:                        r.chainId,
+		auctionContractAddr:            r.auctionContractAddr,
+		auctionContractDomainSeparator: r.domainSeparator,
+		roundTimingInfo:                r.production.RoundTimingInfo,
+		reservePrice:                   unscaledReservePrice,
+		bidsPerSenderInRound:           make(map[common.Address]uint8),
+		maxBidsPerSenderInRound:        defaultMaxBidsPerSenderInRound,
+	}
+
+	arrivals := make(map[*ValidatedBid]int)
+	var winsTieFn func(bid, other *ValidatedBid) bool
+	switch rules.TieBreaking {
+	case TieBreakingFirstSeen:
+		winsTieFn = func(bid, other *ValidatedBid) bool { return arrivals[bid] < arrivals[other] }
+	case TieBreakingLastSeen:
+		winsTieFn = func(bid, other *ValidatedBid) bool { return arrivals[bid] > arrivals[other] }
+	}
+
+	report := &AuctionReplayReport{
+		RoundDuration:        rules.RoundTimingInfo.Round.String(),
+		AuctionClosing:       rules.RoundTimingInfo.AuctionClosing.String(),
+		ReservePrice:         rules.ReservePrice.String(),
+		ExpressLaneAdvantage: rules.ExpressLaneAdvantage.String(),
+		TieBreaking:          rules.TieBreaking,
+		ScaleBids:            rules.ScaleBids,
+		RejectedBids:         make(map[string]uint64),
+		Revenue:              new(big.Int),
+	}
+	caches := make(map[uint64]*bidCache)
+	var lastRound uint64
+	for i, sqlBid := range bids {
+		report.Bids++
+		if sqlBid.Round != lastRound {
+			validator.bidsPerSenderInRound = make(map[common.Address]uint8)
+			lastRound = sqlBid.Round
+		}
+		stored, err := validatedBidFromSqlite(sqlBid)
+		if err != nil {
+			return nil, fmt.Errorf("invalid stored bid of round %d: %w", sqlBid.Round, err)
+		}
+		bid := &Bid{
+			ChainId:                stored.ChainId,
+			ExpressLaneController:  stored.ExpressLaneController,
+			AuctionContractAddress: stored.AuctionContractAddress,
+			Round:                  stored.Round,
+			Amount:                 stored.Amount,
+			Signature:              stored.Signature,
+		}
+		if bid.Round == 0 {
+			// There's no auction for the first round.
+			report.RejectedBids[ErrBadRoundNumber.Error()]++
+			continue
+		}
+		arrivalTime := r.arrivalTime(bid.Round)
+		jsonBid, err := validator.validateBidAt(bid, r.balanceCheckerFn, arrivalTime)
+		if err != nil {
+			reason, ok := rejectionReason(err)
+			if !ok {
+				return nil, fmt.Errorf("error validating bid of round %d: %w", bid.Round, err)
+			}
+			report.RejectedBids[reason]++
+			continue
+		}
+		if rules.RoundTimingInfo.isAuctionRoundClosedAt(arrivalTime) {
+			report.RejectedBids[ErrBadRoundNumber.Error()]++
+			continue
+		}
+		validated := JsonValidatedBidToGo(jsonBid)
+		validated.Round = rules.RoundTimingInfo.RoundNumberAt(arrivalTime) + 1
+		validated.Amount = new(big.Int).Div(new(big.Int).Mul(validated.Amount, num), den)
+		arrivals[validated] = i
+		cache, ok := caches[validated.Round]
+		if !ok {
+			cache = newBidCache(r.domainSeparator)
+			cache.winsTieFn = winsTieFn
+			caches[validated.Round] = cache
+		}
+		cache.add(validated)
+	}
+
+	rounds := make([]uint64, 0, len(caches))
+	for round := range caches {
+		rounds = append(rounds, round)
+	}
+	sort.Slice(rounds, func(i, j int) bool { return rounds[i] < rounds[j] })
+	controllers := make(map[common.Address]*AuctionReplayController)
+	for _, round := range rounds {
+		result := caches[round].topTwoBids()
+		if result.firstPlace == nil {
+			continue
+		}
+		// The winner pays the second highest bid, or the reserve price if it's the only bid.
+		price := rules.ReservePrice
+		if result.secondPlace != nil {
+			price = result.secondPlace.Amount
+		} else {
+			report.SingleBidRounds++
+		}
+		report.AuctionedRounds++
+		report.Revenue.Add(report.Revenue, price)
+		controller, ok := controllers[result.firstPlace.ExpressLaneController]
+		if !ok {
+			controller = &AuctionReplayController{
+				ExpressLaneController: result.firstPlace.ExpressLaneController,
+				Paid:                  new(big.Int),
+			}
+			controllers[controller.ExpressLaneController] = controller
+		}
+		controller.RoundsWon++
+		controller.Paid.Add(controller.Paid, price)
+	}
+	report.Controllers = make([]*AuctionReplayController, 0, len(controllers))
+	for _, controller := range controllers {
+		controller.Share = float64(controller.RoundsWon) / float64(report.AuctionedRounds)
+		report.Controllers = append(report.Controllers, controller)
+	}
+	sort.Slice(report.Controllers, func(i, j int) bool {
+		a, b := report.Controllers[i], report.Controllers[j]
+		if a.RoundsWon != b.RoundsWon {
+			return a.RoundsWon > b.RoundsWon
+		}
+		return a.ExpressLaneController.Cmp(b.ExpressLaneController) < 0
+	})
+	return report, nil
+}
+
+// arrivalTime is when a bid for the round is assumed to have arrived, the middle of the round's bidding window under
+// the production round timing.
+func (r *AuctionReplayer) arrivalTime(round uint64) time.Time {
+	timing := &r.production.RoundTimingInfo
+	biddingStart := timing.Offset.Add(timing.Round * arbmath.SaturatingCast[time.Duration](round-1))
+	return biddingStart.Add((timing.Round - timing.AuctionClosing) / 2)
+}
+
+// rejectionReason returns the reason the bid validator rejected a bid for, and false if the error isn't a
+// rejection but a failure to validate it.
+func rejectionReason(err error) (string, bool) {
+	for _, reason := range []error{
+		ErrMalformedData,
+		ErrWrongChainId,
+		ErrBadRoundNumber,
+		ErrReservePriceNotMet,
+		ErrTooManyBids,
+		ErrNotDepositor,
+		ErrInsufficientBalance,
+	} {
+		if errors.Is(err, reason) {
+			return reason.Error(), true
+		}
+	}
+	return "", false
+}
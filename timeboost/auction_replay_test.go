@@ -0,0 +1,154 @@
+package timeboost
+
+import (
+	"context"
+	"encoding/hex"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func buildReplayBid(t *testing.T, auctionContractAddr common.Address, controller common.Address, round uint64, amount int64) *ValidatedBid {
+	privateKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	bid := &Bid{
+		ChainId:                big.NewInt(1),
+		ExpressLaneController:  controller,
+		AuctionContractAddress: auctionContractAddr,
+		Round:                  round,
+		Amount:                 big.NewInt(amount),
+	}
+	bidHash, err := bid.ToEIP712Hash(common.Hash{})
+	require.NoError(t, err)
+	signature, err := crypto.Sign(bidHash[:], privateKey)
+	require.NoError(t, err)
+	return &ValidatedBid{
+		ChainId:                bid.ChainId,
+		AuctionContractAddress: auctionContractAddr,
+		Signature:              signature,
+		Bidder:                 crypto.PubkeyToAddress(privateKey.PublicKey),
+		ExpressLaneController:  controller,
+		Round:                  round,
+		Amount:                 bid.Amount,
+	}
+}
+
+func TestAuctionReplayer(t *testing.T) {
+	t.Parallel()
+	auctionContractAddr := common.Address{'x'}
+	production := AuctionReplayRules{
+		RoundTimingInfo: RoundTimingInfo{
+			Offset:            time.Unix(1_700_000_000, 0),
+			Round:             time.Minute,
+			AuctionClosing:    15 * time.Second,
+			ReserveSubmission: 15 * time.Second,
+		},
+		ReservePrice:         big.NewInt(2),
+		ExpressLaneAdvantage: 200 * time.Millisecond,
+		TieBreaking:          TieBreakingHash,
+	}
+	replayer, err := NewAuctionReplayer(big.NewInt(1), auctionContractAddr, common.Hash{}, production, nil)
+	require.NoError(t, err)
+
+	a, b, c, d, e, f := common.Address{'a'}, common.Address{'b'}, common.Address{'c'}, common.Address{'d'}, common.Address{'e'}, common.Address{'f'}
+	var bids []*SqliteDatabaseBid
+	for _, bid := range []*ValidatedBid{
+		buildReplayBid(t, auctionContractAddr, a, 5, 10),
+		buildReplayBid(t, auctionContractAddr, b, 5, 7),
+		buildReplayBid(t, auctionContractAddr, c, 6, 5),
+		buildReplayBid(t, auctionContractAddr, f, 7, 1),
+		buildReplayBid(t, auctionContractAddr, d, 8, 4),
+		buildReplayBid(t, auctionContractAddr, e, 8, 4),
+	} {
+		bids = append(bids, &SqliteDatabaseBid{
+			ChainId:                bid.ChainId.String(),
+			Bidder:                 bid.Bidder.Hex(),
+			ExpressLaneController:  bid.ExpressLaneController.Hex(),
+			AuctionContractAddress: bid.AuctionContractAddress.Hex(),
+			Round:                  bid.Round,
+			Amount:                 bid.Amount.String(),
+			Signature:              hex.EncodeToString(bid.Signature),
+		})
+	}
+
+	report, err := replayer.Replay(bids, &production)
+	require.NoError(t, err)
+	require.Equal(t, uint64(6), report.Bids)
+	require.Equal(t, map[string]uint64{ErrReservePriceNotMet.Error(): 1}, report.RejectedBids)
+	require.Equal(t, uint64(3), report.AuctionedRounds)
+	require.Equal(t, uint64(1), report.SingleBidRounds)
+	// 7 for round 5, the reserve price for round 6, and 4 for round 8.
+	require.Equal(t, big.NewInt(13), report.Revenue)
+	require.Len(t, report.Controllers, 3)
+
+	rules := production
+	rules.ReservePrice = big.NewInt(6)
+	report, err = replayer.Replay(bids, &rules)
+	require.NoError(t, err)
+	require.Equal(t, map[string]uint64{ErrReservePriceNotMet.Error(): 4}, report.RejectedBids)
+	require.Equal(t, uint64(1), report.AuctionedRounds)
+	require.Equal(t, big.NewInt(7), report.Revenue)
+	require.Equal(t, []*AuctionReplayController{{ExpressLaneController: a, RoundsWon: 1, Share: 1, Paid: big.NewInt(7)}}, report.Controllers)
+
+	// Bids aren't scaled unless the rules opt into it.
+	rules = production
+	rules.ExpressLaneAdvantage = 400 * time.Millisecond
+	report, err = replayer.Replay(bids, &rules)
+	require.NoError(t, err)
+	require.Equal(t, map[string]uint64{ErrReservePriceNotMet.Error(): 1}, report.RejectedBids)
+	require.Equal(t, big.NewInt(13), report.Revenue)
+	require.False(t, report.ScaleBids)
+
+	// Doubling the advantage doubles the bids, so the bid of 1 in round 7 meets the reserve price.
+	rules.ScaleBids = true
+	report, err = replayer.Replay(bids, &rules)
+	require.NoError(t, err)
+	require.Empty(t, report.RejectedBids)
+	require.Equal(t, uint64(4), report.AuctionedRounds)
+	require.Equal(t, uint64(2), report.SingleBidRounds)
+	require.Equal(t, big.NewInt(14+2+2+8), report.Revenue)
+
+	// Doubling the round duration doubles the bids too, and rounds 5 and 6 become round 3, and rounds 7 and 8 round 4.
+	rules = production
+	rules.RoundTimingInfo.Round = 2 * time.Minute
+	rules.ScaleBids = true
+	report, err = replayer.Replay(bids, &rules)
+	require.NoError(t, err)
+	require.Empty(t, report.RejectedBids)
+	require.Equal(t, uint64(2), report.AuctionedRounds)
+	require.Equal(t, uint64(0), report.SingleBidRounds)
+	require.Equal(t, big.NewInt(14+8), report.Revenue)
+
+	rules = production
+	rules.TieBreaking = TieBreakingFirstSeen
+	report, err = replayer.Replay(bids[4:], &rules)
+	require.NoError(t, err)
+	require.Equal(t, d, report.Controllers[0].ExpressLaneController)
+	rules.TieBreaking = TieBreakingLastSeen
+	report, err = replayer.Replay(bids[4:], &rules)
+	require.NoError(t, err)
+	require.Equal(t, e, report.Controllers[0].ExpressLaneController)
+
+	rules.TieBreaking = "random"
+	_, err = replayer.Replay(bids, &rules)
+	require.ErrorContains(t, err, "unknown tie-breaking")
+}
+
+func TestLoadReplayBids(t *testing.T) {
+	t.Parallel()
+	db, err := NewDatabase(t.TempDir())
+	require.NoError(t, err)
+	auctionContractAddr := common.Address{'x'}
+	for _, round := range []uint64{3, 1, 2, 4, 2} {
+		require.NoError(t, db.InsertBid(buildReplayBid(t, auctionContractAddr, common.Address{'a'}, round, 10)))
+	}
+	bids, err := LoadReplayBids(context.Background(), db, nil, 2, 3)
+	require.NoError(t, err)
+	require.Len(t, bids, 3)
+	require.Equal(t, []uint64{2, 2, 3}, []uint64{bids[0].Round, bids[1].Round, bids[2].Round})
+}
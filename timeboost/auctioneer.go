@@ -67,6 +67,7 @@ type AuctioneerServerConfig struct {
 	DbDirectory               string                   `koanf:"db-directory"`
 	AuctionResolutionWaitTime time.Duration            `koanf:"auction-resolution-wait-time"`
 	S3Storage                 S3StorageServiceConfig   `koanf:"s3-storage"`
+	History                   AuctionHistoryConfig     `koanf:"history"`
 }
 
 var DefaultAuctioneerServerConfig = AuctioneerServerConfig{
@@ -76,6 +77,7 @@ var DefaultAuctioneerServerConfig = AuctioneerServerConfig{
 	StreamTimeout:             10 * time.Minute,
 	AuctionResolutionWaitTime: 2 * time.Second,
 	S3Storage:                 DefaultS3StorageServiceConfig,
+	History:                   DefaultAuctionHistoryConfig,
 }
 
 var TestAuctioneerServerConfig = AuctioneerServerConfig{
@@ -100,6 +102,7 @@ func AuctioneerServerConfigAddOptions(prefix string, f *pflag.FlagSet) {
 	f.String(prefix+".db-directory", DefaultAuctioneerServerConfig.DbDirectory, "path to database directory for persisting validated bids in a sqlite file")
 	f.Duration(prefix+".auction-resolution-wait-time", DefaultAuctioneerServerConfig.AuctionResolutionWaitTime, "wait time after auction closing before resolving the auction")
 	S3StorageServiceConfigAddOptions(prefix+".s3-storage", f)
+	AuctionHistoryConfigAddOptions(prefix+".history", f)
 }
 
 // AuctioneerServer is a struct that represents an autonomous auctioneer.
@@ -120,6 +123,7 @@ type AuctioneerServer struct {
 	auctionResolutionWaitTime      time.Duration
 	database                       *SqliteDatabase
 	s3StorageService               *S3StorageService
+	history                        *AuctionHistory
 }
 
 // NewAuctioneerServer creates a new autonomous auctioneer struct.
@@ -201,12 +205,20 @@ func NewAuctioneerServer(ctx context.Context, configFetcher AuctioneerServerConf
 	if err = roundTimingInfo.ValidateResolutionWaitTime(cfg.AuctionResolutionWaitTime); err != nil {
 		return nil, err
 	}
+	var history *AuctionHistory
+	if cfg.History.Enable {
+		history, err = NewAuctionHistory(&cfg.History, database, s3StorageService, domainSeparator, *roundTimingInfo)
+		if err != nil {
+			return nil, err
+		}
+	}
 	return &AuctioneerServer{
 		txOpts:                         txOpts,
 		endpointManager:                endpointManager,
 		chainId:                        chainId,
 		database:                       database,
 		s3StorageService:               s3StorageService,
+		history:                        history,
 		consumer:                       c,
 		auctionContract:                auctionContract,
 		auctionContractAddr:            auctionContractAddr,
@@ -224,6 +236,11 @@ func (a *AuctioneerServer) Start(ctx_in context.Context) {
 	if a.s3StorageService != nil {
 		a.s3StorageService.Start(ctx_in)
 	}
+	if a.history != nil {
+		if _, err := StartAuctionHistoryServer(ctx_in, a.history); err != nil {
+			log.Error("Failed to start auction history server", "err", err)
+		}
+	}
 	// Channel that consumer uses to indicate its readiness.
 	readyStream := make(chan struct{}, 1)
 	a.consumer.Start(ctx_in)
@@ -334,6 +351,7 @@ func (a *AuctioneerServer) Start(ctx_in context.Context) {
 func (a *AuctioneerServer) resolveAuction(ctx context.Context) error {
 	upcomingRound := a.roundTimingInfo.RoundNumber() + 1
 	result := a.bidCache.topTwoBids()
+	bidCount := a.bidCache.validatedBids()
 	first := result.firstPlace
 	second := result.secondPlace
 	var tx *types.Transaction
@@ -396,6 +414,7 @@ func (a *AuctioneerServer) resolveAuction(ctx context.Context) error {
 	roundEndTime := a.roundTimingInfo.TimeOfNextRound()
 	retryInterval := 1 * time.Second
 
+	var receipt *types.Receipt
 	if err := retryUntil(ctx, func() error {
 		if err := sequencerRpc.CallContext(ctx, nil, "auctioneer_submitAuctionResolutionTransaction", tx); err != nil {
 			log.Error("Error submitting auction resolution to sequencer endpoint", "error", err)
@@ -403,7 +422,8 @@ func (a *AuctioneerServer) resolveAuction(ctx context.Context) error {
 		}
 
 		// Wait for the transaction to be mined
-		receipt, err := bind.WaitMined(ctx, ethclient.NewClient(sequencerRpc), tx)
+		var err error
+		receipt, err = bind.WaitMined(ctx, ethclient.NewClient(sequencerRpc), tx)
 		if err != nil {
 			log.Error("Error waiting for transaction to be mined", "error", err)
 			return err
@@ -423,9 +443,41 @@ func (a *AuctioneerServer) resolveAuction(ctx context.Context) error {
 	}
 
 	log.Info("Auction resolved successfully", "txHash", tx.Hash().Hex())
+	a.persistAuctionResult(receipt, second, bidCount)
 	return nil
 }
 
+// persistAuctionResult records the auction resolved by the receipt for the auction history API.
+func (a *AuctioneerServer) persistAuctionResult(receipt *types.Receipt, second *ValidatedBid, bidCount int) {
+	for _, l := range receipt.Logs {
+		event, err := a.auctionContract.ParseAuctionResolved(*l)
+		if err != nil {
+			continue
+		}
+		result := &SqliteDatabaseAuctionResult{
+			Round:                      event.Round,
+			FirstBidder:                event.FirstPriceBidder.Hex(),
+			FirstExpressLaneController: event.FirstPriceExpressLaneController.Hex(),
+			FirstAmount:                event.FirstPriceAmount.String(),
+			Price:                      event.Price.String(),
+			// #nosec G115
+			BidCount:   uint64(bidCount),
+			TxHash:     receipt.TxHash.Hex(),
+			ResolvedAt: time.Now().Unix(),
+		}
+		if second != nil {
+			result.SecondBidder = second.Bidder.Hex()
+			result.SecondExpressLaneController = second.ExpressLaneController.Hex()
+			result.SecondAmount = second.Amount.String()
+		}
+		if err := a.database.InsertAuctionResult(result); err != nil {
+			log.Error("Could not persist auction result to database", "round", event.Round, "err", err)
+		}
+		return
+	}
+	log.Warn("Auction resolution receipt has no AuctionResolved event", "txHash", receipt.TxHash)
+}
+
 // retryUntil retries a given operation defined by the closure until the specified duration
 // has passed or the operation succeeds. It waits for the specified retry interval between
 // attempts. The function returns an error if all attempts fail.
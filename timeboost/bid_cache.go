@@ -10,6 +10,11 @@ type bidCache struct {
 	auctionContractDomainSeparator [32]byte
 	sync.RWMutex
 	bidsByExpressLaneControllerAddr map[common.Address]*ValidatedBid
+	// bidCount counts the bids added, including those replaced by a later bid of the same controller.
+	bidCount int
+	// winsTieFn overrides how ties between bids of the same amount are broken, which is by BigIntHash like the
+	// auction contract when nil.
+	winsTieFn func(bid, other *ValidatedBid) bool
 }
 
 func newBidCache(auctionContractDomainSeparator [32]byte) *bidCache {
@@ -23,6 +28,7 @@ func (bc *bidCache) add(bid *ValidatedBid) {
 	bc.Lock()
 	defer bc.Unlock()
 	bc.bidsByExpressLaneControllerAddr[bid.ExpressLaneController] = bid
+	bc.bidCount++
 }
 
 // TwoTopBids returns the top two bids for the given chain ID and round
@@ -38,6 +44,13 @@ func (bc *bidCache) size() int {
 
 }
 
+// validatedBids returns how many bids were added to the cache, counting every bid of a controller.
+func (bc *bidCache) validatedBids() int {
+	bc.RLock()
+	defer bc.RUnlock()
+	return bc.bidCount
+}
+
 // topTwoBids returns the top two bids in the cache.
 func (bc *bidCache) topTwoBids() *auctionResult {
 	bc.RLock()
@@ -52,16 +65,16 @@ func (bc *bidCache) topTwoBids() *auctionResult {
 			result.secondPlace = result.firstPlace
 			result.firstPlace = bid
 		} else if bid.Amount.Cmp(result.firstPlace.Amount) == 0 {
-			if bid.BigIntHash(bc.auctionContractDomainSeparator).Cmp(result.firstPlace.BigIntHash(bc.auctionContractDomainSeparator)) > 0 {
+			if bc.winsTie(bid, result.firstPlace) {
 				result.secondPlace = result.firstPlace
 				result.firstPlace = bid
-			} else if result.secondPlace == nil || bid.BigIntHash(bc.auctionContractDomainSeparator).Cmp(result.secondPlace.BigIntHash(bc.auctionContractDomainSeparator)) > 0 {
+			} else if result.secondPlace == nil || bc.winsTie(bid, result.secondPlace) {
 				result.secondPlace = bid
 			}
 		} else if result.secondPlace == nil || bid.Amount.Cmp(result.secondPlace.Amount) > 0 {
 			result.secondPlace = bid
 		} else if bid.Amount.Cmp(result.secondPlace.Amount) == 0 {
-			if bid.BigIntHash(bc.auctionContractDomainSeparator).Cmp(result.secondPlace.BigIntHash(bc.auctionContractDomainSeparator)) > 0 {
+			if bc.winsTie(bid, result.secondPlace) {
 				result.secondPlace = bid
 			}
 		}
@@ -69,3 +82,11 @@ func (bc *bidCache) topTwoBids() *auctionResult {
 
 	return result
 }
+
+// winsTie returns whether bid ranks above other, a bid of the same amount.
+func (bc *bidCache) winsTie(bid, other *ValidatedBid) bool {
+	if bc.winsTieFn != nil {
+		return bc.winsTieFn(bid, other)
+	}
+	return bid.BigIntHash(bc.auctionContractDomainSeparator).Cmp(other.BigIntHash(bc.auctionContractDomainSeparator)) > 0
+}
@@ -56,6 +56,9 @@ func BidValidatorConfigAddOptions(prefix string, f *pflag.FlagSet) {
 	f.String(prefix+".auction-contract-address", DefaultAuctioneerServerConfig.AuctionContractAddress, "express lane auction contract address")
 }
 
+// defaultMaxBidsPerSenderInRound is how many bids a sender address can make in a round.
+const defaultMaxBidsPerSenderInRound = 5
+
 type BidValidator struct {
 	stopwaiter.StopWaiter
 	sync.RWMutex
@@ -142,7 +145,7 @@ func NewBidValidator(
 		reservePrice:                   reservePrice,
 		domainValue:                    domainValue,
 		bidsPerSenderInRound:           make(map[common.Address]uint8),
-		maxBidsPerSenderInRound:        5, // 5 max bids per sender address in a round.
+		maxBidsPerSenderInRound:        defaultMaxBidsPerSenderInRound,
 		producerCfg:                    &cfg.ProducerConfig,
 	}
 	api := &BidValidatorAPI{bidValidator}
@@ -275,6 +278,14 @@ func (bv *BidValidator) fetchReservePrice() *big.Int {
 func (bv *BidValidator) validateBid(
 	bid *Bid,
 	balanceCheckerFn func(opts *bind.CallOpts, account common.Address) (*big.Int, error)) (*JsonValidatedBid, error) {
+	return bv.validateBidAt(bid, balanceCheckerFn, time.Now())
+}
+
+// validateBidAt validates the bid as if it arrived at the given time.
+func (bv *BidValidator) validateBidAt(
+	bid *Bid,
+	balanceCheckerFn func(opts *bind.CallOpts, account common.Address) (*big.Int, error),
+	arrivalTime time.Time) (*JsonValidatedBid, error) {
 	// Check basic integrity.
 	if bid == nil {
 		return nil, errors.Wrap(ErrMalformedData, "nil bid")
@@ -295,13 +306,13 @@ func (bv *BidValidator) validateBid(
 	}
 
 	// Check if the bid is intended for upcoming round.
-	upcomingRound := bv.roundTimingInfo.RoundNumber() + 1
+	upcomingRound := bv.roundTimingInfo.RoundNumberAt(arrivalTime) + 1
 	if bid.Round != upcomingRound {
 		return nil, errors.Wrapf(ErrBadRoundNumber, "wanted %d, got %d", upcomingRound, bid.Round)
 	}
 
 	// Check if the auction is closed.
-	if bv.roundTimingInfo.isAuctionRoundClosed() {
+	if bv.roundTimingInfo.isAuctionRoundClosedAt(arrivalTime) {
 		return nil, errors.Wrap(ErrBadRoundNumber, "auction is closed")
 	}
 
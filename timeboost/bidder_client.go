@@ -33,16 +33,19 @@ type BidderClientConfig struct {
 	AuctionContractAddress string                   `koanf:"auction-contract-address"`
 	DepositGwei            int                      `koanf:"deposit-gwei"`
 	BidGwei                int                      `koanf:"bid-gwei"`
+	Bidding                BiddingEngineConfig      `koanf:"bidding"`
 }
 
 var DefaultBidderClientConfig = BidderClientConfig{
 	ArbitrumNodeEndpoint: "http://localhost:8547",
 	BidValidatorEndpoint: "http://localhost:9372",
+	Bidding:              DefaultBiddingEngineConfig,
 }
 
 var TestBidderClientConfig = BidderClientConfig{
 	ArbitrumNodeEndpoint: "http://localhost:8547",
 	BidValidatorEndpoint: "http://localhost:9372",
+	Bidding:              DefaultBiddingEngineConfig,
 }
 
 func BidderClientConfigAddOptions(f *pflag.FlagSet) {
@@ -52,6 +55,7 @@ func BidderClientConfigAddOptions(f *pflag.FlagSet) {
 	f.String("auction-contract-address", DefaultBidderClientConfig.AuctionContractAddress, "express lane auction contract address")
 	f.Int("deposit-gwei", DefaultBidderClientConfig.DepositGwei, "deposit amount in gwei to take from bidder's account and send to auction contract")
 	f.Int("bid-gwei", DefaultBidderClientConfig.BidGwei, "bid amount in gwei, bidder must have already deposited enough into the auction contract")
+	BiddingEngineConfigAddOptions("bidding", f)
 }
 
 type BidderClient struct {
@@ -184,6 +188,13 @@ func (bd *BidderClient) Deposit(ctx context.Context, amount *big.Int) error {
 
 func (bd *BidderClient) Bid(
 	ctx context.Context, amount *big.Int, expressLaneController common.Address,
+) (*Bid, error) {
+	return bd.BidInRound(ctx, bd.roundTimingInfo.RoundNumber()+1, amount, expressLaneController)
+}
+
+// BidInRound bids for the round, which the auctioneer only accepts while the round's auction is open.
+func (bd *BidderClient) BidInRound(
+	ctx context.Context, round uint64, amount *big.Int, expressLaneController common.Address,
 ) (*Bid, error) {
 	if (expressLaneController == common.Address{}) {
 		expressLaneController = bd.txOpts.From
@@ -199,7 +210,7 @@ func (bd *BidderClient) Bid(
 		ChainId:                bd.chainId,
 		ExpressLaneController:  expressLaneController,
 		AuctionContractAddress: bd.auctionContractAddress,
-		Round:                  bd.roundTimingInfo.RoundNumber() + 1,
+		Round:                  round,
 		Amount:                 amount,
 	}
 	bidHash, err := newBid.ToEIP712Hash(domainSeparator)
@@ -0,0 +1,153 @@
+package timeboost
+
+import (
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+const ledgerFileName = "bidder_ledger.db?_journal_mode=WAL"
+
+// BidderLedger is the local record of the bids the bidding engine submitted, the rounds it won and the deposits
+// it made, so the spend of a strategy can be audited.
+type BidderLedger struct {
+	sqlDB *sqlx.DB
+	lock  sync.Mutex
+}
+
+type LedgerBid struct {
+	Round       uint64 `db:"Round" json:"round"`
+	Strategy    string `db:"Strategy" json:"strategy"`
+	Amount      string `db:"Amount" json:"amount"`
+	SubmittedAt int64  `db:"SubmittedAt" json:"submittedAt"`
+	Won         bool   `db:"Won" json:"won"`
+	Price       string `db:"Price" json:"price"`
+}
+
+type BidderLedgerSummary struct {
+	Bids      hexutil.Uint64 `json:"bids"`
+	Wins      hexutil.Uint64 `json:"wins"`
+	Bid       *hexutil.Big   `json:"bid"`
+	Spent     *hexutil.Big   `json:"spent"`
+	Deposited *hexutil.Big   `json:"deposited"`
+}
+
+func NewBidderLedger(path string) (*BidderLedger, error) {
+	if err := os.MkdirAll(path, 0o755); err != nil {
+		return nil, err
+	}
+	db, err := sqlx.Open("sqlite3", filepath.Join(path, ledgerFileName))
+	if err != nil {
+		return nil, err
+	}
+	if err := dbInit(db, ledgerSchemaList); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &BidderLedger{
+		sqlDB: db,
+	}, nil
+}
+
+func (l *BidderLedger) RecordBid(round uint64, strategy string, amount *big.Int, submittedAt time.Time) error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	_, err := l.sqlDB.Exec(
+		`INSERT INTO LedgerBids (Round, Strategy, Amount, SubmittedAt) VALUES (?, ?, ?, ?)
+        ON CONFLICT(Round) DO UPDATE SET Strategy = excluded.Strategy, Amount = excluded.Amount, SubmittedAt = excluded.SubmittedAt`,
+		round, strategy, amount.String(), submittedAt.Unix(),
+	)
+	return err
+}
+
+// RecordWin marks the round as won for the price paid. A round won with a bid that wasn't submitted by the
+// bidding engine is recorded too, with the winning bid amount.
+func (l *BidderLedger) RecordWin(round uint64, amount *big.Int, price *big.Int) error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	_, err := l.sqlDB.Exec(
+		`INSERT INTO LedgerBids (Round, Strategy, Amount, SubmittedAt, Won, Price) VALUES (?, '', ?, 0, 1, ?)
+        ON CONFLICT(Round) DO UPDATE SET Won = 1, Price = excluded.Price`,
+		round, amount.String(), price.String(),
+	)
+	return err
+}
+
+func (l *BidderLedger) RecordDeposit(amount *big.Int, depositedAt time.Time) error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	_, err := l.sqlDB.Exec(`INSERT INTO LedgerDeposits (Amount, DepositedAt) VALUES (?, ?)`, amount.String(), depositedAt.Unix())
+	return err
+}
+
+// Bids returns the recorded bids from the round onwards, in round order.
+func (l *BidderLedger) Bids(fromRound uint64) ([]*LedgerBid, error) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	var bids []*LedgerBid
+	if err := l.sqlDB.Select(&bids, "SELECT * FROM LedgerBids WHERE Round >= ? ORDER BY Round ASC", fromRound); err != nil {
+		return nil, err
+	}
+	return bids, nil
+}
+
+// Summary totals the ledger. Amounts are stored as decimal strings, so they're summed here rather than in SQL.
+func (l *BidderLedger) Summary() (*BidderLedgerSummary, error) {
+	bids, err := l.Bids(0)
+	if err != nil {
+		return nil, err
+	}
+	l.lock.Lock()
+	var deposits []string
+	err = l.sqlDB.Select(&deposits, "SELECT Amount FROM LedgerDeposits")
+	l.lock.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	bid, spent, deposited := new(big.Int), new(big.Int), new(big.Int)
+	summary := &BidderLedgerSummary{}
+	for _, b := range bids {
+		if b.SubmittedAt != 0 {
+			summary.Bids++
+			if err := addDecimal(bid, b.Amount); err != nil {
+				return nil, err
+			}
+		}
+		if b.Won {
+			summary.Wins++
+			if err := addDecimal(spent, b.Price); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for _, amount := range deposits {
+		if err := addDecimal(deposited, amount); err != nil {
+			return nil, err
+		}
+	}
+	summary.Bid = (*hexutil.Big)(bid)
+	summary.Spent = (*hexutil.Big)(spent)
+	summary.Deposited = (*hexutil.Big)(deposited)
+	return summary, nil
+}
+
+func addDecimal(sum *big.Int, decimal string) error {
+	value, ok := new(big.Int).SetString(decimal, 10)
+	if !ok {
+		return fmt.Errorf("invalid amount %q in bidder ledger", decimal)
+	}
+	sum.Add(sum, value)
+	return nil
+}
+
+func (l *BidderLedger) Close() error {
+	return l.sqlDB.Close()
+}
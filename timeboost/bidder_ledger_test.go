@@ -0,0 +1,49 @@
+package timeboost
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBidderLedger(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	ledger, err := NewBidderLedger(dir)
+	require.NoError(t, err)
+
+	now := time.Now()
+	require.NoError(t, ledger.RecordDeposit(big.NewInt(1000), now))
+	require.NoError(t, ledger.RecordBid(1, FixedBiddingStrategy, big.NewInt(100), now))
+	require.NoError(t, ledger.RecordBid(2, FixedBiddingStrategy, big.NewInt(150), now))
+	require.NoError(t, ledger.RecordWin(2, big.NewInt(150), big.NewInt(120)))
+	// A round won with a bid made outside of the engine.
+	require.NoError(t, ledger.RecordWin(3, big.NewInt(300), big.NewInt(80)))
+
+	bids, err := ledger.Bids(2)
+	require.NoError(t, err)
+	require.Len(t, bids, 2)
+	require.Equal(t, uint64(2), bids[0].Round)
+	require.True(t, bids[0].Won)
+	require.Equal(t, "120", bids[0].Price)
+	require.Equal(t, "", bids[1].Strategy)
+
+	summary, err := ledger.Summary()
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), uint64(summary.Bids))
+	require.Equal(t, uint64(2), uint64(summary.Wins))
+	require.Equal(t, big.NewInt(250), summary.Bid.ToInt())
+	require.Equal(t, big.NewInt(200), summary.Spent.ToInt())
+	require.Equal(t, big.NewInt(1000), summary.Deposited.ToInt())
+
+	// The ledger is kept across restarts.
+	require.NoError(t, ledger.Close())
+	ledger, err = NewBidderLedger(dir)
+	require.NoError(t, err)
+	defer ledger.Close()
+	summary, err = ledger.Summary()
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), uint64(summary.Wins))
+}
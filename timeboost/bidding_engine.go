@@ -0,0 +1,381 @@
+package timeboost
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/offchainlabs/nitro/util/arbmath"
+	"github.com/offchainlabs/nitro/util/stopwaiter"
+)
+
+var (
+	biddingEngineBidsCounter     = metrics.NewRegisteredCounter("arb/timeboost/bidder/bids", nil)
+	biddingEngineSkippedCounter  = metrics.NewRegisteredCounter("arb/timeboost/bidder/skipped", nil)
+	biddingEngineFailedCounter   = metrics.NewRegisteredCounter("arb/timeboost/bidder/failed", nil)
+	biddingEngineWinsCounter     = metrics.NewRegisteredCounter("arb/timeboost/bidder/wins", nil)
+	biddingEngineDepositsCounter = metrics.NewRegisteredCounter("arb/timeboost/bidder/deposits", nil)
+)
+
+const gwei = 1_000_000_000
+
+// biddingRetryInterval is how long the bidding engine waits before retrying a failed bid, until the auction closes.
+const biddingRetryInterval = 250 * time.Millisecond
+
+type BiddingEngineConfig struct {
+	Enable                  bool          `koanf:"enable"`
+	Strategy                string        `koanf:"strategy"`
+	MaxBidGwei              uint64        `koanf:"max-bid-gwei"`
+	Percentile              float64       `koanf:"percentile"`
+	PercentileIncrementGwei uint64        `koanf:"percentile-increment-gwei"`
+	HistoryRounds           int           `koanf:"history-rounds"`
+	ExpectedValueShareBips  int64         `koanf:"expected-value-share-bips"`
+	SignalMaxAge            time.Duration `koanf:"signal-max-age"`
+	SubmitBeforeClose       time.Duration `koanf:"submit-before-close"`
+	MinDepositGwei          uint64        `koanf:"min-deposit-gwei"`
+	TopUpGwei               uint64        `koanf:"top-up-gwei"`
+	LedgerDir               string        `koanf:"ledger-dir"`
+	PollInterval            time.Duration `koanf:"poll-interval"`
+	RPCAddr                 string        `koanf:"rpc-addr"`
+	RPCPort                 uint64        `koanf:"rpc-port"`
+}
+
+var DefaultBiddingEngineConfig = BiddingEngineConfig{
+	Enable:                  false,
+	Strategy:                FixedBiddingStrategy,
+	Percentile:              50,
+	PercentileIncrementGwei: 1,
+	HistoryRounds:           20,
+	ExpectedValueShareBips:  5000,
+	SignalMaxAge:            time.Minute,
+	SubmitBeforeClose:       2 * time.Second,
+	LedgerDir:               "bidder-ledger",
+	PollInterval:            time.Second,
+	RPCAddr:                 "localhost",
+	RPCPort:                 9373,
+}
+
+func BiddingEngineConfigAddOptions(prefix string, f *pflag.FlagSet) {
+	f.Bool(prefix+".enable", DefaultBiddingEngineConfig.Enable, "enable automated bidding in every round with the configured strategy")
+	f.String(prefix+".strategy", DefaultBiddingEngineConfig.Strategy, "bidding strategy, one of \"fixed\" (always bid max-bid-gwei), \"percentile\" (bid a percentile of recent clearing prices) or \"expected-value\" (bid a share of the value sent over RPC)")
+	f.Uint64(prefix+".max-bid-gwei", DefaultBiddingEngineConfig.MaxBidGwei, "maximum bid in gwei, which is also the bid of the fixed strategy")
+	f.Float64(prefix+".percentile", DefaultBiddingEngineConfig.Percentile, "percentile of the recent clearing prices the percentile strategy bids")
+	f.Uint64(prefix+".percentile-increment-gwei", DefaultBiddingEngineConfig.PercentileIncrementGwei, "amount in gwei the percentile strategy bids above the percentile of the recent clearing prices")
+	f.Int(prefix+".history-rounds", DefaultBiddingEngineConfig.HistoryRounds, "number of recent clearing prices the percentile strategy uses")
+	f.Int64(prefix+".expected-value-share-bips", DefaultBiddingEngineConfig.ExpectedValueShareBips, "share of the expected value of the express lane the expected-value strategy bids, in basis points")
+	f.Duration(prefix+".signal-max-age", DefaultBiddingEngineConfig.SignalMaxAge, "how long an expected value sent without a round is used by the expected-value strategy")
+	f.Duration(prefix+".submit-before-close", DefaultBiddingEngineConfig.SubmitBeforeClose, "how long before the auction closes the bid for the round is submitted")
+	f.Uint64(prefix+".min-deposit-gwei", DefaultBiddingEngineConfig.MinDepositGwei, "deposit balance in gwei below which the deposit is topped up, 0 disables topping up")
+	f.Uint64(prefix+".top-up-gwei", DefaultBiddingEngineConfig.TopUpGwei, "amount in gwei deposited when the deposit balance falls below min-deposit-gwei")
+	f.String(prefix+".ledger-dir", DefaultBiddingEngineConfig.LedgerDir, "directory of the local ledger of bids, wins and deposits")
+	f.Duration(prefix+".poll-interval", DefaultBiddingEngineConfig.PollInterval, "interval at which auction resolutions are polled")
+	f.String(prefix+".rpc-addr", DefaultBiddingEngineConfig.RPCAddr, "address the bidder RPC server listens on")
+	f.Uint64(prefix+".rpc-port", DefaultBiddingEngineConfig.RPCPort, "port the bidder RPC server listens on, 0 disables it")
+}
+
+func (c *BiddingEngineConfig) Validate() error {
+	if !c.Enable {
+		return nil
+	}
+	switch c.Strategy {
+	case FixedBiddingStrategy, PercentileBiddingStrategy, ExpectedValueBiddingStrategy:
+	default:
+		return fmt.Errorf("unknown bidding strategy %q", c.Strategy)
+	}
+	if c.MaxBidGwei == 0 {
+		return errors.New("bidding max-bid-gwei must be set")
+	}
+	if c.Percentile <= 0 || c.Percentile > 100 {
+		return fmt.Errorf("bidding percentile %v must be in (0, 100]", c.Percentile)
+	}
+	if c.HistoryRounds <= 0 {
+		return errors.New("bidding history-rounds must be positive")
+	}
+	if c.ExpectedValueShareBips <= 0 || c.ExpectedValueShareBips > int64(arbmath.OneInBips) {
+		return fmt.Errorf("bidding expected-value-share-bips %d must be in (0, %d]", c.ExpectedValueShareBips, arbmath.OneInBips)
+	}
+	if c.Strategy == ExpectedValueBiddingStrategy && c.RPCPort == 0 {
+		return errors.New("the expected-value bidding strategy needs the bidder RPC server to receive the expected value")
+	}
+	if c.MinDepositGwei > 0 && c.TopUpGwei == 0 {
+		return errors.New("bidding top-up-gwei must be set when min-deposit-gwei is")
+	}
+	if c.SubmitBeforeClose <= 0 {
+		return errors.New("bidding submit-before-close must be positive")
+	}
+	if c.PollInterval <= 0 {
+		return errors.New("bidding poll-interval must be positive")
+	}
+	return nil
+}
+
+// BiddingEngine submits a bid in every auction, for an amount decided by its strategy, tops up the deposit when
+// it runs low and keeps a ledger of its bids, wins and deposits.
+type BiddingEngine struct {
+	stopwaiter.StopWaiter
+	config        *BiddingEngineConfig
+	bidder        *BidderClient
+	strategy      BiddingStrategy
+	history       *clearingPriceHistory
+	expectedValue *expectedValueStrategy
+	ledger        *BidderLedger
+
+	lastRound uint64
+	fromBlock uint64
+}
+
+func NewBiddingEngine(config *BiddingEngineConfig, bidder *BidderClient) (*BiddingEngine, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	e := &BiddingEngine{
+		config:  config,
+		bidder:  bidder,
+		history: newClearingPriceHistory(config.HistoryRounds),
+	}
+	switch config.Strategy {
+	case FixedBiddingStrategy:
+		e.strategy = &fixedStrategy{amount: arbmath.BigMulByUint(big.NewInt(gwei), config.MaxBidGwei)}
+	case PercentileBiddingStrategy:
+		e.strategy = &percentileStrategy{
+			history:    e.history,
+			percentile: config.Percentile,
+			increment:  arbmath.BigMulByUint(big.NewInt(gwei), config.PercentileIncrementGwei),
+		}
+	case ExpectedValueBiddingStrategy:
+		e.expectedValue = newExpectedValueStrategy(arbmath.Bips(config.ExpectedValueShareBips), config.SignalMaxAge)
+		e.strategy = e.expectedValue
+	default:
+		return nil, fmt.Errorf("unknown bidding strategy %q", config.Strategy)
+	}
+	ledger, err := NewBidderLedger(config.LedgerDir)
+	if err != nil {
+		return nil, fmt.Errorf("error opening bidder ledger: %w", err)
+	}
+	e.ledger = ledger
+	return e, nil
+}
+
+func (e *BiddingEngine) Start(ctxIn context.Context) error {
+	e.StopWaiter.Start(ctxIn, e)
+	ctx, err := e.GetContextSafe()
+	if err != nil {
+		return err
+	}
+	if e.config.RPCPort != 0 {
+		if err := e.startRPCServer(ctx); err != nil {
+			return err
+		}
+	}
+	fromBlock, err := e.bidder.client.BlockNumber(ctx)
+	if err != nil {
+		return err
+	}
+	e.fromBlock = fromBlock
+	e.CallIteratively(e.pollAuctionResolutions)
+	e.CallIteratively(e.bidInRound)
+	log.Info("Started bidding engine", "strategy", e.strategy.Name(), "bidder", e.bidder.txOpts.From)
+	return nil
+}
+
+func (e *BiddingEngine) StopAndWait() {
+	e.StopWaiter.StopAndWait()
+	if err := e.ledger.Close(); err != nil {
+		log.Warn("Error closing bidder ledger", "err", err)
+	}
+}
+
+// bidInRound waits until shortly before the auction closes, then bids for the next round, retrying until the
+// auction closes if bidding fails.
+func (e *BiddingEngine) bidInRound(ctx context.Context) time.Duration {
+	info := &e.bidder.roundTimingInfo
+	now := time.Now()
+	round := info.RoundNumberAt(now) + 1
+	untilClose := info.TimeTilNextRoundAt(now) - info.AuctionClosing
+	if round <= e.lastRound || untilClose <= 0 {
+		// Already bid, or too late to bid in this auction.
+		return info.TimeTilNextRoundAt(now)
+	}
+	if untilClose > e.config.SubmitBeforeClose {
+		return untilClose - e.config.SubmitBeforeClose
+	}
+	if err := e.bid(ctx, round); err != nil {
+		biddingEngineFailedCounter.Inc(1)
+		log.Error("Error bidding in express lane auction", "round", round, "strategy", e.strategy.Name(), "err", err)
+		return min(biddingRetryInterval, info.TimeTilNextRound()-info.AuctionClosing)
+	}
+	e.lastRound = round
+	return info.TimeTilNextRound()
+}
+
+func (e *BiddingEngine) bid(ctx context.Context, round uint64) error {
+	amount, err := e.strategy.BidAmount(ctx, round)
+	if err != nil {
+		return err
+	}
+	if amount == nil || amount.Sign() <= 0 {
+		biddingEngineSkippedCounter.Inc(1)
+		log.Info("Bidding strategy has no bid for round", "round", round, "strategy", e.strategy.Name())
+		return nil
+	}
+	amount = arbmath.BigMin(amount, arbmath.BigMulByUint(big.NewInt(gwei), e.config.MaxBidGwei))
+
+	callOpts := &bind.CallOpts{Context: ctx}
+	reservePrice, err := e.bidder.auctionContract.ReservePrice(callOpts)
+	if err != nil {
+		return fmt.Errorf("error fetching reserve price: %w", err)
+	}
+	if arbmath.BigLessThan(amount, reservePrice) {
+		biddingEngineSkippedCounter.Inc(1)
+		log.Info("Bid below reserve price, not bidding", "round", round, "amount", amount, "reservePrice", reservePrice)
+		return nil
+	}
+	balance, err := e.topUpDeposit(ctx)
+	if err != nil {
+		return err
+	}
+	if arbmath.BigLessThan(balance, amount) {
+		log.Warn("Deposit balance below bid, bidding the balance", "round", round, "amount", amount, "balance", balance)
+		amount = balance
+		if arbmath.BigLessThan(amount, reservePrice) {
+			biddingEngineSkippedCounter.Inc(1)
+			return nil
+		}
+	}
+
+	if _, err := e.bidder.BidInRound(ctx, round, amount, e.bidder.txOpts.From); err != nil {
+		return err
+	}
+	biddingEngineBidsCounter.Inc(1)
+	log.Info("Submitted bid", "round", round, "amount", amount, "strategy", e.strategy.Name())
+	// The bid was submitted, so failing to record it mustn't make it be submitted again.
+	if err := e.ledger.RecordBid(round, e.strategy.Name(), amount, time.Now()); err != nil {
+		log.Error("Error recording bid in ledger", "round", round, "err", err)
+	}
+	return nil
+}
+
+// topUpDeposit deposits more once the balance falls below the minimum, and returns the balance.
+func (e *BiddingEngine) topUpDeposit(ctx context.Context) (*big.Int, error) {
+	balance, err := e.bidder.auctionContract.BalanceOf(&bind.CallOpts{Context: ctx}, e.bidder.txOpts.From)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching deposit balance: %w", err)
+	}
+	minDeposit := arbmath.BigMulByUint(big.NewInt(gwei), e.config.MinDepositGwei)
+	if e.config.MinDepositGwei == 0 || !arbmath.BigLessThan(balance, minDeposit) {
+		return balance, nil
+	}
+	topUp := arbmath.BigMulByUint(big.NewInt(gwei), e.config.TopUpGwei)
+	log.Info("Deposit balance low, topping up", "balance", balance, "minDeposit", minDeposit, "topUp", topUp)
+	if err := e.bidder.Deposit(ctx, topUp); err != nil {
+		return nil, fmt.Errorf("error topping up deposit: %w", err)
+	}
+	biddingEngineDepositsCounter.Inc(1)
+	if err := e.ledger.RecordDeposit(topUp, time.Now()); err != nil {
+		log.Error("Error recording deposit in bidder ledger", "amount", topUp, "err", err)
+	}
+	return arbmath.BigAdd(balance, topUp), nil
+}
+
+// pollAuctionResolutions records the clearing prices for the percentile strategy, and the rounds won in the ledger.
+func (e *BiddingEngine) pollAuctionResolutions(ctx context.Context) time.Duration {
+	toBlock, err := e.bidder.client.BlockNumber(ctx)
+	if err != nil {
+		log.Error("Bidding engine could not get the latest block number", "err", err)
+		return e.config.PollInterval
+	}
+	if e.fromBlock > toBlock {
+		return e.config.PollInterval
+	}
+	filterOpts := &bind.FilterOpts{
+		Context: ctx,
+		Start:   e.fromBlock,
+		End:     &toBlock,
+	}
+	it, err := e.bidder.auctionContract.FilterAuctionResolved(filterOpts, nil, nil, nil)
+	if err != nil {
+		log.Error("Bidding engine could not filter auction resolutions", "err", err)
+		return e.config.PollInterval
+	}
+	defer it.Close()
+	for it.Next() {
+		e.history.add(it.Event.Round, it.Event.Price)
+		if it.Event.FirstPriceBidder != e.bidder.txOpts.From {
+			continue
+		}
+		biddingEngineWinsCounter.Inc(1)
+		log.Info("Won express lane auction", "round", it.Event.Round, "bid", it.Event.FirstPriceAmount, "price", it.Event.Price)
+		if err := e.ledger.RecordWin(it.Event.Round, it.Event.FirstPriceAmount, it.Event.Price); err != nil {
+			log.Error("Error recording win in bidder ledger", "round", it.Event.Round, "err", err)
+		}
+	}
+	if err := it.Error(); err != nil {
+		log.Error("Bidding engine error iterating auction resolutions", "err", err)
+		return e.config.PollInterval
+	}
+	e.fromBlock = toBlock + 1
+	return e.config.PollInterval
+}
+
+func (e *BiddingEngine) startRPCServer(ctx context.Context) error {
+	listener, err := net.Listen("tcp", fmt.Sprintf("%s:%d", e.config.RPCAddr, e.config.RPCPort))
+	if err != nil {
+		return err
+	}
+	rpcServer := rpc.NewServer()
+	if err := rpcServer.RegisterName("bidder", &BidderAPI{engine: e}); err != nil {
+		return err
+	}
+	srv := &http.Server{
+		Handler:           rpcServer,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		if err := srv.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Error("Bidder RPC server stopped", "err", err)
+		}
+	}()
+	go func() {
+		<-ctx.Done()
+		_ = srv.Shutdown(context.Background())
+	}()
+	log.Info("Bidder RPC server listening", "addr", listener.Addr())
+	return nil
+}
+
+// BidderAPI is served in the "bidder" namespace, to feed the expected-value strategy and to read the ledger.
+type BidderAPI struct {
+	engine *BiddingEngine
+}
+
+// SetExpectedValue sets the value of controlling the express lane that the expected-value strategy bids a share
+// of. Without a round it's used for every round until it's older than the signal max age.
+func (a *BidderAPI) SetExpectedValue(_ context.Context, value *hexutil.Big, round *hexutil.Uint64) error {
+	if a.engine.expectedValue == nil {
+		return fmt.Errorf("the bidding strategy is %q, not %q", a.engine.strategy.Name(), ExpectedValueBiddingStrategy)
+	}
+	if value == nil {
+		return errors.New("missing expected value")
+	}
+	return a.engine.expectedValue.setExpectedValue(value.ToInt(), (*uint64)(round))
+}
+
+func (a *BidderAPI) Ledger(_ context.Context) (*BidderLedgerSummary, error) {
+	return a.engine.ledger.Summary()
+}
+
+func (a *BidderAPI) LedgerBids(_ context.Context, fromRound hexutil.Uint64) ([]*LedgerBid, error) {
+	return a.engine.ledger.Bids(uint64(fromRound))
+}
@@ -0,0 +1,170 @@
+package timeboost
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/offchainlabs/nitro/util/arbmath"
+)
+
+const (
+	FixedBiddingStrategy         = "fixed"
+	PercentileBiddingStrategy    = "percentile"
+	ExpectedValueBiddingStrategy = "expected-value"
+)
+
+// BiddingStrategy decides how much the bidding engine bids for a round. The engine caps the amount at the
+// configured maximum bid, and skips the round if it's below the reserve price.
+type BiddingStrategy interface {
+	Name() string
+	// BidAmount returns the amount to bid for the round, or nil to not bid.
+	BidAmount(ctx context.Context, round uint64) (*big.Int, error)
+}
+
+// fixedStrategy always bids the same amount.
+type fixedStrategy struct {
+	amount *big.Int
+}
+
+func (s *fixedStrategy) Name() string {
+	return FixedBiddingStrategy
+}
+
+func (s *fixedStrategy) BidAmount(_ context.Context, _ uint64) (*big.Int, error) {
+	return new(big.Int).Set(s.amount), nil
+}
+
+// clearingPriceHistory keeps the prices paid in the most recent auctions.
+type clearingPriceHistory struct {
+	mutex     sync.Mutex
+	maxRounds int
+	rounds    []uint64
+	prices    []*big.Int
+}
+
+func newClearingPriceHistory(maxRounds int) *clearingPriceHistory {
+	return &clearingPriceHistory{
+		maxRounds: maxRounds,
+	}
+}
+
+func (h *clearingPriceHistory) add(round uint64, price *big.Int) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	for _, seen := range h.rounds {
+		if seen == round {
+			return
+		}
+	}
+	h.rounds = append(h.rounds, round)
+	h.prices = append(h.prices, new(big.Int).Set(price))
+	if len(h.prices) > h.maxRounds {
+		h.rounds = h.rounds[len(h.rounds)-h.maxRounds:]
+		h.prices = h.prices[len(h.prices)-h.maxRounds:]
+	}
+}
+
+// percentile returns the given percentile of the recent clearing prices, or nil if there are none.
+func (h *clearingPriceHistory) percentile(percentile float64) *big.Int {
+	h.mutex.Lock()
+	sorted := make([]*big.Int, len(h.prices))
+	copy(sorted, h.prices)
+	h.mutex.Unlock()
+	if len(sorted) == 0 {
+		return nil
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Cmp(sorted[j]) < 0 })
+	// Nearest rank percentile.
+	rank := int(percentile / 100 * float64(len(sorted)))
+	if float64(rank) < percentile/100*float64(len(sorted)) {
+		rank++
+	}
+	rank = max(1, min(rank, len(sorted)))
+	return new(big.Int).Set(sorted[rank-1])
+}
+
+// percentileStrategy bids a percentile of the recent clearing prices plus an increment, to outbid the usual winner.
+type percentileStrategy struct {
+	history    *clearingPriceHistory
+	percentile float64
+	increment  *big.Int
+}
+
+func (s *percentileStrategy) Name() string {
+	return PercentileBiddingStrategy
+}
+
+func (s *percentileStrategy) BidAmount(_ context.Context, _ uint64) (*big.Int, error) {
+	price := s.history.percentile(s.percentile)
+	if price == nil {
+		return nil, nil
+	}
+	return arbmath.BigAdd(price, s.increment), nil
+}
+
+type expectedValueSignal struct {
+	value      *big.Int
+	receivedAt time.Time
+}
+
+// expectedValueStrategy bids a share of the value of the express lane for the round, as estimated by an external
+// signal sent over RPC. A signal for a specific round takes precedence over the latest signal for any round.
+type expectedValueStrategy struct {
+	mutex    sync.Mutex
+	share    arbmath.Bips
+	maxAge   time.Duration
+	latest   *expectedValueSignal
+	forRound map[uint64]*expectedValueSignal
+}
+
+func newExpectedValueStrategy(share arbmath.Bips, maxAge time.Duration) *expectedValueStrategy {
+	return &expectedValueStrategy{
+		share:    share,
+		maxAge:   maxAge,
+		forRound: make(map[uint64]*expectedValueSignal),
+	}
+}
+
+func (s *expectedValueStrategy) Name() string {
+	return ExpectedValueBiddingStrategy
+}
+
+func (s *expectedValueStrategy) setExpectedValue(value *big.Int, round *uint64) error {
+	if value.Sign() < 0 {
+		return fmt.Errorf("expected value %v can't be negative", value)
+	}
+	signal := &expectedValueSignal{
+		value:      new(big.Int).Set(value),
+		receivedAt: time.Now(),
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if round == nil {
+		s.latest = signal
+	} else {
+		s.forRound[*round] = signal
+	}
+	return nil
+}
+
+func (s *expectedValueStrategy) BidAmount(_ context.Context, round uint64) (*big.Int, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for r := range s.forRound {
+		if r < round {
+			delete(s.forRound, r)
+		}
+	}
+	signal, ok := s.forRound[round]
+	if !ok {
+		signal = s.latest
+		if signal == nil || time.Since(signal.receivedAt) > s.maxAge {
+			return nil, nil
+		}
+	}
+	return arbmath.BigMulByBips(signal.value, s.share), nil
+}
@@ -0,0 +1,98 @@
+package timeboost
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClearingPriceHistoryPercentile(t *testing.T) {
+	t.Parallel()
+	history := newClearingPriceHistory(4)
+	require.Nil(t, history.percentile(50))
+
+	for round, price := range []int64{50, 10, 40, 20, 30} {
+		history.add(uint64(round), big.NewInt(price))
+	}
+	// The same round is only counted once.
+	history.add(4, big.NewInt(1000))
+
+	// Only the last 4 rounds are kept: 10, 20, 30, 40.
+	require.Equal(t, big.NewInt(10), history.percentile(1))
+	require.Equal(t, big.NewInt(20), history.percentile(50))
+	require.Equal(t, big.NewInt(30), history.percentile(51))
+	require.Equal(t, big.NewInt(40), history.percentile(100))
+}
+
+func TestPercentileStrategy(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	strategy := &percentileStrategy{
+		history:    newClearingPriceHistory(10),
+		percentile: 100,
+		increment:  big.NewInt(5),
+	}
+	amount, err := strategy.BidAmount(ctx, 1)
+	require.NoError(t, err)
+	require.Nil(t, amount, "bid without any clearing price")
+
+	strategy.history.add(1, big.NewInt(100))
+	strategy.history.add(2, big.NewInt(200))
+	amount, err = strategy.BidAmount(ctx, 3)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(205), amount)
+}
+
+func TestExpectedValueStrategy(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	strategy := newExpectedValueStrategy(2500, time.Hour)
+	amount, err := strategy.BidAmount(ctx, 1)
+	require.NoError(t, err)
+	require.Nil(t, amount, "bid without an expected value")
+
+	require.Error(t, strategy.setExpectedValue(big.NewInt(-1), nil))
+	require.NoError(t, strategy.setExpectedValue(big.NewInt(1000), nil))
+	round := uint64(3)
+	require.NoError(t, strategy.setExpectedValue(big.NewInt(4000), &round))
+
+	amount, err = strategy.BidAmount(ctx, 2)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(250), amount)
+	amount, err = strategy.BidAmount(ctx, 3)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(1000), amount, "the value for the round takes precedence")
+
+	// The latest value expires.
+	strategy.latest.receivedAt = time.Now().Add(-2 * time.Hour)
+	amount, err = strategy.BidAmount(ctx, 4)
+	require.NoError(t, err)
+	require.Nil(t, amount)
+	require.Empty(t, strategy.forRound, "values for past rounds weren't dropped")
+}
+
+func TestBiddingEngineConfigValidate(t *testing.T) {
+	t.Parallel()
+	config := DefaultBiddingEngineConfig
+	require.NoError(t, config.Validate(), "the disabled default config is valid")
+
+	config.Enable = true
+	require.Error(t, config.Validate(), "max-bid-gwei must be set")
+	config.MaxBidGwei = 10
+	require.NoError(t, config.Validate())
+
+	config.Strategy = "lowball"
+	require.Error(t, config.Validate())
+	config.Strategy = ExpectedValueBiddingStrategy
+	config.RPCPort = 0
+	require.Error(t, config.Validate(), "the expected-value strategy needs the RPC server")
+
+	config = DefaultBiddingEngineConfig
+	config.Enable = true
+	config.MaxBidGwei = 10
+	config.MinDepositGwei = 100
+	require.Error(t, config.Validate(), "top-up-gwei must be set")
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/hex"
 	"fmt"
 	"io/fs"
+	"math"
 	"os"
 	"path/filepath"
 	"strings"
@@ -167,3 +168,83 @@ func (d *SqliteDatabase) DeleteBids(round uint64) error {
 	_, err := d.sqlDB.Exec(query, round)
 	return err
 }
+
+// InsertAuctionResult records the outcome of a resolved auction. Unlike the bids, auction results aren't deleted
+// once archived to S3, so the auction history can be served from the database.
+func (d *SqliteDatabase) InsertAuctionResult(r *SqliteDatabaseAuctionResult) error {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	query := `INSERT OR REPLACE INTO AuctionResults (
+        Round, FirstBidder, FirstExpressLaneController, FirstAmount, SecondBidder, SecondExpressLaneController, SecondAmount, Price, BidCount, TxHash, ResolvedAt
+    ) VALUES (
+        :Round, :FirstBidder, :FirstExpressLaneController, :FirstAmount, :SecondBidder, :SecondExpressLaneController, :SecondAmount, :Price, :BidCount, :TxHash, :ResolvedAt
+    )`
+	_, err := d.sqlDB.NamedExec(query, r)
+	return err
+}
+
+// GetAuctionResults returns up to limit auction results with rounds from fromRound to toRound, in round order.
+func (d *SqliteDatabase) GetAuctionResults(fromRound, toRound uint64, limit int) ([]*SqliteDatabaseAuctionResult, error) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	// sqlite integers are signed
+	toRound = min(toRound, math.MaxInt64)
+	var results []*SqliteDatabaseAuctionResult
+	if err := d.sqlDB.Select(&results, "SELECT * FROM AuctionResults WHERE Round >= ? AND Round <= ? ORDER BY Round ASC LIMIT ?", fromRound, toRound, limit); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// FirstAuctionResultRound returns the earliest round with an auction result, and false if there are none.
+func (d *SqliteDatabase) FirstAuctionResultRound() (uint64, bool, error) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	var rounds []uint64
+	if err := d.sqlDB.Select(&rounds, "SELECT Round FROM AuctionResults ORDER BY Round ASC LIMIT 1"); err != nil {
+		return 0, false, err
+	}
+	if len(rounds) == 0 {
+		return 0, false, nil
+	}
+	return rounds[0], true, nil
+}
+
+// GetRoundBids returns the bids of the round that haven't been archived to S3 and deleted yet.
+func (d *SqliteDatabase) GetRoundBids(round uint64) ([]*SqliteDatabaseBid, error) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	var bids []*SqliteDatabaseBid
+	if err := d.sqlDB.Select(&bids, "SELECT * FROM Bids WHERE Round = ? ORDER BY Id ASC", round); err != nil {
+		return nil, err
+	}
+	return bids, nil
+}
+
+// GetBidRounds returns up to limit rounds from fromRound to toRound with bids that haven't been archived to S3 and
+// deleted yet, in round order.
+func (d *SqliteDatabase) GetBidRounds(fromRound, toRound uint64, limit int) ([]uint64, error) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	// sqlite integers are signed
+	toRound = min(toRound, math.MaxInt64)
+	var rounds []uint64
+	if err := d.sqlDB.Select(&rounds, "SELECT DISTINCT Round FROM Bids WHERE Round >= ? AND Round <= ? ORDER BY Round ASC LIMIT ?", fromRound, toRound, limit); err != nil {
+		return nil, err
+	}
+	return rounds, nil
+}
+
+// GetBidsInRounds returns the bids with rounds from fromRound to toRound that haven't been archived to S3 and deleted
+// yet, in round order and then in the order they were validated.
+func (d *SqliteDatabase) GetBidsInRounds(fromRound, toRound uint64) ([]*SqliteDatabaseBid, error) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	// sqlite integers are signed
+	toRound = min(toRound, math.MaxInt64)
+	var bids []*SqliteDatabaseBid
+	if err := d.sqlDB.Select(&bids, "SELECT * FROM Bids WHERE Round >= ? AND Round <= ? ORDER BY Round ASC, Id ASC", fromRound, toRound); err != nil {
+		return nil, err
+	}
+	return bids, nil
+}
@@ -0,0 +1,121 @@
+package timeboost
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math/big"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// delegationDomainValue holds the Keccak256 hash of the string "TIMEBOOST_DELEGATION", so that delegations can't be
+// replayed as express lane submissions or the other way around.
+var delegationDomainValue = crypto.Keccak256([]byte("TIMEBOOST_DELEGATION"))
+
+// ExpressLaneDelegation is signed by the express lane controller of a round to let the delegate sign express lane
+// submissions for the round too, between ValidAfter and ValidUntil. A delegate with a nonzero SequenceNamespace
+// numbers its submissions from 0 in a sequence of its own, instead of sharing the controller's sequence.
+// A delegation only replaces an earlier one for the same delegate and round if its Nonce is higher, so the
+// controller can revoke a delegation with one that's no longer valid, without the delegate restoring the old one.
+type ExpressLaneDelegation struct {
+	ChainId                *big.Int
+	AuctionContractAddress common.Address
+	Round                  uint64
+	Delegate               common.Address
+	ValidAfter             uint64
+	ValidUntil             uint64
+	SequenceNamespace      uint64
+	Nonce                  uint64
+	Signature              []byte
+
+	delegator common.Address
+}
+
+type JsonExpressLaneDelegation struct {
+	ChainId                *hexutil.Big   `json:"chainId"`
+	AuctionContractAddress common.Address `json:"auctionContractAddress"`
+	Round                  hexutil.Uint64 `json:"round"`
+	Delegate               common.Address `json:"delegate"`
+	ValidAfter             hexutil.Uint64 `json:"validAfter"`
+	ValidUntil             hexutil.Uint64 `json:"validUntil"`
+	SequenceNamespace      hexutil.Uint64 `json:"sequenceNamespace"`
+	Nonce                  hexutil.Uint64 `json:"nonce"`
+	Signature              hexutil.Bytes  `json:"signature"`
+}
+
+func JsonDelegationToGo(delegation *JsonExpressLaneDelegation) *ExpressLaneDelegation {
+	return &ExpressLaneDelegation{
+		ChainId:                delegation.ChainId.ToInt(),
+		AuctionContractAddress: delegation.AuctionContractAddress,
+		Round:                  uint64(delegation.Round),
+		Delegate:               delegation.Delegate,
+		ValidAfter:             uint64(delegation.ValidAfter),
+		ValidUntil:             uint64(delegation.ValidUntil),
+		SequenceNamespace:      uint64(delegation.SequenceNamespace),
+		Nonce:                  uint64(delegation.Nonce),
+		Signature:              delegation.Signature,
+	}
+}
+
+func (d *ExpressLaneDelegation) ToJson() *JsonExpressLaneDelegation {
+	return &JsonExpressLaneDelegation{
+		ChainId:                (*hexutil.Big)(d.ChainId),
+		AuctionContractAddress: d.AuctionContractAddress,
+		Round:                  hexutil.Uint64(d.Round),
+		Delegate:               d.Delegate,
+		ValidAfter:             hexutil.Uint64(d.ValidAfter),
+		ValidUntil:             hexutil.Uint64(d.ValidUntil),
+		SequenceNamespace:      hexutil.Uint64(d.SequenceNamespace),
+		Nonce:                  hexutil.Uint64(d.Nonce),
+		Signature:              d.Signature,
+	}
+}
+
+func (d *ExpressLaneDelegation) ToMessageBytes() []byte {
+	buf := new(bytes.Buffer)
+	buf.Write(delegationDomainValue)
+	buf.Write(padBigInt(d.ChainId))
+	buf.Write(d.AuctionContractAddress[:])
+	uintBuf := make([]byte, 8)
+	binary.BigEndian.PutUint64(uintBuf, d.Round)
+	buf.Write(uintBuf)
+	buf.Write(d.Delegate[:])
+	binary.BigEndian.PutUint64(uintBuf, d.ValidAfter)
+	buf.Write(uintBuf)
+	binary.BigEndian.PutUint64(uintBuf, d.ValidUntil)
+	buf.Write(uintBuf)
+	binary.BigEndian.PutUint64(uintBuf, d.SequenceNamespace)
+	buf.Write(uintBuf)
+	binary.BigEndian.PutUint64(uintBuf, d.Nonce)
+	buf.Write(uintBuf)
+	return buf.Bytes()
+}
+
+// Delegator returns the address that signed the delegation, which must be the express lane controller of the round
+// for the delegation to be valid.
+func (d *ExpressLaneDelegation) Delegator() (common.Address, error) {
+	if (d.delegator != common.Address{}) {
+		return d.delegator, nil
+	}
+	if d.ChainId == nil {
+		return common.Address{}, errors.Wrap(ErrMalformedData, "empty chain id")
+	}
+	delegator, err := recoverSigner(d.ToMessageBytes(), d.Signature)
+	if err != nil {
+		return common.Address{}, err
+	}
+	d.delegator = delegator
+	return d.delegator, nil
+}
+
+// IsActiveAt returns whether the delegation is within its validity window at the given time.
+func (d *ExpressLaneDelegation) IsActiveAt(now time.Time) bool {
+	// #nosec G115
+	unixNow := uint64(now.Unix())
+	return unixNow >= d.ValidAfter && unixNow < d.ValidUntil
+}
@@ -0,0 +1,64 @@
+package timeboost
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestExpressLaneDelegationDelegator(t *testing.T) {
+	t.Parallel()
+	privateKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	delegation := &ExpressLaneDelegation{
+		ChainId:                big.NewInt(1),
+		AuctionContractAddress: common.Address{'x'},
+		Round:                  5,
+		Delegate:               common.Address{'d'},
+		ValidAfter:             100,
+		ValidUntil:             200,
+		SequenceNamespace:      1,
+	}
+	data := delegation.ToMessageBytes()
+	prefixed := crypto.Keccak256(append([]byte(fmt.Sprintf("\x19Ethereum Signed Message:\n%d", len(data))), data...))
+	delegation.Signature, err = crypto.Sign(prefixed, privateKey)
+	require.NoError(t, err)
+
+	delegator, err := delegation.Delegator()
+	require.NoError(t, err)
+	require.Equal(t, crypto.PubkeyToAddress(privateKey.PublicKey), delegator)
+
+	// The delegation survives being shared over RPC or redis
+	delegationBytes, err := json.Marshal(delegation.ToJson())
+	require.NoError(t, err)
+	var delegationJson JsonExpressLaneDelegation
+	require.NoError(t, json.Unmarshal(delegationBytes, &delegationJson))
+	decoded := JsonDelegationToGo(&delegationJson)
+	delegator, err = decoded.Delegator()
+	require.NoError(t, err)
+	require.Equal(t, crypto.PubkeyToAddress(privateKey.PublicKey), delegator)
+
+	// Changing any signed field changes the recovered delegator
+	decoded = JsonDelegationToGo(&delegationJson)
+	decoded.SequenceNamespace = 0
+	delegator, err = decoded.Delegator()
+	require.NoError(t, err)
+	require.NotEqual(t, crypto.PubkeyToAddress(privateKey.PublicKey), delegator)
+
+	decoded = JsonDelegationToGo(&delegationJson)
+	decoded.Signature = decoded.Signature[:64]
+	_, err = decoded.Delegator()
+	require.ErrorIs(t, err, ErrMalformedData)
+
+	require.False(t, delegation.IsActiveAt(time.Unix(99, 0)))
+	require.True(t, delegation.IsActiveAt(time.Unix(100, 0)))
+	require.True(t, delegation.IsActiveAt(time.Unix(199, 0)))
+	require.False(t, delegation.IsActiveAt(time.Unix(200, 0)))
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/redis/go-redis/v9"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/log"
 
 	"github.com/offchainlabs/nitro/util/arbmath"
@@ -18,20 +19,33 @@ import (
 
 const EXPRESS_LANE_ROUND_SEQUENCE_KEY_PREFIX string = "expressLane.roundSequence." // Only written by sequencer holding CHOSEN (seqCoordinator) key
 const EXPRESS_LANE_ACCEPTED_TX_KEY_PREFIX string = "expressLane.acceptedTx."       // Only written by sequencer holding CHOSEN (seqCoordinator) key
+const EXPRESS_LANE_DELEGATION_KEY_PREFIX string = "expressLane.delegation."        // Only written by sequencer holding CHOSEN (seqCoordinator) key
+
+// roundNamespace identifies a sequence of express lane submissions in a round. Namespace 0 is the controller's
+// sequence, the others are the sequences of delegates with a sequence namespace of their own.
+type roundNamespace struct {
+	round     uint64
+	namespace uint64
+}
 
 type roundSeqUpdateItem struct {
-	round    uint64
+	roundNamespace
 	sequence uint64
 }
 
+type acceptedTxItem struct {
+	msg       *ExpressLaneSubmission
+	namespace uint64
+}
+
 type RedisCoordinator struct {
 	stopwaiter.StopWaiter
 	roundTimingInfo *RoundTimingInfo
 	client          redis.UniversalClient
 
-	roundSeqMap        *containers.LruCache[uint64, uint64]
+	roundSeqMap        *containers.LruCache[roundNamespace, uint64]
 	roundSeqUpdateChan chan roundSeqUpdateItem
-	msgChan            chan *ExpressLaneSubmission
+	msgChan            chan acceptedTxItem
 }
 
 func NewRedisCoordinator(redisUrl string, roundTimingInfo *RoundTimingInfo, updateEventsChannelSize uint64) (*RedisCoordinator, error) {
@@ -43,9 +57,9 @@ func NewRedisCoordinator(redisUrl string, roundTimingInfo *RoundTimingInfo, upda
 	return &RedisCoordinator{
 		roundTimingInfo:    roundTimingInfo,
 		client:             redisClient,
-		roundSeqMap:        containers.NewLruCache[uint64, uint64](4),
+		roundSeqMap:        containers.NewLruCache[roundNamespace, uint64](16),
 		roundSeqUpdateChan: make(chan roundSeqUpdateItem, updateEventsChannelSize),
-		msgChan:            make(chan *ExpressLaneSubmission, updateEventsChannelSize),
+		msgChan:            make(chan acceptedTxItem, updateEventsChannelSize),
 	}, nil
 }
 
@@ -55,13 +69,21 @@ func (rc *RedisCoordinator) Start(ctxIn context.Context) {
 	rc.LaunchThread(rc.trackAcceptedTxAddition)
 }
 
-func roundSequenceKeyFor(round uint64) string {
-	return fmt.Sprintf("%s%d", EXPRESS_LANE_ROUND_SEQUENCE_KEY_PREFIX, round)
+func roundSequenceKeyFor(round, namespace uint64) string {
+	if namespace == 0 {
+		return fmt.Sprintf("%s%d", EXPRESS_LANE_ROUND_SEQUENCE_KEY_PREFIX, round)
+	}
+	return fmt.Sprintf("%s%d.%d", EXPRESS_LANE_ROUND_SEQUENCE_KEY_PREFIX, round, namespace)
 }
 
 func (rc *RedisCoordinator) GetSequenceCount(round uint64) (uint64, error) {
+	return rc.GetNamespaceSequenceCount(round, 0)
+}
+
+// GetNamespaceSequenceCount returns the sequence count of a sequence namespace of the round.
+func (rc *RedisCoordinator) GetNamespaceSequenceCount(round, namespace uint64) (uint64, error) {
 	ctx := rc.GetContext()
-	key := roundSequenceKeyFor(round)
+	key := roundSequenceKeyFor(round, namespace)
 	seqCountBytes, err := rc.client.Get(ctx, key).Bytes()
 	if errors.Is(err, redis.Nil) {
 		return 0, nil
@@ -73,41 +95,44 @@ func (rc *RedisCoordinator) GetSequenceCount(round uint64) (uint64, error) {
 }
 
 func (rc *RedisCoordinator) UpdateSequenceCount(round, sequence uint64) error {
+	return rc.UpdateNamespaceSequenceCount(round, 0, sequence)
+}
+
+// UpdateNamespaceSequenceCount updates the sequence count of a sequence namespace of the round.
+func (rc *RedisCoordinator) UpdateNamespaceSequenceCount(round, namespace, sequence uint64) error {
 	roundSeqUpdate := roundSeqUpdateItem{
-		round:    round,
-		sequence: sequence,
+		roundNamespace: roundNamespace{round: round, namespace: namespace},
+		sequence:       sequence,
 	}
 	select {
 	case rc.roundSeqUpdateChan <- roundSeqUpdate:
 	default:
-		log.Warn("Unable to queue round sequence update operation in redis coordinator", "round", round, "sequence", sequence)
+		log.Warn("Unable to queue round sequence update operation in redis coordinator", "round", round, "namespace", namespace, "sequence", sequence)
 	}
 	return nil
 }
 
 func (rc *RedisCoordinator) trackSequenceCountUpdates(ctx context.Context) {
 	for {
-		var roundSeqUpdate roundSeqUpdateItem
-		select {
-		case update := <-rc.roundSeqUpdateChan:
-			if update.round < rc.roundTimingInfo.RoundNumber() ||
-				update.round < roundSeqUpdate.round ||
-				(update.round == roundSeqUpdate.round && update.sequence < roundSeqUpdate.sequence) {
+		// roundSeqUpdates holds the local maxima of sequence counts per round and namespace in this batch
+		roundSeqUpdates := make(map[roundNamespace]uint64)
+		addUpdate := func(update roundSeqUpdateItem) {
+			if update.round < rc.roundTimingInfo.RoundNumber() {
 				// This prevents stale roundSeqUpdates from being written to redis and unclogs roundSeqUpdateChan
-				continue
+				return
+			}
+			if sequence, ok := roundSeqUpdates[update.roundNamespace]; !ok || update.sequence > sequence {
+				roundSeqUpdates[update.roundNamespace] = update.sequence
 			}
-			roundSeqUpdate = update
+		}
+		select {
+		case update := <-rc.roundSeqUpdateChan:
+			addUpdate(update)
 			// Attempt to pull upto next 5 updates from the channel (batching logic)
 			for i := 0; i < 5; i++ {
 				select {
 				case update := <-rc.roundSeqUpdateChan:
-					if update.round < rc.roundTimingInfo.RoundNumber() ||
-						update.round < roundSeqUpdate.round ||
-						(update.round == roundSeqUpdate.round && update.sequence < roundSeqUpdate.sequence) {
-						// This prevents stale roundSeqUpdates from being written to redis and unclogs roundSeqUpdateChan
-						continue
-					}
-					roundSeqUpdate = update // update roundSeqUpdate with local maxima
+					addUpdate(update)
 				case <-ctx.Done():
 					return
 				default:
@@ -116,23 +141,33 @@ func (rc *RedisCoordinator) trackSequenceCountUpdates(ctx context.Context) {
 		case <-ctx.Done():
 			return
 		}
-		curSeq, _ := rc.roundSeqMap.Get(roundSeqUpdate.round)
-		if roundSeqUpdate.sequence <= curSeq {
-			continue
-		}
-		rc.roundSeqMap.Add(roundSeqUpdate.round, roundSeqUpdate.sequence)
-		key := roundSequenceKeyFor(roundSeqUpdate.round)
-		if err := rc.client.Set(ctx, key, arbmath.UintToBytes(roundSeqUpdate.sequence), rc.roundTimingInfo.Round*2).Err(); err != nil {
-			log.Error("Error updating round's sequence count in redis", "key", key, "err", err) // this shouldn't be a problem if future msgs succeed in updating the count
+		for roundNs, sequence := range roundSeqUpdates {
+			curSeq, _ := rc.roundSeqMap.Get(roundNs)
+			if sequence <= curSeq {
+				continue
+			}
+			rc.roundSeqMap.Add(roundNs, sequence)
+			key := roundSequenceKeyFor(roundNs.round, roundNs.namespace)
+			if err := rc.client.Set(ctx, key, arbmath.UintToBytes(sequence), rc.roundTimingInfo.Round*2).Err(); err != nil {
+				log.Error("Error updating round's sequence count in redis", "key", key, "err", err) // this shouldn't be a problem if future msgs succeed in updating the count
+			}
 		}
 	}
 }
 
-func acceptedTxKeyFor(round, seqNum uint64) string {
-	return fmt.Sprintf("%s%d.%d", EXPRESS_LANE_ACCEPTED_TX_KEY_PREFIX, round, seqNum)
+func acceptedTxKeyFor(round, namespace, seqNum uint64) string {
+	if namespace == 0 {
+		return fmt.Sprintf("%s%d.%d", EXPRESS_LANE_ACCEPTED_TX_KEY_PREFIX, round, seqNum)
+	}
+	return fmt.Sprintf("%s%d.%d.%d", EXPRESS_LANE_ACCEPTED_TX_KEY_PREFIX, round, namespace, seqNum)
 }
 
 func (rc *RedisCoordinator) GetAcceptedTxs(round, startSeqNum, endSeqNum uint64) []*ExpressLaneSubmission {
+	return rc.GetNamespaceAcceptedTxs(round, 0, startSeqNum, endSeqNum)
+}
+
+// GetNamespaceAcceptedTxs returns the accepted txs of a sequence namespace of the round in the given sequence range.
+func (rc *RedisCoordinator) GetNamespaceAcceptedTxs(round, namespace, startSeqNum, endSeqNum uint64) []*ExpressLaneSubmission {
 	ctx := rc.GetContext()
 	fetchMsg := func(key string) *ExpressLaneSubmission {
 		msgBytes, err := rc.client.Get(ctx, key).Bytes()
@@ -159,7 +194,7 @@ func (rc *RedisCoordinator) GetAcceptedTxs(round, startSeqNum, endSeqNum uint64)
 
 	var msgs []*ExpressLaneSubmission
 	for seq := startSeqNum; seq <= endSeqNum; seq++ {
-		if msg := fetchMsg(acceptedTxKeyFor(round, seq)); msg != nil {
+		if msg := fetchMsg(acceptedTxKeyFor(round, namespace, seq)); msg != nil {
 			msgs = append(msgs, msg)
 		}
 	}
@@ -167,8 +202,13 @@ func (rc *RedisCoordinator) GetAcceptedTxs(round, startSeqNum, endSeqNum uint64)
 }
 
 func (rc *RedisCoordinator) AddAcceptedTx(msg *ExpressLaneSubmission) error {
+	return rc.AddNamespaceAcceptedTx(msg, 0)
+}
+
+// AddNamespaceAcceptedTx queues the addition of an accepted tx of the given sequence namespace.
+func (rc *RedisCoordinator) AddNamespaceAcceptedTx(msg *ExpressLaneSubmission, namespace uint64) error {
 	select {
-	case rc.msgChan <- msg:
+	case rc.msgChan <- acceptedTxItem{msg: msg, namespace: namespace}:
 	default:
 		return errors.New("couldn't queue addition of expressLaneSubmission to redis")
 	}
@@ -177,16 +217,17 @@ func (rc *RedisCoordinator) AddAcceptedTx(msg *ExpressLaneSubmission) error {
 
 func (rc *RedisCoordinator) trackAcceptedTxAddition(ctx context.Context) {
 	for {
-		var msg *ExpressLaneSubmission
+		var item acceptedTxItem
 		select {
-		case msg = <-rc.msgChan:
-			if msg.Round < rc.roundTimingInfo.RoundNumber() {
+		case item = <-rc.msgChan:
+			if item.msg.Round < rc.roundTimingInfo.RoundNumber() {
 				// This prevents stale messages from being written to redis and unclogs msgChan
 				continue
 			}
 		case <-ctx.Done():
 			return
 		}
+		msg := item.msg
 		msgJson, err := msg.ToJson()
 		if err != nil {
 			log.Error("Failed to convert ExpressLaneSubmission to JsonExpressLaneSubmission", "err", err)
@@ -197,9 +238,46 @@ func (rc *RedisCoordinator) trackAcceptedTxAddition(ctx context.Context) {
 			log.Error("Failed to marshal JsonExpressLaneSubmission", "err", err)
 			continue
 		}
-		key := acceptedTxKeyFor(msg.Round, msg.SequenceNumber)
+		key := acceptedTxKeyFor(msg.Round, item.namespace, msg.SequenceNumber)
 		if err := rc.client.Set(ctx, key, msgBytes, rc.roundTimingInfo.Round*2).Err(); err != nil {
 			log.Error("Couldn't set key for accepted expressLane transaction in redis", "key", key, "err", err)
 		}
 	}
 }
+
+func delegationKeyFor(round uint64, delegate common.Address) string {
+	return fmt.Sprintf("%s%d.%s", EXPRESS_LANE_DELEGATION_KEY_PREFIX, round, delegate.Hex())
+}
+
+// AddDelegation stores the delegation so that other sequencers accept the delegate's submissions after taking over.
+func (rc *RedisCoordinator) AddDelegation(delegation *ExpressLaneDelegation) error {
+	delegationBytes, err := json.Marshal(delegation.ToJson())
+	if err != nil {
+		return err
+	}
+	return rc.client.Set(rc.GetContext(), delegationKeyFor(delegation.Round, delegation.Delegate), delegationBytes, rc.roundTimingInfo.Round*2).Err()
+}
+
+// GetDelegations returns the delegations stored for the round.
+func (rc *RedisCoordinator) GetDelegations(round uint64) ([]*ExpressLaneDelegation, error) {
+	ctx := rc.GetContext()
+	var delegations []*ExpressLaneDelegation
+	iter := rc.client.Scan(ctx, 0, fmt.Sprintf("%s%d.*", EXPRESS_LANE_DELEGATION_KEY_PREFIX, round), 0).Iterator()
+	for iter.Next(ctx) {
+		key := iter.Val()
+		delegationBytes, err := rc.client.Get(ctx, key).Bytes()
+		if errors.Is(err, redis.Nil) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		delegationJson := JsonExpressLaneDelegation{}
+		if err := json.Unmarshal(delegationBytes, &delegationJson); err != nil {
+			log.Error("Error unmarshalling express lane delegation", "key", key, "err", err)
+			continue
+		}
+		delegations = append(delegations, JsonDelegationToGo(&delegationJson))
+	}
+	return delegations, iter.Err()
+}
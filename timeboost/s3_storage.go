@@ -4,8 +4,12 @@ import (
 	"bytes"
 	"context"
 	"encoding/csv"
+	"errors"
 	"fmt"
+	"regexp"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -138,7 +142,7 @@ func (s *S3StorageService) uploadBatch(ctx context.Context, batch []byte, firstR
 	return nil
 }
 
-// downloadBatch is only used for testing purposes
+// downloadBatch reads back an uploaded batch, for the auction history API.
 func (s *S3StorageService) downloadBatch(ctx context.Context, key string) ([]byte, error) {
 	buf := manager.NewWriteAtBuffer([]byte{})
 	if _, err := s.client.Download(ctx, buf, &s3.GetObjectInput{
@@ -245,3 +249,98 @@ func (s *S3StorageService) uploadBatches(ctx context.Context) time.Duration {
 
 	return s.config.UploadInterval
 }
+
+// archivedBatch is a batch of validated bids uploaded to S3, with the range of rounds of its bids.
+type archivedBatch struct {
+	key        string
+	firstRound uint64
+	lastRound  uint64
+}
+
+var batchRoundsRegexp = regexp.MustCompile(`(\d+)-(\d+)\.csv\.gzip$`)
+
+// listBatches returns the uploaded batches, in order of their first round.
+func (s *S3StorageService) listBatches(ctx context.Context) ([]archivedBatch, error) {
+	client := s.client.Client()
+	if client == nil {
+		return nil, errors.New("S3 client can't list objects")
+	}
+	paginator := s3.NewListObjectsV2Paginator(client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(s.bucket),
+		Prefix: aws.String(s.objectPrefix + "validated-timeboost-bids/"),
+	})
+	var batches []archivedBatch
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, object := range page.Contents {
+			key := aws.ToString(object.Key)
+			batch, ok := parseBatchName(key)
+			if !ok {
+				log.Warn("Ignoring S3 object not named like a batch of validated bids", "key", key)
+				continue
+			}
+			batches = append(batches, batch)
+		}
+	}
+	sort.Slice(batches, func(i, j int) bool { return batches[i].firstRound < batches[j].firstRound })
+	return batches, nil
+}
+
+func parseBatchName(key string) (archivedBatch, bool) {
+	matches := batchRoundsRegexp.FindStringSubmatch(key)
+	if matches == nil {
+		return archivedBatch{}, false
+	}
+	firstRound, err := strconv.ParseUint(matches[1], 10, 64)
+	if err != nil {
+		return archivedBatch{}, false
+	}
+	lastRound, err := strconv.ParseUint(matches[2], 10, 64)
+	if err != nil || lastRound < firstRound {
+		return archivedBatch{}, false
+	}
+	return archivedBatch{key: key, firstRound: firstRound, lastRound: lastRound}, true
+}
+
+// readBatch downloads an uploaded batch and parses its validated bids.
+func (s *S3StorageService) readBatch(ctx context.Context, key string) ([]*SqliteDatabaseBid, error) {
+	data, err := s.downloadBatch(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	return parseBatch(data)
+}
+
+func parseBatch(data []byte) ([]*SqliteDatabaseBid, error) {
+	records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	bids := make([]*SqliteDatabaseBid, 0, len(records))
+	for _, record := range records {
+		if len(record) != 7 {
+			return nil, fmt.Errorf("batch record has %d fields, expected 7", len(record))
+		}
+		if record[0] == "ChainID" {
+			// Header
+			continue
+		}
+		round, err := strconv.ParseUint(strings.TrimSpace(record[4]), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid round in batch record: %w", err)
+		}
+		bids = append(bids, &SqliteDatabaseBid{
+			ChainId:                record[0],
+			Bidder:                 record[1],
+			ExpressLaneController:  record[2],
+			AuctionContractAddress: record[3],
+			Round:                  round,
+			Amount:                 record[5],
+			Signature:              record[6],
+		})
+	}
+	return bids, nil
+}
@@ -21,5 +21,37 @@ CREATE TABLE IF NOT EXISTS Bids (
 );
 CREATE INDEX idx_bids_round ON Bids(Round);
 `
-	schemaList = []string{version1}
+	version2 = `
+CREATE TABLE IF NOT EXISTS AuctionResults (
+    Round INTEGER NOT NULL PRIMARY KEY,
+    FirstBidder TEXT NOT NULL,
+    FirstExpressLaneController TEXT NOT NULL,
+    FirstAmount TEXT NOT NULL,
+    SecondBidder TEXT NOT NULL,
+    SecondExpressLaneController TEXT NOT NULL,
+    SecondAmount TEXT NOT NULL,
+    Price TEXT NOT NULL,
+    BidCount INTEGER NOT NULL,
+    TxHash TEXT NOT NULL,
+    ResolvedAt INTEGER NOT NULL
+);
+`
+	schemaList = []string{version1, version2}
+
+	ledgerVersion1 = `
+CREATE TABLE IF NOT EXISTS LedgerBids (
+    Round INTEGER NOT NULL PRIMARY KEY,
+    Strategy TEXT NOT NULL,
+    Amount TEXT NOT NULL,
+    SubmittedAt INTEGER NOT NULL,
+    Won INTEGER NOT NULL DEFAULT 0,
+    Price TEXT NOT NULL DEFAULT '0'
+);
+CREATE TABLE IF NOT EXISTS LedgerDeposits (
+    Id INTEGER NOT NULL PRIMARY KEY AUTOINCREMENT,
+    Amount TEXT NOT NULL,
+    DepositedAt INTEGER NOT NULL
+);
+`
+	ledgerSchemaList = []string{ledgerVersion1}
 )
@@ -231,13 +231,23 @@ func (els *ExpressLaneSubmission) Sender() (common.Address, error) {
 	if err != nil {
 		return common.Address{}, ErrMalformedData
 	}
-	if len(els.Signature) != 65 {
+	sender, err := recoverSigner(signingMessage, els.Signature)
+	if err != nil {
+		return common.Address{}, err
+	}
+	els.sender = sender
+	return els.sender, nil
+}
+
+// recoverSigner returns the address that signed the message as an Ethereum signed message.
+func recoverSigner(signingMessage []byte, signature []byte) (common.Address, error) {
+	if len(signature) != 65 {
 		return common.Address{}, errors.Wrap(ErrMalformedData, "signature length is not 65")
 	}
 	// Recover the public key.
 	prefixed := crypto.Keccak256(append([]byte(fmt.Sprintf("\x19Ethereum Signed Message:\n%d", len(signingMessage))), signingMessage...))
-	sigItem := make([]byte, len(els.Signature))
-	copy(sigItem, els.Signature)
+	sigItem := make([]byte, len(signature))
+	copy(sigItem, signature)
 	// Signature verification expects the last byte of the signature to have 27 subtracted,
 	// as it represents the recovery ID. If the last byte is greater than or equal to 27, it indicates a recovery ID that hasn't been adjusted yet,
 	// it's needed for internal signature verification logic.
@@ -248,8 +258,7 @@ func (els *ExpressLaneSubmission) Sender() (common.Address, error) {
 	if err != nil {
 		return common.Address{}, ErrMalformedData
 	}
-	els.sender = crypto.PubkeyToAddress(*pubkey)
-	return els.sender, nil
+	return crypto.PubkeyToAddress(*pubkey), nil
 }
 
 // Helper function to pad a big integer to 32 bytes
@@ -270,3 +279,17 @@ type SqliteDatabaseBid struct {
 	Amount                 string `db:"Amount"`
 	Signature              string `db:"Signature"`
 }
+
+type SqliteDatabaseAuctionResult struct {
+	Round                       uint64 `db:"Round"`
+	FirstBidder                 string `db:"FirstBidder"`
+	FirstExpressLaneController  string `db:"FirstExpressLaneController"`
+	FirstAmount                 string `db:"FirstAmount"`
+	SecondBidder                string `db:"SecondBidder"`
+	SecondExpressLaneController string `db:"SecondExpressLaneController"`
+	SecondAmount                string `db:"SecondAmount"`
+	Price                       string `db:"Price"`
+	BidCount                    uint64 `db:"BidCount"`
+	TxHash                      string `db:"TxHash"`
+	ResolvedAt                  int64  `db:"ResolvedAt"`
+}
@@ -26,6 +26,20 @@ func (m *SyncMap[K, V]) Store(key K, val V) {
 	m.internal.Store(key, val)
 }
 
+// Swap stores val for key, and returns the previous value if there was one.
+func (m *SyncMap[K, V]) Swap(key K, val V) (V, bool) {
+	prev, loaded := m.internal.Swap(key, val)
+	if !loaded {
+		var empty V
+		return empty, false
+	}
+	vPrev, ok := prev.(V)
+	if !ok {
+		panic(fmt.Sprintf("type assertion failed on %s", prev))
+	}
+	return vPrev, true
+}
+
 func (m *SyncMap[K, V]) Delete(key K) {
 	m.internal.Delete(key)
 }
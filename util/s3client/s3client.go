@@ -2,12 +2,22 @@ package s3client
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
 	"io"
+	"net/http"
+	"os"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
 	awsConfig "github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
 	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
 )
 
 type Uploader interface {
@@ -30,18 +40,68 @@ type s3Client struct {
 	downloader Downloader
 }
 
+// Options configure how to reach S3, or an S3 compatible object store, and how to authenticate to it.
+// Credentials not given explicitly come from the default AWS credential chain.
+type Options struct {
+	AccessKey string
+	SecretKey string
+	Region    string
+	// Endpoint replaces the AWS endpoint, to use an S3 compatible object store such as MinIO or Ceph RGW.
+	Endpoint string
+	// UsePathStyle addresses buckets as endpoint/bucket instead of bucket.endpoint, which most S3 compatible stores need.
+	UsePathStyle bool
+	// Profile selects a profile of the shared AWS config and credentials files.
+	Profile string
+	// RoleARN is a role to assume with the credentials found otherwise.
+	RoleARN string
+	// RootCA is a PEM file of the certificate authorities to trust, for endpoints with self-signed certificates.
+	RootCA             string
+	InsecureSkipVerify bool
+}
+
 func NewS3FullClient(accessKey, secretKey, region string) (FullClient, error) {
-	cfg, err := awsConfig.LoadDefaultConfig(context.TODO(), awsConfig.WithRegion(region), func(options *awsConfig.LoadOptions) error {
+	return NewS3FullClientWithOptions(Options{
+		AccessKey: accessKey,
+		SecretKey: secretKey,
+		Region:    region,
+	})
+}
+
+func NewS3FullClientWithOptions(opts Options) (FullClient, error) {
+	loadOptions := []func(*awsConfig.LoadOptions) error{awsConfig.WithRegion(opts.Region)}
+	if opts.Profile != "" {
+		loadOptions = append(loadOptions, awsConfig.WithSharedConfigProfile(opts.Profile))
+	}
+	if opts.RootCA != "" || opts.InsecureSkipVerify {
+		tlsCfg, err := tlsConfig(opts)
+		if err != nil {
+			return nil, err
+		}
+		httpClient := awshttp.NewBuildableClient().WithTransportOptions(func(transport *http.Transport) {
+			transport.TLSClientConfig = tlsCfg
+		})
+		loadOptions = append(loadOptions, awsConfig.WithHTTPClient(httpClient))
+	}
+	loadOptions = append(loadOptions, func(options *awsConfig.LoadOptions) error {
 		// remain backward compatible with accessKey and secretKey credentials provided via cli flags
-		if accessKey != "" && secretKey != "" {
-			options.Credentials = credentials.NewStaticCredentialsProvider(accessKey, secretKey, "")
+		if opts.AccessKey != "" && opts.SecretKey != "" {
+			options.Credentials = credentials.NewStaticCredentialsProvider(opts.AccessKey, opts.SecretKey, "")
 		}
 		return nil
 	})
+	cfg, err := awsConfig.LoadDefaultConfig(context.TODO(), loadOptions...)
 	if err != nil {
 		return nil, err
 	}
-	client := s3.NewFromConfig(cfg)
+	if opts.RoleARN != "" {
+		cfg.Credentials = aws.NewCredentialsCache(stscreds.NewAssumeRoleProvider(sts.NewFromConfig(cfg), opts.RoleARN))
+	}
+	client := s3.NewFromConfig(cfg, func(options *s3.Options) {
+		if opts.Endpoint != "" {
+			options.BaseEndpoint = aws.String(opts.Endpoint)
+		}
+		options.UsePathStyle = opts.UsePathStyle
+	})
 	return &s3Client{
 		client:     client,
 		uploader:   manager.NewUploader(client),
@@ -49,6 +109,25 @@ func NewS3FullClient(accessKey, secretKey, region string) (FullClient, error) {
 	}, nil
 }
 
+func tlsConfig(opts Options) (*tls.Config, error) {
+	tlsCfg := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: opts.InsecureSkipVerify, // #nosec G402
+	}
+	if opts.RootCA != "" {
+		rootCrt, err := os.ReadFile(opts.RootCA)
+		if err != nil {
+			return nil, fmt.Errorf("error reading S3 root CA: %w", err)
+		}
+		rootCertPool := x509.NewCertPool()
+		if !rootCertPool.AppendCertsFromPEM(rootCrt) {
+			return nil, errors.New("no certificates found in S3 root CA file")
+		}
+		tlsCfg.RootCAs = rootCertPool
+	}
+	return tlsCfg, nil
+}
+
 func (s *s3Client) Client() *s3.Client {
 	return s.client
 }
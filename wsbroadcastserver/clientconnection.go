@@ -7,6 +7,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
 	"math/rand"
 	"net"
 	"sync"
@@ -27,6 +28,10 @@ import (
 
 var errContextDone = errors.New("context done")
 
+// persistedCatchupBatchSize is the number of messages read from the persistent
+// backlog and sent to a client at a time.
+const persistedCatchupBatchSize = 240
+
 type message struct {
 	data           []byte
 	sequenceNumber *arbutil.MessageIndex
@@ -60,8 +65,11 @@ type ClientConnection struct {
 
 	compression bool
 	flateReader *wsflate.Reader
+	encoding    m.Encoding
 
 	delay time.Duration
+	// maxPersistedCatchup is the most messages sent from the persistent backlog, or -1 for no limit.
+	maxPersistedCatchup int
 }
 
 func NewClientConnection(
@@ -71,25 +79,29 @@ func NewClientConnection(
 	requestedSeqNum arbutil.MessageIndex,
 	connectingIP net.IP,
 	compression bool,
+	encoding m.Encoding,
 	maxSendQueue int,
 	delay time.Duration,
+	maxPersistedCatchup int,
 	bklg backlog.Backlog,
 ) *ClientConnection {
 	clientConnection := &ClientConnection{
-		conn:            conn,
-		clientIp:        connectingIP,
-		desc:            desc,
-		creation:        time.Now(),
-		Name:            fmt.Sprintf("%s@%s-%d", connectingIP, conn.RemoteAddr(), rand.Intn(10)),
-		clientAction:    clientAction,
-		requestedSeqNum: requestedSeqNum,
-		out:             make(chan message, maxSendQueue),
-		compression:     compression,
-		flateReader:     NewFlateReader(),
-		delay:           delay,
-		backlog:         bklg,
-		registered:      make(chan bool, 1),
-		backlogSent:     false,
+		conn:                conn,
+		clientIp:            connectingIP,
+		desc:                desc,
+		creation:            time.Now(),
+		Name:                fmt.Sprintf("%s@%s-%d", connectingIP, conn.RemoteAddr(), rand.Intn(10)),
+		clientAction:        clientAction,
+		requestedSeqNum:     requestedSeqNum,
+		out:                 make(chan message, maxSendQueue),
+		compression:         compression,
+		flateReader:         NewFlateReader(),
+		encoding:            encoding,
+		delay:               delay,
+		maxPersistedCatchup: maxPersistedCatchup,
+		backlog:             bklg,
+		registered:          make(chan bool, 1),
+		backlogSent:         false,
 	}
 	clientConnection.lastHeardUnix.Store(time.Now().Unix())
 	return clientConnection
@@ -103,6 +115,10 @@ func (cc *ClientConnection) Compression() bool {
 	return cc.compression
 }
 
+func (cc *ClientConnection) Encoding() m.Encoding {
+	return cc.encoding
+}
+
 // Register sends the ClientConnection to be registered with the ClientManager.
 func (cc *ClientConnection) Register() {
 	cc.clientAction <- ClientConnectionAction{
@@ -166,8 +182,62 @@ func (cc *ClientConnection) writeBacklog(ctx context.Context, segment backlog.Ba
 	return nil
 }
 
+// writePersistedBacklog sends the messages from the requested sequence number
+// that are only left in the persistent backlog, up to the start of the given
+// in-memory head segment. With a catch-up limit, clients further behind the
+// head than the limit aren't sent persisted messages at all.
+func (cc *ClientConnection) writePersistedBacklog(ctx context.Context, head backlog.BacklogSegment) error {
+	next := uint64(cc.requestedSeqNum)
+	if next == 0 || cc.maxPersistedCatchup == 0 {
+		return nil
+	}
+	end := uint64(math.MaxUint64)
+	if !backlog.IsBacklogSegmentNil(head) {
+		if head.Start() <= next {
+			return nil
+		}
+		end = head.Start() - 1
+	}
+	if cc.maxPersistedCatchup > 0 {
+		// #nosec G115
+		maxCatchup := uint64(cc.maxPersistedCatchup)
+		if end != math.MaxUint64 && end-next+1 > maxCatchup {
+			log.Debug("requested sequence number too far behind for catch-up from persistent backlog", "client", cc.Name, "sequenceNumber", next, "head", end+1)
+			return nil
+		}
+		end = min(end, next+maxCatchup-1)
+	}
+	for next <= end {
+		select {
+		case <-ctx.Done():
+			return errContextDone
+		default:
+		}
+
+		msgs, err := cc.backlog.GetPersisted(next, min(end, next+persistedCatchupBatchSize-1))
+		if err != nil {
+			// The requested messages may not have been persisted, in which case
+			// the client catches up from the in-memory backlog as before.
+			log.Debug("requested messages not in persistent backlog", "client", cc.Name, "sequenceNumber", next, "err", err)
+			return nil
+		}
+		err = cc.writeBroadcastMessage(&m.BroadcastMessage{
+			Version:  m.V1,
+			Messages: msgs,
+		})
+		if err != nil {
+			return err
+		}
+		last := uint64(msgs[len(msgs)-1].SequenceNumber)
+		cc.LastSentSeqNum.Store(last)
+		log.Debug("persisted messages sent to client", "client", cc.Name, "sentCount", len(msgs), "lastSentSeqNum", last)
+		next = last + 1
+	}
+	return nil
+}
+
 func (cc *ClientConnection) writeBroadcastMessage(bm *m.BroadcastMessage) error {
-	notCompressed, compressed, err := serializeMessage(bm, !cc.compression, cc.compression)
+	notCompressed, compressed, err := serializeMessage(bm, !cc.compression, cc.compression, cc.encoding)
 	if err != nil {
 		return err
 	}
@@ -204,6 +274,14 @@ func (cc *ClientConnection) Start(parentCtx context.Context) {
 		// Send the current backlog before registering the ClientConnection in
 		// case the backlog is very large
 		segment := cc.backlog.Head()
+		err := cc.writePersistedBacklog(ctx, segment)
+		if errors.Is(err, errContextDone) {
+			return
+		} else if err != nil {
+			logWarn(err, "error writing messages from persistent backlog")
+			cc.Remove()
+			return
+		}
 		if !backlog.IsBacklogSegmentNil(segment) && segment.Start() < uint64(cc.requestedSeqNum) {
 			s, err := cc.backlog.Lookup(uint64(cc.requestedSeqNum))
 			if err != nil {
@@ -212,7 +290,7 @@ func (cc *ClientConnection) Start(parentCtx context.Context) {
 				segment = s
 			}
 		}
-		err := cc.writeBacklog(ctx, segment)
+		err = cc.writeBacklog(ctx, segment)
 		if errors.Is(err, errContextDone) {
 			return
 		} else if err != nil {
@@ -156,22 +156,36 @@ func (cm *ClientManager) doBroadcast(bm *m.BroadcastMessage) ([]*ClientConnectio
 		return nil, err
 	}
 	config := cm.config()
-	//                                        /-> wsutil.Writer -> not compressed msg buffer
-	// bm -> json.Encoder -> io.MultiWriter -|
-	//                                        \-> flateWriter -> wsutil.Writer -> compressed msg buffer
+	//                                                          /-> wsutil.Writer -> not compressed msg buffer
+	// bm -> json.Encoder or bm.MarshalBinary -> io.MultiWriter -|
+	//                                                          \-> flateWriter -> wsutil.Writer -> compressed msg buffer
 
-	notCompressed, compressed, err := serializeMessage(bm, !config.RequireCompression, config.EnableCompression)
+	notCompressed, compressed, err := serializeMessage(bm, !config.RequireCompression, config.EnableCompression, m.EncodingJSON)
 	if err != nil {
 		return nil, err
 	}
+	// The binary encoding is only serialized if a client negotiated it.
+	var binaryNotCompressed, binaryCompressed bytes.Buffer
+	binarySerialized := false
 
 	sendQueueTooLargeCount := 0
 	clientDeleteList := make([]*ClientConnection, 0, len(cm.clientPtrMap))
 	for client := range cm.clientPtrMap {
+		clientNotCompressed, clientCompressed := &notCompressed, &compressed
+		if client.Encoding() == m.EncodingBinary {
+			if !binarySerialized {
+				binaryNotCompressed, binaryCompressed, err = serializeMessage(bm, !config.RequireCompression, config.EnableCompression, m.EncodingBinary)
+				if err != nil {
+					return nil, err
+				}
+				binarySerialized = true
+			}
+			clientNotCompressed, clientCompressed = &binaryNotCompressed, &binaryCompressed
+		}
 		var data []byte
 		if client.Compression() {
 			if config.EnableCompression {
-				data = compressed.Bytes()
+				data = clientCompressed.Bytes()
 			} else {
 				log.Warn("disconnecting because client has enabled compression, but compression support is disabled", "client", client.Name)
 				clientDeleteList = append(clientDeleteList, client)
@@ -179,7 +193,7 @@ func (cm *ClientManager) doBroadcast(bm *m.BroadcastMessage) ([]*ClientConnectio
 			}
 		} else {
 			if !config.RequireCompression {
-				data = notCompressed.Bytes()
+				data = clientNotCompressed.Bytes()
 			} else {
 				log.Warn("disconnecting because client has disabled compression, but compression support is required", "client", client.Name)
 				clientDeleteList = append(clientDeleteList, client)
@@ -221,7 +235,11 @@ func (cm *ClientManager) doBroadcast(bm *m.BroadcastMessage) ([]*ClientConnectio
 	return clientDeleteList, nil
 }
 
-func serializeMessage(bm *m.BroadcastMessage, enableNonCompressedOutput, enableCompressedOutput bool) (bytes.Buffer, bytes.Buffer, error) {
+func serializeMessage(bm *m.BroadcastMessage, enableNonCompressedOutput, enableCompressedOutput bool, encoding m.Encoding) (bytes.Buffer, bytes.Buffer, error) {
+	opCode := ws.OpText
+	if encoding == m.EncodingBinary {
+		opCode = ws.OpBinary
+	}
 	flateWriter, err := flate.NewWriterDict(nil, DeflateCompressionLevel, GetStaticCompressorDictionary())
 	if err != nil {
 		return bytes.Buffer{}, bytes.Buffer{}, fmt.Errorf("unable to create flate writer: %w", err)
@@ -233,11 +251,11 @@ func serializeMessage(bm *m.BroadcastMessage, enableNonCompressedOutput, enableC
 	var notCompressedWriter *wsutil.Writer
 	var compressedWriter *wsutil.Writer
 	if enableNonCompressedOutput {
-		notCompressedWriter = wsutil.NewWriter(&notCompressed, ws.StateServerSide, ws.OpText)
+		notCompressedWriter = wsutil.NewWriter(&notCompressed, ws.StateServerSide, opCode)
 		writers = append(writers, notCompressedWriter)
 	}
 	if enableCompressedOutput {
-		compressedWriter = wsutil.NewWriter(&compressed, ws.StateServerSide|ws.StateExtended, ws.OpText)
+		compressedWriter = wsutil.NewWriter(&compressed, ws.StateServerSide|ws.StateExtended, opCode)
 		var msg wsflate.MessageState
 		msg.SetCompressed(true)
 		compressedWriter.SetExtensions(&msg)
@@ -246,9 +264,19 @@ func serializeMessage(bm *m.BroadcastMessage, enableNonCompressedOutput, enableC
 	}
 
 	multiWriter := io.MultiWriter(writers...)
-	encoder := json.NewEncoder(multiWriter)
-	if err := encoder.Encode(bm); err != nil {
-		return bytes.Buffer{}, bytes.Buffer{}, fmt.Errorf("unable to encode message: %w", err)
+	if encoding == m.EncodingBinary {
+		data, err := bm.MarshalBinary()
+		if err != nil {
+			return bytes.Buffer{}, bytes.Buffer{}, fmt.Errorf("unable to encode message: %w", err)
+		}
+		if _, err := multiWriter.Write(data); err != nil {
+			return bytes.Buffer{}, bytes.Buffer{}, fmt.Errorf("unable to write message: %w", err)
+		}
+	} else {
+		encoder := json.NewEncoder(multiWriter)
+		if err := encoder.Encode(bm); err != nil {
+			return bytes.Buffer{}, bytes.Buffer{}, fmt.Errorf("unable to encode message: %w", err)
+		}
 	}
 	if notCompressedWriter != nil {
 		if err := notCompressedWriter.Flush(); err != nil {
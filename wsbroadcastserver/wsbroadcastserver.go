@@ -7,6 +7,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"net"
 	"net/http"
 	"net/textproto"
@@ -36,6 +37,7 @@ var (
 	HTTPHeaderFeedClientVersion       = textproto.CanonicalMIMEHeaderKey("Arbitrum-Feed-Client-Version")
 	HTTPHeaderRequestedSequenceNumber = textproto.CanonicalMIMEHeaderKey("Arbitrum-Requested-Sequence-Number")
 	HTTPHeaderChainId                 = textproto.CanonicalMIMEHeaderKey("Arbitrum-Chain-Id")
+	HTTPHeaderFeedEncoding            = textproto.CanonicalMIMEHeaderKey("Arbitrum-Feed-Encoding")
 	upgradeToWSTimer                  = metrics.NewRegisteredTimer("arb/feed/clients/upgrade/duration", nil)
 	startWithHeaderTimer              = metrics.NewRegisteredTimer("arb/feed/clients/start/duration", nil)
 )
@@ -47,36 +49,37 @@ const (
 )
 
 type BroadcasterConfig struct {
-	Enable             bool                    `koanf:"enable"`
-	Signed             bool                    `koanf:"signed"`
-	Addr               string                  `koanf:"addr"`
-	ReadTimeout        time.Duration           `koanf:"read-timeout" reload:"hot"`      // reloaded value will affect all clients (next time the timeout is checked)
-	WriteTimeout       time.Duration           `koanf:"write-timeout" reload:"hot"`     // reloading will affect only new connections
-	HandshakeTimeout   time.Duration           `koanf:"handshake-timeout" reload:"hot"` // reloading will affect only new connections
-	Port               string                  `koanf:"port"`
-	Ping               time.Duration           `koanf:"ping" reload:"hot"`           // reloaded value will change future ping intervals
-	ClientTimeout      time.Duration           `koanf:"client-timeout" reload:"hot"` // reloaded value will affect all clients (next time the timeout is checked)
-	Queue              int                     `koanf:"queue"`
-	Workers            int                     `koanf:"workers"`
-	MaxSendQueue       int                     `koanf:"max-send-queue" reload:"hot"`  // reloaded value will affect only new connections
-	RequireVersion     bool                    `koanf:"require-version" reload:"hot"` // reloaded value will affect only future upgrades to websocket
-	DisableSigning     bool                    `koanf:"disable-signing"`
-	LogConnect         bool                    `koanf:"log-connect"`
-	LogDisconnect      bool                    `koanf:"log-disconnect"`
-	EnableCompression  bool                    `koanf:"enable-compression" reload:"hot"`  // if reloaded to false will cause disconnection of clients with enabled compression on next broadcast
-	RequireCompression bool                    `koanf:"require-compression" reload:"hot"` // if reloaded to true will cause disconnection of clients with disabled compression on next broadcast
-	LimitCatchup       bool                    `koanf:"limit-catchup" reload:"hot"`
-	MaxCatchup         int                     `koanf:"max-catchup" reload:"hot"`
-	ConnectionLimits   ConnectionLimiterConfig `koanf:"connection-limits" reload:"hot"`
-	ClientDelay        time.Duration           `koanf:"client-delay" reload:"hot"`
-	Backlog            backlog.Config          `koanf:"backlog" reload:"hot"`
+	Enable               bool                    `koanf:"enable"`
+	Signed               bool                    `koanf:"signed"`
+	Addr                 string                  `koanf:"addr"`
+	ReadTimeout          time.Duration           `koanf:"read-timeout" reload:"hot"`      // reloaded value will affect all clients (next time the timeout is checked)
+	WriteTimeout         time.Duration           `koanf:"write-timeout" reload:"hot"`     // reloading will affect only new connections
+	HandshakeTimeout     time.Duration           `koanf:"handshake-timeout" reload:"hot"` // reloading will affect only new connections
+	Port                 string                  `koanf:"port"`
+	Ping                 time.Duration           `koanf:"ping" reload:"hot"`           // reloaded value will change future ping intervals
+	ClientTimeout        time.Duration           `koanf:"client-timeout" reload:"hot"` // reloaded value will affect all clients (next time the timeout is checked)
+	Queue                int                     `koanf:"queue"`
+	Workers              int                     `koanf:"workers"`
+	MaxSendQueue         int                     `koanf:"max-send-queue" reload:"hot"`  // reloaded value will affect only new connections
+	RequireVersion       bool                    `koanf:"require-version" reload:"hot"` // reloaded value will affect only future upgrades to websocket
+	DisableSigning       bool                    `koanf:"disable-signing"`
+	LogConnect           bool                    `koanf:"log-connect"`
+	LogDisconnect        bool                    `koanf:"log-disconnect"`
+	EnableCompression    bool                    `koanf:"enable-compression" reload:"hot"`     // if reloaded to false will cause disconnection of clients with enabled compression on next broadcast
+	RequireCompression   bool                    `koanf:"require-compression" reload:"hot"`    // if reloaded to true will cause disconnection of clients with disabled compression on next broadcast
+	EnableBinaryEncoding bool                    `koanf:"enable-binary-encoding" reload:"hot"` // reloaded value will affect only future upgrades to websocket
+	LimitCatchup         bool                    `koanf:"limit-catchup" reload:"hot"`
+	MaxCatchup           int                     `koanf:"max-catchup" reload:"hot"`
+	ConnectionLimits     ConnectionLimiterConfig `koanf:"connection-limits" reload:"hot"`
+	ClientDelay          time.Duration           `koanf:"client-delay" reload:"hot"`
+	Backlog              backlog.Config          `koanf:"backlog" reload:"hot"`
 }
 
 func (bc *BroadcasterConfig) Validate() error {
 	if !bc.EnableCompression && bc.RequireCompression {
 		return errors.New("require-compression cannot be true while enable-compression is false")
 	}
-	return nil
+	return bc.Backlog.Validate()
 }
 
 type BroadcasterConfigFetcher func() *BroadcasterConfig
@@ -100,6 +103,7 @@ func BroadcasterConfigAddOptions(prefix string, f *flag.FlagSet) {
 	f.Bool(prefix+".log-disconnect", DefaultBroadcasterConfig.LogDisconnect, "log every client disconnect")
 	f.Bool(prefix+".enable-compression", DefaultBroadcasterConfig.EnableCompression, "enable per message deflate compression support")
 	f.Bool(prefix+".require-compression", DefaultBroadcasterConfig.RequireCompression, "require clients to use compression")
+	f.Bool(prefix+".enable-binary-encoding", DefaultBroadcasterConfig.EnableBinaryEncoding, "send messages in the compact binary encoding to clients that ask for it, instead of JSON")
 	f.Bool(prefix+".limit-catchup", DefaultBroadcasterConfig.LimitCatchup, "only supply catchup buffer if requested sequence number is reasonable")
 	f.Int(prefix+".max-catchup", DefaultBroadcasterConfig.MaxCatchup, "the maximum size of the catchup buffer (-1 means unlimited)")
 	ConnectionLimiterConfigAddOptions(prefix+".connection-limits", f)
@@ -108,55 +112,57 @@ func BroadcasterConfigAddOptions(prefix string, f *flag.FlagSet) {
 }
 
 var DefaultBroadcasterConfig = BroadcasterConfig{
-	Enable:             false,
-	Signed:             false,
-	Addr:               "",
-	ReadTimeout:        time.Second,
-	WriteTimeout:       2 * time.Second,
-	HandshakeTimeout:   time.Second,
-	Port:               "9642",
-	Ping:               5 * time.Second,
-	ClientTimeout:      15 * time.Second,
-	Queue:              100,
-	Workers:            100,
-	MaxSendQueue:       4096,
-	RequireVersion:     false,
-	DisableSigning:     true,
-	LogConnect:         false,
-	LogDisconnect:      false,
-	EnableCompression:  false,
-	RequireCompression: false,
-	LimitCatchup:       false,
-	MaxCatchup:         -1,
-	ConnectionLimits:   DefaultConnectionLimiterConfig,
-	ClientDelay:        0,
-	Backlog:            backlog.DefaultConfig,
+	Enable:               false,
+	Signed:               false,
+	Addr:                 "",
+	ReadTimeout:          time.Second,
+	WriteTimeout:         2 * time.Second,
+	HandshakeTimeout:     time.Second,
+	Port:                 "9642",
+	Ping:                 5 * time.Second,
+	ClientTimeout:        15 * time.Second,
+	Queue:                100,
+	Workers:              100,
+	MaxSendQueue:         4096,
+	RequireVersion:       false,
+	DisableSigning:       true,
+	LogConnect:           false,
+	LogDisconnect:        false,
+	EnableCompression:    false,
+	RequireCompression:   false,
+	EnableBinaryEncoding: false,
+	LimitCatchup:         false,
+	MaxCatchup:           -1,
+	ConnectionLimits:     DefaultConnectionLimiterConfig,
+	ClientDelay:          0,
+	Backlog:              backlog.DefaultConfig,
 }
 
 var DefaultTestBroadcasterConfig = BroadcasterConfig{
-	Enable:             false,
-	Signed:             false,
-	Addr:               "0.0.0.0",
-	ReadTimeout:        2 * time.Second,
-	WriteTimeout:       2 * time.Second,
-	HandshakeTimeout:   2 * time.Second,
-	Port:               "0",
-	Ping:               5 * time.Second,
-	ClientTimeout:      15 * time.Second,
-	Queue:              1,
-	Workers:            100,
-	MaxSendQueue:       4096,
-	RequireVersion:     false,
-	DisableSigning:     false,
-	LogConnect:         false,
-	LogDisconnect:      false,
-	EnableCompression:  true,
-	RequireCompression: false,
-	LimitCatchup:       false,
-	MaxCatchup:         -1,
-	ConnectionLimits:   DefaultConnectionLimiterConfig,
-	ClientDelay:        0,
-	Backlog:            backlog.DefaultTestConfig,
+	Enable:               false,
+	Signed:               false,
+	Addr:                 "0.0.0.0",
+	ReadTimeout:          2 * time.Second,
+	WriteTimeout:         2 * time.Second,
+	HandshakeTimeout:     2 * time.Second,
+	Port:                 "0",
+	Ping:                 5 * time.Second,
+	ClientTimeout:        15 * time.Second,
+	Queue:                1,
+	Workers:              100,
+	MaxSendQueue:         4096,
+	RequireVersion:       false,
+	DisableSigning:       false,
+	LogConnect:           false,
+	LogDisconnect:        false,
+	EnableCompression:    true,
+	RequireCompression:   false,
+	EnableBinaryEncoding: false,
+	LimitCatchup:         false,
+	MaxCatchup:           -1,
+	ConnectionLimits:     DefaultConnectionLimiterConfig,
+	ClientDelay:          0,
+	Backlog:              backlog.DefaultTestConfig,
 }
 
 type WSBroadcastServer struct {
@@ -257,6 +263,8 @@ func (s *WSBroadcastServer) StartWithHeader(ctx context.Context, header ws.Hands
 			negotiate = compress.Negotiate
 		}
 		var feedClientVersionSeen bool
+		var feedEncodingRequested bool
+		encoding := m.EncodingJSON
 		var connectingIP net.IP
 		var requestedSeqNum arbutil.MessageIndex
 		upgrader := ws.Upgrader{
@@ -294,6 +302,19 @@ func (s *WSBroadcastServer) StartWithHeader(ctx context.Context, header ws.Hands
 						)
 					}
 					requestedSeqNum = arbutil.MessageIndex(num)
+				} else if headerName == HTTPHeaderFeedEncoding {
+					// The client lists the encodings it accepts in order of preference.
+					feedEncodingRequested = true
+					for _, requested := range strings.Split(string(value), ",") {
+						requestedEncoding, err := m.ParseEncoding(requested)
+						if err != nil {
+							continue
+						}
+						if requestedEncoding == m.EncodingJSON || (requestedEncoding == m.EncodingBinary && config.EnableBinaryEncoding) {
+							encoding = requestedEncoding
+							break
+						}
+					}
 				} else if headerName == HTTPHeaderCloudflareConnectingIP {
 					connectingIP = net.ParseIP(string(value))
 					log.Trace("Client IP parsed from header", "ip", connectingIP, "header", headerName, "value", string(value))
@@ -324,6 +345,11 @@ func (s *WSBroadcastServer) StartWithHeader(ctx context.Context, header ws.Hands
 					)
 				}
 
+				if feedEncodingRequested {
+					return handshakeHeaders{header, ws.HandshakeHeaderHTTP(http.Header{
+						HTTPHeaderFeedEncoding: []string{encoding.String()},
+					})}, nil
+				}
 				return header, nil
 			},
 			Negotiate: negotiate,
@@ -379,7 +405,11 @@ func (s *WSBroadcastServer) StartWithHeader(ctx context.Context, header ws.Hands
 		// Register incoming client in clientManager.
 		safeConn := writeDeadliner{conn, config.WriteTimeout}
 
-		client := NewClientConnection(safeConn, desc, s.clientManager.clientAction, requestedSeqNum, connectingIP, compressionAccepted, s.config().MaxSendQueue, s.config().ClientDelay, s.backlog)
+		maxPersistedCatchup := -1
+		if s.config().LimitCatchup {
+			maxPersistedCatchup = s.config().MaxCatchup
+		}
+		client := NewClientConnection(safeConn, desc, s.clientManager.clientAction, requestedSeqNum, connectingIP, compressionAccepted, encoding, s.config().MaxSendQueue, s.config().ClientDelay, maxPersistedCatchup, s.backlog)
 		client.Start(ctx)
 
 		// Subscribe to events about conn.
@@ -544,6 +574,21 @@ func (s *WSBroadcastServer) ClientCount() int32 {
 	return s.clientManager.ClientCount()
 }
 
+// handshakeHeaders writes several handshake headers one after the other.
+type handshakeHeaders []ws.HandshakeHeader
+
+func (h handshakeHeaders) WriteTo(w io.Writer) (int64, error) {
+	var total int64
+	for _, header := range h {
+		n, err := header.WriteTo(w)
+		total += n
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
+
 // writeDeadliner is a wrapper around net.Conn that sets write deadlines before
 // every Write() call.
 type writeDeadliner struct {